@@ -19,6 +19,8 @@
 package polaris
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
@@ -124,6 +126,9 @@ type LimitAPI interface {
 	api.SDKOwner
 	// GetQuota the interface obtains only one quota at a time
 	GetQuota(request QuotaRequest) (QuotaFuture, error)
+	// WaitQuota blocks until token quotas are granted or ctx is done, an error is returned
+	// without consuming quota when the quotas can not be granted before the deadline of ctx
+	WaitQuota(ctx context.Context, request QuotaRequest, token uint32) (QuotaFuture, error)
 	// Destroy the api is destroyed and cannot be called again
 	Destroy()
 }
@@ -152,6 +157,12 @@ func NewAuthenticateRequest() *AuthenticateRequest {
 
 type GetConfigFileRequest api.GetConfigFileRequest
 type GetConfigGroupRequest api.GetConfigGroupRequest
+type WatchConfigGroupRequest api.WatchConfigGroupRequest
+type BindConfigFileRequest api.BindConfigFileRequest
+type DeleteConfigFileRequest api.DeleteConfigFileRequest
+type ListConfigFilesRequest api.ListConfigFilesRequest
+type GetConfigFileReleaseHistoryRequest api.GetConfigFileReleaseHistoryRequest
+type RollbackConfigFileRequest api.RollbackConfigFileRequest
 
 // ConfigFile config
 type ConfigFile model.ConfigFile
@@ -172,6 +183,16 @@ type ConfigAPI interface {
 	PublishConfigFile(namespace, fileGroup, fileName string) error
 	// UpsertAndPublishConfigFile insert and publish configuration file
 	UpsertAndPublishConfigFile(namespace, fileGroup, fileName, content string) error
+	// BindConfigFile bind configuration file content to a user struct, swapped atomically on change
+	BindConfigFile(*BindConfigFileRequest) (model.ConfigFileBinding, error)
+	// DeleteConfigFile delete configuration file
+	DeleteConfigFile(*DeleteConfigFileRequest) error
+	// ListConfigFiles list configuration files with metadata in a group
+	ListConfigFiles(*ListConfigFilesRequest) (*model.ListConfigFilesResponse, error)
+	// GetConfigFileReleaseHistory list release history of configuration file
+	GetConfigFileReleaseHistory(*GetConfigFileReleaseHistoryRequest) (*model.GetConfigFileReleaseHistoryResponse, error)
+	// RollbackConfigFile rollback configuration file to a previous release
+	RollbackConfigFile(*RollbackConfigFileRequest) error
 }
 
 // ConfigGroupAPI .
@@ -182,6 +203,9 @@ type ConfigGroupAPI interface {
 
 	// FetchConfigGroup 获取配置分组
 	FetchConfigGroup(*GetConfigGroupRequest) (model.ConfigFileGroup, error)
+
+	// WatchConfigGroup 获取配置分组下所有文件的内容，并按文件粒度监听变更
+	WatchConfigGroup(*WatchConfigGroupRequest) (model.ConfigGroupWatcher, error)
 }
 
 type CircuitBreakerAPI interface {
@@ -52,6 +52,10 @@ const (
 	LBPolicyMaglev = config.DefaultLoadBalancerMaglev
 	// LBPolicyL5CST L5一致性Hash兼容算法，保证和L5产生相同的结果
 	LBPolicyL5CST = config.DefaultLoadBalancerL5CST
+	// LBPolicyLeastRequest 最少在途请求负载均衡策略，从两个权重随机候选中选择在途请求较少的实例
+	LBPolicyLeastRequest = config.DefaultLoadBalancerLeastRequest
+	// LBPolicyPeakEWMA 时延感知负载均衡策略，从随机候选中选择 时延峰值EWMA×在途请求/权重 最小的实例
+	LBPolicyPeakEWMA = config.DefaultLoadBalancerPeakEWMA
 )
 
 // SDKContext .
@@ -27,6 +27,30 @@ type GetConfigGroupRequest struct {
 	*model.GetConfigGroupRequest
 }
 
+type WatchConfigGroupRequest struct {
+	*model.WatchConfigGroupRequest
+}
+
+type BindConfigFileRequest struct {
+	*model.BindConfigFileRequest
+}
+
+type DeleteConfigFileRequest struct {
+	*model.DeleteConfigFileRequest
+}
+
+type ListConfigFilesRequest struct {
+	*model.ListConfigFilesRequest
+}
+
+type GetConfigFileReleaseHistoryRequest struct {
+	*model.GetConfigFileReleaseHistoryRequest
+}
+
+type RollbackConfigFileRequest struct {
+	*model.RollbackConfigFileRequest
+}
+
 // ConfigFileAPI 配置文件的 API
 type ConfigFileAPI interface {
 	SDKOwner
@@ -43,6 +67,16 @@ type ConfigFileAPI interface {
 	PublishConfigFile(namespace, fileGroup, fileName string) error
 	// UpsertAndPublishConfigFile 创建配置文件并发布
 	UpsertAndPublishConfigFile(namespace, fileGroup, fileName, content string) error
+	// BindConfigFile 获取并订阅配置文件，将内容解析绑定到用户结构体，配置变更时原子替换并产生配置项变更事件
+	BindConfigFile(*BindConfigFileRequest) (model.ConfigFileBinding, error)
+	// DeleteConfigFile 删除配置文件
+	DeleteConfigFile(*DeleteConfigFileRequest) error
+	// ListConfigFiles 查询配置分组下的配置文件及其元数据
+	ListConfigFiles(*ListConfigFilesRequest) (*model.ListConfigFilesResponse, error)
+	// GetConfigFileReleaseHistory 查询配置文件的发布历史
+	GetConfigFileReleaseHistory(*GetConfigFileReleaseHistoryRequest) (*model.GetConfigFileReleaseHistoryResponse, error)
+	// RollbackConfigFile 回滚配置文件到指定发布版本
+	RollbackConfigFile(*RollbackConfigFileRequest) error
 }
 
 type ConfigGroupAPI interface {
@@ -51,6 +85,8 @@ type ConfigGroupAPI interface {
 	GetConfigGroup(namespace, group string) (model.ConfigFileGroup, error)
 	// FetchConfigGroup 获取配置文件
 	FetchConfigGroup(*GetConfigGroupRequest) (model.ConfigFileGroup, error)
+	// WatchConfigGroup 获取配置分组下所有文件的内容，并按文件粒度监听新增、修改与删除
+	WatchConfigGroup(*WatchConfigGroupRequest) (model.ConfigGroupWatcher, error)
 }
 
 var (
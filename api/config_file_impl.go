@@ -19,6 +19,7 @@ package api
 
 import (
 	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/configuration/binding"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -87,6 +88,62 @@ func (c *configFileAPI) UpsertAndPublishConfigFile(namespace, fileGroup, fileNam
 	return c.context.GetEngine().SyncUpsertAndPublishConfigFile(namespace, fileGroup, fileName, content)
 }
 
+// BindConfigFile 获取配置文件并绑定到用户结构体
+func (c *configFileAPI) BindConfigFile(req *BindConfigFileRequest) (model.ConfigFileBinding, error) {
+	if req == nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "BindConfigFileRequest can not be nil")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "invalid BindConfigFileRequest")
+	}
+	configFile, err := c.context.GetEngine().SyncGetConfigFile(&model.GetConfigFileRequest{
+		Namespace: req.Namespace,
+		FileGroup: req.FileGroup,
+		FileName:  req.FileName,
+		Mode:      req.Mode,
+		Subscribe: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return binding.Bind(configFile, req.BindConfigFileRequest,
+		c.context.GetEngine().GetContext().GetContextLogger().GetBaseLogger())
+}
+
+// DeleteConfigFile 删除配置文件
+func (c *configFileAPI) DeleteConfigFile(req *DeleteConfigFileRequest) error {
+	if req == nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "DeleteConfigFileRequest can not be nil")
+	}
+	return c.context.GetEngine().SyncDeleteConfigFile(req.DeleteConfigFileRequest)
+}
+
+// ListConfigFiles 查询配置分组下的配置文件及其元数据
+func (c *configFileAPI) ListConfigFiles(req *ListConfigFilesRequest) (*model.ListConfigFilesResponse, error) {
+	if req == nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "ListConfigFilesRequest can not be nil")
+	}
+	return c.context.GetEngine().SyncListConfigFiles(req.ListConfigFilesRequest)
+}
+
+// GetConfigFileReleaseHistory 查询配置文件的发布历史
+func (c *configFileAPI) GetConfigFileReleaseHistory(
+	req *GetConfigFileReleaseHistoryRequest) (*model.GetConfigFileReleaseHistoryResponse, error) {
+	if req == nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"GetConfigFileReleaseHistoryRequest can not be nil")
+	}
+	return c.context.GetEngine().SyncGetConfigFileReleaseHistory(req.GetConfigFileReleaseHistoryRequest)
+}
+
+// RollbackConfigFile 回滚配置文件到指定发布版本
+func (c *configFileAPI) RollbackConfigFile(req *RollbackConfigFileRequest) error {
+	if req == nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "RollbackConfigFileRequest can not be nil")
+	}
+	return c.context.GetEngine().SyncRollbackConfigFile(req.RollbackConfigFileRequest)
+}
+
 // SDKContext 获取SDK上下文
 func (c *configFileAPI) SDKContext() SDKContext {
 	return c.context
@@ -132,6 +189,14 @@ func (c *configGroupAPI) FetchConfigGroup(req *GetConfigGroupRequest) (model.Con
 	return c.context.GetEngine().SyncGetConfigGroupWithReq(req.GetConfigGroupRequest)
 }
 
+// WatchConfigGroup 获取配置分组下所有文件的内容，并按文件粒度监听变更
+func (c *configGroupAPI) WatchConfigGroup(req *WatchConfigGroupRequest) (model.ConfigGroupWatcher, error) {
+	if req == nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "WatchConfigGroupRequest can not be nil")
+	}
+	return c.context.GetEngine().SyncWatchConfigGroup(req.WatchConfigGroupRequest)
+}
+
 // SDKContext 获取SDK上下文
 func (c *configGroupAPI) SDKContext() SDKContext {
 	return c.context
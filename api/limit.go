@@ -18,6 +18,7 @@
 package api
 
 import (
+	"context"
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -50,6 +51,9 @@ type QuotaRequest interface {
 
 	// SetRetryCount 设置最大重试次数
 	SetRetryCount(retryCount int)
+
+	// SetContext 设置请求上下文，用于透传调用方的 deadline、取消信号以及 trace ID 等请求级别的值
+	SetContext(ctx context.Context)
 }
 
 // NewQuotaRequest 创建配额查询请求
@@ -81,6 +85,9 @@ type LimitAPI interface {
 	SDKOwner
 	// GetQuota 获取限流配额，一次接口只获取一个配额
 	GetQuota(request QuotaRequest) (QuotaFuture, error)
+	// WaitQuota 阻塞获取 token 个配额（token 为0时沿用请求中的配额数），直到分配成功或 ctx 结束；
+	// 预估无法在 ctx 截止时间前获得配额时立即返回 ErrCodeAPITimeoutError 错误，且不划扣配额
+	WaitQuota(ctx context.Context, request QuotaRequest, token uint32) (QuotaFuture, error)
 	// Destroy 销毁API，销毁后无法再进行调用
 	Destroy()
 }
@@ -18,6 +18,8 @@
 package api
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
@@ -44,6 +46,24 @@ func (c *limitAPI) GetQuota(request QuotaRequest) (QuotaFuture, error) {
 	return c.context.GetEngine().AsyncGetQuota(mRequest)
 }
 
+// WaitQuota 阻塞获取配额
+func (c *limitAPI) WaitQuota(ctx context.Context, request QuotaRequest, token uint32) (QuotaFuture, error) {
+	if err := checkAvailable(c); err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "WaitQuota: context can not be nil")
+	}
+	mRequest, ok := request.(*model.QuotaRequestImpl)
+	if !ok {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "WaitQuota: invalid request type %T", request)
+	}
+	if err := mRequest.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncWaitQuota(ctx, mRequest, token)
+}
+
 // Destroy 销毁API
 func (c *limitAPI) Destroy() {
 	if nil != c.context {
@@ -98,6 +98,32 @@ func (c *configAPI) UpsertAndPublishConfigFile(namespace, fileGroup, fileName, c
 	return c.rawAPI.UpsertAndPublishConfigFile(namespace, fileGroup, fileName, content)
 }
 
+// BindConfigFile 获取配置文件并绑定到用户结构体
+func (c *configAPI) BindConfigFile(req *BindConfigFileRequest) (model.ConfigFileBinding, error) {
+	return c.rawAPI.BindConfigFile((*api.BindConfigFileRequest)(req))
+}
+
+// DeleteConfigFile 删除配置文件
+func (c *configAPI) DeleteConfigFile(req *DeleteConfigFileRequest) error {
+	return c.rawAPI.DeleteConfigFile((*api.DeleteConfigFileRequest)(req))
+}
+
+// ListConfigFiles 查询配置分组下的配置文件及其元数据
+func (c *configAPI) ListConfigFiles(req *ListConfigFilesRequest) (*model.ListConfigFilesResponse, error) {
+	return c.rawAPI.ListConfigFiles((*api.ListConfigFilesRequest)(req))
+}
+
+// GetConfigFileReleaseHistory 查询配置文件的发布历史
+func (c *configAPI) GetConfigFileReleaseHistory(
+	req *GetConfigFileReleaseHistoryRequest) (*model.GetConfigFileReleaseHistoryResponse, error) {
+	return c.rawAPI.GetConfigFileReleaseHistory((*api.GetConfigFileReleaseHistoryRequest)(req))
+}
+
+// RollbackConfigFile 回滚配置文件到指定发布版本
+func (c *configAPI) RollbackConfigFile(req *RollbackConfigFileRequest) error {
+	return c.rawAPI.RollbackConfigFile((*api.RollbackConfigFileRequest)(req))
+}
+
 // SDKContext 获取SDK上下文
 func (c *configAPI) SDKContext() api.SDKContext {
 	return c.rawAPI.SDKContext()
@@ -157,6 +183,11 @@ func (c *configGroupAPI) FetchConfigGroup(req *GetConfigGroupRequest) (model.Con
 	return c.rawAPI.FetchConfigGroup((*api.GetConfigGroupRequest)(req))
 }
 
+// WatchConfigGroup 获取配置分组下所有文件的内容，并按文件粒度监听变更
+func (c *configGroupAPI) WatchConfigGroup(req *WatchConfigGroupRequest) (model.ConfigGroupWatcher, error) {
+	return c.rawAPI.WatchConfigGroup((*api.WatchConfigGroupRequest)(req))
+}
+
 // SDKContext 获取SDK上下文
 func (c *configGroupAPI) SDKContext() api.SDKContext {
 	return c.rawAPI.SDKContext()
@@ -18,6 +18,8 @@
 package polaris
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/config"
 )
@@ -36,6 +38,11 @@ func (c *limitAPI) GetQuota(request QuotaRequest) (QuotaFuture, error) {
 	return c.rawAPI.GetQuota(request)
 }
 
+// WaitQuota 阻塞获取 token 个配额，直到分配成功或 ctx 结束
+func (c *limitAPI) WaitQuota(ctx context.Context, request QuotaRequest, token uint32) (QuotaFuture, error) {
+	return c.rawAPI.WaitQuota(ctx, request, token)
+}
+
 // Destroy 销毁API，销毁后无法再进行调用
 func (c *limitAPI) Destroy() {
 	c.rawAPI.Destroy()
@@ -169,6 +169,22 @@ func (c *mockConnector) UpsertAndPublishConfigFile(_ *configconnector.ConfigFile
 	return nil, fmt.Errorf("mock connector does not support UpsertAndPublishConfigFile")
 }
 
+func (c *mockConnector) DeleteConfigFile(_ *configconnector.ConfigFile, _ string) (*configconnector.ConfigFileResponse, error) {
+	return nil, fmt.Errorf("mock connector does not support DeleteConfigFile")
+}
+
+func (c *mockConnector) ListConfigFiles(_ *configconnector.ConfigFileListRequest) (*configconnector.ConfigFileRecordsResponse, error) {
+	return nil, fmt.Errorf("mock connector does not support ListConfigFiles")
+}
+
+func (c *mockConnector) GetConfigFileReleaseHistory(_ *configconnector.ConfigFileReleaseHistoryRequest) (*configconnector.ConfigFileRecordsResponse, error) {
+	return nil, fmt.Errorf("mock connector does not support GetConfigFileReleaseHistory")
+}
+
+func (c *mockConnector) RollbackConfigFile(_ *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	return nil, fmt.Errorf("mock connector does not support RollbackConfigFile")
+}
+
 func fakeGroupRevision(ns, group string) string {
 	return "rev-" + ns + "-" + group
 }
@@ -3,6 +3,7 @@ module github.com/polarismesh/polaris-go
 go 1.15
 
 require (
+	github.com/BurntSushi/toml v0.4.1
 	github.com/agiledragon/gomonkey v2.0.2+incompatible
 	github.com/dlclark/regexp2 v1.7.0
 	github.com/golang/protobuf v1.5.2
@@ -24,6 +25,9 @@ require (
 	github.com/smartystreets/goconvey v1.7.2
 	github.com/spaolacci/murmur3 v1.1.0
 	github.com/stretchr/testify v1.8.2
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
+	go.opentelemetry.io/proto/otlp v0.19.0
 	go.uber.org/zap v1.21.0
 	google.golang.org/genproto v0.0.0-20221014213838-99cd37c6964a // indirect
 	google.golang.org/grpc v1.51.0
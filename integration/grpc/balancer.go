@@ -0,0 +1,205 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Name polaris 负载均衡器名称
+const Name = "polaris"
+
+func init() {
+	balancer.Register(&balancerBuilder{})
+}
+
+// balancerBuilder polaris 负载均衡器构造器
+type balancerBuilder struct{}
+
+// Name 负载均衡器名称
+func (b *balancerBuilder) Name() string {
+	return Name
+}
+
+// Build 创建负载均衡器，连接管理复用 base balancer，选址由 polaris 路由与负载均衡完成
+func (b *balancerBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	pb := &polarisBalancer{}
+	pb.Balancer = base.NewBalancerBuilder(Name, &pickerBuilder{balancer: pb}, base.Config{}).Build(cc, opts)
+	return pb
+}
+
+// polarisBalancer 记录 resolver 传递的解析结果，供 picker 使用
+type polarisBalancer struct {
+	balancer.Balancer
+	// 最近一次的解析结果，类型为 *resolveState。
+	// 地址列表不变时 base balancer 不会重建 picker，picker 在每次 Pick 时从这里读取最新的实例，
+	// 以感知权重、健康状态、隔离状态与元数据的变化
+	state atomic.Value
+}
+
+// UpdateClientConnState 更新解析结果
+func (p *polarisBalancer) UpdateClientConnState(s balancer.ClientConnState) error {
+	if s.ResolverState.Attributes != nil {
+		if state, ok := s.ResolverState.Attributes.Value(resolveStateKey{}).(*resolveState); ok {
+			p.state.Store(state)
+		}
+	}
+	return p.Balancer.UpdateClientConnState(s)
+}
+
+// loadState 获取最近一次的解析结果，尚未收到时返回 nil
+func (p *polarisBalancer) loadState() *resolveState {
+	state, _ := p.state.Load().(*resolveState)
+	return state
+}
+
+// ExitIdle 退出空闲状态
+func (p *polarisBalancer) ExitIdle() {
+	if exitIdler, ok := p.Balancer.(balancer.ExitIdler); ok {
+		exitIdler.ExitIdle()
+	}
+}
+
+// pickerBuilder 根据就绪的连接构建 picker
+type pickerBuilder struct {
+	balancer *polarisBalancer
+}
+
+// Build 构建 picker
+func (b *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 || b.balancer.loadState() == nil {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	readySCs := make(map[string]balancer.SubConn, len(info.ReadySCs))
+	for sc, scInfo := range info.ReadySCs {
+		readySCs[scInfo.Address.Addr] = sc
+	}
+	return &picker{readySCs: readySCs, balancer: b.balancer}
+}
+
+// picker 每次 RPC 执行 polaris 路由与负载均衡，并上报调用结果
+type picker struct {
+	readySCs map[string]balancer.SubConn
+	balancer *polarisBalancer
+}
+
+// Pick 选择本次 RPC 使用的连接
+func (p *picker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	state := p.balancer.loadState()
+	md, _ := metadata.FromOutgoingContext(info.Ctx)
+
+	routeReq := &polaris.ProcessRoutersRequest{}
+	routeReq.DstInstances = state.instances
+	routeReq.SourceService = state.opts.sourceService
+	routeReq.Method = info.FullMethodName
+	routeReq.AddArguments(model.BuildMethodArgument(info.FullMethodName))
+	for key, values := range md {
+		if len(values) == 0 {
+			continue
+		}
+		routeReq.AddArguments(model.BuildHeaderArgument(key, values[0]))
+	}
+	routeResp, err := state.router.ProcessRouters(routeReq)
+	if err != nil {
+		return balancer.PickResult{}, status.Errorf(codes.Unavailable,
+			"polaris: fail to route %s, err: %v", state.service, err)
+	}
+
+	// 只在已就绪的连接中进行负载均衡，避免选中尚未建立连接的实例
+	readyInstances := make([]model.Instance, 0, len(routeResp.GetInstances()))
+	for _, instance := range routeResp.GetInstances() {
+		if _, ok := p.readySCs[instanceAddress(instance)]; ok {
+			readyInstances = append(readyInstances, instance)
+		}
+	}
+	if len(readyInstances) == 0 {
+		return balancer.PickResult{}, balancer.ErrNoSubConnAvailable
+	}
+	lbReq := &polaris.ProcessLoadBalanceRequest{}
+	lbReq.DstInstances = model.NewDefaultServiceInstances(routeResp.ServiceInfo, readyInstances)
+	lbReq.LbPolicy = state.opts.lbPolicy
+	if len(state.opts.hashKeyHeader) > 0 {
+		if values := md.Get(state.opts.hashKeyHeader); len(values) > 0 {
+			lbReq.HashKey = []byte(values[0])
+		}
+	}
+	lbResp, err := state.router.ProcessLoadBalance(lbReq)
+	if err != nil {
+		return balancer.PickResult{}, status.Errorf(codes.Unavailable,
+			"polaris: fail to load balance %s, err: %v", state.service, err)
+	}
+	instance := lbResp.GetInstance()
+	sc, ok := p.readySCs[instanceAddress(instance)]
+	if !ok {
+		return balancer.PickResult{}, balancer.ErrNoSubConnAvailable
+	}
+	startTime := time.Now()
+	return balancer.PickResult{
+		SubConn: sc,
+		Done: func(doneInfo balancer.DoneInfo) {
+			p.report(state, instance, info.FullMethodName, doneInfo.Err, time.Since(startTime))
+		},
+	}, nil
+}
+
+// report 上报调用结果，供熔断与动态权重使用
+func (p *picker) report(
+	state *resolveState, instance model.Instance, method string, err error, delay time.Duration) {
+	code := status.Code(err)
+	result := &polaris.ServiceCallResult{}
+	result.SetCalledInstance(instance)
+	result.SetMethod(method)
+	result.SetDelay(delay)
+	result.SetRetCode(int32(code))
+	result.SetRetStatus(toRetStatus(code))
+	if len(state.opts.sourceService.Service) > 0 {
+		sourceService := state.opts.sourceService
+		result.SetCallerService(&sourceService)
+	}
+	if reportErr := state.consumer.UpdateServiceCallResult(result); reportErr != nil {
+		state.logger.Warnf("[gRPC][Balancer] fail to report call result of %s, err: %v",
+			state.service, reportErr)
+	}
+}
+
+// toRetStatus 将 gRPC 状态码转换为调用结果状态：只有服务端或链路异常计为失败，
+// NotFound、InvalidArgument 等业务状态码计为成功，调用方主动取消的请求不计入失败
+func toRetStatus(code codes.Code) model.RetStatus {
+	switch code {
+	case codes.Unavailable, codes.Internal, codes.Unknown, codes.DataLoss:
+		return model.RetFail
+	case codes.DeadlineExceeded:
+		return model.RetTimeout
+	case codes.ResourceExhausted:
+		return model.RetFlowControl
+	case codes.Canceled:
+		return model.RetUnknown
+	default:
+		return model.RetSuccess
+	}
+}
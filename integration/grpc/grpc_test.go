@@ -0,0 +1,383 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// noopLogger 空日志实现，用于测试
+type noopLogger struct{}
+
+func (n *noopLogger) Tracef(format string, args ...interface{}) {}
+func (n *noopLogger) Debugf(format string, args ...interface{}) {}
+func (n *noopLogger) Infof(format string, args ...interface{})  {}
+func (n *noopLogger) Warnf(format string, args ...interface{})  {}
+func (n *noopLogger) Errorf(format string, args ...interface{}) {}
+func (n *noopLogger) Fatalf(format string, args ...interface{}) {}
+func (n *noopLogger) IsLevelEnabled(l int) bool                 { return true }
+func (n *noopLogger) SetLogLevel(l int) error                   { return nil }
+
+// fakeConsumer 基于可替换实例列表的服务发现实现
+type fakeConsumer struct {
+	mutex     sync.Mutex
+	instances *model.InstancesResponse
+	results   []*polaris.ServiceCallResult
+	listener  model.InstancesListener
+	cancelled []uint64
+}
+
+func (f *fakeConsumer) GetAllInstances(*polaris.GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.instances, nil
+}
+
+func (f *fakeConsumer) WatchAllInstances(
+	req *polaris.WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.listener = req.InstancesListener
+	return model.NewWatchAllInstancesResponse(1, f.instances, func(watchId uint64) {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		f.cancelled = append(f.cancelled, watchId)
+	}), nil
+}
+
+// setInstances 替换实例列表并通知订阅方
+func (f *fakeConsumer) setInstances(instances *model.InstancesResponse) {
+	f.mutex.Lock()
+	f.instances = instances
+	listener := f.listener
+	f.mutex.Unlock()
+	listener.OnInstancesUpdate(instances)
+}
+
+func (f *fakeConsumer) getCancelled() []uint64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]uint64{}, f.cancelled...)
+}
+
+func (f *fakeConsumer) UpdateServiceCallResult(req *polaris.ServiceCallResult) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.results = append(f.results, req)
+	return nil
+}
+
+func (f *fakeConsumer) getResults() []*polaris.ServiceCallResult {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]*polaris.ServiceCallResult{}, f.results...)
+}
+
+// fakeRouter 路由不做过滤，负载均衡选择第一个实例
+type fakeRouter struct {
+	mutex     sync.Mutex
+	arguments map[string]string
+	// 最近一次参与路由的实例
+	instances []model.Instance
+}
+
+func (f *fakeRouter) ProcessRouters(req *polaris.ProcessRoutersRequest) (*model.InstancesResponse, error) {
+	labels := map[string]string{}
+	for _, arg := range req.Arguments {
+		arg.ToLabels(labels)
+	}
+	f.mutex.Lock()
+	f.arguments = labels
+	f.instances = req.DstInstances.GetInstances()
+	f.mutex.Unlock()
+	return req.DstInstances.(*model.InstancesResponse), nil
+}
+
+func (f *fakeRouter) ProcessLoadBalance(req *polaris.ProcessLoadBalanceRequest) (*model.OneInstanceResponse, error) {
+	resp := &model.OneInstanceResponse{}
+	resp.Instances = req.DstInstances.GetInstances()[:1]
+	return resp, nil
+}
+
+func (f *fakeRouter) getArguments() map[string]string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.arguments
+}
+
+func (f *fakeRouter) getInstances() []model.Instance {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.instances
+}
+
+// newTestInstance 创建测试实例
+func newTestInstance(t *testing.T, addr string) model.Instance {
+	return newTestInstanceWithWeight(t, addr, 100)
+}
+
+// newTestInstanceWithWeight 创建指定权重的测试实例
+func newTestInstanceWithWeight(t *testing.T, addr string, weight uint32) model.Instance {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.Atoi(portStr)
+	inst := &apiservice.Instance{
+		Id:      wrapperspb.String(addr),
+		Host:    wrapperspb.String(host),
+		Port:    wrapperspb.UInt32(uint32(port)),
+		Weight:  wrapperspb.UInt32(weight),
+		Healthy: wrapperspb.Bool(true),
+		Isolate: wrapperspb.Bool(false),
+	}
+	svcKey := &model.ServiceKey{Namespace: "test-ns", Service: "test-svc"}
+	return pb.NewInstanceInProto(inst, svcKey, local.NewInstanceLocalValue())
+}
+
+// TestResolverAndBalancer 测试通过 polaris target 发起调用并上报调用结果
+func TestResolverAndBalancer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(ln)
+	}()
+	defer server.Stop()
+
+	consumer := &fakeConsumer{instances: &model.InstancesResponse{
+		ServiceInfo: model.ServiceInfo{Namespace: "test-ns", Service: "test-svc"},
+		Instances:   []model.Instance{newTestInstance(t, ln.Addr().String())},
+	}}
+	router := &fakeRouter{}
+	builder := &resolverBuilder{
+		consumer: consumer,
+		router:   router,
+		opts:     newOptions([]Option{WithSourceService("test-ns", "caller-svc")}),
+		logger:   &noopLogger{},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, BuildTarget("test-ns", "test-svc"),
+		grpc.WithResolvers(builder), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	callCtx := metadata.AppendToOutgoingContext(ctx, "env", "gray")
+	if _, err := healthpb.NewHealthClient(conn).Check(callCtx, &healthpb.HealthCheckRequest{},
+		grpc.WaitForReady(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	args := router.getArguments()
+	if args[model.LabelKeyHeader+"env"] != "gray" {
+		t.Fatalf("expect header argument env=gray, actual %v", args)
+	}
+	if args[model.LabelKeyMethod] != "/grpc.health.v1.Health/Check" {
+		t.Fatalf("expect method argument, actual %v", args)
+	}
+	results := consumer.getResults()
+	if len(results) != 1 {
+		t.Fatalf("expect 1 call result, actual %d", len(results))
+	}
+	if results[0].GetRetCodeValue() != int32(codes.OK) || results[0].GetRetStatus() != model.RetSuccess {
+		t.Fatalf("unexpected call result, code %d, status %s",
+			results[0].GetRetCodeValue(), results[0].GetRetStatus())
+	}
+	if results[0].GetCallerService() != "caller-svc" {
+		t.Fatalf("expect caller service caller-svc, actual %s", results[0].GetCallerService())
+	}
+}
+
+// TestInstancesUpdateWithSameAddress 测试地址不变时实例属性变化的感知，以及连接关闭时取消订阅
+func TestInstancesUpdateWithSameAddress(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(ln)
+	}()
+	defer server.Stop()
+
+	svcInfo := model.ServiceInfo{Namespace: "test-ns", Service: "test-svc"}
+	consumer := &fakeConsumer{instances: &model.InstancesResponse{
+		ServiceInfo: svcInfo,
+		Instances:   []model.Instance{newTestInstance(t, ln.Addr().String())},
+	}}
+	router := &fakeRouter{}
+	builder := &resolverBuilder{consumer: consumer, router: router, opts: newOptions(nil), logger: &noopLogger{}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, BuildTarget("test-ns", "test-svc"),
+		grpc.WithResolvers(builder), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := healthpb.NewHealthClient(conn)
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 同一地址的实例权重变化，地址列表不变，picker 不会重建，但需要使用最新的实例进行路由
+	consumer.setInstances(&model.InstancesResponse{
+		ServiceInfo: svcInfo,
+		Instances:   []model.Instance{newTestInstanceWithWeight(t, ln.Addr().String(), 50)},
+	})
+	for {
+		if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true)); err != nil {
+			t.Fatal(err)
+		}
+		if instances := router.getInstances(); len(instances) == 1 && instances[0].GetWeight() == 50 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("picker does not route with the updated instance")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for len(consumer.getCancelled()) == 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("watch is not cancelled after conn closed")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if cancelled := consumer.getCancelled(); len(cancelled) != 1 || cancelled[0] != 1 {
+		t.Fatalf("expect watch 1 cancelled once, actual %v", cancelled)
+	}
+}
+
+// TestParseTarget 测试 target 解析
+func TestParseTarget(t *testing.T) {
+	cases := []struct {
+		target  string
+		expect  model.ServiceKey
+		invalid bool
+	}{
+		{target: "polaris://ns/svc", expect: model.ServiceKey{Namespace: "ns", Service: "svc"}},
+		{target: "polaris:///svc", expect: model.ServiceKey{Namespace: DefaultNamespace, Service: "svc"}},
+		{target: "polaris://ns/", invalid: true},
+		{target: "polaris://ns/a/b", invalid: true},
+	}
+	for _, c := range cases {
+		target := parseTestTarget(t, c.target)
+		svcKey, err := parseTarget(target)
+		if c.invalid {
+			if err == nil {
+				t.Fatalf("expect error for target %s", c.target)
+			}
+			continue
+		}
+		if err != nil || svcKey != c.expect {
+			t.Fatalf("target %s, expect %v, actual %v, err %v", c.target, c.expect, svcKey, err)
+		}
+	}
+}
+
+func parseTestTarget(t *testing.T, target string) resolver.Target {
+	builder := &captureBuilder{}
+	conn, err := grpc.Dial(target, grpc.WithResolvers(builder), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	return builder.target
+}
+
+// captureBuilder 记录 gRPC 解析出的 target
+type captureBuilder struct {
+	target resolver.Target
+}
+
+func (c *captureBuilder) Build(target resolver.Target, _ resolver.ClientConn,
+	_ resolver.BuildOptions) (resolver.Resolver, error) {
+	c.target = target
+	return c, nil
+}
+
+func (c *captureBuilder) Scheme() string {
+	return Scheme
+}
+
+func (c *captureBuilder) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (c *captureBuilder) Close() {}
+
+// TestToRetStatus 测试 gRPC 状态码到调用结果状态的转换
+func TestToRetStatus(t *testing.T) {
+	cases := []struct {
+		code   codes.Code
+		expect model.RetStatus
+	}{
+		{code: codes.OK, expect: model.RetSuccess},
+		{code: codes.NotFound, expect: model.RetSuccess},
+		{code: codes.InvalidArgument, expect: model.RetSuccess},
+		{code: codes.AlreadyExists, expect: model.RetSuccess},
+		{code: codes.PermissionDenied, expect: model.RetSuccess},
+		{code: codes.FailedPrecondition, expect: model.RetSuccess},
+		{code: codes.Unauthenticated, expect: model.RetSuccess},
+		{code: codes.Unimplemented, expect: model.RetSuccess},
+		{code: codes.Unavailable, expect: model.RetFail},
+		{code: codes.Internal, expect: model.RetFail},
+		{code: codes.Unknown, expect: model.RetFail},
+		{code: codes.DataLoss, expect: model.RetFail},
+		{code: codes.ResourceExhausted, expect: model.RetFlowControl},
+		{code: codes.DeadlineExceeded, expect: model.RetTimeout},
+		{code: codes.Canceled, expect: model.RetUnknown},
+	}
+	for _, c := range cases {
+		if got := toRetStatus(c.code); got != c.expect {
+			t.Fatalf("code %v, expect %v, got %v", c.code, c.expect, got)
+		}
+	}
+}
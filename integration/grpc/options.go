@@ -0,0 +1,150 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"strings"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// options gRPC 集成的可选配置
+type options struct {
+	// 主调服务信息，用于匹配路由规则以及上报调用结果
+	sourceService model.ServiceInfo
+	// 负载均衡策略，为空时使用SDK配置的负载均衡插件
+	lbPolicy string
+	// 一致性哈希负载均衡使用的请求头，从 outgoing metadata 中获取
+	hashKeyHeader string
+}
+
+// Option gRPC 集成的可选配置项
+type Option func(*options)
+
+// WithSourceService 设置主调服务，用于匹配路由规则以及上报调用结果
+func WithSourceService(namespace, service string) Option {
+	return func(o *options) {
+		o.sourceService.Namespace = namespace
+		o.sourceService.Service = service
+	}
+}
+
+// WithSourceMetadata 设置主调服务的静态标签，参与路由规则的源标签匹配
+func WithSourceMetadata(metadata map[string]string) Option {
+	return func(o *options) {
+		o.sourceService.Metadata = metadata
+	}
+}
+
+// WithLbPolicy 设置负载均衡策略，取值为已注册的负载均衡插件名
+func WithLbPolicy(lbPolicy string) Option {
+	return func(o *options) {
+		o.lbPolicy = lbPolicy
+	}
+}
+
+// WithHashKeyHeader 设置一致性哈希负载均衡使用的请求头
+func WithHashKeyHeader(header string) Option {
+	return func(o *options) {
+		o.hashKeyHeader = header
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// serverOptions gRPC 服务端拦截器的可选配置
+type serverOptions struct {
+	// 关闭限流检查
+	disableRateLimit bool
+	// 关闭鉴权检查
+	disableAuth bool
+	// 携带主调服务命名空间的 metadata key，为空时不识别主调服务
+	callerNamespaceKey string
+	// 携带主调服务名的 metadata key，为空时不识别主调服务
+	callerServiceKey string
+	// 是否信任 x-forwarded-for metadata 中的主调 IP
+	trustForwardedFor bool
+	// 是否将主调 IP 作为规则匹配参数
+	callerIP bool
+	// 参与规则匹配的 incoming metadata key，统一为小写
+	metadataKeys map[string]struct{}
+}
+
+// ServerOption gRPC 服务端拦截器的可选配置项
+type ServerOption func(*serverOptions)
+
+// WithoutRateLimit 关闭服务端拦截器的限流检查
+func WithoutRateLimit() ServerOption {
+	return func(o *serverOptions) {
+		o.disableRateLimit = true
+	}
+}
+
+// WithoutAuth 关闭服务端拦截器的鉴权检查
+func WithoutAuth() ServerOption {
+	return func(o *serverOptions) {
+		o.disableAuth = true
+	}
+}
+
+// WithCallerServiceMetadata 设置携带主调服务命名空间与服务名的 incoming metadata key，用于匹配限流与鉴权规则中的主调服务
+func WithCallerServiceMetadata(namespaceKey, serviceKey string) ServerOption {
+	return func(o *serverOptions) {
+		o.callerNamespaceKey = namespaceKey
+		o.callerServiceKey = serviceKey
+	}
+}
+
+// WithTrustForwardedFor 使用 x-forwarded-for metadata 中的第一个地址作为主调 IP，仅应在可信代理之后开启，
+// 需同时通过 WithCallerIP 开启主调 IP 参数
+func WithTrustForwardedFor() ServerOption {
+	return func(o *serverOptions) {
+		o.trustForwardedFor = true
+	}
+}
+
+// WithCallerIP 将主调 IP 作为规则匹配参数，主调 IP 取值较多，仅应在规则需要按主调 IP 匹配时开启
+func WithCallerIP() ServerOption {
+	return func(o *serverOptions) {
+		o.callerIP = true
+	}
+}
+
+// WithMetadataKeys 设置参与规则匹配的 incoming metadata key，默认不传递任何 metadata；
+// 参数会随限流指标上报，不应包含 authorization 等携带凭据的 key
+func WithMetadataKeys(keys ...string) ServerOption {
+	return func(o *serverOptions) {
+		for _, key := range keys {
+			o.metadataKeys[strings.ToLower(key)] = struct{}{}
+		}
+	}
+}
+
+func newServerOptions(opts []ServerOption) *serverOptions {
+	o := &serverOptions{metadataKeys: map[string]struct{}{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
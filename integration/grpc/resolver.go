@@ -0,0 +1,246 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package grpc 提供 gRPC 客户端的 polaris 服务发现、路由与负载均衡集成，以及服务端的鉴权与限流拦截器。
+//
+// 通过 polaris://namespace/service 格式的 target 建立连接，resolver 基于 WatchAllInstances 订阅实例变更，
+// 名为 polaris 的 balancer 在每次 RPC 时执行路由与负载均衡，并将调用结果与时延上报给熔断及动态权重。
+// outgoing metadata 中的请求头会作为 header 参数参与路由规则的源标签匹配。
+//
+//	sdkCtx, _ := polaris.NewSDKContext()
+//	conn, err := grpc.Dial(polarisgrpc.BuildTarget("default", "echo"),
+//		grpc.WithResolvers(polarisgrpc.NewResolverBuilder(sdkCtx, polarisgrpc.WithSourceService("default", "caller"))),
+//		grpc.WithTransportCredentials(insecure.NewCredentials()))
+//
+// 服务端通过 ServerInterceptor 对入站 RPC 执行鉴权与限流，见 NewServerInterceptor。
+package grpc
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// Scheme polaris 服务发现的 target scheme，target 格式为 polaris://namespace/service
+	Scheme = "polaris"
+	// DefaultNamespace target 未指定命名空间时使用的命名空间
+	DefaultNamespace = "default"
+)
+
+// 默认使用 polaris 负载均衡器的服务配置
+var defaultServiceConfig = fmt.Sprintf(`{"loadBalancingConfig":[{"%s":{}}]}`, Name)
+
+// consumerAPI gRPC 集成依赖的服务发现能力
+type consumerAPI interface {
+	GetAllInstances(req *polaris.GetAllInstancesRequest) (*model.InstancesResponse, error)
+	WatchAllInstances(req *polaris.WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error)
+	UpdateServiceCallResult(req *polaris.ServiceCallResult) error
+}
+
+// routerAPI gRPC 集成依赖的路由与负载均衡能力
+type routerAPI interface {
+	ProcessRouters(req *polaris.ProcessRoutersRequest) (*model.InstancesResponse, error)
+	ProcessLoadBalance(req *polaris.ProcessLoadBalanceRequest) (*model.OneInstanceResponse, error)
+}
+
+// BuildTarget 构造 polaris://namespace/service 格式的 target
+func BuildTarget(namespace, service string) string {
+	return fmt.Sprintf("%s://%s/%s", Scheme, namespace, service)
+}
+
+// NewResolverBuilder 基于SDK上下文创建 polaris resolver，通过 grpc.WithResolvers 传入
+func NewResolverBuilder(sdkCtx api.SDKContext, opts ...Option) resolver.Builder {
+	return &resolverBuilder{
+		consumer: polaris.NewConsumerAPIByContext(sdkCtx),
+		router:   polaris.NewRouterAPIByContext(sdkCtx),
+		opts:     newOptions(opts),
+		logger:   sdkCtx.GetValueContext().GetContextLogger().GetBaseLogger(),
+	}
+}
+
+// RegisterResolver 全局注册 polaris resolver，之后可直接通过 grpc.Dial("polaris://namespace/service") 建立连接
+func RegisterResolver(sdkCtx api.SDKContext, opts ...Option) {
+	resolver.Register(NewResolverBuilder(sdkCtx, opts...))
+}
+
+// resolverBuilder polaris resolver 构造器
+type resolverBuilder struct {
+	consumer consumerAPI
+	router   routerAPI
+	opts     *options
+	logger   log.Logger
+}
+
+// Scheme 返回 resolver 对应的 scheme
+func (b *resolverBuilder) Scheme() string {
+	return Scheme
+}
+
+// Build 创建 resolver，首次同步获取实例列表并订阅实例变更
+func (b *resolverBuilder) Build(
+	target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	svcKey, err := parseTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	r := &polarisResolver{
+		builder: b,
+		cc:      cc,
+		service: svcKey,
+		done:    make(chan struct{}),
+	}
+	r.serviceConfig = cc.ParseServiceConfig(defaultServiceConfig)
+	watchReq := &polaris.WatchAllInstancesRequest{}
+	watchReq.ServiceKey = svcKey
+	watchReq.WatchMode = model.WatchModeNotify
+	watchReq.InstancesListener = r
+	watchResp, err := b.consumer.WatchAllInstances(watchReq)
+	if err != nil {
+		return nil, err
+	}
+	r.watchResp = watchResp
+	r.updateState(watchResp.InstancesResponse())
+	return r, nil
+}
+
+// parseTarget 从 target 中解析服务标识
+func parseTarget(target resolver.Target) (model.ServiceKey, error) {
+	namespace := target.URL.Host
+	service := strings.TrimPrefix(target.URL.Path, "/")
+	if len(target.URL.Opaque) > 0 {
+		service = target.URL.Opaque
+	}
+	if len(namespace) == 0 {
+		namespace = DefaultNamespace
+	}
+	if len(service) == 0 || strings.Contains(service, "/") {
+		return model.ServiceKey{}, fmt.Errorf("invalid polaris target %s, expect %s://namespace/service",
+			target.URL.String(), Scheme)
+	}
+	return model.ServiceKey{Namespace: namespace, Service: service}, nil
+}
+
+// resolveStateKey resolver 向 balancer 传递解析结果的属性 key
+type resolveStateKey struct{}
+
+// resolveState 一次解析的结果，通过 resolver.State.Attributes 传递给 balancer
+type resolveState struct {
+	consumer  consumerAPI
+	router    routerAPI
+	opts      *options
+	service   model.ServiceKey
+	instances *model.InstancesResponse
+	logger    log.Logger
+}
+
+// polarisResolver 基于 WatchAllInstances 的 resolver
+type polarisResolver struct {
+	builder       *resolverBuilder
+	cc            resolver.ClientConn
+	service       model.ServiceKey
+	serviceConfig *serviceconfig.ParseResult
+	// 实例变更订阅，Close 时取消
+	watchResp *model.WatchAllInstancesResponse
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// ResolveNow 立即刷新实例列表
+func (r *polarisResolver) ResolveNow(resolver.ResolveNowOptions) {
+	r.refresh()
+}
+
+// Close 关闭 resolver，取消实例变更订阅
+func (r *polarisResolver) Close() {
+	r.closeOnce.Do(func() {
+		close(r.done)
+		if r.watchResp != nil {
+			r.watchResp.CancelWatch()
+		}
+	})
+}
+
+// OnInstancesUpdate 实现 model.InstancesListener，实例变更后刷新实例列表。
+// 通知在独立协程中异步回调，多次通知的先后不确定，因此不使用通知中的实例，而是从SDK缓存中获取最新的实例列表
+func (r *polarisResolver) OnInstancesUpdate(*model.InstancesResponse) {
+	select {
+	case <-r.done:
+		return
+	default:
+	}
+	r.refresh()
+}
+
+// refresh 从SDK缓存中获取最新的实例列表
+func (r *polarisResolver) refresh() {
+	req := &polaris.GetAllInstancesRequest{}
+	req.Namespace = r.service.Namespace
+	req.Service = r.service.Service
+	resp, err := r.builder.consumer.GetAllInstances(req)
+	if err != nil {
+		r.builder.logger.Errorf("[gRPC][Resolver] fail to get instances of %s, err: %v", r.service, err)
+		r.cc.ReportError(err)
+		return
+	}
+	r.updateState(resp)
+}
+
+// updateState 将实例列表转换为 gRPC 地址列表
+func (r *polarisResolver) updateState(resp *model.InstancesResponse) {
+	if resp == nil {
+		return
+	}
+	addrs := make([]resolver.Address, 0, len(resp.GetInstances()))
+	for _, instance := range resp.GetInstances() {
+		if instance.IsIsolated() || instance.GetWeight() == 0 {
+			continue
+		}
+		addrs = append(addrs, resolver.Address{Addr: instanceAddress(instance)})
+	}
+	state := resolver.State{
+		Addresses:     addrs,
+		ServiceConfig: r.serviceConfig,
+		Attributes: attributes.New(resolveStateKey{}, &resolveState{
+			consumer:  r.builder.consumer,
+			router:    r.builder.router,
+			opts:      r.builder.opts,
+			service:   r.service,
+			instances: resp,
+			logger:    r.builder.logger,
+		}),
+	}
+	if err := r.cc.UpdateState(state); err != nil {
+		r.builder.logger.Warnf("[gRPC][Resolver] fail to update state of %s, err: %v", r.service, err)
+	}
+}
+
+// instanceAddress 获取实例的 host:port 地址
+func instanceAddress(instance model.Instance) string {
+	return net.JoinHostPort(instance.GetHost(), strconv.Itoa(int(instance.GetPort())))
+}
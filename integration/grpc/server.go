@@ -0,0 +1,269 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// Protocol 鉴权请求使用的协议名
+	Protocol = "grpc"
+	// MetadataRetryAfter 被限流时建议客户端重试的等待秒数
+	MetadataRetryAfter = "retry-after"
+	// MetadataRateLimitLimit 命中限流规则的阈值
+	MetadataRateLimitLimit = "ratelimit-limit"
+	// MetadataRateLimitRemaining 当前周期剩余的配额，被限流时为 0
+	MetadataRateLimitRemaining = "ratelimit-remaining"
+	// MetadataRateLimitReset 距配额重置的秒数
+	MetadataRateLimitReset = "ratelimit-reset"
+	// MetadataForwardedFor 代理透传的主调地址
+	MetadataForwardedFor = "x-forwarded-for"
+)
+
+// limitAPI 服务端拦截器依赖的限流能力
+type limitAPI interface {
+	GetQuota(request polaris.QuotaRequest) (polaris.QuotaFuture, error)
+}
+
+// authAPI 服务端拦截器依赖的鉴权能力
+type authAPI interface {
+	Authenticate(request *polaris.AuthenticateRequest) (*model.AuthenticateResponse, error)
+}
+
+// ServerInterceptor 对入站 RPC 执行 polaris 鉴权与限流的 gRPC 服务端拦截器。
+// RPC 的完整方法名与主调服务作为参数参与规则匹配，incoming metadata 与主调 IP 仅在通过 WithMetadataKeys、
+// WithCallerIP 显式开启后传递，避免凭据与高基数取值随限流指标上报；限流接口以完整方法名作为方法名；
+// 鉴权拒绝返回 PermissionDenied，被限流返回 ResourceExhausted 并在响应 header 中携带 ratelimit-* 与 retry-after。
+// SDK 调用失败时放通请求，避免治理能力故障影响业务。
+//
+//	interceptor := polarisgrpc.NewServerInterceptor(sdkCtx, "default", "echo")
+//	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor.UnaryServerInterceptor()),
+//		grpc.StreamInterceptor(interceptor.StreamServerInterceptor()))
+type ServerInterceptor struct {
+	service model.ServiceKey
+	limit   limitAPI
+	auth    authAPI
+	opts    *serverOptions
+	logger  log.Logger
+}
+
+// NewServerInterceptor 基于SDK上下文创建被调服务 namespace/service 的服务端拦截器
+func NewServerInterceptor(sdkCtx api.SDKContext, namespace, service string, opts ...ServerOption) *ServerInterceptor {
+	return &ServerInterceptor{
+		service: model.ServiceKey{Namespace: namespace, Service: service},
+		limit:   polaris.NewLimitAPIByContext(sdkCtx),
+		auth:    polaris.NewAuthAPIByContext(sdkCtx),
+		opts:    newServerOptions(opts),
+		logger:  sdkCtx.GetValueContext().GetContextLogger().GetBaseLogger(),
+	}
+}
+
+// UnaryServerInterceptor 返回一元 RPC 拦截器，handler 返回后释放并发数限流的配额
+func (s *ServerInterceptor) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		future, err := s.check(ctx, info.FullMethod, func(md metadata.MD) error {
+			return grpc.SetHeader(ctx, md)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if future != nil {
+			defer future.Release()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 返回流式 RPC 拦截器，流结束后释放并发数限流的配额
+func (s *ServerInterceptor) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		future, err := s.check(ss.Context(), info.FullMethod, ss.SetHeader)
+		if err != nil {
+			return err
+		}
+		if future != nil {
+			defer future.Release()
+		}
+		return handler(srv, ss)
+	}
+}
+
+// check 依次执行鉴权与限流，返回需要在 RPC 结束后释放的配额；SDK 调用失败时返回 nil future 并放通
+func (s *ServerInterceptor) check(ctx context.Context, fullMethod string,
+	setHeader func(metadata.MD) error) (polaris.QuotaFuture, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	caller := s.callerService(md)
+	arguments := []model.Argument{model.BuildMethodArgument(fullMethod)}
+	if s.opts.callerIP {
+		arguments = append(arguments, model.BuildCallerIPArgument(s.callerIP(ctx, md)))
+	}
+	for key, values := range md {
+		if _, ok := s.opts.metadataKeys[key]; ok && len(values) > 0 {
+			arguments = append(arguments, model.BuildHeaderArgument(key, values[0]))
+		}
+	}
+	if caller != nil {
+		arguments = append(arguments, model.BuildCallerServiceArgument(caller.Namespace, caller.Service))
+	}
+	if err := s.authenticate(fullMethod, caller, arguments); err != nil {
+		return nil, err
+	}
+	return s.acquireQuota(ctx, fullMethod, arguments, setHeader)
+}
+
+// authenticate 执行鉴权，拒绝时返回 PermissionDenied
+func (s *ServerInterceptor) authenticate(fullMethod string, caller *model.ServiceInfo,
+	arguments []model.Argument) error {
+	if s.opts.disableAuth {
+		return nil
+	}
+	authReq := polaris.NewAuthenticateRequest()
+	authReq.Namespace = s.service.Namespace
+	authReq.Service = s.service.Service
+	authReq.Method = fullMethod
+	authReq.Protocol = Protocol
+	authReq.SourceService = caller
+	authReq.Arguments = arguments
+	resp, err := s.auth.Authenticate(authReq)
+	if err != nil {
+		s.logger.Warnf("[gRPC][Server] fail to authenticate %s of %s, pass through, err: %v",
+			fullMethod, s.service, err)
+		return nil
+	}
+	if resp.IsAllowed() {
+		return nil
+	}
+	message := resp.GetInfo()
+	if len(message) == 0 {
+		message = fmt.Sprintf("polaris: %s of %s is forbidden", fullMethod, s.service)
+	}
+	return status.Error(codes.PermissionDenied, message)
+}
+
+// acquireQuota 获取配额并等待匀速排队结束，被限流时写入限流响应 header 并返回 ResourceExhausted
+func (s *ServerInterceptor) acquireQuota(ctx context.Context, fullMethod string, arguments []model.Argument,
+	setHeader func(metadata.MD) error) (polaris.QuotaFuture, error) {
+	if s.opts.disableRateLimit {
+		return nil, nil
+	}
+	quotaReq := polaris.NewQuotaRequest()
+	quotaReq.SetNamespace(s.service.Namespace)
+	quotaReq.SetService(s.service.Service)
+	quotaReq.SetMethod(fullMethod)
+	quotaReq.SetContext(ctx)
+	for _, argument := range arguments {
+		quotaReq.AddArgument(argument)
+	}
+	future, err := s.limit.GetQuota(quotaReq)
+	if err != nil {
+		s.logger.Warnf("[gRPC][Server] fail to get quota for %s of %s, pass through, err: %v",
+			fullMethod, s.service, err)
+		return nil, nil
+	}
+	resp := future.Get()
+	if resp == nil || resp.Code != model.QuotaResultLimited {
+		return future, nil
+	}
+	if md := rateLimitMetadata(resp); len(md) > 0 {
+		if err := setHeader(md); err != nil {
+			s.logger.Warnf("[gRPC][Server] fail to set rate limit header of %s, err: %v", fullMethod, err)
+		}
+	}
+	message := resp.GetActiveRule().GetCustomResponse().GetBody()
+	if len(message) == 0 {
+		message = fmt.Sprintf("polaris: %s of %s is rate limited", fullMethod, s.service)
+	}
+	return nil, status.Error(codes.ResourceExhausted, message)
+}
+
+// callerService 从 incoming metadata 中识别主调服务，未配置或请求未携带时返回 nil
+func (s *ServerInterceptor) callerService(md metadata.MD) *model.ServiceInfo {
+	if len(s.opts.callerServiceKey) == 0 {
+		return nil
+	}
+	values := md.Get(s.opts.callerServiceKey)
+	if len(values) == 0 || len(values[0]) == 0 {
+		return nil
+	}
+	namespace := DefaultNamespace
+	if len(s.opts.callerNamespaceKey) > 0 {
+		if namespaces := md.Get(s.opts.callerNamespaceKey); len(namespaces) > 0 && len(namespaces[0]) > 0 {
+			namespace = namespaces[0]
+		}
+	}
+	return &model.ServiceInfo{Namespace: namespace, Service: values[0]}
+}
+
+// callerIP 获取主调 IP，开启 WithTrustForwardedFor 时优先使用 x-forwarded-for 中的第一个地址
+func (s *ServerInterceptor) callerIP(ctx context.Context, md metadata.MD) string {
+	if s.opts.trustForwardedFor {
+		if values := md.Get(MetadataForwardedFor); len(values) > 0 && len(values[0]) > 0 {
+			forwarded := values[0]
+			if index := strings.IndexByte(forwarded, ','); index >= 0 {
+				forwarded = forwarded[:index]
+			}
+			return strings.TrimSpace(forwarded)
+		}
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// rateLimitMetadata 根据命中规则中周期最短的阈值构造 ratelimit-* 与 retry-after 响应 header，
+// 限流桶按周期重置配额，因此以周期长度作为重试等待时间的上界；并发数限流规则没有周期，不写入重置时间
+func rateLimitMetadata(resp *model.QuotaResponse) metadata.MD {
+	maxAmount, duration, ok := resp.GetActiveRuleAmount()
+	if !ok {
+		return nil
+	}
+	md := metadata.Pairs(
+		MetadataRateLimitLimit, strconv.FormatUint(uint64(maxAmount), 10),
+		MetadataRateLimitRemaining, "0")
+	if duration > 0 {
+		reset := strconv.FormatInt(int64((duration+time.Second-1)/time.Second), 10)
+		md.Set(MetadataRateLimitReset, reset)
+		md.Set(MetadataRetryAfter, reset)
+	}
+	return md
+}
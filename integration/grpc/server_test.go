@@ -0,0 +1,227 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// fakeLimit 返回固定结果的限流实现，并记录请求与配额释放次数
+type fakeLimit struct {
+	mutex    sync.Mutex
+	resp     *model.QuotaResponse
+	requests []*model.QuotaRequestImpl
+	released int
+}
+
+func (f *fakeLimit) GetQuota(request polaris.QuotaRequest) (polaris.QuotaFuture, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.requests = append(f.requests, request.(*model.QuotaRequestImpl))
+	resp := *f.resp
+	if resp.Code == model.QuotaResultOk {
+		resp.AddRelease(func() {
+			f.mutex.Lock()
+			defer f.mutex.Unlock()
+			f.released++
+		})
+	}
+	return model.QuotaFutureCompleted(&resp), nil
+}
+
+func (f *fakeLimit) getReleased() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.released
+}
+
+// fakeAuth 返回固定结果的鉴权实现，并记录请求
+type fakeAuth struct {
+	mutex    sync.Mutex
+	resp     *model.AuthenticateResponse
+	requests []*polaris.AuthenticateRequest
+}
+
+func (f *fakeAuth) Authenticate(request *polaris.AuthenticateRequest) (*model.AuthenticateResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.requests = append(f.requests, request)
+	return f.resp, nil
+}
+
+// startInterceptedServer 启动注册了拦截器的健康检查服务，返回客户端连接与清理函数
+func startInterceptedServer(t *testing.T, interceptor *ServerInterceptor) (*grpc.ClientConn, func()) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(interceptor.StreamServerInterceptor()))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(ln)
+	}()
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		server.Stop()
+		t.Fatal(err)
+	}
+	return conn, func() {
+		_ = conn.Close()
+		server.Stop()
+	}
+}
+
+func newTestInterceptor(limit *fakeLimit, auth *fakeAuth, opts ...ServerOption) *ServerInterceptor {
+	return &ServerInterceptor{
+		service: model.ServiceKey{Namespace: "test-ns", Service: "test-svc"},
+		limit:   limit,
+		auth:    auth,
+		opts:    newServerOptions(opts),
+		logger:  &noopLogger{},
+	}
+}
+
+// TestServerInterceptorPass 测试鉴权与限流通过时的参数构造以及并发数配额释放
+func TestServerInterceptorPass(t *testing.T) {
+	limit := &fakeLimit{resp: &model.QuotaResponse{Code: model.QuotaResultOk}}
+	auth := &fakeAuth{resp: &model.AuthenticateResponse{Code: model.AuthResultOk}}
+	conn, cleanup := startInterceptedServer(t, newTestInterceptor(limit, auth,
+		WithCallerServiceMetadata("caller-namespace", "caller-service"), WithCallerIP(), WithMetadataKeys("Env")))
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	callCtx := metadata.AppendToOutgoingContext(ctx, "env", "gray", "authorization", "Bearer secret",
+		"caller-namespace", "caller-ns", "caller-service", "caller")
+	if _, err := healthpb.NewHealthClient(conn).Check(callCtx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if limit.getReleased() != 1 {
+		t.Fatalf("expect quota released once, actual %d", limit.getReleased())
+	}
+	if len(limit.requests) != 1 || limit.requests[0].GetMethod() != "/grpc.health.v1.Health/Check" ||
+		limit.requests[0].GetService() != "test-svc" {
+		t.Fatalf("unexpected quota request %+v", limit.requests)
+	}
+	labels := map[string]string{}
+	for _, arg := range limit.requests[0].Arguments() {
+		arg.ToLabels(labels)
+	}
+	expectLabels := map[string]string{
+		model.LabelKeyMethod:                      "/grpc.health.v1.Health/Check",
+		model.LabelKeyHeader + "env":              "gray",
+		model.LabelKeyCallerIP:                    "127.0.0.1",
+		model.LabelKeyCallerService + "caller-ns": "caller",
+	}
+	for key, value := range expectLabels {
+		if labels[key] != value {
+			t.Fatalf("expect argument %s=%s, actual %v", key, value, labels)
+		}
+	}
+	// 未加入白名单的 metadata（包括 authorization 凭据）不会进入限流参数
+	if len(labels) != len(expectLabels) {
+		t.Fatalf("expect arguments %v, actual %v", expectLabels, labels)
+	}
+	if len(auth.requests) != 1 || auth.requests[0].Protocol != Protocol ||
+		auth.requests[0].SourceService == nil || auth.requests[0].SourceService.Namespace != "caller-ns" {
+		t.Fatalf("unexpected auth request %+v", auth.requests)
+	}
+}
+
+// TestServerInterceptorRateLimited 测试一元与流式 RPC 被限流时返回 ResourceExhausted 及限流响应 header
+func TestServerInterceptorRateLimited(t *testing.T) {
+	limit := &fakeLimit{resp: &model.QuotaResponse{
+		Code: model.QuotaResultLimited,
+		ActiveRule: &apitraffic.Rule{
+			Amounts: []*apitraffic.Amount{
+				{MaxAmount: wrapperspb.UInt32(5), ValidDuration: durationpb.New(time.Second)},
+			},
+		},
+	}}
+	conn, cleanup := startInterceptedServer(t, newTestInterceptor(limit, nil, WithoutAuth()))
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := healthpb.NewHealthClient(conn)
+	var header metadata.MD
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expect ResourceExhausted, actual %v", err)
+	}
+	expectHeaders := map[string]string{
+		MetadataRateLimitLimit:     "5",
+		MetadataRateLimitRemaining: "0",
+		MetadataRateLimitReset:     "1",
+		MetadataRetryAfter:         "1",
+	}
+	for key, value := range expectHeaders {
+		if values := header.Get(key); len(values) != 1 || values[0] != value {
+			t.Fatalf("expect header %s=%s, actual %v", key, value, header)
+		}
+	}
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expect ResourceExhausted on stream, actual %v", err)
+	}
+	if limit.getReleased() != 0 {
+		t.Fatalf("limited rpc should not release quota, actual %d", limit.getReleased())
+	}
+}
+
+// TestServerInterceptorForbidden 测试鉴权拒绝时返回 PermissionDenied 且不再获取配额
+func TestServerInterceptorForbidden(t *testing.T) {
+	limit := &fakeLimit{resp: &model.QuotaResponse{Code: model.QuotaResultOk}}
+	auth := &fakeAuth{resp: &model.AuthenticateResponse{Code: model.AuthResultForbidden, Info: "in blocklist"}}
+	conn, cleanup := startInterceptedServer(t, newTestInterceptor(limit, auth))
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.PermissionDenied || status.Convert(err).Message() != "in blocklist" {
+		t.Fatalf("expect PermissionDenied, actual %v", err)
+	}
+	if len(limit.requests) != 0 {
+		t.Fatalf("forbidden rpc should not acquire quota, actual %d", len(limit.requests))
+	}
+}
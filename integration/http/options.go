@@ -0,0 +1,188 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// options HTTP 集成的可选配置
+type options struct {
+	// 主调服务信息，用于匹配路由规则、熔断资源以及上报调用结果
+	sourceService model.ServiceInfo
+	// 负载均衡策略，为空时使用SDK配置的负载均衡插件
+	lbPolicy string
+	// 一致性哈希负载均衡使用的请求头
+	hashKeyHeader string
+	// 实际发起请求的底层 RoundTripper，为空时使用 http.DefaultTransport
+	base http.RoundTripper
+}
+
+// Option HTTP 集成的可选配置项
+type Option func(*options)
+
+// WithSourceService 设置主调服务，用于匹配路由规则、熔断资源以及上报调用结果
+func WithSourceService(namespace, service string) Option {
+	return func(o *options) {
+		o.sourceService.Namespace = namespace
+		o.sourceService.Service = service
+	}
+}
+
+// WithSourceMetadata 设置主调服务的静态标签，参与路由规则的源标签匹配
+func WithSourceMetadata(metadata map[string]string) Option {
+	return func(o *options) {
+		o.sourceService.Metadata = metadata
+	}
+}
+
+// WithLbPolicy 设置负载均衡策略，取值为已注册的负载均衡插件名
+func WithLbPolicy(lbPolicy string) Option {
+	return func(o *options) {
+		o.lbPolicy = lbPolicy
+	}
+}
+
+// WithHashKeyHeader 设置一致性哈希负载均衡使用的请求头
+func WithHashKeyHeader(header string) Option {
+	return func(o *options) {
+		o.hashKeyHeader = header
+	}
+}
+
+// WithBaseTransport 设置实际发起请求的底层 RoundTripper，可用于定制连接池、TLS 等
+func WithBaseTransport(base http.RoundTripper) Option {
+	return func(o *options) {
+		o.base = base
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.base == nil {
+		o.base = http.DefaultTransport
+	}
+	return o
+}
+
+// serverOptions HTTP 服务端中间件的可选配置
+type serverOptions struct {
+	// 关闭限流检查
+	disableRateLimit bool
+	// 关闭鉴权检查
+	disableAuth bool
+	// 携带主调服务命名空间的请求头，为空时不识别主调服务
+	callerNamespaceHeader string
+	// 携带主调服务名的请求头，为空时不识别主调服务
+	callerServiceHeader string
+	// 是否信任 X-Forwarded-For 请求头中的主调 IP
+	trustForwardedFor bool
+	// 是否将主调 IP 作为规则匹配参数
+	callerIP bool
+	// 参与规则匹配的请求头，key 为规范化后的请求头名
+	headerKeys map[string]struct{}
+	// 参与规则匹配的 query 参数
+	queryKeys map[string]struct{}
+	// 参与规则匹配的 cookie
+	cookieKeys map[string]struct{}
+}
+
+// ServerOption HTTP 服务端中间件的可选配置项
+type ServerOption func(*serverOptions)
+
+// WithoutRateLimit 关闭服务端中间件的限流检查
+func WithoutRateLimit() ServerOption {
+	return func(o *serverOptions) {
+		o.disableRateLimit = true
+	}
+}
+
+// WithoutAuth 关闭服务端中间件的鉴权检查
+func WithoutAuth() ServerOption {
+	return func(o *serverOptions) {
+		o.disableAuth = true
+	}
+}
+
+// WithCallerServiceHeaders 设置携带主调服务命名空间与服务名的请求头，用于匹配限流与鉴权规则中的主调服务
+func WithCallerServiceHeaders(namespaceHeader, serviceHeader string) ServerOption {
+	return func(o *serverOptions) {
+		o.callerNamespaceHeader = namespaceHeader
+		o.callerServiceHeader = serviceHeader
+	}
+}
+
+// WithTrustForwardedFor 使用 X-Forwarded-For 请求头中的第一个地址作为主调 IP，仅应在可信代理之后开启，
+// 需同时通过 WithCallerIP 开启主调 IP 参数
+func WithTrustForwardedFor() ServerOption {
+	return func(o *serverOptions) {
+		o.trustForwardedFor = true
+	}
+}
+
+// WithCallerIP 将主调 IP 作为规则匹配参数，主调 IP 取值较多，仅应在规则需要按主调 IP 匹配时开启
+func WithCallerIP() ServerOption {
+	return func(o *serverOptions) {
+		o.callerIP = true
+	}
+}
+
+// WithHeaderKeys 设置参与规则匹配的请求头，默认不传递任何请求头；
+// 参数会随限流指标上报，不应包含 Authorization 等携带凭据的请求头
+func WithHeaderKeys(keys ...string) ServerOption {
+	return func(o *serverOptions) {
+		for _, key := range keys {
+			o.headerKeys[http.CanonicalHeaderKey(key)] = struct{}{}
+		}
+	}
+}
+
+// WithQueryKeys 设置参与规则匹配的 query 参数，默认不传递任何 query 参数
+func WithQueryKeys(keys ...string) ServerOption {
+	return func(o *serverOptions) {
+		for _, key := range keys {
+			o.queryKeys[key] = struct{}{}
+		}
+	}
+}
+
+// WithCookieKeys 设置参与规则匹配的 cookie，默认不传递任何 cookie；不应包含会话凭据等敏感 cookie
+func WithCookieKeys(keys ...string) ServerOption {
+	return func(o *serverOptions) {
+		for _, key := range keys {
+			o.cookieKeys[key] = struct{}{}
+		}
+	}
+}
+
+func newServerOptions(opts []ServerOption) *serverOptions {
+	o := &serverOptions{
+		headerKeys: map[string]struct{}{},
+		queryKeys:  map[string]struct{}{},
+		cookieKeys: map[string]struct{}{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,253 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package http
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// HeaderRetryAfter 被限流时建议客户端重试的等待秒数
+	HeaderRetryAfter = "Retry-After"
+	// HeaderRateLimitLimit 命中限流规则的阈值
+	HeaderRateLimitLimit = "RateLimit-Limit"
+	// HeaderRateLimitRemaining 当前周期剩余的配额，被限流时为 0
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	// HeaderRateLimitReset 距配额重置的秒数
+	HeaderRateLimitReset = "RateLimit-Reset"
+	// HeaderForwardedFor 代理透传的主调地址请求头
+	HeaderForwardedFor = "X-Forwarded-For"
+)
+
+// limitAPI 服务端中间件依赖的限流能力
+type limitAPI interface {
+	GetQuota(request polaris.QuotaRequest) (polaris.QuotaFuture, error)
+}
+
+// authAPI 服务端中间件依赖的鉴权能力
+type authAPI interface {
+	Authenticate(request *polaris.AuthenticateRequest) (*model.AuthenticateResponse, error)
+}
+
+// Middleware 对入站请求执行 polaris 鉴权与限流的 net/http 服务端中间件。
+// 请求的 HTTP 方法、路径与主调服务作为参数参与规则匹配，请求头、query 参数、cookie 与主调 IP
+// 仅在通过 WithHeaderKeys、WithQueryKeys、WithCookieKeys、WithCallerIP 显式开启后传递，
+// 避免凭据与高基数取值随限流指标上报；
+// 限流接口以请求路径作为方法名；鉴权拒绝返回 403，被限流返回 429 并携带 RateLimit-* 与 Retry-After 响应头。
+// SDK 调用失败时放通请求，避免治理能力故障影响业务。
+//
+//	sdkCtx, _ := polaris.NewSDKContext()
+//	handler := polarishttp.NewMiddleware(sdkCtx, "default", "echo").Handler(mux)
+//	http.ListenAndServe(":8080", handler)
+type Middleware struct {
+	service model.ServiceKey
+	limit   limitAPI
+	auth    authAPI
+	opts    *serverOptions
+	logger  log.Logger
+}
+
+// NewMiddleware 基于SDK上下文创建被调服务 namespace/service 的服务端中间件
+func NewMiddleware(sdkCtx api.SDKContext, namespace, service string, opts ...ServerOption) *Middleware {
+	return &Middleware{
+		service: model.ServiceKey{Namespace: namespace, Service: service},
+		limit:   polaris.NewLimitAPIByContext(sdkCtx),
+		auth:    polaris.NewAuthAPIByContext(sdkCtx),
+		opts:    newServerOptions(opts),
+		logger:  sdkCtx.GetValueContext().GetContextLogger().GetBaseLogger(),
+	}
+}
+
+// Handler 包装 next，鉴权与限流通过后才调用 next，并在 next 返回后释放并发数限流的配额
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		caller := m.callerService(req)
+		arguments := m.buildArguments(req, caller)
+		if !m.authenticate(w, req, caller, arguments) {
+			return
+		}
+		future, ok := m.acquireQuota(w, req, arguments)
+		if !ok {
+			return
+		}
+		if future != nil {
+			defer future.Release()
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// authenticate 执行鉴权，拒绝时写入 403 响应并返回 false
+func (m *Middleware) authenticate(w http.ResponseWriter, req *http.Request,
+	caller *model.ServiceInfo, arguments []model.Argument) bool {
+	if m.opts.disableAuth {
+		return true
+	}
+	authReq := polaris.NewAuthenticateRequest()
+	authReq.Namespace = m.service.Namespace
+	authReq.Service = m.service.Service
+	authReq.Method = req.Method
+	authReq.Path = req.URL.Path
+	authReq.Protocol = Protocol
+	authReq.SourceService = caller
+	authReq.Arguments = arguments
+	resp, err := m.auth.Authenticate(authReq)
+	if err != nil {
+		m.logger.Warnf("[HTTP][Server] fail to authenticate %s %s of %s, pass through, err: %v",
+			req.Method, req.URL.Path, m.service, err)
+		return true
+	}
+	if resp.IsAllowed() {
+		return true
+	}
+	body := resp.GetInfo()
+	if len(body) == 0 {
+		body = http.StatusText(http.StatusForbidden)
+	}
+	http.Error(w, body, http.StatusForbidden)
+	return false
+}
+
+// acquireQuota 获取配额并等待匀速排队结束，被限流时写入 429 响应并返回 false；
+// SDK 调用失败时返回 nil future 并放通
+func (m *Middleware) acquireQuota(w http.ResponseWriter, req *http.Request,
+	arguments []model.Argument) (polaris.QuotaFuture, bool) {
+	if m.opts.disableRateLimit {
+		return nil, true
+	}
+	quotaReq := polaris.NewQuotaRequest()
+	quotaReq.SetNamespace(m.service.Namespace)
+	quotaReq.SetService(m.service.Service)
+	quotaReq.SetMethod(req.URL.Path)
+	quotaReq.SetContext(req.Context())
+	for _, argument := range arguments {
+		quotaReq.AddArgument(argument)
+	}
+	future, err := m.limit.GetQuota(quotaReq)
+	if err != nil {
+		m.logger.Warnf("[HTTP][Server] fail to get quota for %s %s of %s, pass through, err: %v",
+			req.Method, req.URL.Path, m.service, err)
+		return nil, true
+	}
+	resp := future.Get()
+	if resp == nil || resp.Code != model.QuotaResultLimited {
+		return future, true
+	}
+	writeRateLimitHeaders(w.Header(), resp)
+	body := resp.GetActiveRule().GetCustomResponse().GetBody()
+	if len(body) == 0 {
+		body = http.StatusText(http.StatusTooManyRequests)
+	}
+	http.Error(w, body, http.StatusTooManyRequests)
+	return nil, false
+}
+
+// buildArguments 构造参与规则匹配的参数，请求头、query 参数与 cookie 仅传递白名单中的 key，多值时取第一个值
+func (m *Middleware) buildArguments(req *http.Request, caller *model.ServiceInfo) []model.Argument {
+	arguments := []model.Argument{
+		model.BuildMethodArgument(req.Method),
+		model.BuildPathArgument(req.URL.Path),
+	}
+	for key, values := range req.Header {
+		if _, ok := m.opts.headerKeys[key]; ok && len(values) > 0 {
+			arguments = append(arguments, model.BuildHeaderArgument(strings.ToLower(key), values[0]))
+		}
+	}
+	if len(m.opts.queryKeys) > 0 {
+		for key, values := range req.URL.Query() {
+			if _, ok := m.opts.queryKeys[key]; ok && len(values) > 0 {
+				arguments = append(arguments, model.BuildQueryArgument(key, values[0]))
+			}
+		}
+	}
+	if len(m.opts.cookieKeys) > 0 {
+		for _, cookie := range req.Cookies() {
+			if _, ok := m.opts.cookieKeys[cookie.Name]; ok {
+				arguments = append(arguments, model.BuildCookieArgument(cookie.Name, cookie.Value))
+			}
+		}
+	}
+	if m.opts.callerIP {
+		arguments = append(arguments, model.BuildCallerIPArgument(m.callerIP(req)))
+	}
+	if caller != nil {
+		arguments = append(arguments, model.BuildCallerServiceArgument(caller.Namespace, caller.Service))
+	}
+	return arguments
+}
+
+// callerService 从请求头中识别主调服务，未配置或请求未携带时返回 nil
+func (m *Middleware) callerService(req *http.Request) *model.ServiceInfo {
+	if len(m.opts.callerServiceHeader) == 0 {
+		return nil
+	}
+	service := req.Header.Get(m.opts.callerServiceHeader)
+	if len(service) == 0 {
+		return nil
+	}
+	namespace := DefaultNamespace
+	if len(m.opts.callerNamespaceHeader) > 0 {
+		if value := req.Header.Get(m.opts.callerNamespaceHeader); len(value) > 0 {
+			namespace = value
+		}
+	}
+	return &model.ServiceInfo{Namespace: namespace, Service: service}
+}
+
+// callerIP 获取主调 IP，开启 WithTrustForwardedFor 时优先使用 X-Forwarded-For 中的第一个地址
+func (m *Middleware) callerIP(req *http.Request) string {
+	if m.opts.trustForwardedFor {
+		if forwarded := req.Header.Get(HeaderForwardedFor); len(forwarded) > 0 {
+			if index := strings.IndexByte(forwarded, ','); index >= 0 {
+				forwarded = forwarded[:index]
+			}
+			return strings.TrimSpace(forwarded)
+		}
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// writeRateLimitHeaders 根据命中规则中周期最短的阈值写入 RateLimit-* 与 Retry-After 响应头，
+// 限流桶按周期重置配额，因此以周期长度作为重试等待时间的上界；并发数限流规则没有周期，不写入重置时间
+func writeRateLimitHeaders(header http.Header, resp *model.QuotaResponse) {
+	maxAmount, duration, ok := resp.GetActiveRuleAmount()
+	if !ok {
+		return
+	}
+	header.Set(HeaderRateLimitLimit, strconv.FormatUint(uint64(maxAmount), 10))
+	header.Set(HeaderRateLimitRemaining, "0")
+	if duration <= 0 {
+		return
+	}
+	reset := strconv.FormatInt(int64((duration+time.Second-1)/time.Second), 10)
+	header.Set(HeaderRateLimitReset, reset)
+	header.Set(HeaderRetryAfter, reset)
+}
@@ -0,0 +1,281 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package http
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// fakeLimit 返回固定结果的限流实现，并记录请求与配额释放次数
+type fakeLimit struct {
+	mutex    sync.Mutex
+	resp     *model.QuotaResponse
+	err      error
+	requests []*model.QuotaRequestImpl
+	released int
+}
+
+func (f *fakeLimit) GetQuota(request polaris.QuotaRequest) (polaris.QuotaFuture, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.requests = append(f.requests, request.(*model.QuotaRequestImpl))
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := *f.resp
+	if resp.Code == model.QuotaResultOk {
+		resp.AddRelease(func() {
+			f.mutex.Lock()
+			defer f.mutex.Unlock()
+			f.released++
+		})
+	}
+	return model.QuotaFutureCompleted(&resp), nil
+}
+
+// fakeAuth 返回固定结果的鉴权实现，并记录请求
+type fakeAuth struct {
+	resp     *model.AuthenticateResponse
+	err      error
+	requests []*polaris.AuthenticateRequest
+}
+
+func (f *fakeAuth) Authenticate(request *polaris.AuthenticateRequest) (*model.AuthenticateResponse, error) {
+	f.requests = append(f.requests, request)
+	return f.resp, f.err
+}
+
+func newTestMiddleware(limit *fakeLimit, auth *fakeAuth, opts ...ServerOption) *Middleware {
+	return &Middleware{
+		service: model.ServiceKey{Namespace: "test-ns", Service: "test.svc"},
+		limit:   limit,
+		auth:    auth,
+		opts:    newServerOptions(opts),
+		logger:  &noopLogger{},
+	}
+}
+
+func argumentLabels(arguments []model.Argument) map[string]string {
+	labels := map[string]string{}
+	for _, arg := range arguments {
+		arg.ToLabels(labels)
+	}
+	return labels
+}
+
+// TestMiddlewarePass 测试鉴权与限流通过时的参数构造以及并发数配额释放
+func TestMiddlewarePass(t *testing.T) {
+	limit := &fakeLimit{resp: &model.QuotaResponse{Code: model.QuotaResultOk}}
+	auth := &fakeAuth{resp: &model.AuthenticateResponse{Code: model.AuthResultOk}}
+	middleware := newTestMiddleware(limit, auth,
+		WithCallerServiceHeaders("X-Caller-Namespace", "X-Caller-Service"), WithTrustForwardedFor(), WithCallerIP(),
+		WithHeaderKeys("x-caller-service"), WithQueryKeys("user"))
+	var releasedInHandler int
+	handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		releasedInHandler = limit.released
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://test.svc/echo?user=polaris", nil)
+	req.Header.Set("X-Caller-Namespace", "caller-ns")
+	req.Header.Set("X-Caller-Service", "caller")
+	req.Header.Set(HeaderForwardedFor, "10.0.0.1, 10.0.0.2")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "ok" {
+		t.Fatalf("unexpected response %d %q", recorder.Code, recorder.Body.String())
+	}
+	if releasedInHandler != 0 || limit.released != 1 {
+		t.Fatalf("quota should be released after handler, got %d/%d", releasedInHandler, limit.released)
+	}
+	if len(limit.requests) != 1 || limit.requests[0].GetNamespace() != "test-ns" ||
+		limit.requests[0].GetService() != "test.svc" || limit.requests[0].GetMethod() != "/echo" {
+		t.Fatalf("unexpected quota request %+v", limit.requests)
+	}
+	expectLabels := map[string]string{
+		model.LabelKeyMethod:                      http.MethodPost,
+		model.LabelKeyPath:                        "/echo",
+		model.LabelKeyQuery + "user":              "polaris",
+		model.LabelKeyHeader + "x-caller-service": "caller",
+		model.LabelKeyCallerIP:                    "10.0.0.1",
+		model.LabelKeyCallerService + "caller-ns": "caller",
+	}
+	labels := argumentLabels(limit.requests[0].Arguments())
+	for key, value := range expectLabels {
+		if labels[key] != value {
+			t.Fatalf("expect argument %s=%s, got %v", key, value, labels)
+		}
+	}
+	if len(auth.requests) != 1 {
+		t.Fatalf("expect 1 auth request, got %d", len(auth.requests))
+	}
+	authReq := auth.requests[0]
+	if authReq.Method != http.MethodPost || authReq.Path != "/echo" || authReq.Protocol != Protocol ||
+		authReq.SourceService == nil || authReq.SourceService.Service != "caller" {
+		t.Fatalf("unexpected auth request %+v", authReq)
+	}
+}
+
+// TestMiddlewareArgumentsAllowList 测试服务端中间件只传递白名单中的请求头、query 参数与 cookie
+func TestMiddlewareArgumentsAllowList(t *testing.T) {
+	// 前置条件：请求携带 Authorization、Cookie 等凭据以及未加入白名单的 query 参数
+	// 预期结果：默认只传递方法与路径；开启白名单后只传递白名单中的 key，凭据始终不会进入限流参数
+	req := httptest.NewRequest(http.MethodGet, "http://test.svc/echo?token=secret&user=polaris", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Env", "gray")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "secret"})
+	req.AddCookie(&http.Cookie{Name: "region", Value: "gz"})
+
+	cases := []struct {
+		opts   []ServerOption
+		expect map[string]string
+	}{
+		{
+			expect: map[string]string{model.LabelKeyMethod: http.MethodGet, model.LabelKeyPath: "/echo"},
+		},
+		{
+			opts: []ServerOption{WithHeaderKeys("x-env"), WithQueryKeys("user"), WithCookieKeys("region"), WithCallerIP()},
+			expect: map[string]string{
+				model.LabelKeyMethod:            http.MethodGet,
+				model.LabelKeyPath:              "/echo",
+				model.LabelKeyHeader + "x-env":  "gray",
+				model.LabelKeyQuery + "user":    "polaris",
+				model.LabelKeyCookie + "region": "gz",
+				model.LabelKeyCallerIP:          "192.0.2.1",
+			},
+		},
+	}
+	for _, c := range cases {
+		limit := &fakeLimit{resp: &model.QuotaResponse{Code: model.QuotaResultOk}}
+		middleware := newTestMiddleware(limit, &fakeAuth{}, append(c.opts, WithoutAuth())...)
+		middleware.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).
+			ServeHTTP(httptest.NewRecorder(), req)
+		if len(limit.requests) != 1 {
+			t.Fatalf("expect 1 quota request, got %d", len(limit.requests))
+		}
+		labels := argumentLabels(limit.requests[0].Arguments())
+		if len(labels) != len(c.expect) {
+			t.Fatalf("expect arguments %v, got %v", c.expect, labels)
+		}
+		for key, value := range c.expect {
+			if labels[key] != value {
+				t.Fatalf("expect argument %s=%s, got %v", key, value, labels)
+			}
+		}
+		for key, value := range labels {
+			if strings.Contains(value, "secret") {
+				t.Fatalf("credential leaked into argument %s=%s", key, value)
+			}
+		}
+	}
+}
+
+// TestMiddlewareRateLimited 测试被限流时返回 429、限流响应头与规则自定义返回内容
+func TestMiddlewareRateLimited(t *testing.T) {
+	limit := &fakeLimit{resp: &model.QuotaResponse{
+		Code: model.QuotaResultLimited,
+		ActiveRule: &apitraffic.Rule{
+			Amounts: []*apitraffic.Amount{
+				{MaxAmount: wrapperspb.UInt32(10), ValidDuration: durationpb.New(1500 * time.Millisecond)},
+			},
+			CustomResponse: &apitraffic.CustomResponse{Body: "slow down"},
+		},
+	}}
+	middleware := newTestMiddleware(limit, nil, WithoutAuth())
+	var hits int
+	handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://test.svc/echo", nil))
+
+	if hits != 0 || recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("expect rejected with 429, got %d, hits %d", recorder.Code, hits)
+	}
+	expectHeaders := map[string]string{
+		HeaderRateLimitLimit:     "10",
+		HeaderRateLimitRemaining: "0",
+		HeaderRateLimitReset:     "2",
+		HeaderRetryAfter:         "2",
+	}
+	for key, value := range expectHeaders {
+		if recorder.Header().Get(key) != value {
+			t.Fatalf("expect header %s=%s, got %v", key, value, recorder.Header())
+		}
+	}
+	body, _ := ioutil.ReadAll(recorder.Body)
+	if strings.TrimSpace(string(body)) != "slow down" {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+// TestMiddlewareAuthForbidden 测试鉴权拒绝时返回 403 且不再获取配额
+func TestMiddlewareAuthForbidden(t *testing.T) {
+	limit := &fakeLimit{resp: &model.QuotaResponse{Code: model.QuotaResultOk}}
+	auth := &fakeAuth{resp: &model.AuthenticateResponse{Code: model.AuthResultForbidden, Info: "in blocklist"}}
+	handler := newTestMiddleware(limit, auth, WithCallerIP()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called")
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://test.svc/echo", nil))
+
+	if recorder.Code != http.StatusForbidden || strings.TrimSpace(recorder.Body.String()) != "in blocklist" {
+		t.Fatalf("unexpected response %d %q", recorder.Code, recorder.Body.String())
+	}
+	if len(limit.requests) != 0 {
+		t.Fatalf("forbidden request should not acquire quota, got %d", len(limit.requests))
+	}
+}
+
+// TestMiddlewareFailOpen 测试 SDK 调用失败时放通请求
+func TestMiddlewareFailOpen(t *testing.T) {
+	limit := &fakeLimit{err: errors.New("limit unavailable")}
+	auth := &fakeAuth{err: errors.New("auth unavailable")}
+	var hits int
+	handler := newTestMiddleware(limit, auth, WithCallerIP()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://test.svc/echo", nil)
+	handler.ServeHTTP(recorder, req)
+
+	if hits != 1 || recorder.Code != http.StatusOK {
+		t.Fatalf("expect pass through, got %d, hits %d", recorder.Code, hits)
+	}
+	if labels := argumentLabels(limit.requests[0].Arguments()); labels[model.LabelKeyCallerIP] != "192.0.2.1" {
+		t.Fatalf("expect caller ip from remote addr, got %v", labels)
+	}
+}
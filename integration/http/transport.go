@@ -0,0 +1,336 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package http 提供 net/http 客户端的 polaris 服务发现、路由、负载均衡与熔断集成，以及服务端的鉴权与限流中间件。
+//
+// Transport 将 http://service.namespace/path 格式的请求地址解析为 polaris 服务，请求头、query 参数、
+// cookie、HTTP 方法与路径作为参数参与路由规则的源标签匹配；调用前基于接口级资源（协议、HTTP 方法、路径）
+// 进行熔断检查，调用后将状态码与时延上报给熔断及服务调用统计。
+//
+//	sdkCtx, _ := polaris.NewSDKContext()
+//	client := polarishttp.NewClient(sdkCtx, polarishttp.WithSourceService("default", "caller"))
+//	resp, err := client.Get("http://echo.default/echo?user=polaris")
+//
+// 服务端通过 Middleware 对入站请求执行鉴权与限流，见 NewMiddleware。
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// Protocol 接口级熔断资源使用的协议名
+	Protocol = "http"
+	// DefaultNamespace 请求地址未指定命名空间时使用的命名空间
+	DefaultNamespace = "default"
+	// RetCodeTransportError 请求未拿到响应（连接失败、超时等）时上报的返回码
+	RetCodeTransportError = -1
+)
+
+// consumerAPI HTTP 集成依赖的服务发现能力
+type consumerAPI interface {
+	GetAllInstances(req *polaris.GetAllInstancesRequest) (*model.InstancesResponse, error)
+	UpdateServiceCallResult(req *polaris.ServiceCallResult) error
+}
+
+// routerAPI HTTP 集成依赖的路由与负载均衡能力
+type routerAPI interface {
+	ProcessRouters(req *polaris.ProcessRoutersRequest) (*model.InstancesResponse, error)
+	ProcessLoadBalance(req *polaris.ProcessLoadBalanceRequest) (*model.OneInstanceResponse, error)
+}
+
+// circuitBreakerAPI HTTP 集成依赖的熔断能力
+type circuitBreakerAPI interface {
+	Check(res model.Resource) (*model.CheckResult, error)
+	Report(stat *model.ResourceStat) error
+}
+
+// CircuitBreakerError 接口被熔断且熔断规则未配置降级响应时，RoundTrip 返回的错误
+type CircuitBreakerError struct {
+	// Service 被调服务
+	Service model.ServiceKey
+	// Method HTTP 方法
+	Method string
+	// Path 接口路径
+	Path string
+	// RuleName 触发熔断的规则名
+	RuleName string
+}
+
+// Error 实现 error 接口
+func (e *CircuitBreakerError) Error() string {
+	return fmt.Sprintf("polaris: %s %s of %s is blocked by circuit breaker rule %s",
+		e.Method, e.Path, e.Service, e.RuleName)
+}
+
+// Is 使 errors.Is(err, model.ErrorCallAborted) 成立，与熔断装饰器的判断方式保持一致
+func (e *CircuitBreakerError) Is(target error) bool {
+	return target == model.ErrorCallAborted
+}
+
+// Transport 执行 polaris 服务发现、路由、负载均衡与熔断的 http.RoundTripper
+type Transport struct {
+	consumer       consumerAPI
+	router         routerAPI
+	circuitBreaker circuitBreakerAPI
+	opts           *options
+	logger         log.Logger
+}
+
+// NewTransport 基于SDK上下文创建 Transport
+func NewTransport(sdkCtx api.SDKContext, opts ...Option) *Transport {
+	return &Transport{
+		consumer:       polaris.NewConsumerAPIByContext(sdkCtx),
+		router:         polaris.NewRouterAPIByContext(sdkCtx),
+		circuitBreaker: polaris.NewCircuitBreakerAPIByContext(sdkCtx),
+		opts:           newOptions(opts),
+		logger:         sdkCtx.GetValueContext().GetContextLogger().GetBaseLogger(),
+	}
+}
+
+// NewClient 基于SDK上下文创建使用 Transport 的 http.Client
+func NewClient(sdkCtx api.SDKContext, opts ...Option) *http.Client {
+	return &http.Client{Transport: NewTransport(sdkCtx, opts...)}
+}
+
+// RoundTrip 选择服务实例并发起请求，实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	svcKey := parseHost(req.URL.Hostname())
+	path := req.URL.Path
+	if len(path) == 0 {
+		path = "/"
+	}
+	var caller *model.ServiceKey
+	if len(t.opts.sourceService.Service) > 0 {
+		caller = &model.ServiceKey{
+			Namespace: t.opts.sourceService.Namespace,
+			Service:   t.opts.sourceService.Service,
+		}
+	}
+	resource, err := model.NewMethodResourceWithAPI(&svcKey, caller, Protocol, req.Method, path)
+	if err != nil {
+		closeRequestBody(req)
+		return nil, err
+	}
+	if resp, err := t.checkCircuitBreaker(req, resource); resp != nil || err != nil {
+		closeRequestBody(req)
+		return resp, err
+	}
+
+	instance, err := t.chooseInstance(req, svcKey, path)
+	if err != nil {
+		closeRequestBody(req)
+		return nil, err
+	}
+	outReq := req.Clone(req.Context())
+	outReq.URL.Host = net.JoinHostPort(instance.GetHost(), strconv.Itoa(int(instance.GetPort())))
+	outReq.Host = ""
+
+	startTime := time.Now()
+	resp, err := t.opts.base.RoundTrip(outReq)
+	delay := time.Since(startTime)
+	retCode := RetCodeTransportError
+	if err == nil {
+		retCode = resp.StatusCode
+	}
+	t.report(instance, resource, path, retCode, toRetStatus(retCode, err), delay)
+	return resp, err
+}
+
+// closeRequestBody 请求未交给底层 Transport 时关闭请求体，遵循 http.RoundTripper 的约定
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
+
+// checkCircuitBreaker 调用前检查接口级熔断，被熔断时返回降级响应或 CircuitBreakerError；
+// 熔断检查本身出错时放通请求，避免熔断能力异常影响业务调用
+func (t *Transport) checkCircuitBreaker(
+	req *http.Request, resource *model.MethodResource) (*http.Response, error) {
+	result, err := t.circuitBreaker.Check(resource)
+	if err != nil {
+		t.logger.Warnf("[HTTP][Transport] fail to check circuit breaker of %s %s, err: %v",
+			resource.GetService(), resource.Path, err)
+		return nil, nil
+	}
+	if result == nil || result.Pass {
+		return nil, nil
+	}
+	if result.FallbackInfo != nil {
+		return buildFallbackResponse(req, result.FallbackInfo), nil
+	}
+	return nil, &CircuitBreakerError{
+		Service:  *resource.GetService(),
+		Method:   req.Method,
+		Path:     resource.Path,
+		RuleName: result.RuleName,
+	}
+}
+
+// chooseInstance 获取服务实例并执行路由与负载均衡
+func (t *Transport) chooseInstance(req *http.Request, svcKey model.ServiceKey, path string) (model.Instance, error) {
+	allReq := &polaris.GetAllInstancesRequest{}
+	allReq.Namespace = svcKey.Namespace
+	allReq.Service = svcKey.Service
+	allReq.SetContext(req.Context())
+	allResp, err := t.consumer.GetAllInstances(allReq)
+	if err != nil {
+		return nil, err
+	}
+
+	routeReq := &polaris.ProcessRoutersRequest{}
+	routeReq.DstInstances = allResp
+	routeReq.SourceService = t.opts.sourceService
+	routeReq.Method = path
+	routeReq.AddArguments(buildArguments(req, path)...)
+	routeResp, err := t.router.ProcessRouters(routeReq)
+	if err != nil {
+		return nil, err
+	}
+
+	lbReq := &polaris.ProcessLoadBalanceRequest{}
+	lbReq.DstInstances = routeResp
+	lbReq.LbPolicy = t.opts.lbPolicy
+	if len(t.opts.hashKeyHeader) > 0 {
+		if value := req.Header.Get(t.opts.hashKeyHeader); len(value) > 0 {
+			lbReq.HashKey = []byte(value)
+		}
+	}
+	lbResp, err := t.router.ProcessLoadBalance(lbReq)
+	if err != nil {
+		return nil, err
+	}
+	return lbResp.GetInstance(), nil
+}
+
+// report 上报调用结果，供熔断、动态权重以及服务调用统计使用
+func (t *Transport) report(instance model.Instance, resource *model.MethodResource, path string,
+	retCode int, retStatus model.RetStatus, delay time.Duration) {
+	result := &polaris.ServiceCallResult{}
+	result.SetCalledInstance(instance)
+	result.SetMethod(path)
+	result.SetDelay(delay)
+	result.SetRetCode(int32(retCode))
+	result.SetRetStatus(retStatus)
+	if len(t.opts.sourceService.Service) > 0 {
+		sourceService := t.opts.sourceService
+		result.SetCallerService(&sourceService)
+	}
+	if err := t.consumer.UpdateServiceCallResult(result); err != nil {
+		t.logger.Warnf("[HTTP][Transport] fail to report call result of %s, err: %v",
+			resource.GetService(), err)
+	}
+	stat := &model.ResourceStat{
+		Resource:  resource,
+		RetCode:   strconv.Itoa(retCode),
+		Delay:     delay,
+		RetStatus: retStatus,
+	}
+	if err := t.circuitBreaker.Report(stat); err != nil {
+		t.logger.Warnf("[HTTP][Transport] fail to report circuit breaker stat of %s %s, err: %v",
+			resource.GetService(), path, err)
+	}
+}
+
+// parseHost 从 service.namespace 格式的主机名中解析服务标识，服务名中可以包含 "."，
+// 因此以最后一个 "." 作为分隔，未包含 "." 时使用默认命名空间
+func parseHost(host string) model.ServiceKey {
+	index := strings.LastIndex(host, ".")
+	if index <= 0 || index == len(host)-1 {
+		return model.ServiceKey{Namespace: DefaultNamespace, Service: host}
+	}
+	return model.ServiceKey{Namespace: host[index+1:], Service: host[:index]}
+}
+
+// buildArguments 将请求的 HTTP 方法、路径、请求头、query 参数与 cookie 转换为路由参数，多值时取第一个值
+func buildArguments(req *http.Request, path string) []model.Argument {
+	arguments := []model.Argument{
+		model.BuildMethodArgument(req.Method),
+		model.BuildPathArgument(path),
+	}
+	for key, values := range req.Header {
+		if len(values) > 0 {
+			arguments = append(arguments, model.BuildHeaderArgument(strings.ToLower(key), values[0]))
+		}
+	}
+	for key, values := range req.URL.Query() {
+		if len(values) > 0 {
+			arguments = append(arguments, model.BuildQueryArgument(key, values[0]))
+		}
+	}
+	for _, cookie := range req.Cookies() {
+		arguments = append(arguments, model.BuildCookieArgument(cookie.Name, cookie.Value))
+	}
+	return arguments
+}
+
+// toRetStatus 将 HTTP 调用结果转换为调用结果状态：
+// 5xx 与连接类错误计为失败，超时计为超时，429 计为被限流，调用方主动取消的请求不计入失败，
+// 其余状态码（包括 4xx）说明被调实例正常处理了请求，计为成功
+func toRetStatus(statusCode int, err error) model.RetStatus {
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return model.RetUnknown
+		}
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return model.RetTimeout
+		}
+		return model.RetFail
+	}
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return model.RetFlowControl
+	case statusCode >= http.StatusInternalServerError:
+		return model.RetFail
+	default:
+		return model.RetSuccess
+	}
+}
+
+// buildFallbackResponse 根据熔断规则中的降级配置构造响应
+func buildFallbackResponse(req *http.Request, fallback *model.FallbackInfo) *http.Response {
+	header := make(http.Header, len(fallback.Headers))
+	for key, value := range fallback.Headers {
+		header.Set(key, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fallback.Code, http.StatusText(fallback.Code)),
+		StatusCode:    fallback.Code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(fallback.Body)),
+		ContentLength: int64(len(fallback.Body)),
+		Request:       req,
+	}
+}
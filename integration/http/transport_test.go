@@ -0,0 +1,372 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// noopLogger 空日志实现，用于测试
+type noopLogger struct{}
+
+func (n *noopLogger) Tracef(format string, args ...interface{}) {}
+func (n *noopLogger) Debugf(format string, args ...interface{}) {}
+func (n *noopLogger) Infof(format string, args ...interface{})  {}
+func (n *noopLogger) Warnf(format string, args ...interface{})  {}
+func (n *noopLogger) Errorf(format string, args ...interface{}) {}
+func (n *noopLogger) Fatalf(format string, args ...interface{}) {}
+func (n *noopLogger) IsLevelEnabled(l int) bool                 { return true }
+func (n *noopLogger) SetLogLevel(l int) error                   { return nil }
+
+// fakeConsumer 基于固定实例列表的服务发现实现
+type fakeConsumer struct {
+	mutex     sync.Mutex
+	instances *model.InstancesResponse
+	requests  []*polaris.GetAllInstancesRequest
+	results   []*polaris.ServiceCallResult
+	err       error
+}
+
+func (f *fakeConsumer) GetAllInstances(req *polaris.GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.instances, nil
+}
+
+func (f *fakeConsumer) UpdateServiceCallResult(req *polaris.ServiceCallResult) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.results = append(f.results, req)
+	return nil
+}
+
+// fakeRouter 路由不做过滤，负载均衡选择第一个实例
+type fakeRouter struct {
+	arguments map[string]string
+}
+
+func (f *fakeRouter) ProcessRouters(req *polaris.ProcessRoutersRequest) (*model.InstancesResponse, error) {
+	labels := map[string]string{}
+	for _, arg := range req.Arguments {
+		arg.ToLabels(labels)
+	}
+	f.arguments = labels
+	return req.DstInstances.(*model.InstancesResponse), nil
+}
+
+func (f *fakeRouter) ProcessLoadBalance(req *polaris.ProcessLoadBalanceRequest) (*model.OneInstanceResponse, error) {
+	resp := &model.OneInstanceResponse{}
+	resp.Instances = req.DstInstances.GetInstances()[:1]
+	return resp, nil
+}
+
+// fakeCircuitBreaker 返回固定的熔断检查结果，并记录上报的资源统计
+type fakeCircuitBreaker struct {
+	result *model.CheckResult
+	checks []model.Resource
+	stats  []*model.ResourceStat
+}
+
+func (f *fakeCircuitBreaker) Check(res model.Resource) (*model.CheckResult, error) {
+	f.checks = append(f.checks, res)
+	if f.result == nil {
+		return &model.CheckResult{Pass: true}, nil
+	}
+	return f.result, nil
+}
+
+func (f *fakeCircuitBreaker) Report(stat *model.ResourceStat) error {
+	f.stats = append(f.stats, stat)
+	return nil
+}
+
+// newTestTransport 创建指向 httptest server 的 Transport
+func newTestTransport(t *testing.T, server *httptest.Server) (*Transport, *fakeConsumer, *fakeRouter,
+	*fakeCircuitBreaker) {
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, portStr, err := net.SplitHostPort(serverURL.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.Atoi(portStr)
+	svcKey := &model.ServiceKey{Namespace: "test-ns", Service: "test.svc"}
+	inst := pb.NewInstanceInProto(&apiservice.Instance{
+		Id:      wrapperspb.String(serverURL.Host),
+		Host:    wrapperspb.String(host),
+		Port:    wrapperspb.UInt32(uint32(port)),
+		Weight:  wrapperspb.UInt32(100),
+		Healthy: wrapperspb.Bool(true),
+		Isolate: wrapperspb.Bool(false),
+	}, svcKey, local.NewInstanceLocalValue())
+	consumer := &fakeConsumer{instances: &model.InstancesResponse{
+		ServiceInfo: model.ServiceInfo{Namespace: svcKey.Namespace, Service: svcKey.Service},
+		Instances:   []model.Instance{inst},
+	}}
+	router := &fakeRouter{}
+	circuitBreaker := &fakeCircuitBreaker{}
+	transport := &Transport{
+		consumer:       consumer,
+		router:         router,
+		circuitBreaker: circuitBreaker,
+		opts:           newOptions([]Option{WithSourceService("test-ns", "caller")}),
+		logger:         &noopLogger{},
+	}
+	return transport, consumer, router, circuitBreaker
+}
+
+// TestRoundTrip 测试服务发现、路由参数构造以及调用结果上报
+func TestRoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("hello " + r.URL.Query().Get("user")))
+	}))
+	defer server.Close()
+	transport, consumer, router, circuitBreaker := newTestTransport(t, server)
+	client := &http.Client{Transport: transport}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://test.svc.test-ns/echo?user=polaris", nil)
+	req.Header.Set("X-Env", "gray")
+	req.AddCookie(&http.Cookie{Name: "uid", Value: "1001"})
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "hello polaris" {
+		t.Fatalf("unexpected body %q", body)
+	}
+
+	if len(consumer.requests) != 1 || consumer.requests[0].Namespace != "test-ns" ||
+		consumer.requests[0].Service != "test.svc" {
+		t.Fatalf("unexpected instances request %+v", consumer.requests)
+	}
+	expectLabels := map[string]string{
+		model.LabelKeyMethod:           http.MethodGet,
+		model.LabelKeyPath:             "/echo",
+		model.LabelKeyHeader + "x-env": "gray",
+		model.LabelKeyQuery + "user":   "polaris",
+		model.LabelKeyCookie + "uid":   "1001",
+	}
+	for key, value := range expectLabels {
+		if router.arguments[key] != value {
+			t.Fatalf("expect argument %s=%s, got %v", key, value, router.arguments)
+		}
+	}
+	if len(circuitBreaker.checks) != 1 {
+		t.Fatalf("expect 1 circuit breaker check, got %d", len(circuitBreaker.checks))
+	}
+	resource := circuitBreaker.checks[0].(*model.MethodResource)
+	if resource.Protocol != Protocol || resource.Method != http.MethodGet || resource.Path != "/echo" {
+		t.Fatalf("unexpected method resource %+v", resource)
+	}
+
+	resp, err = client.Get("http://test.svc.test-ns/fail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	if len(consumer.results) != 2 {
+		t.Fatalf("expect 2 call results, got %d", len(consumer.results))
+	}
+	if consumer.results[0].GetRetCodeValue() != http.StatusOK || consumer.results[0].GetRetStatus() != model.RetSuccess {
+		t.Fatalf("unexpected result %d/%v", consumer.results[0].GetRetCodeValue(), consumer.results[0].GetRetStatus())
+	}
+	if consumer.results[1].GetRetCodeValue() != http.StatusServiceUnavailable ||
+		consumer.results[1].GetRetStatus() != model.RetFail {
+		t.Fatalf("unexpected result %d/%v", consumer.results[1].GetRetCodeValue(), consumer.results[1].GetRetStatus())
+	}
+	if consumer.results[1].GetCallerService() != "caller" {
+		t.Fatalf("unexpected caller %v", consumer.results[1].GetCallerService())
+	}
+	if len(circuitBreaker.stats) != 2 || circuitBreaker.stats[1].RetCode != "503" ||
+		circuitBreaker.stats[1].RetStatus != model.RetFail {
+		t.Fatalf("unexpected circuit breaker stats %+v", circuitBreaker.stats)
+	}
+}
+
+// TestRoundTripCircuitBreakerOpen 测试接口被熔断时的拦截与降级
+func TestRoundTripCircuitBreakerOpen(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+	transport, consumer, _, circuitBreaker := newTestTransport(t, server)
+	client := &http.Client{Transport: transport}
+
+	circuitBreaker.result = &model.CheckResult{Pass: false, RuleName: "rule-1"}
+	_, err := client.Get("http://test.svc.test-ns/echo")
+	if !errors.Is(err, model.ErrorCallAborted) {
+		t.Fatalf("expect call aborted, got %v", err)
+	}
+	var cbErr *CircuitBreakerError
+	if !errors.As(err, &cbErr) || cbErr.RuleName != "rule-1" || cbErr.Path != "/echo" {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	circuitBreaker.result = &model.CheckResult{Pass: false, RuleName: "rule-1", FallbackInfo: &model.FallbackInfo{
+		Code:    http.StatusOK,
+		Headers: map[string]string{"X-Fallback": "true"},
+		Body:    "fallback",
+	}}
+	resp, err := client.Get("http://test.svc.test-ns/echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "fallback" || resp.Header.Get("X-Fallback") != "true" {
+		t.Fatalf("unexpected fallback response %q %v", body, resp.Header)
+	}
+	if hits != 0 || len(consumer.results) != 0 {
+		t.Fatalf("blocked request should not reach instance, hits %d, results %d", hits, len(consumer.results))
+	}
+}
+
+// trackingBody 记录是否被关闭的请求体
+type trackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+// TestRoundTripClosesBodyOnEarlyReturn 测试请求未发出时关闭请求体
+// 测试场景：接口被熔断（无降级与有降级两种）、获取服务实例失败
+// 预期结果：请求不会发到实例，RoundTrip 返回前请求体已被关闭
+func TestRoundTripClosesBodyOnEarlyReturn(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name  string
+		setup func(consumer *fakeConsumer, circuitBreaker *fakeCircuitBreaker)
+	}{
+		{name: "circuit breaker open", setup: func(_ *fakeConsumer, circuitBreaker *fakeCircuitBreaker) {
+			circuitBreaker.result = &model.CheckResult{Pass: false, RuleName: "rule-1"}
+		}},
+		{name: "fallback", setup: func(_ *fakeConsumer, circuitBreaker *fakeCircuitBreaker) {
+			circuitBreaker.result = &model.CheckResult{Pass: false, FallbackInfo: &model.FallbackInfo{
+				Code: http.StatusOK}}
+		}},
+		{name: "choose instance fail", setup: func(consumer *fakeConsumer, _ *fakeCircuitBreaker) {
+			consumer.err = errors.New("service not found")
+		}},
+	}
+	for _, c := range cases {
+		transport, consumer, _, circuitBreaker := newTestTransport(t, server)
+		c.setup(consumer, circuitBreaker)
+		body := &trackingBody{Reader: strings.NewReader("payload")}
+		req, err := http.NewRequest(http.MethodPost, "http://test.svc.test-ns/echo", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, _ := transport.RoundTrip(req)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		if !body.closed {
+			t.Fatalf("%s: request body should be closed", c.name)
+		}
+	}
+	if hits != 0 {
+		t.Fatalf("blocked request should not reach instance, hits %d", hits)
+	}
+}
+
+// TestParseHost 测试请求主机名解析
+func TestParseHost(t *testing.T) {
+	cases := map[string]model.ServiceKey{
+		"echo.test":     {Namespace: "test", Service: "echo"},
+		"a.b.echo.test": {Namespace: "test", Service: "a.b.echo"},
+		"echo":          {Namespace: DefaultNamespace, Service: "echo"},
+		"echo.":         {Namespace: DefaultNamespace, Service: "echo."},
+	}
+	for host, expect := range cases {
+		if got := parseHost(host); got != expect {
+			t.Fatalf("parse %s, expect %v, got %v", host, expect, got)
+		}
+	}
+}
+
+// timeoutError 超时类网络错误
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// TestToRetStatus 测试调用结果状态映射
+func TestToRetStatus(t *testing.T) {
+	cases := []struct {
+		code   int
+		err    error
+		expect model.RetStatus
+	}{
+		{code: http.StatusOK, expect: model.RetSuccess},
+		{code: http.StatusNotFound, expect: model.RetSuccess},
+		{code: http.StatusTooManyRequests, expect: model.RetFlowControl},
+		{code: http.StatusBadGateway, expect: model.RetFail},
+		{code: RetCodeTransportError, err: errors.New("connection refused"), expect: model.RetFail},
+		{code: RetCodeTransportError, err: context.DeadlineExceeded, expect: model.RetTimeout},
+		{code: RetCodeTransportError, err: &url.Error{Op: "Get", Err: timeoutError{}}, expect: model.RetTimeout},
+		{code: RetCodeTransportError, err: context.Canceled, expect: model.RetUnknown},
+	}
+	for _, c := range cases {
+		if got := toRetStatus(c.code, c.err); got != c.expect {
+			t.Fatalf("code %d err %v, expect %v, got %v", c.code, c.err, c.expect, got)
+		}
+	}
+}
@@ -20,9 +20,13 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"net"
+	"strings"
 	"sync"
 
+	"github.com/hashicorp/go-multierror"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 )
@@ -40,6 +44,12 @@ type AdminConfigImpl struct {
 	Type string `yaml:"type" json:"type"`
 	// Plugin 插件配置反序列化后的对象
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
+	// TLS admin 服务端TLS配置，certFile/keyFile 为服务端证书，配置 caFile 时要求客户端证书
+	TLS *TLSConfigImpl `yaml:"tls" json:"tls"`
+	// Auth admin 接口认证配置
+	Auth *AdminAuthConfigImpl `yaml:"auth" json:"auth"`
+	// AllowedIPs 允许访问 admin 接口的IP或CIDR，为空表示不限制
+	AllowedIPs []string `yaml:"allowedIPs" json:"allowedIPs"`
 	// Handlers 注册的路径
 	Handlers []model.AdminHandler `yaml:"-" json:"-"`
 	// mu 保护 Handlers 的并发访问
@@ -106,6 +116,26 @@ func (a *AdminConfigImpl) GetPaths() []model.AdminHandler {
 	return a.Handlers
 }
 
+// GetTLS 获取admin服务端TLS配置
+func (a *AdminConfigImpl) GetTLS() TLSConfig {
+	return a.TLS
+}
+
+// GetAuth 获取admin接口认证配置
+func (a *AdminConfigImpl) GetAuth() AdminAuthConfig {
+	return a.Auth
+}
+
+// GetAllowedIPs 获取允许访问admin接口的IP或CIDR
+func (a *AdminConfigImpl) GetAllowedIPs() []string {
+	return a.AllowedIPs
+}
+
+// SetAllowedIPs 设置允许访问admin接口的IP或CIDR
+func (a *AdminConfigImpl) SetAllowedIPs(ips []string) {
+	a.AllowedIPs = ips
+}
+
 // GetPluginConfig 获取插件配置
 func (a *AdminConfigImpl) GetPluginConfig(name string) BaseConfig {
 	value, ok := a.Plugin[name]
@@ -136,6 +166,24 @@ func (a *AdminConfigImpl) Verify() error {
 	if a.Port < 0 || a.Port > 65535 {
 		return errors.New("admin.port must be between 0 and 65535")
 	}
+	for _, item := range a.AllowedIPs {
+		if _, err := ParseIPNet(item); err != nil {
+			return fmt.Errorf("admin.allowedIPs: %v", err)
+		}
+	}
+	if a.TLS != nil {
+		if err := a.TLS.Verify(); err != nil {
+			return err
+		}
+		if a.TLS.IsEnable() && (len(a.TLS.CertFile) == 0 || len(a.TLS.KeyFile) == 0) {
+			return errors.New("admin.tls.certFile and admin.tls.keyFile are required when admin tls is enabled")
+		}
+	}
+	if a.Auth != nil {
+		if err := a.Auth.Verify(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -150,6 +198,14 @@ func (a *AdminConfigImpl) SetDefault() {
 	if a.Type == "" {
 		a.Type = DefaultAdminType
 	}
+	if a.TLS == nil {
+		a.TLS = &TLSConfigImpl{}
+	}
+	a.TLS.SetDefault()
+	if a.Auth == nil {
+		a.Auth = &AdminAuthConfigImpl{}
+	}
+	a.Auth.SetDefault()
 	a.Plugin.SetDefault(common.TypeAdmin)
 }
 
@@ -157,5 +213,170 @@ func (a *AdminConfigImpl) SetDefault() {
 func (a *AdminConfigImpl) Init() {
 	a.Plugin = PluginConfigs{}
 	a.Plugin.Init(common.TypeAdmin)
+	a.TLS = &TLSConfigImpl{}
+	a.Auth = &AdminAuthConfigImpl{}
 	a.mu = &sync.RWMutex{}
 }
+
+// ParseIPNet 解析IP或CIDR，单个IP按全掩码处理
+func ParseIPNet(value string) (*net.IPNet, error) {
+	if strings.Contains(value, "/") {
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", value)
+		}
+		return ipNet, nil
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP %q", value)
+	}
+	bits := 8 * net.IPv4len
+	if ip.To4() == nil {
+		bits = 8 * net.IPv6len
+	} else {
+		ip = ip.To4()
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+const (
+	// AdminAuthNone 不认证
+	AdminAuthNone = "none"
+	// AdminAuthBearer 使用 Authorization: Bearer <token> 认证
+	AdminAuthBearer = "bearer"
+	// AdminAuthBasic 使用 HTTP Basic 认证
+	AdminAuthBasic = "basic"
+)
+
+// Ensure AdminAuthConfigImpl implements AdminAuthConfig
+var _ AdminAuthConfig = (*AdminAuthConfigImpl)(nil)
+
+// AdminAuthConfigImpl admin接口认证配置实现
+type AdminAuthConfigImpl struct {
+	// Type 默认认证方式，取值 none、bearer、basic
+	Type string `yaml:"type" json:"type"`
+	// Token bearer 认证使用的令牌
+	Token string `yaml:"token" json:"token"`
+	// Username basic 认证用户名
+	Username string `yaml:"username" json:"username"`
+	// Password basic 认证密码
+	Password string `yaml:"password" json:"password"`
+	// Paths 按路径前缀覆盖认证方式，最长前缀优先，如 /metrics: none
+	Paths map[string]string `yaml:"paths" json:"paths"`
+}
+
+// GetType 获取默认认证方式
+func (a *AdminAuthConfigImpl) GetType() string {
+	return a.Type
+}
+
+// SetType 设置默认认证方式
+func (a *AdminAuthConfigImpl) SetType(typ string) {
+	a.Type = typ
+}
+
+// GetToken 获取bearer令牌
+func (a *AdminAuthConfigImpl) GetToken() string {
+	return a.Token
+}
+
+// SetToken 设置bearer令牌
+func (a *AdminAuthConfigImpl) SetToken(token string) {
+	a.Token = token
+}
+
+// GetUsername 获取basic认证用户名
+func (a *AdminAuthConfigImpl) GetUsername() string {
+	return a.Username
+}
+
+// SetUsername 设置basic认证用户名
+func (a *AdminAuthConfigImpl) SetUsername(username string) {
+	a.Username = username
+}
+
+// GetPassword 获取basic认证密码
+func (a *AdminAuthConfigImpl) GetPassword() string {
+	return a.Password
+}
+
+// SetPassword 设置basic认证密码
+func (a *AdminAuthConfigImpl) SetPassword(password string) {
+	a.Password = password
+}
+
+// GetPaths 获取按路径前缀覆盖的认证方式
+func (a *AdminAuthConfigImpl) GetPaths() map[string]string {
+	return a.Paths
+}
+
+// SetPaths 设置按路径前缀覆盖的认证方式
+func (a *AdminAuthConfigImpl) SetPaths(paths map[string]string) {
+	a.Paths = paths
+}
+
+// GetPathType 获取请求路径对应的认证方式，按路径前缀最长匹配，未匹配时使用默认认证方式
+func (a *AdminAuthConfigImpl) GetPathType(path string) string {
+	if a == nil {
+		return AdminAuthNone
+	}
+	matched, typ := -1, a.Type
+	for prefix, pathType := range a.Paths {
+		if !matchPathPrefix(path, prefix) || len(prefix) <= matched {
+			continue
+		}
+		matched, typ = len(prefix), pathType
+	}
+	if typ == "" {
+		return AdminAuthNone
+	}
+	return typ
+}
+
+// matchPathPrefix 按路径段匹配前缀，/metrics 匹配 /metrics 与 /metrics/xxx，不匹配 /metricsx
+func matchPathPrefix(path, prefix string) bool {
+	if path == prefix || prefix == "/" {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
+
+// Verify 校验认证配置
+func (a *AdminAuthConfigImpl) Verify() error {
+	if a == nil {
+		return errors.New("AdminAuthConfig is nil")
+	}
+	var errs error
+	types := map[string]bool{a.Type: true}
+	for prefix, typ := range a.Paths {
+		if !strings.HasPrefix(prefix, "/") {
+			errs = multierror.Append(errs, fmt.Errorf("admin.auth.paths: path %q must start with /", prefix))
+		}
+		types[typ] = true
+	}
+	for typ := range types {
+		switch typ {
+		case "", AdminAuthNone:
+		case AdminAuthBearer:
+			if len(a.Token) == 0 {
+				errs = multierror.Append(errs, errors.New("admin.auth.token is required for bearer auth"))
+			}
+		case AdminAuthBasic:
+			if len(a.Username) == 0 || len(a.Password) == 0 {
+				errs = multierror.Append(errs,
+					errors.New("admin.auth.username and admin.auth.password are required for basic auth"))
+			}
+		default:
+			errs = multierror.Append(errs, fmt.Errorf("admin.auth: unsupported auth type %q", typ))
+		}
+	}
+	return errs
+}
+
+// SetDefault 设置认证配置默认值
+func (a *AdminAuthConfigImpl) SetDefault() {
+	if len(a.Type) == 0 {
+		a.Type = AdminAuthNone
+	}
+}
@@ -50,6 +50,8 @@ type GlobalConfig interface {
 	GetClient() ClientConfig
 	// GetAdmin global.admin前缀开头的所有配置项
 	GetAdmin() AdminConfig
+	// GetTracing global.tracing前缀开头的所有配置项
+	GetTracing() TracingConfig
 }
 
 // ConsumerConfig consumer config object.
@@ -127,6 +129,10 @@ type RateLimitConfig interface {
 	SetLimiterNamespace(value string)
 	// GetLimiterNamespace 获取限流命名空间
 	GetLimiterNamespace() string
+	// IsDryRun 是否以演练模式执行所有限流规则，规则 metadata 中的 dryRun 可单独覆盖
+	IsDryRun() bool
+	// SetDryRun 设置是否以演练模式执行所有限流规则
+	SetDryRun(bool)
 }
 
 // LosslessConfig 无损上下线配置.
@@ -158,6 +164,7 @@ type LosslessConfig interface {
 // AdminConfig Admin相关配置.
 type AdminConfig interface {
 	BaseConfig
+	PluginConfig
 	// GetHost 获取Admin监听的IP地址
 	GetHost() string
 	// SetHost 设置Admin监听的IP地址
@@ -174,6 +181,50 @@ type AdminConfig interface {
 	RegisterPath(adminHandler model.AdminHandler)
 	// GetPaths 获取注册的路径
 	GetPaths() []model.AdminHandler
+	// GetTLS 获取admin服务端TLS配置
+	GetTLS() TLSConfig
+	// GetAuth 获取admin接口认证配置
+	GetAuth() AdminAuthConfig
+	// GetAllowedIPs 获取允许访问admin接口的IP或CIDR，为空表示不限制
+	GetAllowedIPs() []string
+	// SetAllowedIPs 设置允许访问admin接口的IP或CIDR
+	SetAllowedIPs([]string)
+}
+
+// AdminAuthConfig admin接口认证配置.
+type AdminAuthConfig interface {
+	BaseConfig
+	// GetType 获取默认认证方式，取值 none、bearer、basic
+	GetType() string
+	// SetType 设置默认认证方式
+	SetType(string)
+	// GetToken 获取bearer令牌
+	GetToken() string
+	// SetToken 设置bearer令牌
+	SetToken(string)
+	// GetUsername 获取basic认证用户名
+	GetUsername() string
+	// SetUsername 设置basic认证用户名
+	SetUsername(string)
+	// GetPassword 获取basic认证密码
+	GetPassword() string
+	// SetPassword 设置basic认证密码
+	SetPassword(string)
+	// GetPaths 获取按路径前缀覆盖的认证方式
+	GetPaths() map[string]string
+	// SetPaths 设置按路径前缀覆盖的认证方式
+	SetPaths(map[string]string)
+	// GetPathType 获取请求路径对应的认证方式
+	GetPathType(path string) string
+}
+
+// TracingConfig 链路追踪相关配置.
+type TracingConfig interface {
+	BaseConfig
+	// IsEnable 是否为 SDK 的流程创建 OpenTelemetry span
+	IsEnable() bool
+	// SetEnable 设置是否启用链路追踪
+	SetEnable(bool)
 }
 
 // SystemConfig 系统配置信息.
@@ -346,6 +397,40 @@ type ServerConnectorConfig interface {
 	GetToken() string
 	// SetToken .
 	SetToken(string)
+	// GetTLS global.serverConnector.tls
+	// 与server通信的TLS配置
+	GetTLS() TLSConfig
+}
+
+// TLSConfig 与server通信的TLS配置，证书文件在磁盘上轮换后会自动重新加载.
+type TLSConfig interface {
+	BaseConfig
+	// IsEnable 是否启用TLS
+	IsEnable() bool
+	// IsEnableSet 是否显式配置了是否启用TLS
+	IsEnableSet() bool
+	// SetEnable 设置是否启用TLS
+	SetEnable(bool)
+	// GetCAFile 校验服务端证书的CA证书文件路径，为空时使用系统根证书
+	GetCAFile() string
+	// SetCAFile 设置CA证书文件路径
+	SetCAFile(string)
+	// GetCertFile 客户端证书文件路径，与私钥同时配置时启用mTLS
+	GetCertFile() string
+	// SetCertFile 设置客户端证书文件路径
+	SetCertFile(string)
+	// GetKeyFile 客户端私钥文件路径
+	GetKeyFile() string
+	// SetKeyFile 设置客户端私钥文件路径
+	SetKeyFile(string)
+	// GetServerName 校验服务端证书时使用的域名
+	GetServerName() string
+	// SetServerName 设置校验服务端证书时使用的域名
+	SetServerName(string)
+	// IsInsecureSkipVerify 是否跳过服务端证书校验，仅用于开发测试环境
+	IsInsecureSkipVerify() bool
+	// SetInsecureSkipVerify 设置是否跳过服务端证书校验
+	SetInsecureSkipVerify(bool)
 }
 
 // LocalCacheConfig 本地缓存相关配置项.
@@ -477,6 +562,14 @@ type LoadbalancerConfig interface {
 	GetType() string
 	// SetType 设置负载均衡类型
 	SetType(string)
+	// GetInflightReleaseTimeout 在途请求超过该时间仍未上报调用结果时自动归还
+	GetInflightReleaseTimeout() time.Duration
+	// SetInflightReleaseTimeout 设置在途请求的归还超时时间
+	SetInflightReleaseTimeout(time.Duration)
+	// GetInflightReportInterval 在途请求数的上报周期
+	GetInflightReportInterval() time.Duration
+	// SetInflightReportInterval 设置在途请求数的上报周期
+	SetInflightReportInterval(time.Duration)
 }
 
 // CircuitBreakerConfig 熔断相关的配置项.
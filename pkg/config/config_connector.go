@@ -53,6 +53,8 @@ type ConfigConnectorConfigImpl struct {
 
 	Token string `yaml:"token" json:"token"`
 
+	TLS *TLSConfigImpl `yaml:"tls" json:"tls"`
+
 	ConnectorType string `yaml:"connectorType" json:"connectorType"`
 }
 
@@ -170,6 +172,12 @@ func (c *ConfigConnectorConfigImpl) SetToken(token string) {
 	c.Token = token
 }
 
+// GetTLS config.configConnector.tls
+// 与server通信的TLS配置.
+func (c *ConfigConnectorConfigImpl) GetTLS() TLSConfig {
+	return c.TLS
+}
+
 // Verify 检验ConfigConnector配置.
 func (c *ConfigConnectorConfigImpl) Verify() error {
 	if nil == c {
@@ -198,6 +206,11 @@ func (c *ConfigConnectorConfigImpl) Verify() error {
 	if len(c.ConnectorType) == 0 {
 		errs = multierror.Append(errs, fmt.Errorf("config.configConnector.connectorType is empty"))
 	}
+	if nil != c.TLS {
+		if err := c.TLS.Verify(); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
 	return errs
 }
 
@@ -230,11 +243,16 @@ func (c *ConfigConnectorConfigImpl) SetDefault() {
 	if len(c.ConnectorType) == 0 {
 		c.ConnectorType = DefaultConnectorType
 	}
+	// tls.enable 未配置时沿用 global.serverConnector.tls，这里不设置默认值
+	if nil == c.TLS {
+		c.TLS = &TLSConfigImpl{}
+	}
 	c.Plugin.SetDefault(common.TypeConfigConnector)
 }
 
 // Init 配置初始化.
 func (c *ConfigConnectorConfigImpl) Init() {
 	c.Plugin = PluginConfigs{}
+	c.TLS = &TLSConfigImpl{}
 	c.Plugin.Init(common.TypeConfigConnector)
 }
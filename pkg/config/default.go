@@ -101,6 +101,12 @@ const (
 	MinSleepWindow = 1 * time.Second
 	// DefaultRecoverWindow 默认恢复周期，半开后按多久的统计窗口进行恢复统计.
 	DefaultRecoverWindow = 60 * time.Second
+	// DefaultInflightReleaseTimeout 在途请求未上报调用结果的最长时间，超过后自动归还.
+	DefaultInflightReleaseTimeout = 30 * time.Second
+	// DefaultInflightReportInterval 负载均衡在途请求数的上报周期.
+	DefaultInflightReportInterval = 5 * time.Second
+	// MinInflightReportInterval 最小在途请求数上报周期，100ms.
+	MinInflightReportInterval = 100 * time.Millisecond
 	// MinRecoverWindow 最小恢复周期，10s.
 	MinRecoverWindow = 10 * time.Second
 	// DefaultRecoverNumBuckets 默认恢复统计的滑桶数.
@@ -179,6 +185,8 @@ var containerNameEnvs = []string{
 const (
 	// DefaultServerConnector 默认的服务端连接器插件.
 	DefaultServerConnector string = "grpc"
+	// ServerConnectorFile 基于本地文件的服务端连接器插件，无需配置服务端地址.
+	ServerConnectorFile string = "file"
 	// DefaultLocalCache 默认本地缓存策略.
 	DefaultLocalCache string = "inmemory"
 	// DefaultServiceRouterRuleBased 默认规则路由.
@@ -208,6 +216,10 @@ const (
 	DefaultLoadBalancerL5CST string = "l5cst"
 	// DefaultLoadBalancerHash 负载均衡器,普通hash.
 	DefaultLoadBalancerHash string = "hash"
+	// DefaultLoadBalancerLeastRequest 负载均衡器,最少在途请求(P2C).
+	DefaultLoadBalancerLeastRequest string = "leastRequest"
+	// DefaultLoadBalancerPeakEWMA 负载均衡器,基于时延峰值EWMA.
+	DefaultLoadBalancerPeakEWMA string = "peakEwma"
 	// DefaultCircuitBreaker 默认错误率熔断器.
 	DefaultCircuitBreaker string = "composite"
 	// DefaultWeightAdjuster 默认权重调整插件.
@@ -222,6 +234,8 @@ const (
 	DefaultTCPHealthCheck string = "tcp"
 	// DefaultUDPHealthCheck 默认UDP探测器.
 	DefaultUDPHealthCheck string = "udp"
+	// DefaultGRPCHealthCheck 默认gRPC探测器.
+	DefaultGRPCHealthCheck string = "grpc"
 
 	// DefaultRejectRateLimiter 默认的reject限流器.
 	DefaultRejectRateLimiter = "reject"
@@ -232,6 +246,11 @@ const (
 	DefaultWarmUpRateLimiter = "warmUp"
 	// DefaultUniformRateLimiter 默认的匀速限流器.
 	DefaultUniformRateLimiter = "unirate"
+	// DefaultGCRARateLimiter 基于 GCRA 的精确限流器，按理论到达时间逐个准入，保证请求间的精确间隔.
+	DefaultGCRARateLimiter = "gcra"
+	// DefaultBBRRateLimiter 基于系统负载的自适应限流器.
+	// 根据 CPU 使用率、在途请求数与响应时间自适应估算并发容量，只依赖本地信号，框架对其强制使用本地模式.
+	DefaultBBRRateLimiter = "bbr"
 	// DefaultWarmUpWaitLimiter 默认限流插件，预热匀速.
 	DefaultWarmUpWaitLimiter = "warmup-wait"
 	// SubscribeLocalChannel 默认订阅事件处理插件.
@@ -292,6 +311,8 @@ const (
 	DefaultServiceRouteReporter = "serviceRoute"
 	// DefaultStatReportEnabled .
 	DefaultStatReportEnabled = true
+	// DefaultTracingEnabled 默认不创建链路追踪 span
+	DefaultTracingEnabled = false
 	// DefaultMetricsChain .
 	DefaultMetricsChain = "prometheus"
 	// DefaultEventReporterEnabled 事件上报默认不开启
@@ -488,6 +509,9 @@ func (g *GlobalConfigImpl) Verify() error {
 	if err = g.Admin.Verify(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
+	if err = g.Tracing.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	return errs
 }
 
@@ -500,6 +524,7 @@ func (g *GlobalConfigImpl) SetDefault() {
 	g.EventReporter.SetDefault()
 	g.Location.SetDefault()
 	g.Admin.SetDefault()
+	g.Tracing.SetDefault()
 }
 
 // Init 全局配置初始化.
@@ -519,6 +544,8 @@ func (g *GlobalConfigImpl) Init() {
 	g.Client.Init()
 	g.Admin = &AdminConfigImpl{}
 	g.Admin.Init()
+	g.Tracing = &TracingConfigImpl{}
+	g.Tracing.Init()
 }
 
 // Init 初始化ConsumerConfigImpl.
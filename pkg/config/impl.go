@@ -67,6 +67,7 @@ type GlobalConfigImpl struct {
 	Location        *LocationConfigImpl        `yaml:"location" json:"location"`
 	Client          *ClientConfigImpl          `yaml:"client" json:"client"`
 	Admin           *AdminConfigImpl           `yaml:"admin" json:"admin"`
+	Tracing         *TracingConfigImpl         `yaml:"tracing" json:"tracing"`
 }
 
 // GetSystem 获取系统配置.
@@ -109,6 +110,11 @@ func (g *GlobalConfigImpl) GetAdmin() AdminConfig {
 	return g.Admin
 }
 
+// GetTracing global.tracing前缀开头的所有配置项.
+func (g *GlobalConfigImpl) GetTracing() TracingConfig {
+	return g.Tracing
+}
+
 // ConsumerConfigImpl 消费者配置.
 type ConsumerConfigImpl struct {
 	LocalCache       *LocalCacheConfigImpl     `yaml:"localCache" json:"localCache"`
@@ -18,6 +18,12 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 )
 
@@ -25,6 +31,10 @@ import (
 type LoadBalancerConfigImpl struct {
 	// 负载均衡类型
 	Type string `yaml:"type" json:"type"`
+	// 在途请求超过该时间仍未上报调用结果时自动归还，仅对感知调用结果的负载均衡插件生效
+	InflightReleaseTimeout *time.Duration `yaml:"inflightReleaseTimeout" json:"inflightReleaseTimeout"`
+	// 在途请求数的上报周期
+	InflightReportInterval *time.Duration `yaml:"inflightReportInterval" json:"inflightReportInterval"`
 	// 插件相关配置
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
 }
@@ -39,6 +49,26 @@ func (l *LoadBalancerConfigImpl) SetType(typ string) {
 	l.Type = typ
 }
 
+// GetInflightReleaseTimeout 在途请求的归还超时时间.
+func (l *LoadBalancerConfigImpl) GetInflightReleaseTimeout() time.Duration {
+	return *l.InflightReleaseTimeout
+}
+
+// SetInflightReleaseTimeout 设置在途请求的归还超时时间.
+func (l *LoadBalancerConfigImpl) SetInflightReleaseTimeout(timeout time.Duration) {
+	l.InflightReleaseTimeout = &timeout
+}
+
+// GetInflightReportInterval 在途请求数的上报周期.
+func (l *LoadBalancerConfigImpl) GetInflightReportInterval() time.Duration {
+	return *l.InflightReportInterval
+}
+
+// SetInflightReportInterval 设置在途请求数的上报周期.
+func (l *LoadBalancerConfigImpl) SetInflightReportInterval(interval time.Duration) {
+	l.InflightReportInterval = &interval
+}
+
 // GetPluginConfig consumer.loadbalancer.plugin.
 func (l *LoadBalancerConfigImpl) GetPluginConfig(pluginName string) BaseConfig {
 	cfgValue, ok := l.Plugin[pluginName]
@@ -55,7 +85,20 @@ func (l *LoadBalancerConfigImpl) SetPluginConfig(pluginName string, value BaseCo
 
 // Verify 检验LocalCacheConfig配置.
 func (l *LoadBalancerConfigImpl) Verify() error {
-	return l.Plugin.Verify()
+	var errs error
+	if nil != l.InflightReleaseTimeout && *l.InflightReleaseTimeout <= 0 {
+		errs = multierror.Append(errs,
+			fmt.Errorf("consumer.loadbalancer.inflightReleaseTimeout must be greater than 0"))
+	}
+	if nil != l.InflightReportInterval && *l.InflightReportInterval < MinInflightReportInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("consumer.loadbalancer.inflightReportInterval must be greater than %v",
+				MinInflightReportInterval))
+	}
+	if err := l.Plugin.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return errs
 }
 
 // SetDefault 设置LocalCacheConfig配置的默认值.
@@ -63,6 +106,12 @@ func (l *LoadBalancerConfigImpl) SetDefault() {
 	if len(l.Type) == 0 {
 		l.Type = DefaultLoadBalancerWR
 	}
+	if nil == l.InflightReleaseTimeout {
+		l.InflightReleaseTimeout = model.ToDurationPtr(DefaultInflightReleaseTimeout)
+	}
+	if nil == l.InflightReportInterval {
+		l.InflightReportInterval = model.ToDurationPtr(DefaultInflightReportInterval)
+	}
 	l.Plugin.SetDefault(common.TypeLoadBalancer)
 }
 
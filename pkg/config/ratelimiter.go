@@ -39,6 +39,8 @@ type RateLimitConfigImpl struct {
 	LimiterNamespace string `yaml:"limiterNamespace" json:"limiterNamespace"`
 	// LimiterService 限流服务的服务名
 	LimiterService string `yaml:"limiterService" json:"limiterService"`
+	// DryRun 是否以演练模式执行所有限流规则，演练模式下只记录本应限流的请求，不拦截
+	DryRun bool `yaml:"dryRun" json:"dryRun"`
 }
 
 // IsEnable 是否启用限流能力.
@@ -140,3 +142,13 @@ func (r *RateLimitConfigImpl) SetLimiterNamespace(value string) {
 func (r *RateLimitConfigImpl) GetLimiterNamespace() string {
 	return r.LimiterNamespace
 }
+
+// IsDryRun 是否以演练模式执行所有限流规则
+func (r *RateLimitConfigImpl) IsDryRun() bool {
+	return r.DryRun
+}
+
+// SetDryRun 设置是否以演练模式执行所有限流规则
+func (r *RateLimitConfigImpl) SetDryRun(value bool) {
+	r.DryRun = value
+}
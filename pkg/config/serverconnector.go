@@ -51,6 +51,8 @@ type ServerConnectorConfigImpl struct {
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
 
 	Token string `yaml:"token" json:"token"`
+
+	TLS *TLSConfigImpl `yaml:"tls" json:"tls"`
 }
 
 // GetAddresses model.serverConnector.addresses
@@ -165,13 +167,19 @@ func (s *ServerConnectorConfigImpl) SetToken(t string) {
 	s.Token = t
 }
 
+// GetTLS global.serverConnector.tls
+// 与server通信的TLS配置.
+func (s *ServerConnectorConfigImpl) GetTLS() TLSConfig {
+	return s.TLS
+}
+
 // Verify 检验ServerConnector配置.
 func (s *ServerConnectorConfigImpl) Verify() error {
 	if nil == s {
 		return errors.New("ServerConnectorConfig is nil")
 	}
 	var errs error
-	if len(s.Addresses) == 0 {
+	if len(s.Addresses) == 0 && s.Protocol != ServerConnectorFile {
 		errs = multierror.Append(errs, fmt.Errorf("model.serverConnector.addresses is empty"))
 	}
 	if nil != s.RequestQueueSize && *s.RequestQueueSize < 0 {
@@ -190,6 +198,11 @@ func (s *ServerConnectorConfigImpl) Verify() error {
 				" is less than or equal to model.serverConnector.connectionIdleTimeout %v",
 				*s.ServerSwitchInterval, *s.ConnectionIdleTimeout))
 	}
+	if nil != s.TLS {
+		if err := s.TLS.Verify(); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
 	return errs
 }
 
@@ -216,11 +229,16 @@ func (s *ServerConnectorConfigImpl) SetDefault() {
 	if len(s.Protocol) == 0 {
 		s.Protocol = DefaultServerConnector
 	}
+	if nil == s.TLS {
+		s.TLS = &TLSConfigImpl{}
+	}
+	s.TLS.SetDefault()
 	s.Plugin.SetDefault(common.TypeServerConnector)
 }
 
 // Init 配置初始化.
 func (s *ServerConnectorConfigImpl) Init() {
 	s.Plugin = PluginConfigs{}
+	s.TLS = &TLSConfigImpl{}
 	s.Plugin.Init(common.TypeServerConnector)
 }
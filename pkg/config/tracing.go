@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+// TracingConfigImpl global.tracing 链路追踪配置.
+type TracingConfigImpl struct {
+	// 是否为 SDK 的流程创建 OpenTelemetry span
+	Enable *bool `yaml:"enable" json:"enable"`
+}
+
+// IsEnable 是否启用链路追踪.
+func (t *TracingConfigImpl) IsEnable() bool {
+	if t == nil || t.Enable == nil {
+		return DefaultTracingEnabled
+	}
+	return *t.Enable
+}
+
+// SetEnable 设置是否启用链路追踪.
+func (t *TracingConfigImpl) SetEnable(enable bool) {
+	t.Enable = &enable
+}
+
+// Verify 检测tracing配置.
+func (t *TracingConfigImpl) Verify() error {
+	return nil
+}
+
+// SetDefault 设置tracing默认值.
+func (t *TracingConfigImpl) SetDefault() {
+	if nil == t.Enable {
+		enable := DefaultTracingEnabled
+		t.Enable = &enable
+	}
+}
+
+// Init 配置初始化.
+func (t *TracingConfigImpl) Init() {
+}
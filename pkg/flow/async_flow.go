@@ -18,19 +18,31 @@
 package flow
 
 import (
+	"context"
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/tracing"
 )
 
 // AsyncGetQuota 异步获取配额信息
 func (e *Engine) AsyncGetQuota(request *model.QuotaRequestImpl) (*model.QuotaFutureImpl, error) {
 	commonRequest := data.PoolGetCommonRateLimitRequest()
 	commonRequest.InitByGetQuotaRequest(request, e.configuration)
+	ctx, span := e.tracer.Start(commonRequest.ControlParam.GetContext(), tracing.SpanGetQuota,
+		append(tracing.ServiceAttributes(&commonRequest.DstService),
+			tracing.AttrMethod.String(commonRequest.Method))...)
+	commonRequest.ControlParam.Context = ctx
 	startTime := model.CurrentMillisecond()
 	future, err := e.flowQuotaAssistant.GetQuota(commonRequest)
 	consumeTime := model.CurrentMillisecond() - startTime
+	if future != nil && span.IsRecording() {
+		if resp := future.GetImmediately(); resp != nil {
+			span.SetAttributes(tracing.AttrQuotaCode.Int(int(resp.Code)), tracing.AttrQuotaInfo.String(resp.Info))
+		}
+	}
+	tracing.End(span, err)
 	if err != nil {
 		(&commonRequest.CallResult).SetFail(model.GetErrorCodeFromError(err), time.Duration(consumeTime)*time.Millisecond)
 	} else {
@@ -44,3 +56,34 @@ func (e *Engine) AsyncGetQuota(request *model.QuotaRequestImpl) (*model.QuotaFut
 	}
 	return future, err
 }
+
+// SyncWaitQuota 阻塞获取 token 个配额，直到分配成功或 ctx 结束
+func (e *Engine) SyncWaitQuota(
+	ctx context.Context, request *model.QuotaRequestImpl, token uint32) (*model.QuotaFutureImpl, error) {
+	commonRequest := data.PoolGetCommonRateLimitRequest()
+	commonRequest.InitByGetQuotaRequest(request, e.configuration)
+	if token > 0 {
+		commonRequest.Token = token
+	}
+	spanCtx, span := e.tracer.Start(ctx, tracing.SpanWaitQuota,
+		append(tracing.ServiceAttributes(&commonRequest.DstService),
+			tracing.AttrMethod.String(commonRequest.Method))...)
+	commonRequest.ControlParam.Context = spanCtx
+	startTime := model.CurrentMillisecond()
+	future, err := e.flowQuotaAssistant.WaitQuota(ctx, commonRequest)
+	consumeTime := model.CurrentMillisecond() - startTime
+	if future != nil && span.IsRecording() {
+		if resp := future.GetImmediately(); resp != nil {
+			span.SetAttributes(tracing.AttrQuotaCode.Int(int(resp.Code)), tracing.AttrQuotaInfo.String(resp.Info))
+		}
+	}
+	tracing.End(span, err)
+	if err != nil {
+		(&commonRequest.CallResult).SetFail(model.GetErrorCodeFromError(err), time.Duration(consumeTime)*time.Millisecond)
+		e.syncRateLimitReportAndFinalize(commonRequest, nil)
+		return nil, err
+	}
+	(&commonRequest.CallResult).SetDelay(time.Duration(consumeTime) * time.Millisecond)
+	e.syncRateLimitReportAndFinalize(commonRequest, future.GetImmediately())
+	return future, nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/go-multierror"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -28,6 +29,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
 	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
 	"github.com/polarismesh/polaris-go/pkg/sdk"
+	"github.com/polarismesh/polaris-go/pkg/tracing"
 )
 
 // cacheFilters 过滤器参数集合
@@ -376,6 +378,14 @@ func tryGetServiceValuesFromCache(registry localregistry.LocalRegistry, request
 // afterLazyGetInstances 懒加载后执行的服务实例筛选流程
 func (e *Engine) afterLazyGetInstances(
 	req *data.CommonInstancesRequest) (cls *model.Cluster, redirected *model.ServiceInfo, err model.SDKError) {
+	_, span := e.tracer.Start(req.ControlParam.GetContext(), tracing.SpanRoute,
+		tracing.ServiceAttributes(&req.DstService)...)
+	defer func() {
+		if err == nil && span.IsRecording() {
+			span.SetAttributes(routeResultAttributes(e.resolveRouterChain(req), cls, redirected)...)
+		}
+		tracing.End(span, err)
+	}()
 	var result *servicerouter.RouteResult
 	req.RouteInfo.FilterOnlyRouter = e.finalRouterPlugin
 	// 服务路由
@@ -393,10 +403,32 @@ func (e *Engine) afterLazyGetInstances(
 	}
 	cls = result.OutputCluster
 	redirected = result.RedirectDestService
+	span.SetAttributes(tracing.AttrRouterStatus.String(result.Status.String()))
 	servicerouter.GetRouteResultPool().Put(result)
 	return cls, redirected, nil
 }
 
+// routeResultAttributes 路由链及路由结果对应的 span 属性
+func routeResultAttributes(chain *servicerouter.RouterChain, cls *model.Cluster,
+	redirected *model.ServiceInfo) []attribute.KeyValue {
+	routers := make([]string, 0, len(chain.BeforeChain)+len(chain.Chain))
+	for _, router := range chain.BeforeChain {
+		routers = append(routers, router.Name())
+	}
+	for _, router := range chain.Chain {
+		routers = append(routers, router.Name())
+	}
+	attrs := []attribute.KeyValue{tracing.AttrRouterChain.StringSlice(routers)}
+	if redirected != nil {
+		attrs = append(attrs, tracing.AttrRouterRedirect.String(redirected.Namespace+"/"+redirected.Service))
+	}
+	if cls != nil {
+		instances, _ := cls.GetInstances()
+		attrs = append(attrs, tracing.AttrRouterInstances.Int(len(instances)))
+	}
+	return attrs
+}
+
 // combineSDKErrors 把多个SDK error合成一个error
 func combineSDKErrors(sdkErrs map[ContextKey]model.SDKError) error {
 	var errs error
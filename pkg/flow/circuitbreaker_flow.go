@@ -22,8 +22,11 @@ import (
 	"errors"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
+	"github.com/polarismesh/polaris-go/pkg/tracing"
 )
 
 // Check
@@ -122,6 +125,8 @@ func (e *CircuitBreakerFlow) MakeFunctionDecorator(f model.CustomerFunction, req
 			reqCtx: reqCtx,
 		},
 		customerFunc: f,
+		tracer:       e.engine.tracer,
+		reqCtx:       reqCtx,
 	}
 	return decorator.Decorator
 }
@@ -136,11 +141,13 @@ func (e *CircuitBreakerFlow) MakeInvokeHandler(reqCtx *model.RequestContext) mod
 type DefaultFunctionalDecorator struct {
 	invoke       model.InvokeHandler
 	customerFunc model.CustomerFunction
+	tracer       *tracing.Tracer
+	reqCtx       *model.RequestContext
 }
 
 func (df *DefaultFunctionalDecorator) Decorator(ctx context.Context, args interface{}) (interface{}, *model.CallAborted, error) {
 	invoke := df.invoke
-	pass, aborted, err := invoke.AcquirePermission()
+	pass, aborted, err := df.acquirePermission(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -189,6 +196,22 @@ func (df *DefaultFunctionalDecorator) Decorator(ctx context.Context, args interf
 	return ret, nil, nil
 }
 
+// acquirePermission 熔断检查，检查过程记录为调用方 ctx 下的子 span
+func (df *DefaultFunctionalDecorator) acquirePermission(ctx context.Context) (bool, *model.CallAborted, error) {
+	var attrs []attribute.KeyValue
+	if df.reqCtx != nil {
+		attrs = append(tracing.ServiceAttributes(df.reqCtx.Callee), tracing.AttrMethod.String(df.reqCtx.Method))
+	}
+	_, span := df.tracer.Start(ctx, tracing.SpanCircuitBreak, attrs...)
+	pass, aborted, err := df.invoke.AcquirePermission()
+	span.SetAttributes(tracing.AttrCircuitBreakPass.Bool(pass))
+	if aborted != nil {
+		span.SetAttributes(tracing.AttrCircuitBreakRule.String(aborted.GetRuleName()))
+	}
+	tracing.End(span, err)
+	return pass, aborted, err
+}
+
 type DefaultInvokeHandler struct {
 	flow   *CircuitBreakerFlow
 	reqCtx *model.RequestContext
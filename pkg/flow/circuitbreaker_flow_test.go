@@ -18,6 +18,7 @@
 package flow
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -25,6 +26,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/tracing"
+	"github.com/polarismesh/polaris-go/pkg/tracing/tracingtest"
 )
 
 // TestCircuitBreakerStatusToResult_OpenAlwaysReject 测试场景：Open 态总是返回 Pass=false
@@ -96,3 +99,58 @@ func TestCallAborted_OptionalFallback(t *testing.T) {
 	assert.Equal(t, "service unavailable", aborted.GetFallbackBody())
 	assert.Equal(t, "open", aborted.GetFallbackHeaders()["X-Cb"])
 }
+
+type rejectInvokeHandler struct {
+	errs int
+}
+
+func (h *rejectInvokeHandler) AcquirePermission() (bool, *model.CallAborted, error) {
+	return false, model.NewCallAborted(model.ErrorCallAborted, "rule-C", nil), nil
+}
+
+func (h *rejectInvokeHandler) OnSuccess(*model.ResponseContext) {}
+
+func (h *rejectInvokeHandler) OnError(*model.ResponseContext) {
+	h.errs++
+}
+
+// TestFunctionalDecorator_TraceCircuitBreakerCheck 测试场景：开启 tracing 后熔断检查被拒绝
+// 前置条件：InvokeHandler 固定返回 rule-C 的熔断拒绝
+// 预期结果：在调用方 span 下生成熔断检查子 span，记录被调服务、放行结果与命中规则
+func TestFunctionalDecorator_TraceCircuitBreakerCheck(t *testing.T) {
+	recorder := tracingtest.NewRecorder()
+	invoke := &rejectInvokeHandler{}
+	decorator := &DefaultFunctionalDecorator{
+		invoke: invoke,
+		customerFunc: func(context.Context, interface{}) (interface{}, error) {
+			t.Fatal("customer function should not be called")
+			return nil, nil
+		},
+		tracer: tracing.NewTracerWithProvider(recorder),
+		reqCtx: &model.RequestContext{
+			Callee: &model.ServiceKey{Namespace: "default", Service: "callee"},
+			Method: "/echo",
+		},
+	}
+	parentCtx, parent := recorder.Tracer("test").Start(context.Background(), "caller")
+
+	_, aborted, err := decorator.Decorator(parentCtx, nil)
+	assert.True(t, errors.Is(err, model.ErrorCallAborted))
+	assert.Equal(t, "rule-C", aborted.GetRuleName())
+	assert.Equal(t, 0, invoke.errs)
+
+	span := recorder.Find(tracing.SpanCircuitBreak)
+	if !assert.NotNil(t, span) {
+		return
+	}
+	assert.True(t, span.Ended())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	svc, _ := span.Attribute(tracing.AttrService)
+	assert.Equal(t, "callee", svc.AsString())
+	method, _ := span.Attribute(tracing.AttrMethod)
+	assert.Equal(t, "/echo", method.AsString())
+	pass, _ := span.Attribute(tracing.AttrCircuitBreakPass)
+	assert.False(t, pass.AsBool())
+	rule, _ := span.Attribute(tracing.AttrCircuitBreakRule)
+	assert.Equal(t, "rule-C", rule.AsString())
+}
@@ -0,0 +1,171 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package binding 提供配置文件到用户结构体的绑定能力，支持 yaml、json、properties、toml 格式，
+// 配置变更时原子替换绑定值并产生配置项级别的变更事件。
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Ensure configFileBinding implements model.ConfigFileBinding
+var _ model.ConfigFileBinding = (*configFileBinding)(nil)
+
+type configFileBinding struct {
+	file   model.ConfigFile
+	format string
+	// typ 绑定目标的元素类型
+	typ    reflect.Type
+	logger log.Logger
+	// value 当前绑定值，类型始终为 *typ
+	value atomic.Value
+	// lastErr 最近一次解析的错误，类型为 errHolder
+	lastErr atomic.Value
+	// updateLock 保证变更串行处理，flat 仅在持有该锁时访问
+	updateLock sync.Mutex
+	flat       map[string]interface{}
+	// listenerLock 保护 listeners
+	listenerLock sync.RWMutex
+	listeners    []model.OnConfigBindingChange
+}
+
+type errHolder struct {
+	err error
+}
+
+// Bind 将配置文件绑定到 req.Target，首次解析失败时返回错误，之后监听配置文件变更并原子替换绑定值
+func Bind(file model.ConfigFile, req *model.BindConfigFileRequest, logger log.Logger) (model.ConfigFileBinding, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	format, err := ResolveFormat(req.FileName, req.Format)
+	if err != nil {
+		return nil, err
+	}
+	target := reflect.ValueOf(req.Target)
+	binding := &configFileBinding{
+		file:   file,
+		format: format,
+		typ:    target.Type().Elem(),
+		logger: logger,
+	}
+	content := file.GetContent()
+	tree, err := parseTree(format, content)
+	if err == nil {
+		err = decode(format, content, tree, target)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bind config file %s/%s/%s: %v", req.Namespace, req.FileGroup, req.FileName, err)
+	}
+	binding.flat = flatten(tree)
+	binding.value.Store(req.Target)
+	binding.lastErr.Store(errHolder{})
+	// 首次绑定成功后才注册监听，失败时不会在配置文件上残留监听；
+	// 注册前发生的变更通过比较内容补偿处理
+	file.AddChangeListener(binding.onConfigFileChange)
+	if latest := file.GetContent(); latest != content {
+		binding.onConfigFileChange(model.ConfigFileChangeEvent{
+			ConfigFileMetadata: file,
+			OldValue:           content,
+			NewValue:           latest,
+			ChangeType:         model.Modified,
+		})
+	}
+	return binding, nil
+}
+
+// GetConfigFile 获取绑定的配置文件
+func (b *configFileBinding) GetConfigFile() model.ConfigFile {
+	return b.file
+}
+
+// Get 获取当前绑定值
+func (b *configFileBinding) Get() interface{} {
+	return b.value.Load()
+}
+
+// GetFormat 获取配置文件格式
+func (b *configFileBinding) GetFormat() string {
+	return b.format
+}
+
+// GetLastError 获取最近一次解析失败的错误
+func (b *configFileBinding) GetLastError() error {
+	if holder, ok := b.lastErr.Load().(errHolder); ok {
+		return holder.err
+	}
+	return nil
+}
+
+// AddChangeListener 增加绑定值变更监听器
+func (b *configFileBinding) AddChangeListener(cb model.OnConfigBindingChange) {
+	b.listenerLock.Lock()
+	defer b.listenerLock.Unlock()
+	b.listeners = append(b.listeners, cb)
+}
+
+// onConfigFileChange 配置文件变更回调，解析失败时保留上一次成功解析的值
+func (b *configFileBinding) onConfigFileChange(event model.ConfigFileChangeEvent) {
+	if event.ChangeType == model.NotChanged {
+		return
+	}
+	b.updateLock.Lock()
+	metadata := event.ConfigFileMetadata
+	tree, err := parseTree(b.format, event.NewValue)
+	newValue := reflect.New(b.typ)
+	if err == nil {
+		err = decode(b.format, event.NewValue, tree, newValue)
+	}
+	if err != nil {
+		b.lastErr.Store(errHolder{err: err})
+		b.updateLock.Unlock()
+		b.logger.Errorf("[Config] fail to bind config file %s/%s/%s, keep last good value: %v",
+			metadata.GetNamespace(), metadata.GetFileGroup(), metadata.GetFileName(), err)
+		return
+	}
+	newFlat := flatten(tree)
+	changes := diffKeys(b.flat, newFlat)
+	oldValue := b.value.Load()
+	b.flat = newFlat
+	b.value.Store(newValue.Interface())
+	b.lastErr.Store(errHolder{})
+	b.updateLock.Unlock()
+	if len(changes) == 0 {
+		return
+	}
+	b.logger.Infof("[Config] config file %s/%s/%s rebound, %d keys changed",
+		metadata.GetNamespace(), metadata.GetFileGroup(), metadata.GetFileName(), len(changes))
+	bindingEvent := &model.ConfigBindingChangeEvent{
+		ConfigFileMetadata: metadata,
+		OldValue:           oldValue,
+		NewValue:           newValue.Interface(),
+		Changes:            changes,
+	}
+	b.listenerLock.RLock()
+	listeners := b.listeners
+	b.listenerLock.RUnlock()
+	for _, listener := range listeners {
+		listener(bindingEvent)
+	}
+}
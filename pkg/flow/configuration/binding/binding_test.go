@@ -0,0 +1,297 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package binding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// fakeConfigFile 以内存内容实现 model.ConfigFile，通过 publish 触发变更回调
+type fakeConfigFile struct {
+	model.DefaultConfigFileMetadata
+	content   string
+	listeners []model.OnConfigFileChange
+}
+
+func (f *fakeConfigFile) GetLabels() map[string]string { return nil }
+func (f *fakeConfigFile) GetContent() string           { return f.content }
+func (f *fakeConfigFile) HasContent() bool             { return len(f.content) > 0 }
+func (f *fakeConfigFile) AddChangeListenerWithChannel() <-chan model.ConfigFileChangeEvent {
+	return nil
+}
+func (f *fakeConfigFile) GetPersistent() model.Persistent { return model.Persistent{} }
+func (f *fakeConfigFile) GetVersionName() string          { return "" }
+func (f *fakeConfigFile) GetVersion() uint64              { return 0 }
+func (f *fakeConfigFile) GetMd5() string                  { return "" }
+
+func (f *fakeConfigFile) AddChangeListener(cb model.OnConfigFileChange) {
+	f.listeners = append(f.listeners, cb)
+}
+
+func (f *fakeConfigFile) publish(content string) {
+	event := model.ConfigFileChangeEvent{
+		ConfigFileMetadata: &f.DefaultConfigFileMetadata,
+		OldValue:           f.content,
+		NewValue:           content,
+		ChangeType:         model.Modified,
+	}
+	f.content = content
+	for _, listener := range f.listeners {
+		listener(event)
+	}
+}
+
+func newFakeConfigFile(fileName, content string) *fakeConfigFile {
+	return &fakeConfigFile{
+		DefaultConfigFileMetadata: model.DefaultConfigFileMetadata{
+			Namespace: "default", FileGroup: "group", FileName: fileName},
+		content: content,
+	}
+}
+
+// noopLogger 测试中替代日志
+type noopLogger struct{}
+
+func (noopLogger) Tracef(string, ...interface{}) {}
+func (noopLogger) Debugf(string, ...interface{}) {}
+func (noopLogger) Infof(string, ...interface{})  {}
+func (noopLogger) Warnf(string, ...interface{})  {}
+func (noopLogger) Errorf(string, ...interface{}) {}
+func (noopLogger) Fatalf(string, ...interface{}) {}
+func (noopLogger) IsLevelEnabled(int) bool       { return false }
+func (noopLogger) SetLogLevel(int) error         { return nil }
+
+type serverConfig struct {
+	Server struct {
+		Host    string        `yaml:"host" json:"host"`
+		Port    int           `yaml:"port" json:"port"`
+		Timeout time.Duration `yaml:"timeout" json:"timeout"`
+	} `yaml:"server" json:"server"`
+	Features []string          `yaml:"features" json:"features"`
+	Labels   map[string]string `yaml:"labels" json:"labels"`
+	Debug    bool              `yaml:"debug" json:"debug"`
+}
+
+func newBindRequest(fileName string, target interface{}) *model.BindConfigFileRequest {
+	return &model.BindConfigFileRequest{Namespace: "default", FileGroup: "group", FileName: fileName, Target: target}
+}
+
+// TestBind_YAMLChangeEvents 验证 yaml 配置绑定与配置项变更事件
+// 测试场景：绑定 yaml 配置后依次发布修改、解析失败、恢复三个版本
+// 前置条件：首次内容合法
+// 预期结果：首次解析写入 Target；修改后 Get 返回新对象且旧对象不被修改，事件包含按 key 排序的 Added/Modified/Deleted；
+// 解析失败时保留上一次的值并记录错误、不触发事件；恢复后错误清除
+func TestBind_YAMLChangeEvents(t *testing.T) {
+	file := newFakeConfigFile("app.yaml", "server:\n  host: 127.0.0.1\n  port: 8080\nfeatures: [a, b]\ndebug: true\n")
+	target := &serverConfig{}
+	b, err := Bind(file, newBindRequest("app.yaml", target), noopLogger{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.ConfigFormatYAML, b.GetFormat())
+	assert.Same(t, target, b.Get())
+	assert.Equal(t, 8080, target.Server.Port)
+	assert.Equal(t, []string{"a", "b"}, target.Features)
+
+	var events []*model.ConfigBindingChangeEvent
+	b.AddChangeListener(func(event *model.ConfigBindingChangeEvent) {
+		events = append(events, event)
+	})
+
+	file.publish("server:\n  host: 127.0.0.1\n  port: 9090\n  timeout: 3s\nfeatures: [a, b]\n")
+	current := b.Get().(*serverConfig)
+	assert.Equal(t, 9090, current.Server.Port)
+	assert.Equal(t, 3*time.Second, current.Server.Timeout)
+	assert.False(t, current.Debug)
+	assert.Equal(t, 8080, target.Server.Port, "old value must not be mutated")
+	assert.Len(t, events, 1)
+	assert.Same(t, target, events[0].OldValue)
+	assert.Same(t, current, events[0].NewValue)
+	assert.Equal(t, []model.ConfigKeyChange{
+		{Key: "debug", OldValue: true, ChangeType: model.Deleted},
+		{Key: "server.port", OldValue: 8080, NewValue: 9090, ChangeType: model.Modified},
+		{Key: "server.timeout", NewValue: "3s", ChangeType: model.Added},
+	}, events[0].Changes)
+
+	file.publish("server: [broken")
+	assert.Same(t, current, b.Get())
+	assert.Error(t, b.GetLastError())
+	assert.Len(t, events, 1)
+
+	file.publish("server:\n  host: 127.0.0.1\n  port: 9090\n  timeout: 3s\nfeatures: [a, b]\n")
+	assert.NoError(t, b.GetLastError())
+	assert.Len(t, events, 1, "content equal to last good value must not emit key changes")
+}
+
+// TestBind_InvalidRequest 验证绑定参数及首次解析失败
+// 测试场景：Target 非指针、无法推断格式、不支持的格式、首次内容非法
+// 预期结果：均返回错误，且不在配置文件上残留监听
+func TestBind_InvalidRequest(t *testing.T) {
+	file := newFakeConfigFile("app.yaml", "port: [")
+	_, err := Bind(file, newBindRequest("app.yaml", serverConfig{}), noopLogger{})
+	assert.Error(t, err)
+	_, err = Bind(file, newBindRequest("app.conf", &serverConfig{}), noopLogger{})
+	assert.Error(t, err)
+	req := newBindRequest("app.conf", &serverConfig{})
+	req.Format = "xml"
+	_, err = Bind(file, req, noopLogger{})
+	assert.Error(t, err)
+	_, err = Bind(file, newBindRequest("app.yaml", &serverConfig{}), noopLogger{})
+	assert.Error(t, err)
+	assert.Empty(t, file.listeners)
+}
+
+// racingConfigFile 首次读取内容后即切换为新内容，模拟读取与注册监听之间发生的变更
+type racingConfigFile struct {
+	*fakeConfigFile
+	next string
+}
+
+func (f *racingConfigFile) GetContent() string {
+	content := f.fakeConfigFile.GetContent()
+	f.fakeConfigFile.content = f.next
+	return content
+}
+
+// TestBind_ChangeBeforeListen 验证首次解析与注册监听之间的变更不会丢失
+// 测试场景：首次读取内容后配置文件立即变为新版本，变更回调在注册监听前已经发出
+// 预期结果：Bind 返回的绑定值为新版本，且只注册一个监听
+func TestBind_ChangeBeforeListen(t *testing.T) {
+	file := &racingConfigFile{
+		fakeConfigFile: newFakeConfigFile("app.yaml", "server:\n  port: 8080\n"),
+		next:           "server:\n  port: 9090\n",
+	}
+	b, err := Bind(file, newBindRequest("app.yaml", &serverConfig{}), noopLogger{})
+	assert.NoError(t, err)
+	assert.Equal(t, 9090, b.Get().(*serverConfig).Server.Port)
+	assert.Len(t, file.listeners, 1)
+}
+
+// TestBind_JSONExplicitFormat 验证显式指定格式的 json 绑定
+// 测试场景：文件名无扩展名，显式指定 json 格式，随后删除配置文件
+// 预期结果：按 json 标签解析；删除后绑定值为零值，所有配置项产生 Deleted 事件
+func TestBind_JSONExplicitFormat(t *testing.T) {
+	file := newFakeConfigFile("app", `{"server":{"host":"h","port":80},"labels":{"env":"prod"}}`)
+	req := newBindRequest("app", &serverConfig{})
+	req.Format = "JSON"
+	b, err := Bind(file, req, noopLogger{})
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", b.Get().(*serverConfig).Labels["env"])
+
+	var changes []model.ConfigKeyChange
+	b.AddChangeListener(func(event *model.ConfigBindingChangeEvent) { changes = event.Changes })
+	file.publish("")
+	assert.Equal(t, serverConfig{}, *b.Get().(*serverConfig))
+	assert.Len(t, changes, 3)
+	for _, change := range changes {
+		assert.Equal(t, model.Deleted, change.ChangeType)
+	}
+}
+
+// TestBind_Properties 验证 properties 格式的解析与宽松类型映射
+// 测试场景：包含注释、续行、转义、逗号分隔列表、时长与布尔值
+// 预期结果：按点号展开映射到结构体，字符串按目标类型转换
+func TestBind_Properties(t *testing.T) {
+	content := "# comment\n! another\nserver.host = 127.0.0.1\nserver.port:8080\n" +
+		"server.timeout 1500ms\nfeatures=a, \\\n  b\nlabels.greeting=hello\\tworld\ndebug=true\n"
+	target := &serverConfig{}
+	b, err := Bind(newFakeConfigFile("app.properties", content), newBindRequest("app.properties", target), noopLogger{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.ConfigFormatProperties, b.GetFormat())
+	assert.Equal(t, "127.0.0.1", target.Server.Host)
+	assert.Equal(t, 8080, target.Server.Port)
+	assert.Equal(t, 1500*time.Millisecond, target.Server.Timeout)
+	assert.Equal(t, []string{"a", "b"}, target.Features)
+	assert.Equal(t, "hello\tworld", target.Labels["greeting"])
+	assert.True(t, target.Debug)
+
+	_, err = parseProperties("a=1\na.b=2\n")
+	assert.Error(t, err)
+	_, err = Bind(newFakeConfigFile("bad.properties", "server.port=abc\n"),
+		newBindRequest("bad.properties", &serverConfig{}), noopLogger{})
+	assert.Error(t, err)
+}
+
+type tomlConfig struct {
+	Title   string `toml:"title"`
+	Servers []struct {
+		Name string `toml:"name"`
+		Port uint16 `toml:"port"`
+	} `toml:"servers"`
+	Database struct {
+		Ports   []int             `toml:"ports"`
+		Enabled bool              `toml:"enabled"`
+		Ratio   float64           `toml:"ratio"`
+		Owner   map[string]string `toml:"owner"`
+	} `toml:"database"`
+	Note string `toml:"note"`
+}
+
+// TestBind_TOML 验证 toml 格式的解析
+// 测试场景：包含表、数组表、点号 key、内联表、多行数组与多行字符串
+// 预期结果：按 toml 标签映射到结构体；重复 key 等非法内容返回错误
+func TestBind_TOML(t *testing.T) {
+	content := `# sample
+title = "TOML \"demo\""
+note = """
+line1
+line2"""
+
+[database]
+ports = [
+  8000,
+  8001, # comment
+]
+enabled = true
+ratio = 1_000.5
+owner = { name = 'Tom', "dob" = 1979-05-27T07:32:00Z }
+
+[[servers]]
+name = "alpha"
+port = 0x1F90
+
+[[servers]]
+name = "beta"
+port = 8081
+`
+	target := &tomlConfig{}
+	_, err := Bind(newFakeConfigFile("app.toml", content), newBindRequest("app.toml", target), noopLogger{})
+	assert.NoError(t, err)
+	assert.Equal(t, `TOML "demo"`, target.Title)
+	assert.Equal(t, "line1\nline2", target.Note)
+	assert.Equal(t, []int{8000, 8001}, target.Database.Ports)
+	assert.True(t, target.Database.Enabled)
+	assert.Equal(t, 1000.5, target.Database.Ratio)
+	assert.Equal(t, map[string]string{"name": "Tom", "dob": "1979-05-27T07:32:00Z"}, target.Database.Owner)
+	assert.Len(t, target.Servers, 2)
+	assert.Equal(t, "alpha", target.Servers[0].Name)
+	assert.Equal(t, uint16(8080), target.Servers[0].Port)
+	assert.Equal(t, uint16(8081), target.Servers[1].Port)
+
+	tree, err := parseTOML("a.b.c = 1\nd = 1979-05-27\n[x]\ny = 'z'\n")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a.b.c": int64(1), "d": "1979-05-27", "x.y": "z"}, flatten(tree))
+
+	for _, invalid := range []string{"a = 1\na = 2\n", "a = \n", "[a\n", "a = \"open\n", "a = 1 b\n", "a = [1 2]\n"} {
+		_, err = parseTOML(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package binding
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// extToFormat 文件扩展名与格式的映射
+var extToFormat = map[string]string{
+	".yaml":       model.ConfigFormatYAML,
+	".yml":        model.ConfigFormatYAML,
+	".json":       model.ConfigFormatJSON,
+	".properties": model.ConfigFormatProperties,
+	".toml":       model.ConfigFormatTOML,
+}
+
+// ResolveFormat 确定配置文件格式，format 为空时按文件扩展名推断
+func ResolveFormat(fileName, format string) (string, error) {
+	if len(format) == 0 {
+		format = extToFormat[strings.ToLower(path.Ext(fileName))]
+		if len(format) == 0 {
+			return "", fmt.Errorf("can not detect format of config file %s, please specify it explicitly", fileName)
+		}
+		return format, nil
+	}
+	format = strings.ToLower(format)
+	switch format {
+	case model.ConfigFormatYAML, model.ConfigFormatJSON, model.ConfigFormatProperties, model.ConfigFormatTOML:
+		return format, nil
+	case "yml":
+		return model.ConfigFormatYAML, nil
+	}
+	return "", fmt.Errorf("unsupported config format %s", format)
+}
+
+// parseTree 将配置内容解析为以 map[string]interface{} 表示的树
+func parseTree(format, content string) (map[string]interface{}, error) {
+	if len(strings.TrimSpace(content)) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	switch format {
+	case model.ConfigFormatYAML:
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(content), &value); err != nil {
+			return nil, err
+		}
+		return toStringMap(normalizeYAML(value))
+	case model.ConfigFormatJSON:
+		var value interface{}
+		if err := json.Unmarshal([]byte(content), &value); err != nil {
+			return nil, err
+		}
+		return toStringMap(value)
+	case model.ConfigFormatProperties:
+		return parseProperties(content)
+	case model.ConfigFormatTOML:
+		return parseTOML(content)
+	}
+	return nil, fmt.Errorf("unsupported config format %s", format)
+}
+
+func toStringMap(value interface{}) (map[string]interface{}, error) {
+	if value == nil {
+		return map[string]interface{}{}, nil
+	}
+	tree, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("config content must be a map at top level, got %T", value)
+	}
+	return tree, nil
+}
+
+// normalizeYAML 将 yaml 解析出的 map[interface{}]interface{} 转换为 map[string]interface{}
+func normalizeYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			ret[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return ret
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeYAML(v[i])
+		}
+	}
+	return value
+}
+
+// decode 将配置内容解析到 target 指向的对象上，tree 为 parseTree 的结果
+func decode(format, content string, tree map[string]interface{}, target reflect.Value) error {
+	if len(strings.TrimSpace(content)) == 0 {
+		return nil
+	}
+	switch format {
+	case model.ConfigFormatYAML:
+		return yaml.Unmarshal([]byte(content), target.Interface())
+	case model.ConfigFormatJSON:
+		return json.Unmarshal([]byte(content), target.Interface())
+	default:
+		// properties 与 toml 没有标准库实现，基于解析出的树按字段标签宽松映射
+		return mapTree(tree, target.Elem(), format, "")
+	}
+}
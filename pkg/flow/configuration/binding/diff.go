@@ -0,0 +1,67 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package binding
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// flatten 将配置树展开为以点号分隔路径为 key 的叶子节点，数组整体作为一个叶子
+func flatten(tree map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{}, len(tree))
+	flattenInto(flat, "", tree)
+	return flat
+}
+
+func flattenInto(flat map[string]interface{}, prefix string, tree map[string]interface{}) {
+	for key, value := range tree {
+		path := joinPath(prefix, key)
+		if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+			flattenInto(flat, path, child)
+			continue
+		}
+		flat[path] = value
+	}
+}
+
+// diffKeys 对比新旧两份展开后的配置，返回按 key 排序的变更列表
+func diffKeys(oldFlat, newFlat map[string]interface{}) []model.ConfigKeyChange {
+	changes := make([]model.ConfigKeyChange, 0, 4)
+	for key, newValue := range newFlat {
+		oldValue, exists := oldFlat[key]
+		switch {
+		case !exists:
+			changes = append(changes, model.ConfigKeyChange{Key: key, NewValue: newValue, ChangeType: model.Added})
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, model.ConfigKeyChange{
+				Key: key, OldValue: oldValue, NewValue: newValue, ChangeType: model.Modified})
+		}
+	}
+	for key, oldValue := range oldFlat {
+		if _, exists := newFlat[key]; !exists {
+			changes = append(changes, model.ConfigKeyChange{Key: key, OldValue: oldValue, ChangeType: model.Deleted})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
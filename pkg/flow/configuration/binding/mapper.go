@@ -0,0 +1,286 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// mapTree 将解析出的配置值宽松地映射到 dst 上，字符串形式的标量会按目标类型转换。
+// 结构体字段名依次取 tagName、yaml、json 标签，均未配置时按字段名忽略大小写匹配。
+func mapTree(src interface{}, dst reflect.Value, tagName, path string) error {
+	if src == nil {
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return mapTree(src, dst.Elem(), tagName, path)
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			return fmt.Errorf("%s: can not bind to non-empty interface %s", displayPath(path), dst.Type())
+		}
+		dst.Set(reflect.ValueOf(src))
+		return nil
+	case reflect.Struct:
+		return mapStruct(src, dst, tagName, path)
+	case reflect.Map:
+		return mapMap(src, dst, tagName, path)
+	case reflect.Slice:
+		return mapSlice(src, dst, tagName, path)
+	}
+	return mapScalar(src, dst, path)
+}
+
+func mapStruct(src interface{}, dst reflect.Value, tagName, path string) error {
+	values, ok := src.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: can not bind %T to struct %s", displayPath(path), src, dst.Type())
+	}
+	typ := dst.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if len(field.PkgPath) != 0 {
+			continue
+		}
+		name, inline := fieldName(field, tagName)
+		if name == "-" {
+			continue
+		}
+		if inline || (field.Anonymous && !hasTag(field, tagName)) {
+			if err := mapTree(values, dst.Field(i), tagName, path); err != nil {
+				return err
+			}
+			continue
+		}
+		value, exists := lookupKey(values, name)
+		if !exists {
+			continue
+		}
+		if err := mapTree(value, dst.Field(i), tagName, joinPath(path, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mapMap(src interface{}, dst reflect.Value, tagName, path string) error {
+	values, ok := src.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: can not bind %T to map %s", displayPath(path), src, dst.Type())
+	}
+	typ := dst.Type()
+	if typ.Key().Kind() != reflect.String {
+		return fmt.Errorf("%s: map key of %s must be string", displayPath(path), typ)
+	}
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMapWithSize(typ, len(values)))
+	}
+	for key, value := range values {
+		elem := reflect.New(typ.Elem()).Elem()
+		if err := mapTree(value, elem, tagName, joinPath(path, key)); err != nil {
+			return err
+		}
+		dst.SetMapIndex(reflect.ValueOf(key).Convert(typ.Key()), elem)
+	}
+	return nil
+}
+
+func mapSlice(src interface{}, dst reflect.Value, tagName, path string) error {
+	var items []interface{}
+	switch v := src.(type) {
+	case []interface{}:
+		items = v
+	case string:
+		// properties 中的列表以逗号分隔
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); len(item) > 0 {
+				items = append(items, item)
+			}
+		}
+	default:
+		return fmt.Errorf("%s: can not bind %T to slice %s", displayPath(path), src, dst.Type())
+	}
+	slice := reflect.MakeSlice(dst.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := mapTree(item, slice.Index(i), tagName, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	dst.Set(slice)
+	return nil
+}
+
+func mapScalar(src interface{}, dst reflect.Value, path string) error {
+	var err error
+	switch dst.Kind() {
+	case reflect.String:
+		switch src.(type) {
+		case map[string]interface{}, []interface{}:
+			return fmt.Errorf("%s: can not bind %T to string", displayPath(path), src)
+		}
+		dst.SetString(fmt.Sprint(src))
+		return nil
+	case reflect.Bool:
+		var value bool
+		if value, err = toBool(src); err == nil {
+			dst.SetBool(value)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var value int64
+		if value, err = toInt(src, dst.Type()); err == nil {
+			if dst.OverflowInt(value) {
+				err = fmt.Errorf("value %d overflows %s", value, dst.Type())
+			} else {
+				dst.SetInt(value)
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var value int64
+		if value, err = toInt(src, dst.Type()); err == nil {
+			if value < 0 || dst.OverflowUint(uint64(value)) {
+				err = fmt.Errorf("value %d overflows %s", value, dst.Type())
+			} else {
+				dst.SetUint(uint64(value))
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		var value float64
+		if value, err = toFloat(src); err == nil {
+			dst.SetFloat(value)
+		}
+	default:
+		err = fmt.Errorf("unsupported type %s", dst.Type())
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", displayPath(path), err)
+	}
+	return nil
+}
+
+func toBool(src interface{}) (bool, error) {
+	switch v := src.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(strings.TrimSpace(v))
+	}
+	return false, fmt.Errorf("can not convert %T to bool", src)
+}
+
+func toInt(src interface{}, typ reflect.Type) (int64, error) {
+	switch v := src.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, fmt.Errorf("can not convert %v to integer", v)
+		}
+		return int64(v), nil
+	case string:
+		v = strings.TrimSpace(v)
+		if typ == durationType {
+			if duration, err := time.ParseDuration(v); err == nil {
+				return int64(duration), nil
+			}
+		}
+		return strconv.ParseInt(v, 0, 64)
+	}
+	return 0, fmt.Errorf("can not convert %T to integer", src)
+}
+
+func toFloat(src interface{}) (float64, error) {
+	switch v := src.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	}
+	return 0, fmt.Errorf("can not convert %T to float", src)
+}
+
+// fieldName 获取结构体字段对应的配置名及是否内联
+func fieldName(field reflect.StructField, tagName string) (string, bool) {
+	for _, name := range []string{tagName, "yaml", "json"} {
+		tag, ok := field.Tag.Lookup(name)
+		if !ok {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		inline := false
+		for _, opt := range parts[1:] {
+			if opt == "inline" || opt == "squash" {
+				inline = true
+			}
+		}
+		if len(parts[0]) > 0 || inline {
+			return parts[0], inline
+		}
+	}
+	return field.Name, false
+}
+
+func hasTag(field reflect.StructField, tagName string) bool {
+	for _, name := range []string{tagName, "yaml", "json"} {
+		if tag, ok := field.Tag.Lookup(name); ok && len(strings.Split(tag, ",")[0]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupKey 优先精确匹配，其次忽略大小写匹配
+func lookupKey(values map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := values[name]; ok {
+		return value, true
+	}
+	for key, value := range values {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func joinPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if len(path) == 0 {
+		return "<root>"
+	}
+	return path
+}
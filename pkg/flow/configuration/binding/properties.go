@@ -0,0 +1,138 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package binding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseProperties 解析 properties 内容，key 按点号展开为嵌套结构，值均为字符串
+func parseProperties(content string) (map[string]interface{}, error) {
+	tree := map[string]interface{}{}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if len(line) == 0 || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// 以奇数个反斜杠结尾表示续行
+		for endsWithContinuation(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		key, value := splitProperty(line)
+		key, value = unescapeProperty(key), unescapeProperty(value)
+		if err := setDotted(tree, key, value); err != nil {
+			return nil, err
+		}
+	}
+	return tree, nil
+}
+
+func endsWithContinuation(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// splitProperty 按第一个未转义的 =、: 或空白分隔 key 与 value
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':':
+			return strings.TrimRight(line[:i], " \t\f"), strings.TrimLeft(line[i+1:], " \t\f")
+		case ' ', '\t', '\f':
+			key := line[:i]
+			rest := strings.TrimLeft(line[i:], " \t\f")
+			if len(rest) > 0 && (rest[0] == '=' || rest[0] == ':') {
+				rest = strings.TrimLeft(rest[1:], " \t\f")
+			}
+			return key, rest
+		}
+	}
+	return line, ""
+}
+
+func unescapeProperty(value string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+	builder := strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c != '\\' || i+1 == len(value) {
+			builder.WriteByte(c)
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 't':
+			builder.WriteByte('\t')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			if i+4 < len(value) {
+				if r, err := strconv.ParseUint(value[i+1:i+5], 16, 32); err == nil {
+					builder.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			builder.WriteByte('u')
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+	return builder.String()
+}
+
+// setDotted 按点号分隔的 key 写入嵌套 map
+func setDotted(tree map[string]interface{}, key, value string) error {
+	parts := strings.Split(key, ".")
+	current := tree
+	for i, part := range parts[:len(parts)-1] {
+		next, exists := current[part]
+		if !exists {
+			child := map[string]interface{}{}
+			current[part] = child
+			current = child
+			continue
+		}
+		child, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("properties key %s conflicts with %s", key, strings.Join(parts[:i+1], "."))
+		}
+		current = child
+	}
+	last := parts[len(parts)-1]
+	if existing, ok := current[last].(map[string]interface{}); ok && len(existing) > 0 {
+		return fmt.Errorf("properties key %s conflicts with nested keys under it", key)
+	}
+	current[last] = value
+	return nil
+}
@@ -0,0 +1,66 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package binding
+
+import (
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlLocalLayouts toml 本地日期时间（时区名）对应的输出格式，带偏移的日期时间按 RFC3339 输出
+var tomlLocalLayouts = map[string]string{
+	"datetime-local": "2006-01-02T15:04:05.999999999",
+	"date-local":     "2006-01-02",
+	"time-local":     "15:04:05.999999999",
+}
+
+// parseTOML 解析 toml 内容，日期时间以字符串形式保留，数组表转换为 []interface{} 以便统一映射
+func parseTOML(content string) (map[string]interface{}, error) {
+	tree := map[string]interface{}{}
+	if _, err := toml.Decode(content, &tree); err != nil {
+		return nil, err
+	}
+	normalizeTOML(tree)
+	return tree, nil
+}
+
+// normalizeTOML 将 toml 解析出的值转换为与 yaml、json 一致的表示
+func normalizeTOML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeTOML(item)
+		}
+	case []map[string]interface{}:
+		ret := make([]interface{}, len(v))
+		for i, item := range v {
+			ret[i] = normalizeTOML(item)
+		}
+		return ret
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeTOML(v[i])
+		}
+	case time.Time:
+		if layout, ok := tomlLocalLayouts[v.Location().String()]; ok {
+			return v.Format(layout)
+		}
+		return v.Format(time.RFC3339Nano)
+	}
+	return value
+}
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -34,6 +35,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
 	"github.com/polarismesh/polaris-go/pkg/plugin/events"
 	"github.com/polarismesh/polaris-go/pkg/sdk"
+	"github.com/polarismesh/polaris-go/pkg/tracing"
 )
 
 // ConfigFileFlow 配置中心核心服务门面类
@@ -61,6 +63,8 @@ type ConfigFileFlow struct {
 	startLongPollingTaskOnce sync.Once
 
 	eventReporterChain []events.EventReporter
+	// 链路追踪
+	tracer *tracing.Tracer
 }
 
 // NewConfigFileFlow 创建配置中心服务
@@ -93,6 +97,7 @@ func NewConfigFileFlow(globalCtx sdk.ValueContext, connector configconnector.Con
 		shardLockCount:     16, // 使用16个分段锁
 		shardLocks:         make([]sync.RWMutex, 16),
 		fclock:             sync.RWMutex{}, // 初始化全局锁
+		tracer:             tracing.NewTracer(conf.GetGlobal().GetTracing()),
 	}
 
 	return configFileService, nil
@@ -107,6 +112,26 @@ func (c *ConfigFileFlow) Destroy() {
 
 // GetConfigFile 获取配置文件
 func (c *ConfigFileFlow) GetConfigFile(req *model.GetConfigFileRequest) (model.ConfigFile, error) {
+	ctx, span := c.tracer.Start(req.GetContext(), tracing.SpanGetConfigFile,
+		configFileAttributes(req.Namespace, req.FileGroup, req.FileName)...)
+	configFile, cacheHit, err := c.getConfigFile(ctx, req)
+	span.SetAttributes(tracing.AttrCacheHit.Bool(cacheHit))
+	tracing.End(span, err)
+	return configFile, err
+}
+
+// configFileAttributes 配置文件对应的 span 属性
+func configFileAttributes(namespace, fileGroup, fileName string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{tracing.AttrNamespace.String(namespace), tracing.AttrConfigFileGroup.String(fileGroup)}
+	if fileName != "" {
+		attrs = append(attrs, tracing.AttrConfigFileName.String(fileName))
+	}
+	return attrs
+}
+
+// getConfigFile 优先读取已订阅的配置文件缓存，未命中时从服务端拉取
+func (c *ConfigFileFlow) getConfigFile(ctx context.Context,
+	req *model.GetConfigFileRequest) (model.ConfigFile, bool, error) {
 	configFileMetadata := &model.DefaultConfigFileMetadata{
 		Namespace: req.Namespace,
 		FileGroup: req.FileGroup,
@@ -122,7 +147,7 @@ func (c *ConfigFileFlow) GetConfigFile(req *model.GetConfigFileRequest) (model.C
 			c.logCtx.GetBaseLogger().Debugf("[ConfigFileFlow] 命中配置文件缓存. file=%s/%s/%s",
 				req.Namespace, req.FileGroup, req.FileName)
 		}
-		return configFile.(model.ConfigFile), nil
+		return configFile.(model.ConfigFile), true, nil
 	}
 
 	c.logCtx.GetBaseLogger().Infof("[ConfigFileFlow] 配置文件缓存未命中，开始创建. file=%s/%s/%s, subscribe=%v",
@@ -134,13 +159,13 @@ func (c *ConfigFileFlow) GetConfigFile(req *model.GetConfigFileRequest) (model.C
 
 	// double check
 	if configFile, ok := c.configFileCache.Load(cacheKey); ok {
-		return configFile.(model.ConfigFile), nil
+		return configFile.(model.ConfigFile), true, nil
 	}
 
-	fileRepo, err := newConfigFileRepo(c.globalCtx, configFileMetadata, c.connector, c.chain, c.conf, c.persistHandler,
-		c.eventReporterChain)
+	fileRepo, err := newConfigFileRepo(ctx, c.globalCtx, configFileMetadata, c.connector, c.chain,
+		c.conf, c.persistHandler, c.eventReporterChain)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	configFile := newDefaultConfigFile(configFileMetadata, fileRepo)
 
@@ -154,7 +179,7 @@ func (c *ConfigFileFlow) GetConfigFile(req *model.GetConfigFileRequest) (model.C
 		c.logCtx.GetBaseLogger().Infof("[ConfigFileFlow] 配置文件已订阅并加入长轮询池. file=%s/%s/%s, version=%d",
 			req.Namespace, req.FileGroup, req.FileName, fileRepo.getVersion())
 	}
-	return configFile, nil
+	return configFile, false, nil
 }
 
 // CreateConfigFile 创建配置文件
@@ -109,6 +109,22 @@ func (m *MockConnector) GetConfigGroup(req *configconnector.ConfigGroup) (*confi
 	return &configconnector.ConfigGroupResponse{}, nil
 }
 
+func (m *MockConnector) DeleteConfigFile(configFile *configconnector.ConfigFile, operator string) (*configconnector.ConfigFileResponse, error) {
+	return &configconnector.ConfigFileResponse{}, nil
+}
+
+func (m *MockConnector) ListConfigFiles(req *configconnector.ConfigFileListRequest) (*configconnector.ConfigFileRecordsResponse, error) {
+	return &configconnector.ConfigFileRecordsResponse{}, nil
+}
+
+func (m *MockConnector) GetConfigFileReleaseHistory(req *configconnector.ConfigFileReleaseHistoryRequest) (*configconnector.ConfigFileRecordsResponse, error) {
+	return &configconnector.ConfigFileRecordsResponse{}, nil
+}
+
+func (m *MockConnector) RollbackConfigFile(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	return &configconnector.ConfigFileResponse{}, nil
+}
+
 // TestConfigFileCacheConcurrency 测试configFileCache的并发安全性
 func TestConfigFileCacheConcurrency(t *testing.T) {
 	connector := &MockConnector{}
@@ -0,0 +1,232 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+// configTimeLayout 服务端返回的时间格式
+const configTimeLayout = "2006-01-02 15:04:05"
+
+// DeleteConfigFile 删除配置文件
+func (c *ConfigFileFlow) DeleteConfigFile(req *model.DeleteConfigFileRequest) error {
+	if err := req.Validate(); err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "")
+	}
+	configFile := &configconnector.ConfigFile{
+		Namespace: req.Namespace,
+		FileGroup: req.FileGroup,
+		FileName:  req.FileName,
+	}
+
+	cacheKey := genCacheKey(req.Namespace, req.FileGroup, req.FileName)
+	c.getShardLock(cacheKey)
+	defer c.getShardUnlock(cacheKey)
+
+	resp, err := c.connector.DeleteConfigFile(configFile, req.Operator)
+	if err != nil {
+		return err
+	}
+	return c.checkManageResponse("delete", req.Namespace, req.FileGroup, req.FileName, resp.GetCode(), resp.GetMessage())
+}
+
+// ListConfigFiles 查询分组下的配置文件元数据列表
+func (c *ConfigFileFlow) ListConfigFiles(req *model.ListConfigFilesRequest) (*model.ListConfigFilesResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "")
+	}
+	resp, err := c.connector.ListConfigFiles(&configconnector.ConfigFileListRequest{
+		Namespace: req.Namespace,
+		FileGroup: req.FileGroup,
+		FileName:  req.FileName,
+		Offset:    req.Offset,
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkManageResponse("list", req.Namespace, req.FileGroup, req.FileName,
+		resp.GetCode(), resp.GetMessage()); err != nil {
+		return nil, err
+	}
+	ret := &model.ListConfigFilesResponse{
+		Total: resp.Total,
+		Files: make([]*model.ConfigFileInfo, 0, len(resp.Records)),
+	}
+	for _, record := range resp.Records {
+		ret.Files = append(ret.Files, &model.ConfigFileInfo{
+			Namespace:   record.GetNamespace(),
+			FileGroup:   record.GetFileGroup(),
+			FileName:    record.GetFileName(),
+			Format:      record.Format,
+			Comment:     record.Comment,
+			Status:      record.Status,
+			Labels:      userLabels(record.ConfigFile),
+			Encrypted:   record.GetEncrypted(),
+			EncryptAlgo: record.EncryptAlgo,
+			CreateTime:  parseConfigTime(record.CreateTime),
+			CreateBy:    record.CreateBy,
+			ModifyTime:  parseConfigTime(record.ModifyTime),
+			ModifyBy:    record.ModifyBy,
+			ReleaseTime: parseConfigTime(record.ReleaseTime),
+			ReleaseBy:   record.ReleaseBy,
+		})
+	}
+	return ret, nil
+}
+
+// GetConfigFileReleaseHistory 查询配置文件发布历史，每条记录的内容都经过 configFilter 链处理，加密配置返回明文
+func (c *ConfigFileFlow) GetConfigFileReleaseHistory(
+	req *model.GetConfigFileReleaseHistoryRequest) (*model.GetConfigFileReleaseHistoryResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "")
+	}
+	resp, err := c.connector.GetConfigFileReleaseHistory(&configconnector.ConfigFileReleaseHistoryRequest{
+		Namespace: req.Namespace,
+		FileGroup: req.FileGroup,
+		FileName:  req.FileName,
+		Offset:    req.Offset,
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkManageResponse("get release history of", req.Namespace, req.FileGroup, req.FileName,
+		resp.GetCode(), resp.GetMessage()); err != nil {
+		return nil, err
+	}
+	ret := &model.GetConfigFileReleaseHistoryResponse{
+		Total:     resp.Total,
+		Histories: make([]*model.ConfigFileReleaseHistory, 0, len(resp.Records)),
+	}
+	for _, record := range resp.Records {
+		content, err := c.filterRecordContent(record)
+		if err != nil {
+			return nil, model.NewSDKError(model.ErrCodeInternalError, err,
+				"fail to decode release %s of config file %s/%s/%s", record.GetVersionName(),
+				req.Namespace, req.FileGroup, req.FileName)
+		}
+		ret.Histories = append(ret.Histories, &model.ConfigFileReleaseHistory{
+			ID:                 record.ID,
+			ReleaseName:        record.GetVersionName(),
+			Namespace:          record.GetNamespace(),
+			FileGroup:          record.GetFileGroup(),
+			FileName:           record.GetFileName(),
+			Content:            content,
+			Format:             record.Format,
+			Comment:            record.Comment,
+			Md5:                record.GetMd5(),
+			Type:               record.Type,
+			Status:             record.Status,
+			Reason:             record.Reason,
+			ReleaseDescription: record.ReleaseDescription,
+			Labels:             userLabels(record.ConfigFile),
+			Encrypted:          record.GetEncrypted(),
+			CreateTime:         parseConfigTime(record.CreateTime),
+			CreateBy:           record.CreateBy,
+			ModifyTime:         parseConfigTime(record.ModifyTime),
+			ModifyBy:           record.ModifyBy,
+		})
+	}
+	return ret, nil
+}
+
+// RollbackConfigFile 回滚配置文件到指定发布版本
+func (c *ConfigFileFlow) RollbackConfigFile(req *model.RollbackConfigFileRequest) error {
+	if err := req.Validate(); err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "")
+	}
+	configFile := &configconnector.ConfigFile{
+		Namespace:   req.Namespace,
+		FileGroup:   req.FileGroup,
+		FileName:    req.FileName,
+		VersionName: req.ReleaseName,
+	}
+
+	cacheKey := genCacheKey(req.Namespace, req.FileGroup, req.FileName)
+	c.getShardLock(cacheKey)
+	defer c.getShardUnlock(cacheKey)
+
+	resp, err := c.connector.RollbackConfigFile(configFile)
+	if err != nil {
+		return err
+	}
+	return c.checkManageResponse("rollback", req.Namespace, req.FileGroup, req.FileName, resp.GetCode(), resp.GetMessage())
+}
+
+// filterRecordContent 返回发布记录的明文内容，加密记录使用记录中的明文数据密钥与加密算法直接解密
+func (c *ConfigFileFlow) filterRecordContent(record *configconnector.ConfigFileRecord) (string, error) {
+	if !record.GetEncrypted() {
+		if record.GetContent() != "" {
+			return record.GetContent(), nil
+		}
+		return record.GetSourceContent(), nil
+	}
+	dataKey := record.GetDataKey()
+	if dataKey == "" {
+		return "", errors.New("encrypted release has no data key")
+	}
+	return c.chain.Decrypt(record.GetSourceContent(), dataKey, record.GetEncryptAlgo())
+}
+
+// checkManageResponse 校验配置管理类操作的服务端应答码
+func (c *ConfigFileFlow) checkManageResponse(op, namespace, fileGroup, fileName string,
+	responseCode uint32, responseMessage string) error {
+	if responseCode == uint32(apimodel.Code_ExecuteSuccess) {
+		return nil
+	}
+	c.logCtx.GetBaseLogger().Infof("[ConfigFileFlow] failed to %s config file. namespace = %s, fileGroup = %s, "+
+		"fileName = %s, response code = %d, msg:%v", op, namespace, fileGroup, fileName, responseCode, responseMessage)
+	errMsg := fmt.Sprintf("failed to %s config file. namespace = %s, fileGroup = %s, fileName = %s, "+
+		"response code = %d, msg:%v", op, namespace, fileGroup, fileName, responseCode, responseMessage)
+	return model.NewSDKErrorWithServerInfo(model.ErrCodeInternalError, nil, responseCode, responseMessage, "%s", errMsg)
+}
+
+// userLabels 返回去除加密相关内部 tag 后的标签，避免数据密钥出现在管理接口的返回值中
+func userLabels(configFile *configconnector.ConfigFile) map[string]string {
+	labels := make(map[string]string, len(configFile.Tags))
+	for _, tag := range configFile.Tags {
+		switch tag.Key {
+		case configconnector.ConfigFileTagKeyUseEncrypted, configconnector.ConfigFileTagKeyDataKey,
+			configconnector.ConfigFileTagKeyEncryptAlgo:
+			continue
+		}
+		labels[tag.Key] = tag.Value
+	}
+	return labels
+}
+
+// parseConfigTime 解析服务端返回的时间，为空或格式不符时返回零值
+func parseConfigTime(value string) time.Time {
+	if len(value) == 0 {
+		return time.Time{}
+	}
+	t, err := time.Parse(configTimeLayout, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
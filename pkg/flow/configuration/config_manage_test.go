@@ -0,0 +1,247 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
+	"github.com/polarismesh/polaris-go/pkg/plugin/events"
+	"github.com/polarismesh/polaris-go/pkg/sdk"
+	"github.com/polarismesh/polaris-go/plugin/configfilter/crypto"
+	"github.com/polarismesh/polaris-go/plugin/configfilter/crypto/aes"
+)
+
+// manageConnector 模拟配置管理类操作的连接器，记录请求并返回预置应答
+type manageConnector struct {
+	MockConnector
+	code         uint32
+	records      []*configconnector.ConfigFileRecord
+	deleted      *configconnector.ConfigFile
+	operator     string
+	rollbacked   *configconnector.ConfigFile
+	listRequest  *configconnector.ConfigFileListRequest
+	historyTotal uint32
+}
+
+func (m *manageConnector) DeleteConfigFile(configFile *configconnector.ConfigFile,
+	operator string) (*configconnector.ConfigFileResponse, error) {
+	m.deleted, m.operator = configFile, operator
+	return &configconnector.ConfigFileResponse{Code: m.code, Message: "msg", ConfigFile: configFile}, nil
+}
+
+func (m *manageConnector) ListConfigFiles(
+	req *configconnector.ConfigFileListRequest) (*configconnector.ConfigFileRecordsResponse, error) {
+	m.listRequest = req
+	return &configconnector.ConfigFileRecordsResponse{Code: m.code, Total: uint32(len(m.records)), Records: m.records}, nil
+}
+
+func (m *manageConnector) GetConfigFileReleaseHistory(
+	req *configconnector.ConfigFileReleaseHistoryRequest) (*configconnector.ConfigFileRecordsResponse, error) {
+	return &configconnector.ConfigFileRecordsResponse{Code: m.code, Total: m.historyTotal, Records: m.records}, nil
+}
+
+func (m *manageConnector) RollbackConfigFile(
+	configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	m.rollbacked = configFile
+	return &configconnector.ConfigFileResponse{Code: m.code, ConfigFile: configFile}, nil
+}
+
+// base64Filter 模拟加密过滤器：加密配置的内容为 base64 编码，由过滤器解码后写入 content
+type base64Filter struct {
+	MockConnector
+	calls int
+}
+
+func (f *base64Filter) DoFilter(configFile *configconnector.ConfigFile,
+	next configfilter.ConfigFileHandleFunc) configfilter.ConfigFileHandleFunc {
+	return func(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+		f.calls++
+		resp, err := next(configFile)
+		if err != nil || !resp.GetConfigFile().GetEncrypted() {
+			return resp, err
+		}
+		plain, err := base64.StdEncoding.DecodeString(resp.GetConfigFile().GetSourceContent())
+		if err != nil {
+			return nil, err
+		}
+		resp.GetConfigFile().SetContent(string(plain))
+		return resp, nil
+	}
+}
+
+func newManageFlow(t *testing.T, connector configconnector.ConfigConnector, chain configfilter.Chain) *ConfigFileFlow {
+	flow, err := NewConfigFileFlow(sdk.NewValueContext(), connector, chain,
+		config.NewDefaultConfiguration([]string{"127.0.0.1:8091"}), []events.EventReporter{})
+	assert.NoError(t, err)
+	t.Cleanup(flow.Destroy)
+	return flow
+}
+
+// TestConfigFileFlowDeleteAndRollback 测试删除与回滚配置文件
+// 测试场景：删除、回滚成功以及服务端返回失败码
+// 前置条件：连接器按预置应答码返回
+// 预期结果：请求参数透传到连接器，失败码转换为携带服务端信息的 SDKError，非法参数直接返回参数错误
+func TestConfigFileFlowDeleteAndRollback(t *testing.T) {
+	connector := &manageConnector{code: uint32(apimodel.Code_ExecuteSuccess)}
+	flow := newManageFlow(t, connector, configfilter.Chain{})
+
+	err := flow.DeleteConfigFile(&model.DeleteConfigFileRequest{
+		Namespace: "ns", FileGroup: "group", FileName: "app.yaml", Operator: "ops"})
+	assert.NoError(t, err)
+	assert.Equal(t, "app.yaml", connector.deleted.GetFileName())
+	assert.Equal(t, "ops", connector.operator)
+
+	err = flow.RollbackConfigFile(&model.RollbackConfigFileRequest{
+		Namespace: "ns", FileGroup: "group", FileName: "app.yaml", ReleaseName: "release-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "release-1", connector.rollbacked.GetVersionName())
+
+	err = flow.RollbackConfigFile(&model.RollbackConfigFileRequest{Namespace: "ns", FileGroup: "group", FileName: "a"})
+	assert.Equal(t, model.ErrCodeAPIInvalidArgument, err.(model.SDKError).ErrorCode())
+	err = flow.DeleteConfigFile(nil)
+	assert.Equal(t, model.ErrCodeAPIInvalidArgument, err.(model.SDKError).ErrorCode())
+
+	connector.code = uint32(apimodel.Code_NotFoundResource)
+	err = flow.DeleteConfigFile(&model.DeleteConfigFileRequest{Namespace: "ns", FileGroup: "group", FileName: "a"})
+	assert.Error(t, err)
+	sdkErr := err.(model.SDKError)
+	assert.Equal(t, model.ErrCodeInternalError, sdkErr.ErrorCode())
+	assert.Equal(t, uint32(apimodel.Code_NotFoundResource), sdkErr.ServerCode())
+}
+
+// TestConfigFileFlowListConfigFiles 测试查询配置文件列表
+// 测试场景：查询分组下的文件元数据
+// 前置条件：连接器返回带内部加密 tag 的文件记录
+// 预期结果：分页默认值生效，时间被解析，加密相关内部 tag 不出现在标签中
+func TestConfigFileFlowListConfigFiles(t *testing.T) {
+	connector := &manageConnector{
+		code: uint32(apimodel.Code_ExecuteSuccess),
+		records: []*configconnector.ConfigFileRecord{{
+			ConfigFile: &configconnector.ConfigFile{
+				Namespace: "ns", FileGroup: "group", FileName: "app.yaml", Encrypted: true,
+				Tags: []*configconnector.ConfigFileTag{
+					{Key: "env", Value: "prod"},
+					{Key: configconnector.ConfigFileTagKeyDataKey, Value: "secret"},
+				},
+			},
+			Format:     "yaml",
+			CreateTime: "2023-01-02 03:04:05",
+			ModifyTime: "invalid",
+		}},
+	}
+	flow := newManageFlow(t, connector, configfilter.Chain{})
+
+	resp, err := flow.ListConfigFiles(&model.ListConfigFilesRequest{Namespace: "ns", FileGroup: "group"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(model.DefaultConfigFileQueryLimit), connector.listRequest.Limit)
+	assert.Equal(t, uint32(1), resp.Total)
+	file := resp.Files[0]
+	assert.Equal(t, "yaml", file.Format)
+	assert.True(t, file.Encrypted)
+	assert.Equal(t, map[string]string{"env": "prod"}, file.Labels)
+	assert.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), file.CreateTime)
+	assert.True(t, file.ModifyTime.IsZero())
+
+	_, err = flow.ListConfigFiles(&model.ListConfigFilesRequest{Namespace: "ns", FileGroup: "group", Limit: 101})
+	assert.Equal(t, model.ErrCodeAPIInvalidArgument, err.(model.SDKError).ErrorCode())
+}
+
+// newCryptoFilter 创建使用默认配置（AES）初始化的加密过滤器
+func newCryptoFilter(t *testing.T) *crypto.CryptoFilter {
+	filter := &crypto.CryptoFilter{}
+	err := filter.Init(&plugin.InitContext{
+		Config:   config.NewDefaultConfiguration([]string{"127.0.0.1:8091"}),
+		ValueCtx: sdk.NewValueContext(),
+	})
+	assert.NoError(t, err)
+	return filter
+}
+
+// TestConfigFileFlowReleaseHistory 测试查询发布历史
+// 测试场景：发布历史同时包含明文与加密配置，加密记录分别携带与缺失数据密钥
+// 前置条件：过滤链中配置真实的加密过滤器，加密记录的 tag 中携带明文数据密钥与加密算法
+// 预期结果：加密记录使用数据密钥直接解密，明文记录返回原始内容；缺失数据密钥时立即返回错误
+func TestConfigFileFlowReleaseHistory(t *testing.T) {
+	filter := newCryptoFilter(t)
+	cryptor, err := filter.GetCrypto(aes.AES)
+	assert.NoError(t, err)
+	dataKey, err := cryptor.GenerateKey()
+	assert.NoError(t, err)
+	cipherContent, err := cryptor.Encrypt("plain", dataKey)
+	assert.NoError(t, err)
+
+	connector := &manageConnector{
+		code:         uint32(apimodel.Code_ExecuteSuccess),
+		historyTotal: 5,
+		records: []*configconnector.ConfigFileRecord{
+			{
+				ConfigFile: &configconnector.ConfigFile{Namespace: "ns", FileGroup: "group", FileName: "app.yaml",
+					VersionName: "release-2", SourceContent: cipherContent, Encrypted: true,
+					Tags: []*configconnector.ConfigFileTag{
+						{Key: configconnector.ConfigFileTagKeyUseEncrypted, Value: "true"},
+						{Key: configconnector.ConfigFileTagKeyDataKey, Value: base64.StdEncoding.EncodeToString(dataKey)},
+						{Key: configconnector.ConfigFileTagKeyEncryptAlgo, Value: aes.AES},
+					}},
+				ID:   2,
+				Type: "normal",
+			},
+			{
+				ConfigFile: &configconnector.ConfigFile{Namespace: "ns", FileGroup: "group", FileName: "app.yaml",
+					VersionName: "release-1", SourceContent: "origin"},
+				ID: 1,
+			},
+		},
+	}
+	flow := newManageFlow(t, connector, configfilter.Chain{filter})
+
+	resp, err := flow.GetConfigFileReleaseHistory(&model.GetConfigFileReleaseHistoryRequest{
+		Namespace: "ns", FileGroup: "group", FileName: "app.yaml"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), resp.Total)
+	assert.Len(t, resp.Histories, 2)
+	assert.Equal(t, "release-2", resp.Histories[0].ReleaseName)
+	assert.Equal(t, "plain", resp.Histories[0].Content)
+	assert.True(t, resp.Histories[0].Encrypted)
+	assert.Equal(t, map[string]string{}, resp.Histories[0].Labels)
+	assert.Equal(t, "origin", resp.Histories[1].Content)
+
+	// 缺失数据密钥时不再重新请求数据密钥，直接返回错误
+	connector.records[0].Tags = connector.records[0].Tags[:1]
+	_, err = flow.GetConfigFileReleaseHistory(&model.GetConfigFileReleaseHistoryRequest{
+		Namespace: "ns", FileGroup: "group", FileName: "app.yaml"})
+	assert.Error(t, err)
+
+	// 过滤链中没有加密过滤器时，加密记录无法解密
+	flow = newManageFlow(t, connector, configfilter.Chain{})
+	connector.records[0].Tags = append(connector.records[0].Tags,
+		&configconnector.ConfigFileTag{Key: configconnector.ConfigFileTagKeyDataKey,
+			Value: base64.StdEncoding.EncodeToString(dataKey)})
+	_, err = flow.GetConfigFileReleaseHistory(&model.GetConfigFileReleaseHistoryRequest{
+		Namespace: "ns", FileGroup: "group", FileName: "app.yaml"})
+	assert.Error(t, err)
+}
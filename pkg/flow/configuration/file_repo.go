@@ -18,6 +18,7 @@
 package configuration
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"sync/atomic"
@@ -72,8 +73,8 @@ type ConfigFileRepo struct {
 // ConfigFileRepoChangeListener 远程配置文件发布监听器
 type ConfigFileRepoChangeListener func(configFileMetadata model.ConfigFileMetadata, newContent string, persistent model.Persistent) error
 
-// newConfigFileRepo 创建远程配置文件
-func newConfigFileRepo(globalCtx sdk.ValueContext, metadata model.ConfigFileMetadata,
+// newConfigFileRepo 创建远程配置文件，reqCtx 为调用方的请求上下文，仅作用于首次同步拉取
+func newConfigFileRepo(reqCtx context.Context, globalCtx sdk.ValueContext, metadata model.ConfigFileMetadata,
 	connector configconnector.ConfigConnector,
 	chain configfilter.Chain,
 	conf config.Configuration,
@@ -109,7 +110,7 @@ func newConfigFileRepo(globalCtx sdk.ValueContext, metadata model.ConfigFileMeta
 		metadata.GetFileMode(), initVersion)
 
 	// 1. 同步从服务端拉取配置
-	if err := repo.pull(); err != nil {
+	if err := repo.pullWithContext(reqCtx); err != nil {
 		repo.logCtx.GetBaseLogger().Errorf("[Config][FileRepo] 初始拉取配置失败. file=%s/%s/%s, err=%v",
 			metadata.GetNamespace(), metadata.GetFileGroup(), metadata.GetFileName(), err)
 		return nil, err
@@ -169,6 +170,11 @@ func (r *ConfigFileRepo) getDataKey() string {
 }
 
 func (r *ConfigFileRepo) pull() error {
+	return r.pullWithContext(context.Background())
+}
+
+// pullWithContext 拉取配置文件，reqCtx 结束后不再重试
+func (r *ConfigFileRepo) pullWithContext(reqCtx context.Context) error {
 	pullStartTime := time.Now()
 
 	if r.logCtx.GetBaseLogger().IsLevelEnabled(log.DebugLog) {
@@ -185,6 +191,7 @@ func (r *ConfigFileRepo) pull() error {
 		Mode:      r.configFileMetadata.GetFileMode(),
 		Tags:      make([]*configconnector.ConfigFileTag, 0, len(r.conf.GetGlobal().GetClient().GetLabels())),
 	}
+	pullConfigFileReq.SetContext(reqCtx)
 	for k, v := range r.conf.GetGlobal().GetClient().GetLabels() {
 		pullConfigFileReq.Tags = append(pullConfigFileReq.Tags, &configconnector.ConfigFileTag{
 			Key:   k,
@@ -202,6 +209,10 @@ func (r *ConfigFileRepo) pull() error {
 		response   *configconnector.ConfigFileResponse
 	)
 	for retryTimes < 3 {
+		if reqCtx.Err() != nil {
+			return model.NewContextError(reqCtx, "pull config file %s/%s/%s canceled after %d retry times",
+				pullConfigFileReq.Namespace, pullConfigFileReq.FileGroup, pullConfigFileReq.FileName, retryTimes)
+		}
 		startTime := time.Now()
 
 		// 执行过滤器链和网络请求
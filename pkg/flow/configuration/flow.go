@@ -37,7 +37,7 @@ func NewConfigFlow(globalCtx sdk.ValueContext, connector configconnector.ConfigC
 	if err != nil {
 		return nil, err
 	}
-	groupFlow, err := newConfigGroupFlow(globalCtx, connector, configuration)
+	groupFlow, err := newConfigGroupFlow(globalCtx, connector, chain, configuration)
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +26,9 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
 	"github.com/polarismesh/polaris-go/pkg/sdk"
+	"github.com/polarismesh/polaris-go/pkg/tracing"
 )
 
 // inFlightEntry 记录正在进行中的分组仓库创建，确保相同 key 的并发请求
@@ -50,24 +52,28 @@ type ConfigGroupFlow struct {
 	inFlight map[string]*inFlightEntry
 
 	connector     configconnector.ConfigConnector
+	chain         configfilter.Chain
 	configuration config.Configuration
 	logCtx        *log.ContextLogger
 	globalCtx     sdk.ValueContext
+	tracer        *tracing.Tracer
 }
 
 func newConfigGroupFlow(globalCtx sdk.ValueContext, connector configconnector.ConfigConnector,
-	configuration config.Configuration) (*ConfigGroupFlow, error) {
+	chain configfilter.Chain, configuration config.Configuration) (*ConfigGroupFlow, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	groupFlow := &ConfigGroupFlow{
 		cancel:        cancel,
 		connector:     connector,
+		chain:         chain,
 		configuration: configuration,
 		repos:         map[string]*ConfigGroupRepo{},
 		groupCache:    map[string]model.ConfigFileGroup{},
 		inFlight:      map[string]*inFlightEntry{},
 		globalCtx:     globalCtx,
 		logCtx:        globalCtx.GetContextLogger(),
+		tracer:        tracing.NewTracer(configuration.GetGlobal().GetTracing()),
 	}
 
 	go groupFlow.doSync(ctx)
@@ -75,11 +81,22 @@ func newConfigGroupFlow(globalCtx sdk.ValueContext, connector configconnector.Co
 }
 
 func (flow *ConfigGroupFlow) GetConfigGroup(namespace, fileGroup string) (model.ConfigFileGroup, error) {
-	return flow.getOrCreateGroup(namespace, fileGroup, model.SDKMode)
+	return flow.getConfigGroup(namespace, fileGroup, model.SDKMode)
 }
 
 func (flow *ConfigGroupFlow) GetConfigGroupWithReq(req *model.GetConfigGroupRequest) (model.ConfigFileGroup, error) {
-	return flow.getOrCreateGroup(req.Namespace, req.FileGroup, req.Mode)
+	return flow.getConfigGroup(req.Namespace, req.FileGroup, req.Mode)
+}
+
+// getConfigGroup 获取配置分组，获取过程记录为 span
+func (flow *ConfigGroupFlow) getConfigGroup(namespace, fileGroup string,
+	mode model.GetConfigFileRequestMode) (model.ConfigFileGroup, error) {
+	_, span := flow.tracer.Start(context.Background(), tracing.SpanGetConfigGroup,
+		configFileAttributes(namespace, fileGroup, "")...)
+	cg, cacheHit, err := flow.getOrCreateGroup(namespace, fileGroup, mode)
+	span.SetAttributes(tracing.AttrCacheHit.Bool(cacheHit))
+	tracing.End(span, err)
+	return cg, err
 }
 
 // getOrCreateGroup 是 GetConfigGroup 和 GetConfigGroupWithReq 的统一实现。
@@ -88,7 +105,7 @@ func (flow *ConfigGroupFlow) GetConfigGroupWithReq(req *model.GetConfigGroupRequ
 //   - 相同 key 只发起一次 gRPC 调用（通过 inFlight map 实现 singleflight 语义）。
 //   - fclock 写锁仅用于 map 读写（纳秒级），gRPC 网络调用在锁外执行。
 func (flow *ConfigGroupFlow) getOrCreateGroup(namespace, fileGroup string,
-	mode model.GetConfigFileRequestMode) (model.ConfigFileGroup, error) {
+	mode model.GetConfigFileRequestMode) (model.ConfigFileGroup, bool, error) {
 	cacheKey := namespace + "@" + fileGroup
 
 	// 快速路径：缓存命中（仅读锁）。
@@ -100,7 +117,7 @@ func (flow *ConfigGroupFlow) getOrCreateGroup(namespace, fileGroup string,
 			flow.logCtx.GetBaseLogger().Debugf("[Config][GroupFlow] 命中配置分组缓存. namespace=%s, group=%s",
 				namespace, fileGroup)
 		}
-		return cg, nil
+		return cg, true, nil
 	}
 
 	flow.logCtx.GetBaseLogger().Infof("[Config][GroupFlow] 配置分组缓存未命中，开始创建(WithReq). namespace=%s, group=%s, mode=%v",
@@ -111,14 +128,14 @@ func (flow *ConfigGroupFlow) getOrCreateGroup(namespace, fileGroup string,
 	// 获取写锁后二次检查缓存。
 	if cg, ok = flow.groupCache[cacheKey]; ok {
 		flow.fclock.Unlock()
-		return cg, nil
+		return cg, true, nil
 	}
 	// 检查是否有其他 goroutine 正在创建同一个 key。
 	if entry, exists := flow.inFlight[cacheKey]; exists {
 		flow.fclock.Unlock()
 		// 等待进行中的创建完成。
 		<-entry.done
-		return entry.cg, entry.err
+		return entry.cg, false, entry.err
 	}
 	// 将自己注册为该 key 的创建者。
 	entry := &inFlightEntry{done: make(chan struct{})}
@@ -151,7 +168,7 @@ func (flow *ConfigGroupFlow) getOrCreateGroup(namespace, fileGroup string,
 		flow.fclock.Unlock()
 	}
 
-	return entry.cg, entry.err
+	return entry.cg, false, entry.err
 }
 
 // doSync 定时同步所有已知的配置分组仓库。
@@ -0,0 +1,275 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+// WatchConfigGroup 监听配置分组，返回维护分组内文件内容快照的监听器，变更时自动拉取变更文件的内容
+func (flow *ConfigGroupFlow) WatchConfigGroup(req *model.WatchConfigGroupRequest) (model.ConfigGroupWatcher, error) {
+	if err := req.Validate(); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "")
+	}
+	cg, _, err := flow.getOrCreateGroup(req.Namespace, req.FileGroup, req.Mode)
+	if err != nil {
+		return nil, err
+	}
+	repo := cg.(*defaultConfigGroup).repo
+	watcher := &configGroupWatcher{
+		namespace: req.Namespace,
+		group:     req.FileGroup,
+		files:     map[string]*model.ConfigGroupFile{},
+		logCtx:    flow.logCtx,
+		fetch: func(fileName string) (string, error) {
+			return flow.fetchGroupFile(req.Namespace, req.FileGroup, fileName, req.Mode)
+		},
+	}
+	watcher.updateLock.Lock()
+	_, err = watcher.sync(repo.loadRemoteGroup(), true)
+	watcher.updateLock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	// 初始快照完成后才注册监听，注册前发生的变更由随后的补偿同步处理
+	repo.AddChangeListener(watcher.onGroupChange)
+	watcher.onGroupChange(nil, repo.loadRemoteGroup())
+	return watcher, nil
+}
+
+// fetchGroupFile 拉取分组内单个文件的内容，经过 configFilter 链处理以支持加密配置
+func (flow *ConfigGroupFlow) fetchGroupFile(namespace, fileGroup, fileName string,
+	mode model.GetConfigFileRequestMode) (string, error) {
+	req := &configconnector.ConfigFile{
+		Namespace: namespace,
+		FileGroup: fileGroup,
+		FileName:  fileName,
+		Mode:      mode,
+	}
+	resp, err := flow.chain.Execute(req, flow.connector.GetConfigFile, flow.logCtx)
+	if err != nil {
+		return "", err
+	}
+	if resp.GetCode() != uint32(apimodel.Code_ExecuteSuccess) {
+		return "", fmt.Errorf("fetch config file %s/%s/%s with unexpect code %d, msg: %s",
+			namespace, fileGroup, fileName, resp.GetCode(), resp.GetMessage())
+	}
+	if resp.GetConfigFile().GetContent() == "" {
+		return resp.GetConfigFile().GetSourceContent(), nil
+	}
+	return resp.GetConfigFile().GetContent(), nil
+}
+
+// configGroupWatcher 配置分组监听器实现
+type configGroupWatcher struct {
+	namespace string
+	group     string
+	fetch     func(fileName string) (string, error)
+	logCtx    *log.ContextLogger
+
+	// updateLock 串行化快照同步，保证事件顺序与快照一致
+	updateLock sync.Mutex
+
+	lock          sync.RWMutex
+	revision      string
+	files         map[string]*model.ConfigGroupFile
+	listeners     []model.OnConfigGroupFilesChange
+	listenerChans []chan *model.ConfigGroupFilesChangeEvent
+}
+
+// GetNamespace 获取命名空间
+func (w *configGroupWatcher) GetNamespace() string {
+	return w.namespace
+}
+
+// GetFileGroup 获取配置分组
+func (w *configGroupWatcher) GetFileGroup() string {
+	return w.group
+}
+
+// GetRevision 获取当前快照对应的分组版本
+func (w *configGroupWatcher) GetRevision() string {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.revision
+}
+
+// GetFiles 获取分组内所有文件及其内容
+func (w *configGroupWatcher) GetFiles() []*model.ConfigGroupFile {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	files := make([]*model.ConfigGroupFile, 0, len(w.files))
+	for _, file := range w.files {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].FileName < files[j].FileName
+	})
+	return files
+}
+
+// GetFile 获取分组内指定文件
+func (w *configGroupWatcher) GetFile(fileName string) (*model.ConfigGroupFile, bool) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	file, ok := w.files[fileName]
+	return file, ok
+}
+
+// AddChangeListener 增加文件级变更监听器
+func (w *configGroupWatcher) AddChangeListener(cb model.OnConfigGroupFilesChange) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.listeners = append(w.listeners, cb)
+}
+
+// AddChangeListenerWithChannel 增加文件级变更监听器
+func (w *configGroupWatcher) AddChangeListenerWithChannel() <-chan *model.ConfigGroupFilesChangeEvent {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	changeChan := make(chan *model.ConfigGroupFilesChangeEvent, 64)
+	w.listenerChans = append(w.listenerChans, changeChan)
+	return changeChan
+}
+
+// onGroupChange 分组仓库的变更回调，与本地快照比较得到文件级变更，不依赖仓库传入的旧值
+func (w *configGroupWatcher) onGroupChange(_ *configconnector.ConfigGroupResponse,
+	newVal *configconnector.ConfigGroupResponse) {
+	w.updateLock.Lock()
+	defer w.updateLock.Unlock()
+	event, _ := w.sync(newVal, false)
+	if event == nil {
+		return
+	}
+	w.logCtx.GetBaseLogger().Infof("[Config][GroupWatcher] 配置分组文件变更. namespace=%s, group=%s, "+
+		"oldRevision=%s, newRevision=%s, changeCount=%d", w.namespace, w.group, event.OldRevision,
+		event.NewRevision, len(event.Changes))
+
+	w.lock.RLock()
+	listeners := w.listeners
+	listenerChans := w.listenerChans
+	w.lock.RUnlock()
+	for _, listenerChan := range listenerChans {
+		listenerChan <- event
+	}
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// sync 将分组最新的文件列表同步到本地快照，拉取新增与修改文件的内容，返回文件级变更事件（无变更时返回 nil）
+// 初始化时任一文件拉取失败即返回错误；变更时拉取失败的文件记录在变更的 Err 中，本地快照保留旧值以便下次变更时重试
+func (w *configGroupWatcher) sync(newVal *configconnector.ConfigGroupResponse,
+	initial bool) (*model.ConfigGroupFilesChangeEvent, error) {
+	var (
+		newRevision string
+		newFiles    = map[string]*model.SimpleConfigFile{}
+	)
+	if newVal != nil {
+		newRevision = newVal.Revision
+		for _, file := range newVal.ReleaseFiles {
+			newFiles[file.FileName] = file
+		}
+	}
+
+	w.lock.RLock()
+	oldRevision := w.revision
+	oldFiles := make(map[string]*model.ConfigGroupFile, len(w.files))
+	for name, file := range w.files {
+		oldFiles[name] = file
+	}
+	w.lock.RUnlock()
+
+	var changes []*model.ConfigGroupFileChange
+	snapshot := make(map[string]*model.ConfigGroupFile, len(newFiles))
+	for name, file := range newFiles {
+		oldFile, exist := oldFiles[name]
+		if exist && oldFile.Version == file.Version && oldFile.Md5 == file.Md5 {
+			snapshot[name] = oldFile
+			continue
+		}
+		change := &model.ConfigGroupFileChange{
+			FileName:   name,
+			ChangeType: model.Added,
+			NewVersion: file.Version,
+			NewMd5:     file.Md5,
+		}
+		if exist {
+			change.ChangeType = model.Modified
+			change.OldVersion = oldFile.Version
+			change.OldMd5 = oldFile.Md5
+			change.OldValue = oldFile.Content
+		}
+		content, err := w.fetch(name)
+		if err != nil {
+			if initial {
+				return nil, model.NewSDKError(model.ErrCodeInternalError, err,
+					"fail to fetch config file %s/%s/%s", w.namespace, w.group, name)
+			}
+			w.logCtx.GetBaseLogger().Errorf("[Config][GroupWatcher] 拉取变更文件内容失败. namespace=%s, group=%s, "+
+				"file=%s, err=%v", w.namespace, w.group, name, err)
+			change.Err = err
+			if exist {
+				snapshot[name] = oldFile
+			}
+		} else {
+			change.NewValue = content
+			snapshot[name] = &model.ConfigGroupFile{SimpleConfigFile: file, Content: content}
+		}
+		changes = append(changes, change)
+	}
+	for name, oldFile := range oldFiles {
+		if _, exist := newFiles[name]; exist {
+			continue
+		}
+		changes = append(changes, &model.ConfigGroupFileChange{
+			FileName:   name,
+			ChangeType: model.Deleted,
+			OldVersion: oldFile.Version,
+			OldMd5:     oldFile.Md5,
+			OldValue:   oldFile.Content,
+		})
+	}
+
+	w.lock.Lock()
+	w.revision = newRevision
+	w.files = snapshot
+	w.lock.Unlock()
+
+	if initial || len(changes) == 0 {
+		return nil, nil
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].FileName < changes[j].FileName
+	})
+	return &model.ConfigGroupFilesChangeEvent{
+		Namespace:   w.namespace,
+		FileGroup:   w.group,
+		OldRevision: oldRevision,
+		NewRevision: newRevision,
+		Changes:     changes,
+	}, nil
+}
@@ -0,0 +1,229 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"encoding/base64"
+	"sync"
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
+	"github.com/polarismesh/polaris-go/pkg/sdk"
+)
+
+// groupFile 模拟服务端分组内的文件
+type groupFile struct {
+	version   uint64
+	content   string
+	encrypted bool
+	fail      bool
+}
+
+// groupConnector 模拟配置分组与配置文件查询的连接器
+type groupConnector struct {
+	MockConnector
+	lock     sync.Mutex
+	revision string
+	files    map[string]*groupFile
+	fetched  []string
+}
+
+func (g *groupConnector) set(revision string, files map[string]*groupFile) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.revision, g.files = revision, files
+}
+
+func (g *groupConnector) GetConfigGroup(req *configconnector.ConfigGroup) (*configconnector.ConfigGroupResponse, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	resp := &configconnector.ConfigGroupResponse{
+		Code:      uint32(apimodel.Code_ExecuteSuccess),
+		Namespace: req.Namespace,
+		Group:     req.Group,
+		Revision:  g.revision,
+	}
+	for name, file := range g.files {
+		resp.ReleaseFiles = append(resp.ReleaseFiles, &model.SimpleConfigFile{
+			Namespace: req.Namespace, FileGroup: req.Group, FileName: name, Version: file.version,
+			Md5: file.content,
+		})
+	}
+	return resp, nil
+}
+
+func (g *groupConnector) GetConfigFile(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.fetched = append(g.fetched, configFile.FileName)
+	file, ok := g.files[configFile.FileName]
+	if !ok || file.fail {
+		return &configconnector.ConfigFileResponse{Code: uint32(apimodel.Code_ExecuteException), ConfigFile: configFile}, nil
+	}
+	resp := &configconnector.ConfigFile{
+		Namespace:     configFile.Namespace,
+		FileGroup:     configFile.FileGroup,
+		FileName:      configFile.FileName,
+		Version:       file.version,
+		SourceContent: file.content,
+		Encrypted:     file.encrypted,
+	}
+	if file.encrypted {
+		resp.SourceContent = base64.StdEncoding.EncodeToString([]byte(file.content))
+	}
+	return &configconnector.ConfigFileResponse{Code: uint32(apimodel.Code_ExecuteSuccess), ConfigFile: resp}, nil
+}
+
+func (g *groupConnector) takeFetched() []string {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	fetched := g.fetched
+	g.fetched = nil
+	return fetched
+}
+
+func newTestGroupFlow(t *testing.T, connector configconnector.ConfigConnector) *ConfigGroupFlow {
+	flow, err := newConfigGroupFlow(sdk.NewValueContext(), connector, configfilter.Chain{&base64Filter{}},
+		config.NewDefaultConfiguration([]string{"127.0.0.1:8091"}))
+	assert.NoError(t, err)
+	t.Cleanup(flow.cancel)
+	return flow
+}
+
+func pullGroup(t *testing.T, flow *ConfigGroupFlow, namespace, group string) {
+	flow.fclock.RLock()
+	repo := flow.repos[namespace+"@"+group]
+	flow.fclock.RUnlock()
+	assert.NoError(t, repo.pull())
+}
+
+// TestWatchConfigGroup 测试配置分组文件级监听
+// 测试场景：分组内文件新增、修改、删除
+// 前置条件：模拟连接器返回分组文件列表与文件内容，其中一个文件为加密文件
+// 预期结果：初始快照包含所有文件内容（加密文件经过过滤链解密），变更事件只拉取变更文件并按文件名给出变更类型与版本
+func TestWatchConfigGroup(t *testing.T) {
+	connector := &groupConnector{}
+	connector.set("r1", map[string]*groupFile{
+		"a.yaml": {version: 1, content: "a1"},
+		"b.yaml": {version: 1, content: "b1", encrypted: true},
+	})
+	flow := newTestGroupFlow(t, connector)
+
+	watcher, err := flow.WatchConfigGroup(&model.WatchConfigGroupRequest{Namespace: "ns", FileGroup: "group"})
+	assert.NoError(t, err)
+	assert.Equal(t, "r1", watcher.GetRevision())
+	files := watcher.GetFiles()
+	assert.Len(t, files, 2)
+	assert.Equal(t, "a1", files[0].Content)
+	assert.Equal(t, "b1", files[1].Content)
+	assert.ElementsMatch(t, []string{"a.yaml", "b.yaml"}, connector.takeFetched())
+
+	// 后台定时同步也可能触发变更，回调结果通过 channel 收集
+	events := make(chan *model.ConfigGroupFilesChangeEvent, 8)
+	watcher.AddChangeListener(func(event *model.ConfigGroupFilesChangeEvent) {
+		events <- event
+	})
+	changeChan := watcher.AddChangeListenerWithChannel()
+
+	connector.set("r2", map[string]*groupFile{
+		"a.yaml": {version: 2, content: "a2"},
+		"c.yaml": {version: 1, content: "c1"},
+	})
+	pullGroup(t, flow, "ns", "group")
+
+	event := <-changeChan
+	assert.Equal(t, event, <-events)
+	assert.Equal(t, "r1", event.OldRevision)
+	assert.Equal(t, "r2", event.NewRevision)
+	assert.Len(t, event.Changes, 3)
+	assert.Equal(t, &model.ConfigGroupFileChange{FileName: "a.yaml", ChangeType: model.Modified, OldVersion: 1,
+		NewVersion: 2, OldMd5: "a1", NewMd5: "a2", OldValue: "a1", NewValue: "a2"}, event.Changes[0])
+	assert.Equal(t, &model.ConfigGroupFileChange{FileName: "b.yaml", ChangeType: model.Deleted, OldVersion: 1,
+		OldMd5: "b1", OldValue: "b1"}, event.Changes[1])
+	assert.Equal(t, model.Added, event.Changes[2].ChangeType)
+	assert.Equal(t, "c1", event.Changes[2].NewValue)
+	assert.Len(t, event.GetChanges(model.Added), 1)
+	assert.ElementsMatch(t, []string{"a.yaml", "c.yaml"}, connector.takeFetched())
+
+	_, ok := watcher.GetFile("b.yaml")
+	assert.False(t, ok)
+	file, ok := watcher.GetFile("a.yaml")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), file.Version)
+
+	// 版本未变化时不产生事件
+	pullGroup(t, flow, "ns", "group")
+	assert.Len(t, events, 0)
+	assert.Len(t, changeChan, 0)
+}
+
+// TestWatchConfigGroupFetchFailure 测试变更文件内容拉取失败
+// 测试场景：新增文件的内容拉取失败，随后分组再次变更
+// 前置条件：模拟连接器对新增文件返回异常码
+// 预期结果：变更事件中携带错误且快照不包含该文件，下一次分组变更时重新拉取并作为新增文件通知
+func TestWatchConfigGroupFetchFailure(t *testing.T) {
+	connector := &groupConnector{}
+	connector.set("r1", map[string]*groupFile{"a.yaml": {version: 1, content: "a1"}})
+	flow := newTestGroupFlow(t, connector)
+	watcher, err := flow.WatchConfigGroup(&model.WatchConfigGroupRequest{Namespace: "ns", FileGroup: "group"})
+	assert.NoError(t, err)
+	changeChan := watcher.AddChangeListenerWithChannel()
+
+	connector.set("r2", map[string]*groupFile{
+		"a.yaml": {version: 1, content: "a1"},
+		"d.yaml": {version: 1, content: "d1", fail: true},
+	})
+	pullGroup(t, flow, "ns", "group")
+	event := <-changeChan
+	assert.Len(t, event.Changes, 1)
+	assert.Error(t, event.Changes[0].Err)
+	_, ok := watcher.GetFile("d.yaml")
+	assert.False(t, ok)
+
+	connector.set("r3", map[string]*groupFile{
+		"a.yaml": {version: 1, content: "a1"},
+		"d.yaml": {version: 1, content: "d1"},
+	})
+	pullGroup(t, flow, "ns", "group")
+	event = <-changeChan
+	assert.Len(t, event.Changes, 1)
+	assert.Equal(t, model.Added, event.Changes[0].ChangeType)
+	assert.Equal(t, "d1", event.Changes[0].NewValue)
+	assert.NoError(t, event.Changes[0].Err)
+}
+
+// TestWatchConfigGroupInitFailure 测试初始化失败
+// 测试场景：初始快照中的文件内容拉取失败、请求参数非法
+// 前置条件：模拟连接器对分组内文件返回异常码
+// 预期结果：WatchConfigGroup 返回错误
+func TestWatchConfigGroupInitFailure(t *testing.T) {
+	connector := &groupConnector{}
+	connector.set("r1", map[string]*groupFile{"a.yaml": {version: 1, content: "a1", fail: true}})
+	flow := newTestGroupFlow(t, connector)
+	_, err := flow.WatchConfigGroup(&model.WatchConfigGroupRequest{Namespace: "ns", FileGroup: "group"})
+	assert.Error(t, err)
+
+	_, err = flow.WatchConfigGroup(&model.WatchConfigGroupRequest{Namespace: "ns"})
+	assert.Equal(t, model.ErrCodeAPIInvalidArgument, err.(model.SDKError).ErrorCode())
+}
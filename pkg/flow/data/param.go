@@ -52,6 +52,7 @@ func BuildControlParam(
 		param.MaxRetry = *provider.GetRetryCountPtr()
 	}
 	param.RetryInterval = cfg.GetGlobal().GetAPI().GetRetryInterval()
+	param.Context = model.GetContextOf(provider)
 	if !reflect2.IsNil(provider) {
 		provider.SetTimeout(param.Timeout)
 		provider.SetRetryCount(param.MaxRetry)
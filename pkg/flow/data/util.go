@@ -19,7 +19,6 @@ package data
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/clock"
 	"github.com/polarismesh/polaris-go/pkg/config"
@@ -238,6 +237,24 @@ func GetLoadBalancerByLbType(lbType string, supplier plugin.Supplier) (loadbalan
 	return targetPlugin.(loadbalancer.LoadBalancer), nil
 }
 
+// GetCallResultListeners 获取需要感知调用结果的负载均衡插件，
+// 各插件代理只处理由自身选出的实例的调用结果
+func GetCallResultListeners(supplier plugin.Supplier) []loadbalancer.CallResultListener {
+	plugins, err := supplier.GetPlugins(common.TypeLoadBalancer)
+	if err != nil {
+		return nil
+	}
+	listeners := make([]loadbalancer.CallResultListener, 0)
+	for _, plug := range plugins {
+		proxy, ok := plug.(*loadbalancer.Proxy)
+		if !ok || !proxy.IsCallResultListener() {
+			continue
+		}
+		listeners = append(listeners, proxy)
+	}
+	return listeners
+}
+
 // SingleInvoke 同步调用的通用方法定义
 type SingleInvoke func(request interface{}) (interface{}, error)
 
@@ -263,7 +280,10 @@ func RetrySyncCall(name string, svcKey *model.ServiceKey,
 		if retryTimes >= param.MaxRetry {
 			break
 		}
-		time.Sleep(retryInterval)
+		if ctxErr := model.SleepWithContext(param.GetContext(), retryInterval); ctxErr != nil {
+			return resp, model.NewSDKError(model.ErrCodeAPITimeoutError, ctxErr,
+				fmt.Sprintf("request context done while retrying %s after %v times", name, retryTimes))
+		}
 		logCtx.GetBaseLogger().Warnf("retry %s for timeout, consume time %v,"+
 			" Namespace: %s, Service: %s, retry times: %d",
 			name, consumeTime, svcKey.Namespace, svcKey.Service, retryTimes)
@@ -49,6 +49,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
 	"github.com/polarismesh/polaris-go/pkg/plugin/weightadjuster"
 	"github.com/polarismesh/polaris-go/pkg/sdk"
+	"github.com/polarismesh/polaris-go/pkg/tracing"
 )
 
 // Engine 编排调度引擎，API相关逻辑在这里执行
@@ -73,6 +74,8 @@ type Engine struct {
 	lossless lossless.Lossless
 	// 负载均衡器
 	loadbalancer loadbalancer.LoadBalancer
+	// 需要感知调用结果的负载均衡插件
+	callResultListeners []loadbalancer.CallResultListener
 	// 限流处理协助辅助类
 	flowQuotaAssistant *quota.FlowQuotaAssistant
 	// 全局上下文，在reportclient
@@ -102,6 +105,8 @@ type Engine struct {
 	authenticators []authenticator.Authenticator
 	// 本端已注册实例的 metadata 表，用于鉴权 CALLEE_METADATA 取值
 	localMetadata *localMetadataStore
+	// 链路追踪，未启用时创建的 span 均为 no-op
+	tracer *tracing.Tracer
 }
 
 // InitFlowEngine 初始化flowEngine实例
@@ -113,6 +118,7 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 	globalCtx := initContext.ValueCtx
 	flowEngine.configuration = cfg
 	flowEngine.plugins = plugins
+	flowEngine.tracer = tracing.NewTracer(cfg.GetGlobal().GetTracing())
 	// 加载服务端连接器
 	flowEngine.connector, err = data.GetServerConnector(cfg, plugins)
 	if err != nil {
@@ -157,6 +163,7 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 	if err != nil {
 		return err
 	}
+	flowEngine.callResultListeners = data.GetCallResultListeners(plugins)
 
 	// 加载服务路由链插件
 	err = flowEngine.LoadFlowRouteChain()
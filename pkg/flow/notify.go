@@ -72,10 +72,18 @@ func (s *SingleNotifyContext) Err() model.SDKError {
 
 // Wait notify 异步任务执行回调函数
 func (s *SingleNotifyContext) Wait(timeout time.Duration) bool {
+	return s.WaitWithContext(context.Background(), timeout)
+}
+
+// WaitWithContext 等待异步任务回调，请求上下文结束时提前返回
+// 返回值，是否超时（包括请求上下文结束）
+func (s *SingleNotifyContext) WaitWithContext(reqCtx context.Context, timeout time.Duration) bool {
 	afterTimer := time.After(timeout)
 	select {
 	case <-afterTimer:
 		return true
+	case <-reqCtx.Done():
+		return true
 	case <-s.notifier.GetContext().Done():
 		s.logCtx.GetBaseLogger().Debugf("context %s has been notified", *s.name)
 		return false
@@ -131,6 +139,12 @@ func (c *CombineNotifyContext) logNotifier(operation string, notifier *SingleNot
 // Wait notify 异步任务执行回调函数
 // 返回值，是否超时
 func (c *CombineNotifyContext) Wait(timeout time.Duration) (exceedTime bool) {
+	return c.WaitWithContext(context.Background(), timeout)
+}
+
+// WaitWithContext 等待所有的子回调返回，请求上下文结束时提前返回
+// 返回值，是否超时（包括请求上下文结束）
+func (c *CombineNotifyContext) WaitWithContext(reqCtx context.Context, timeout time.Duration) (exceedTime bool) {
 	var restWait = atomic.LoadInt32(&c.waitCount)
 	if restWait == 0 {
 		return false
@@ -160,6 +174,8 @@ func (c *CombineNotifyContext) Wait(timeout time.Duration) (exceedTime bool) {
 			select {
 			case <-afterTimer:
 				return
+			case <-reqCtx.Done():
+				return
 			case <-notifier.notifier.GetContext().Done():
 				doneKeyChan <- notifier.name.Operation
 				nextWait := atomic.AddInt32(&c.waitCount, -1)
@@ -74,7 +74,9 @@ type FlowQuotaAssistant struct {
 
 	remoteNamespace string
 	remoteService   string
-	logCtx          *log.ContextLogger
+	// 是否以演练模式执行所有限流规则
+	dryRun bool
+	logCtx *log.ContextLogger
 }
 
 // AsyncRateLimitConnector 异步限流连接器
@@ -146,6 +148,7 @@ func (f *FlowQuotaAssistant) Init(engine sdk.Engine, cfg config.Configuration, s
 	f.purgeIntervalMilli = model.ToMilliSeconds(cfg.GetProvider().GetRateLimit().GetPurgeInterval())
 	f.remoteNamespace = cfg.GetProvider().GetRateLimit().GetLimiterNamespace()
 	f.remoteService = cfg.GetProvider().GetRateLimit().GetLimiterService()
+	f.dryRun = cfg.GetProvider().GetRateLimit().IsDryRun()
 	f.mutex = &sync.Mutex{}
 	f.svcToWindowSet = &sync.Map{}
 	return nil
@@ -263,14 +266,24 @@ func (f *FlowQuotaAssistant) GetQuota(commonRequest *data.CommonRateLimitRequest
 	}
 	var maxWaitMs int64 = 0
 	var allReleaseFuncs []func()
+	var dryRunRules []*apitraffic.Rule
 	for _, window := range windows {
 		window.Init()
 		quotaResult := window.AllocateQuota(commonRequest)
+		if window.dryRun {
+			// 演练模式只记录本应限流的规则，不拦截也不排队；并发配额照常登记，保证计数可归还
+			if quotaResult.Code == model.QuotaResultLimited {
+				dryRunRules = append(dryRunRules, window.Rule)
+			}
+			allReleaseFuncs = append(allReleaseFuncs, quotaResult.GetReleaseFuncs()...)
+			continue
+		}
 		if quotaResult.Code == model.QuotaResultLimited {
 			// 限流场景下填充命中的规则信息，业务侧可读取 CustomResponse 等字段。
 			// 不在 bucket.GetQuota 内部写入 ActiveRule，是为了让 bucket 层只关注令牌计算，
 			// 规则元信息由 Window 持有、由这里的统一编排路径注入。
 			quotaResult.ActiveRule = window.Rule
+			quotaResult.DryRunRules = dryRunRules
 			// 被限流，归还前面已分配的并发配额，避免泄漏
 			for _, fn := range allReleaseFuncs {
 				fn()
@@ -284,9 +297,10 @@ func (f *FlowQuotaAssistant) GetQuota(commonRequest *data.CommonRateLimitRequest
 		allReleaseFuncs = append(allReleaseFuncs, quotaResult.GetReleaseFuncs()...)
 	}
 	finalResp := &model.QuotaResponse{
-		Code:   model.QuotaResultOk,
-		Info:   QuotaGranted,
-		WaitMs: maxWaitMs,
+		Code:        model.QuotaResultOk,
+		Info:        QuotaGranted,
+		WaitMs:      maxWaitMs,
+		DryRunRules: dryRunRules,
 	}
 	for _, fn := range allReleaseFuncs {
 		finalResp.AddRelease(fn)
@@ -0,0 +1,240 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	wrappers "google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+	"github.com/polarismesh/polaris-go/pkg/sdk"
+)
+
+// noopLogger 空日志实现，用于测试
+type noopLogger struct{}
+
+func (n *noopLogger) Tracef(format string, args ...interface{}) {}
+func (n *noopLogger) Debugf(format string, args ...interface{}) {}
+func (n *noopLogger) Infof(format string, args ...interface{})  {}
+func (n *noopLogger) Warnf(format string, args ...interface{})  {}
+func (n *noopLogger) Errorf(format string, args ...interface{}) {}
+func (n *noopLogger) Fatalf(format string, args ...interface{}) {}
+func (n *noopLogger) IsLevelEnabled(l int) bool                 { return true }
+func (n *noopLogger) SetLogLevel(l int) error                   { return nil }
+
+// fakeQuotaEngine 规则已预置在请求中，获取资源时直接返回；不配置事件上报链
+type fakeQuotaEngine struct {
+	sdk.Engine
+}
+
+func (f *fakeQuotaEngine) SyncGetResources(_ sdk.CacheValueQuery) error { return nil }
+func (f *fakeQuotaEngine) GetEventReportChain() interface{}             { return nil }
+
+// countingBucket 按规则阈值计数的配额池，并发数规则在分配成功时登记归还函数
+type countingBucket struct {
+	ratelimiter.QuotaBucket
+	mutex       sync.Mutex
+	max         uint32
+	used        uint32
+	concurrency bool
+}
+
+func (c *countingBucket) GetQuota(_ int64, token uint32) *model.QuotaResponse {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.used+token > c.max {
+		return &model.QuotaResponse{Code: model.QuotaResultLimited, Info: "exhausted"}
+	}
+	c.used += token
+	resp := &model.QuotaResponse{Code: model.QuotaResultOk}
+	if c.concurrency {
+		resp.AddRelease(func() {
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			c.used -= token
+		})
+	}
+	return resp
+}
+
+func (c *countingBucket) inUse() uint32 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.used
+}
+
+// countingLimiter 为每条规则创建 countingBucket，并按规则 ID 记录
+type countingLimiter struct {
+	plugin.Plugin
+	buckets map[string]*countingBucket
+}
+
+func (c *countingLimiter) InitQuota(criteria *ratelimiter.InitCriteria) ratelimiter.QuotaBucket {
+	rule := criteria.DstRule
+	bucket := &countingBucket{concurrency: rule.GetResource() == apitraffic.Rule_CONCURRENCY}
+	if bucket.concurrency {
+		bucket.max = rule.GetConcurrencyAmount().GetMaxAmount()
+	} else {
+		bucket.max = rule.GetAmounts()[0].GetMaxAmount().GetValue()
+	}
+	c.buckets[rule.GetId().GetValue()] = bucket
+	return bucket
+}
+
+// fakeQuotaSupplier 任意限流插件名均返回同一个 countingLimiter
+type fakeQuotaSupplier struct {
+	plugin.Supplier
+	limiter *countingLimiter
+}
+
+func (f *fakeQuotaSupplier) GetPlugin(_ common.Type, _ string) (plugin.Plugin, error) {
+	return f.limiter, nil
+}
+
+func (f *fakeQuotaSupplier) GetEventSubscribers(_ common.PluginEventType) []common.PluginEventHandler {
+	return nil
+}
+
+func newTestAssistant() (*FlowQuotaAssistant, *countingLimiter) {
+	log.SetBaseLogger(&noopLogger{})
+	log.SetRateLimitLogger(&noopLogger{})
+	logCtx := &log.ContextLogger{}
+	logCtx.Init()
+	limiter := &countingLimiter{buckets: map[string]*countingBucket{}}
+	return &FlowQuotaAssistant{
+		enable:             true,
+		engine:             &fakeQuotaEngine{},
+		supplier:           &fakeQuotaSupplier{limiter: limiter},
+		mutex:              &sync.Mutex{},
+		svcToWindowSet:     &sync.Map{},
+		purgeIntervalMilli: 60 * 1000,
+		logCtx:             logCtx,
+	}, limiter
+}
+
+func newQPSRule(id string, maxAmount uint32, metadata map[string]string) *apitraffic.Rule {
+	return &apitraffic.Rule{
+		Id:        wrappers.String(id),
+		Service:   wrappers.String("svc"),
+		Namespace: wrappers.String("default"),
+		Revision:  wrappers.String(id + "-rev"),
+		Type:      apitraffic.Rule_LOCAL,
+		Action:    wrappers.String("reject"),
+		Amounts: []*apitraffic.Amount{{
+			MaxAmount:     wrappers.UInt32(maxAmount),
+			ValidDuration: &durationpb.Duration{Seconds: 1},
+		}},
+		Metadata: metadata,
+	}
+}
+
+func newQuotaRequest(rules ...*apitraffic.Rule) *data.CommonRateLimitRequest {
+	svcRule := pb.NewServiceRuleInProto(&apiservice.DiscoverResponse{
+		Type:      apiservice.DiscoverResponse_RATE_LIMIT,
+		Service:   &apiservice.Service{Namespace: wrappers.String("default"), Name: wrappers.String("svc")},
+		RateLimit: &apitraffic.RateLimit{Rules: rules},
+	}, log.GetBaseLogger())
+	return &data.CommonRateLimitRequest{
+		DstService:    model.ServiceKey{Namespace: "default", Service: "svc"},
+		Token:         1,
+		RateLimitRule: svcRule,
+	}
+}
+
+// TestFlowQuotaAssistant_GetQuotaDryRun 验证演练规则在配额分配主流程中的行为
+// 测试场景：依次匹配演练 QPS 规则（阈值 1）、并发数规则（阈值 10）与普通 QPS 规则（阈值 2），连续请求三次
+// 前置条件：演练规则通过 metadata 开启，本地配置未开启演练
+// 预期结果：演练窗口耗尽后请求仍放通并在 DryRunRules 中返回该规则，并发配额照常登记且可归还；
+// 普通规则不受演练规则影响，自身耗尽时返回限流与命中的规则，且不占用并发配额
+func TestFlowQuotaAssistant_GetQuotaDryRun(t *testing.T) {
+	assistant, limiter := newTestAssistant()
+	dryRule := newQPSRule("dry", 1, map[string]string{model.RateLimitMetadataDryRun: "true"})
+	concurrencyRule := &apitraffic.Rule{
+		Id:                wrappers.String("concurrency"),
+		Service:           wrappers.String("svc"),
+		Namespace:         wrappers.String("default"),
+		Revision:          wrappers.String("concurrency-rev"),
+		Resource:          apitraffic.Rule_CONCURRENCY,
+		ConcurrencyAmount: &apitraffic.ConcurrencyAmount{MaxAmount: 10},
+	}
+	normalRule := newQPSRule("normal", 2, nil)
+	request := newQuotaRequest(dryRule, concurrencyRule, normalRule)
+
+	future, err := assistant.GetQuota(request)
+	assert.NoError(t, err)
+	resp := future.Get()
+	assert.Equal(t, model.QuotaResultOk, resp.Code)
+	assert.Empty(t, resp.DryRunRules)
+	future.Release()
+	assert.Equal(t, uint32(0), limiter.buckets["concurrency"].inUse())
+
+	// 演练窗口已耗尽：请求放通，返回本应限流的演练规则，并发配额照常登记
+	future, err = assistant.GetQuota(request)
+	assert.NoError(t, err)
+	resp = future.Get()
+	assert.Equal(t, model.QuotaResultOk, resp.Code)
+	assert.Equal(t, QuotaGranted, resp.Info)
+	assert.Equal(t, []*apitraffic.Rule{dryRule}, resp.DryRunRules)
+	assert.Equal(t, uint32(1), limiter.buckets["concurrency"].inUse())
+	future.Release()
+	assert.Equal(t, uint32(0), limiter.buckets["concurrency"].inUse())
+	assert.Equal(t, uint32(2), limiter.buckets["normal"].inUse())
+
+	// 普通规则耗尽：返回限流，已登记的并发配额被归还
+	future, err = assistant.GetQuota(request)
+	assert.NoError(t, err)
+	resp = future.Get()
+	assert.Equal(t, model.QuotaResultLimited, resp.Code)
+	assert.Same(t, normalRule, resp.ActiveRule)
+	assert.Equal(t, uint32(0), limiter.buckets["concurrency"].inUse())
+}
+
+// TestNewRateLimitWindow_DetachContext 验证限流窗口不持有创建它的请求上下文
+// 测试场景：首个请求携带带有 deadline 的上下文与超时参数，触发窗口创建
+// 预期结果：窗口保留超时参数，上下文被清空，后续同步操作使用 context.Background()
+func TestNewRateLimitWindow_DetachContext(t *testing.T) {
+	assistant, _ := newTestAssistant()
+	rule := newQPSRule("normal", 2, nil)
+	request := newQuotaRequest(rule)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	request.ControlParam = model.ControlParam{Timeout: time.Second, MaxRetry: 2, Context: ctx}
+
+	windows, err := assistant.lookupRateLimitWindow(request)
+	assert.NoError(t, err)
+	assert.Len(t, windows, 1)
+	assert.Equal(t, time.Second, windows[0].syncParam.Timeout)
+	assert.Equal(t, 2, windows[0].syncParam.MaxRetry)
+	assert.Nil(t, windows[0].syncParam.Context)
+	assert.Equal(t, context.Background(), windows[0].syncParam.GetContext())
+	assert.Same(t, ctx, request.ControlParam.Context)
+}
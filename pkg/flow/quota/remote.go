@@ -31,13 +31,14 @@ import (
 	"github.com/modern-go/reflect2"
 	"github.com/polarismesh/specification/source/go/api/v1/traffic_manage/ratelimiter"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	limitpb "github.com/polarismesh/polaris-go/pkg/model/pb/metric/v2"
+	"github.com/polarismesh/polaris-go/pkg/network"
 	"github.com/polarismesh/polaris-go/pkg/sdk"
 )
 
@@ -173,7 +174,10 @@ func (s *StreamCounterSet) HasInitialized(svcKey model.ServiceKey, labels string
 // createConnection 创建连接
 func (s *StreamCounterSet) createConnection() (*grpc.ClientConn, error) {
 	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if s.asyncConnector.credsErr != nil {
+		return nil, s.asyncConnector.credsErr
+	}
+	opts = append(opts, grpc.WithTransportCredentials(s.asyncConnector.creds))
 	opts = append(opts, grpc.WithBlock())
 	ctx, cancel := context.WithTimeout(context.Background(), s.asyncConnector.connTimeout)
 	defer cancel()
@@ -601,6 +605,10 @@ type asyncRateLimitConnector struct {
 	reconnectInterval time.Duration
 	// 协议
 	protocol string
+	// 与限流server通信的传输凭证，开启TLS时支持证书热加载
+	creds credentials.TransportCredentials
+	// 创建传输凭证失败的原因，存在时不会建立连接
+	credsErr error
 	logCtx   *log.ContextLogger
 }
 
@@ -626,6 +634,10 @@ func NewAsyncRateLimitConnector(valueCtx sdk.ValueContext, cfg config.Configurat
 		stopChan:          make(chan struct{}),
 		logCtx:            valueCtx.GetContextLogger(),
 	}
+	c.creds, c.credsErr = network.NewTransportCredentials(cfg.GetGlobal().GetServerConnector().GetTLS())
+	if c.credsErr != nil {
+		c.logCtx.GetNetworkLogger().Errorf("[RateLimit]fail to create tls credentials, err is %v", c.credsErr)
+	}
 	go c.startClearTask()
 	return c
 }
@@ -717,6 +729,7 @@ func (a *asyncRateLimitConnector) Destroy() {
 	a.mutex.Lock()
 	if !a.destroyed {
 		close(a.stopChan)
+		network.CloseTransportCredentials(a.creds)
 	}
 	a.destroyed = true
 	streams := a.streams
@@ -0,0 +1,123 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// waitQuotaRetryInterval 预估时间点到达后仍被限流（并发抢占或配额池不支持预估）时的重试间隔
+const waitQuotaRetryInterval = 10 * time.Millisecond
+
+// WaitQuota 阻塞获取配额，直到分配成功、ctx 结束或确定无法在 ctx 截止时间前获得配额。
+//
+// 每一轮先在不划扣配额的前提下预估所有命中窗口的最早可分配时间：
+//   - 任一窗口永远无法分配 token 个配额，或预估时间晚于 ctx 截止时间，直接返回错误，不划扣配额
+//   - 否则睡眠到预估时间点后再真正分配；仍被限流则按重试间隔进入下一轮
+//
+// 分配成功时若配额池要求排队（WaitMs > 0），会在此处等待完毕，返回的 future 调用 Get 不再阻塞；
+// 排队期间 ctx 结束会释放已分配的资源并返回错误。
+func (f *FlowQuotaAssistant) WaitQuota(
+	ctx context.Context, commonRequest *data.CommonRateLimitRequest) (*model.QuotaFutureImpl, error) {
+	for {
+		if err := f.waitPermitTime(ctx, commonRequest); err != nil {
+			return nil, err
+		}
+		future, err := f.GetQuota(commonRequest)
+		if err != nil {
+			return nil, err
+		}
+		resp := future.GetImmediately()
+		if resp.Code == model.QuotaResultOk {
+			if resp.WaitMs > 0 {
+				deadlineMilli := model.CurrentMillisecond() + resp.WaitMs
+				if err := sleepUntil(ctx, deadlineMilli, commonRequest.Token); err != nil {
+					future.Release()
+					return nil, err
+				}
+			}
+			return model.QuotaFutureCompleted(resp), nil
+		}
+		if err := sleepUntil(ctx, model.CurrentMillisecond()+waitQuotaRetryInterval.Milliseconds(),
+			commonRequest.Token); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitPermitTime 等待到所有命中窗口的最早可分配时间点
+func (f *FlowQuotaAssistant) waitPermitTime(ctx context.Context, commonRequest *data.CommonRateLimitRequest) error {
+	if !f.enable {
+		return nil
+	}
+	windows, err := f.lookupRateLimitWindow(commonRequest)
+	if err != nil {
+		return err
+	}
+	var permitTimeMilli int64
+	for _, window := range windows {
+		if window.dryRun {
+			// 演练模式的窗口不拦截请求，无需等待
+			continue
+		}
+		window.Init()
+		windowPermitTime, ok := window.EarliestPermitTime(commonRequest.Token)
+		if !ok {
+			return model.NewSDKError(model.ErrCodeAPITimeoutError, nil,
+				"quota of %d token(s) can never be granted by rule %s, token count exceeds the rule threshold",
+				commonRequest.Token, window.Rule.GetId().GetValue())
+		}
+		if windowPermitTime > permitTimeMilli {
+			permitTimeMilli = windowPermitTime
+		}
+	}
+	return sleepUntil(ctx, permitTimeMilli, commonRequest.Token)
+}
+
+// sleepUntil 睡眠到指定的本地时间点（毫秒）；时间点晚于 ctx 截止时间时不睡眠直接返回错误
+func sleepUntil(ctx context.Context, timeMilli int64, token uint32) error {
+	waitMilli := timeMilli - model.CurrentMillisecond()
+	if waitMilli <= 0 {
+		return ctxError(ctx, token)
+	}
+	if deadline, ok := ctx.Deadline(); ok && timeMilli > deadline.UnixNano()/1e6 {
+		return model.NewSDKError(model.ErrCodeAPITimeoutError, context.DeadlineExceeded,
+			"quota of %d token(s) can not be granted before deadline, need to wait %dms but only %s left",
+			token, waitMilli, time.Until(deadline).Round(time.Millisecond))
+	}
+	timer := time.NewTimer(time.Duration(waitMilli) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctxError(ctx, token)
+	}
+}
+
+// ctxError ctx 已结束时返回对应的错误
+func ctxError(ctx context.Context, token uint32) error {
+	if err := ctx.Err(); err != nil {
+		return model.NewSDKError(model.ErrCodeAPITimeoutError, err, "wait quota of %d token(s) interrupted", token)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// permitBucket 固定返回预估结果的配额池，记录预估时传入的时间
+type permitBucket struct {
+	ratelimiter.QuotaBucket
+	delayMs   int64
+	permit    bool
+	curTimeMs int64
+}
+
+func (p *permitBucket) EarliestPermitTime(curTimeMs int64, token uint32) (int64, bool) {
+	p.curTimeMs = curTimeMs
+	return curTimeMs + p.delayMs, p.permit
+}
+
+// TestRateLimitWindow_EarliestPermitTime 测试窗口对配额池预估时间的换算
+// 测试场景：与服务端存在时间差，配额池分别支持/不支持预估
+// 预期结果：配额池以服务端时间预估，返回值换算回本地时间；不支持预估时返回当前时间
+func TestRateLimitWindow_EarliestPermitTime(t *testing.T) {
+	bucket := &permitBucket{delayMs: 300, permit: true}
+	window := &RateLimitWindow{trafficShapingBucket: bucket, timeDiff: 5000}
+
+	nowMilli := model.CurrentMillisecond()
+	permitTime, ok := window.EarliestPermitTime(1)
+	assert.True(t, ok)
+	assert.InDelta(t, nowMilli+300, permitTime, 20)
+	assert.InDelta(t, nowMilli+5000, bucket.curTimeMs, 20)
+
+	bucket.permit = false
+	_, ok = window.EarliestPermitTime(1)
+	assert.False(t, ok)
+
+	window = &RateLimitWindow{trafficShapingBucket: struct{ ratelimiter.QuotaBucket }{}}
+	permitTime, ok = window.EarliestPermitTime(1)
+	assert.True(t, ok)
+	assert.InDelta(t, model.CurrentMillisecond(), permitTime, 20)
+}
+
+// TestSleepUntil 测试按截止时间等待
+// 测试场景：等待时间点在截止时间之前、之后，以及等待中 ctx 被取消
+// 预期结果：截止时间前可达则睡眠到时间点；不可达时立即返回超时错误；取消时返回超时错误
+func TestSleepUntil(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	assert.NoError(t, sleepUntil(ctx, model.CurrentMillisecond()+50, 1))
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+
+	start = time.Now()
+	err := sleepUntil(ctx, model.CurrentMillisecond()+1000, 1)
+	assert.Error(t, err)
+	assert.Equal(t, model.ErrCodeAPITimeoutError, err.(model.SDKError).ErrorCode())
+	assert.True(t, time.Since(start) < 50*time.Millisecond)
+
+	cancelCtx, cancelFunc := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancelFunc()
+	}()
+	err = sleepUntil(cancelCtx, model.CurrentMillisecond()+1000, 1)
+	assert.Error(t, err)
+	assert.Equal(t, model.ErrCodeAPITimeoutError, err.(model.SDKError).ErrorCode())
+}
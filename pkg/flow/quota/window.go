@@ -19,6 +19,7 @@ package quota
 
 import (
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -31,6 +32,7 @@ import (
 
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/model/event"
 	"github.com/polarismesh/polaris-go/pkg/model/pb"
@@ -365,6 +367,8 @@ type RateLimitWindow struct {
 	// 零值 0 = QuotaResultOk = UNLIMITED，与"窗口刚创建尚未限流"的语义一致：
 	// 首次出现限流时会构成 UNLIMITED→LIMITED 切换，触发 RateLimitStart。
 	lastCode int64
+	// dryRun 演练模式：按规则计算配额并记录本应限流的请求，但不拦截
+	dryRun bool
 }
 
 // remoteErrLogIntervalNano 同一窗口同类远端错误日志的最小输出间隔（5s）；
@@ -422,12 +426,15 @@ func NewRateLimitWindow(windowSet *RateLimitWindowSet, rule *apitraffic.Rule,
 	window.uniqueKey, window.hashValue = window.buildQuotaHashValue()
 	window.Rule = rule
 	window.expireDuration = getExpireDuration(rule)
+	window.dryRun = isDryRunRule(rule, windowSet.flowAssistant.dryRun)
 	// 并发数限流为纯本地模式，不需要远程集群信息；只有 GLOBAL 类型的 QPS 规则才填充 remoteCluster
 	if rule.GetResource() != apitraffic.Rule_CONCURRENCY && rule.GetType() == apitraffic.Rule_GLOBAL {
 		window.remoteCluster.Namespace = windowSet.flowAssistant.remoteNamespace
 		window.remoteCluster.Service = windowSet.flowAssistant.remoteService
 	}
+	// 窗口长期存在，只保留超时与重试参数，不持有首个请求的上下文（span、deadline 及其携带的值）
 	window.syncParam.ControlParam = commonRequest.ControlParam
+	window.syncParam.ControlParam.Context = nil
 
 	window.rateLimiter = createBehavior(windowSet.flowAssistant.supplier, resolveRateLimiterName(rule))
 	// 初始化流量整形窗口
@@ -454,6 +461,19 @@ func NewRateLimitWindow(windowSet *RateLimitWindowSet, rule *apitraffic.Rule,
 	return window
 }
 
+// isDryRunRule 规则 metadata 中配置了 dryRun 时以规则为准，否则使用本地配置
+func isDryRunRule(rule *apitraffic.Rule, defaultDryRun bool) bool {
+	value, ok := rule.GetMetadata()[model.RateLimitMetadataDryRun]
+	if !ok {
+		return defaultDryRun
+	}
+	dryRun, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return defaultDryRun
+	}
+	return dryRun
+}
+
 // toServerTimeMilli 客户端时间转为服务端时间
 func (r *RateLimitWindow) toServerTimeMilli(timeMilli int64) int64 {
 	timeDiff := atomic.LoadInt64(&r.timeDiff)
@@ -470,8 +490,8 @@ func (r *RateLimitWindow) UpdateTimeDiff(timeDiff int64) {
 
 // buildRemoteConfigMode 构建限流模式及集群
 func (r *RateLimitWindow) buildRemoteConfigMode(windowSet *RateLimitWindowSet, rule *apitraffic.Rule) {
-	// 并发数限流强制走本地模式，无视 Rule.Type / Cluster 配置，避免发起远程 init/acquire
-	if rule.GetResource() == apitraffic.Rule_CONCURRENCY {
+	// 并发数限流与自适应限流只依赖本地信号，强制走本地模式，无视 Rule.Type / Cluster 配置，避免发起远程 init/acquire
+	if rule.GetResource() == apitraffic.Rule_CONCURRENCY || rule.GetAction().GetValue() == config.DefaultBBRRateLimiter {
 		r.configMode = model.ConfigQuotaLocalMode
 		return
 	}
@@ -679,10 +699,50 @@ func (r *RateLimitWindow) AllocateQuota(commonRequest *data.CommonRateLimitReque
 		r.reportRateLimitEvent(commonRequest,
 			model.QuotaResultCode(previousCode), quotaResult.Code, quotaResult.Info)
 	}
+	if r.dryRun {
+		r.logDryRun(previousCode != currentCode, quotaResult)
+	}
 
 	return quotaResult
 }
 
+// logDryRun 记录演练模式下的限流结果：状态切换时输出 info，逐次的本应限流结果仅在 debug 级别输出
+func (r *RateLimitWindow) logDryRun(changed bool, quotaResult *model.QuotaResponse) {
+	logger := r.WindowSet.flowAssistant.logCtx.GetRateLimitLogger()
+	if changed {
+		if quotaResult.Code == model.QuotaResultLimited {
+			logger.Infof("[RateLimit] dry-run rule[%s] window %s starts limiting, requests are still passed, info: %s",
+				r.Rule.GetId().GetValue(), r.uniqueKey, quotaResult.Info)
+		} else {
+			logger.Infof("[RateLimit] dry-run rule[%s] window %s stops limiting", r.Rule.GetId().GetValue(), r.uniqueKey)
+		}
+		return
+	}
+	if quotaResult.Code == model.QuotaResultLimited && logger.IsLevelEnabled(log.DebugLog) {
+		logger.Debugf("[RateLimit] dry-run rule[%s] window %s would have limited, info: %s",
+			r.Rule.GetId().GetValue(), r.uniqueKey, quotaResult.Info)
+	}
+}
+
+// EarliestPermitTime 预估获取 token 个配额最早可成功的本地时间点（毫秒），不划扣配额。
+//
+// 配额池未实现 ratelimiter.QuotaPermitEstimator 时返回当前时间，由调用方按重试间隔轮询；
+// 返回 false 表示该窗口永远无法分配这么多配额。
+func (r *RateLimitWindow) EarliestPermitTime(token uint32) (int64, bool) {
+	nowMilli := model.CurrentMillisecond()
+	estimator, ok := r.trafficShapingBucket.(ratelimiter.QuotaPermitEstimator)
+	if !ok {
+		return nowMilli, true
+	}
+	// 配额池以服务端时间计算，换算回本地时间
+	curTimeMs := r.toServerTimeMilli(nowMilli)
+	permitTimeMs, ok := estimator.EarliestPermitTime(curTimeMs, token)
+	if !ok {
+		return 0, false
+	}
+	return permitTimeMs - (curTimeMs - nowMilli), true
+}
+
 // reportRateLimitEvent 上报限流状态切换事件到 EventReporter 插件链。
 //
 // 输入：
@@ -726,6 +786,13 @@ func (r *RateLimitWindow) reportRateLimitEvent(
 	if eventInfo == nil {
 		return
 	}
+	if r.dryRun {
+		// 演练模式的事件仅代表本应发生的限流，通过扩展参数与真实限流区分
+		eventInfo.AdditionalParams = map[string]string{
+			event.AdditionalParamDryRun: "true",
+			event.AdditionalParamRuleID: r.Rule.GetId().GetValue(),
+		}
+	}
 
 	logger := r.WindowSet.flowAssistant.logCtx.GetRateLimitLogger()
 	for _, reporter := range chain {
@@ -84,3 +84,23 @@ func TestBuildRemoteConfigMode_QpsLocalRule_ShouldUseLocalMode(t *testing.T) {
 
 	assert.Equal(t, model.ConfigQuotaLocalMode, window.configMode)
 }
+
+// TestBuildRemoteConfigMode_BBRGlobalRule_ShouldFallbackToLocalMode 验证自适应限流规则强制走 LocalMode:
+// bbr 只依赖本地 CPU、在途请求数与响应时间，即便 Rule.Type=GLOBAL 且 Cluster 已配置也不应发起远程同步.
+func TestBuildRemoteConfigMode_BBRGlobalRule_ShouldFallbackToLocalMode(t *testing.T) {
+	rule := &apitraffic.Rule{
+		Resource: apitraffic.Rule_QPS,
+		Type:     apitraffic.Rule_GLOBAL,
+		Action:   &wrappers.StringValue{Value: "bbr"},
+		Cluster: &apitraffic.RateLimitCluster{
+			Namespace: &wrappers.StringValue{Value: "Polaris"},
+			Service:   &wrappers.StringValue{Value: "polaris.metric.v2.test"},
+		},
+	}
+	window := &RateLimitWindow{}
+
+	window.buildRemoteConfigMode(nil, rule)
+
+	assert.Equal(t, model.ConfigQuotaLocalMode, window.configMode)
+	assert.Empty(t, window.remoteCluster.Namespace)
+}
@@ -0,0 +1,50 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"testing"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestIsDryRunRule 验证规则 metadata 与本地配置共同决定演练模式：
+// metadata 中的合法取值优先，缺失或非法时沿用本地配置.
+func TestIsDryRunRule(t *testing.T) {
+	cases := []struct {
+		name          string
+		metadata      map[string]string
+		defaultDryRun bool
+		expect        bool
+	}{
+		{name: "no metadata, local off", expect: false},
+		{name: "no metadata, local on", defaultDryRun: true, expect: true},
+		{name: "rule on", metadata: map[string]string{model.RateLimitMetadataDryRun: "true"}, expect: true},
+		{name: "rule off overrides local", metadata: map[string]string{model.RateLimitMetadataDryRun: " false "},
+			defaultDryRun: true, expect: false},
+		{name: "invalid value falls back", metadata: map[string]string{model.RateLimitMetadataDryRun: "yes"},
+			defaultDryRun: true, expect: true},
+	}
+	for _, c := range cases {
+		rule := &apitraffic.Rule{Metadata: c.metadata}
+		assert.Equal(t, c.expect, isDryRunRule(rule, c.defaultDryRun), c.name)
+	}
+}
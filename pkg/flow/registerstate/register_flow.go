@@ -87,9 +87,12 @@ func (c *RegisterStateManager) PutRegister(instance *model.InstanceRegisterReque
 		return nil, false
 	}
 
+	// 后台心跳与重注册不应受调用方请求上下文的 deadline 与取消影响，这里保存去除上下文的副本
+	stored := *instance
+	stored.SetContext(nil)
 	ctx, cancel := context.WithCancel(context.Background())
 	state := &registerState{
-		instance:         instance,
+		instance:         &stored,
 		lastRegisterTime: time.Now(),
 		cancel:           cancel,
 	}
@@ -23,6 +23,8 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
 	"github.com/polarismesh/polaris-go/pkg/flow/registerstate"
@@ -33,6 +35,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
 	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
 	"github.com/polarismesh/polaris-go/pkg/sdk"
+	"github.com/polarismesh/polaris-go/pkg/tracing"
 )
 
 // syncInstancesReportAndFinalize 结果上报及归还请求实例请求对象
@@ -58,7 +61,8 @@ func (e *Engine) syncRateLimitReportAndFinalize(commonRequest *data.CommonRateLi
 //   - Namespace / Service / Method：被调服务身份与方法
 //   - Result：本次配额分配的结果码（QuotaResultOk / QuotaResultLimited）
 //   - Arguments：限流维度的原始参数（部分上报实现用其展开 label）
-//   - RuleName：仅限流时有值，标识命中的规则名（来自 QuotaResponse.GetActiveRuleName）
+//   - RuleName / RuleID：仅限流时有值，标识命中的规则（来自 QuotaResponse.GetActiveRuleName / GetActiveRuleID）
+//   - DryRun：演练模式下本应限流的规则（QuotaResponse.DryRunRules）额外逐条上报，DryRun=true
 //   - Labels：把 Arguments 拍平成 TYPE:key:value|... 字符串，对接 caller_labels 维度
 //
 // 非限流场景下 RuleName 为空串、Labels 由 Arguments 驱动；
@@ -72,9 +76,19 @@ func (e *Engine) reportRateLimitGauge(req *model.QuotaRequestImpl, resp *model.Q
 		Result:             resp.Code,
 		Arguments:          req.Arguments(),
 		RuleName:           resp.GetActiveRuleName(),
+		RuleID:             resp.GetActiveRuleID(),
 		Labels:             formatArgumentsToLabels(req.Arguments()),
 	}
 	_ = e.SyncReportStat(model.RateLimitStat, stat)
+	// 演练模式下本应限流的规则逐条上报，便于按规则统计
+	for _, rule := range resp.DryRunRules {
+		dryRunStat := *stat
+		dryRunStat.Result = model.QuotaResultLimited
+		dryRunStat.RuleName = rule.GetName().GetValue()
+		dryRunStat.RuleID = rule.GetId().GetValue()
+		dryRunStat.DryRun = true
+		_ = e.SyncReportStat(model.RateLimitStat, &dryRunStat)
+	}
 }
 
 // formatArgumentsToLabels 将 Arguments 列表拍平为单个标签字符串，便于作为 Prometheus 等监控的 label 值上报。
@@ -123,11 +137,21 @@ func (e *Engine) SyncGetOneInstance(req *model.GetOneInstanceRequest) (*model.On
 	// 方法开始时间
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
 	commonRequest.InitByGetOneRequest(req, e.configuration)
+	span := e.startInstancesSpan(commonRequest, tracing.SpanGetOneInstance)
 	resp, err := e.doSyncGetOneInstance(commonRequest)
+	tracing.End(span, err)
 	e.syncInstancesReportAndFinalize(commonRequest)
 	return resp, err
 }
 
+// startInstancesSpan 创建获取实例的 span，并作为后续资源加载、路由、负载均衡 span 的父 span
+func (e *Engine) startInstancesSpan(commonRequest *data.CommonInstancesRequest, name string) trace.Span {
+	ctx, span := e.tracer.Start(commonRequest.ControlParam.GetContext(), name,
+		tracing.ServiceAttributes(&commonRequest.DstService)...)
+	commonRequest.ControlParam.Context = ctx
+	return span
+}
+
 // doSyncGetOneInstance 操作主要业务逻辑
 func (e *Engine) doSyncGetOneInstance(commonRequest *data.CommonInstancesRequest) (*model.OneInstanceResponse, error) {
 	startTime := e.globalCtx.Now()
@@ -160,7 +184,13 @@ func (e *Engine) doLoadBalanceToOneInstance(
 	if err != nil {
 		return nil, err
 	}
+	_, span := e.tracer.Start(commonRequest.ControlParam.GetContext(), tracing.SpanLoadBalance,
+		tracing.AttrLoadBalancer.String(balancer.Name()))
 	inst, err := loadbalancer.ChooseInstance(e.globalCtx, balancer, &commonRequest.Criteria, commonRequest.DstInstances)
+	if err == nil {
+		span.SetAttributes(tracing.InstanceAttributes(inst)...)
+	}
+	tracing.End(span, err)
 	consumeTime := e.globalCtx.Since(startTime)
 	if err != nil {
 		(&commonRequest.CallResult).SetFail(model.GetErrorCodeFromError(err), consumeTime)
@@ -183,11 +213,21 @@ func (e *Engine) doLoadBalanceToOneInstance(
 
 // SyncGetResources 同步加载资源
 func (e *Engine) SyncGetResources(req sdk.CacheValueQuery) error {
+	_, span := e.tracer.Start(req.GetControlParam().GetContext(), tracing.SpanLoadResources,
+		tracing.ServiceAttributes(req.GetDstService())...)
+	err := e.doSyncGetResources(req, span)
+	tracing.End(span, err)
+	return err
+}
+
+// doSyncGetResources 优先读取本地缓存，未加载时向服务端发起请求并等待，超时后尝试使用缓存文件中的数据
+func (e *Engine) doSyncGetResources(req sdk.CacheValueQuery, span trace.Span) error {
 	var err error
 	var retryTimes = -1
 	var combineContext *CombineNotifyContext
 	dstService := req.GetDstService()
 	param := req.GetControlParam()
+	reqCtx := param.GetContext()
 	var totalConsumedTime, totalSleepTime time.Duration
 outLoop:
 	for retryTimes < param.MaxRetry {
@@ -199,12 +239,13 @@ outLoop:
 		}
 		// 本地缓存已经加载完成，退出
 		if nil == combineContext {
+			span.SetAttributes(tracing.AttrCacheHit.Bool(retryTimes < 0), tracing.AttrRemoteWaits.Int(retryTimes+1))
 			return nil
 		}
 		// 发起并等待远程的结果
 		retryTimes++
 		syncCtx := combineContext
-		exceedTimeout := syncCtx.Wait(param.Timeout)
+		exceedTimeout := syncCtx.WaitWithContext(reqCtx, param.Timeout)
 		// 计算请求耗时
 		consumedTime := e.globalCtx.Since(startTime)
 		totalConsumedTime += consumedTime
@@ -214,9 +255,15 @@ outLoop:
 			err = combineSDKErrors(sdkErrs)
 			break
 		}
+		if reqCtx.Err() != nil {
+			// 调用方的请求上下文已经结束，不再等待远程结果
+			break outLoop
+		}
 		if exceedTimeout {
 			// 只有网络错误才可以重试
-			time.Sleep(param.RetryInterval)
+			if sleepErr := model.SleepWithContext(reqCtx, param.RetryInterval); sleepErr != nil {
+				break outLoop
+			}
 			totalSleepTime += param.RetryInterval
 			continue
 		}
@@ -225,8 +272,10 @@ outLoop:
 			" serviceKey: %s, time consume is %v, retryTimes: %v", *dstService, consumedTime, retryTimes)
 		continue
 	}
+	span.SetAttributes(tracing.AttrCacheHit.Bool(false), tracing.AttrRemoteWaits.Int(retryTimes+1))
 	// 超时过后，尝试使用从缓存中获取的信息
 	success, err2 := tryGetServiceValuesFromCache(e.registry, req, e.logCtx)
+	span.SetAttributes(tracing.AttrCacheFallback.Bool(success))
 	if success {
 		e.logCtx.GetBaseLogger().Warnf("retryTimes %d equals maxRetryTimes %d, get %s from cache",
 			retryTimes, param.MaxRetry, *dstService)
@@ -236,6 +285,12 @@ outLoop:
 		e.logCtx.GetBaseLogger().Warnf("retryTimes %d equals maxRetryTimes %d, get %s from cache fail %v",
 			retryTimes, param.MaxRetry, *dstService, err)
 	}
+	if reqCtx.Err() != nil {
+		errMsg := fmt.Sprintf("request context done in SyncGetResources, serviceKey: %s, retryTimes: %d, "+
+			"total consumed time: %v", *dstService, retryTimes, totalConsumedTime)
+		e.logCtx.GetBaseLogger().Warnf(errMsg)
+		return model.NewContextError(reqCtx, errMsg)
+	}
 	e.logCtx.GetBaseLogger().Errorf("fail to get resource of %s for timeout, retryTimes: %d, total consumed time: %v,"+
 		" total sleep time: %v", *dstService, retryTimes, totalConsumedTime, totalSleepTime)
 	errMsg := fmt.Sprintf("retry times exceed %d in SyncGetResources, serviceKey: %s, timeout is %v",
@@ -386,7 +441,9 @@ func verifyCluster(svcInstances model.ServiceInstances, cluster *model.Cluster,
 func (e *Engine) SyncGetInstances(req *model.GetInstancesRequest) (*model.InstancesResponse, error) {
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
 	commonRequest.InitByGetMultiRequest(req, e.configuration)
+	span := e.startInstancesSpan(commonRequest, tracing.SpanGetInstances)
 	resp, err := e.doSyncGetInstances(commonRequest)
+	tracing.End(span, err)
 	e.syncInstancesReportAndFinalize(commonRequest)
 	return resp, err
 }
@@ -395,7 +452,9 @@ func (e *Engine) SyncGetInstances(req *model.GetInstancesRequest) (*model.Instan
 func (e *Engine) SyncGetAllInstances(req *model.GetAllInstancesRequest) (*model.InstancesResponse, error) {
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
 	commonRequest.InitByGetAllRequest(req, e.configuration)
+	span := e.startInstancesSpan(commonRequest, tracing.SpanGetAllInstances)
 	resp, err := e.doSyncGetAllInstances(commonRequest)
+	tracing.End(span, err)
 	e.syncInstancesReportAndFinalize(commonRequest)
 	return resp, err
 }
@@ -585,6 +644,10 @@ func (e *Engine) SyncUpdateServiceCallResult(result *model.ServiceCallResult) er
 
 // realSyncUpdateServiceCallResult 同步上报调用结果信息 实际处理函数
 func (e *Engine) realSyncUpdateServiceCallResult(result *model.ServiceCallResult) error {
+	// 有状态的负载均衡插件（如最少在途请求）需要感知调用结束，实例不是由该插件选出时会被忽略
+	for _, listener := range e.callResultListeners {
+		listener.OnServiceCallResult(result)
+	}
 	// 当前处理熔断和服务调用统计上报
 	if err := e.reportSvcStat(result); err != nil {
 		return err
@@ -617,13 +680,19 @@ func (e *Engine) SyncGetServiceRule(
 	eventType model.EventType, req *model.GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
 	commonRequest := data.PoolGetCommonRuleRequest()
 	commonRequest.InitByGetRuleRequest(eventType, req, e.configuration)
-	resp, err := e.doSyncGetServiceRule(commonRequest)
+	ctx, span := e.tracer.Start(commonRequest.ControlParam.GetContext(), tracing.SpanGetServiceRule,
+		append(tracing.ServiceAttributes(&commonRequest.DstService.ServiceKey),
+			tracing.AttrRuleType.String(eventType.String()))...)
+	commonRequest.ControlParam.Context = ctx
+	resp, err := e.doSyncGetServiceRule(commonRequest, span)
+	tracing.End(span, err)
 	e.syncRuleReportAndFinalize(commonRequest)
 	return resp, err
 }
 
 // doSyncGetServiceRule 同步获取服务规则
-func (e *Engine) doSyncGetServiceRule(commonRequest *data.CommonRuleRequest) (*model.ServiceRuleResponse, error) {
+func (e *Engine) doSyncGetServiceRule(
+	commonRequest *data.CommonRuleRequest, span trace.Span) (*model.ServiceRuleResponse, error) {
 	maxRetryTimes := commonRequest.ControlParam.MaxRetry
 	// 构建规则过滤器
 	var retryTimes = -1
@@ -632,10 +701,12 @@ func (e *Engine) doSyncGetServiceRule(commonRequest *data.CommonRuleRequest) (*m
 		ServiceKey: &commonRequest.DstService.ServiceKey,
 		Operation:  keyDstRoute}
 	apiStartTime := e.globalCtx.Now()
+	reqCtx := commonRequest.ControlParam.GetContext()
 	for retryTimes < maxRetryTimes {
 		startTime := e.globalCtx.Now()
 		svcRule := e.registry.GetServiceRule(&commonRequest.DstService, false)
 		if svcRule.IsInitialized() {
+			span.SetAttributes(tracing.AttrCacheHit.Bool(retryTimes < 0), tracing.AttrRemoteWaits.Int(retryTimes+1))
 			commonRequest.CallResult.SetSuccess(e.globalCtx.Since(startTime))
 			return commonRequest.BuildServiceRuleResponse(svcRule), nil
 		}
@@ -647,12 +718,18 @@ func (e *Engine) doSyncGetServiceRule(commonRequest *data.CommonRuleRequest) (*m
 		}
 		singleCtx := NewSingleNotifyContext(svcRuleKey, notifier, e.logCtx)
 		retryTimes++
-		exceedTimeout := singleCtx.Wait(commonRequest.ControlParam.Timeout)
+		exceedTimeout := singleCtx.WaitWithContext(reqCtx, commonRequest.ControlParam.Timeout)
 		// 计算请求耗时
 		consumedTime := e.globalCtx.Since(startTime)
+		if reqCtx.Err() != nil {
+			// 调用方的请求上下文已经结束，不再等待远程结果
+			break
+		}
 		if exceedTimeout {
 			// 只有网络错误才可以重试
-			time.Sleep(commonRequest.ControlParam.RetryInterval)
+			if sleepErr := model.SleepWithContext(reqCtx, commonRequest.ControlParam.RetryInterval); sleepErr != nil {
+				break
+			}
 			e.logCtx.GetBaseLogger().Warnf("retry GetRoutes for timeout, consume time %v,"+
 				" Namespace: %s, Service: %s, retry times: %d",
 				consumedTime, commonRequest.DstService.Namespace, commonRequest.DstService.Service, retryTimes)
@@ -674,12 +751,18 @@ func (e *Engine) doSyncGetServiceRule(commonRequest *data.CommonRuleRequest) (*m
 		commonRequest.DstService.Namespace, commonRequest.DstService.Service, commonRequest.DstService.String())
 	// 上面的尝试超时之后，向尝试获取从缓存文件加载的信息
 	svcRule := e.registry.GetServiceRule(&commonRequest.DstService, true)
+	span.SetAttributes(tracing.AttrCacheHit.Bool(false), tracing.AttrRemoteWaits.Int(retryTimes+1),
+		tracing.AttrCacheFallback.Bool(svcRule.IsInitialized()))
 	if svcRule.IsInitialized() {
 		commonRequest.CallResult.SetSuccess(e.globalCtx.Since(apiStartTime))
 		return commonRequest.BuildServiceRuleResponse(svcRule), nil
 	}
 	(&commonRequest.CallResult).SetFail(
 		model.ErrCodeAPITimeoutError, e.globalCtx.Since(apiStartTime))
+	if reqCtx.Err() != nil {
+		return nil, model.NewContextError(reqCtx, "request context done in SyncGetServiceRule, service %s, "+
+			"namespace %s", commonRequest.DstService.Service, commonRequest.DstService.Namespace)
+	}
 	return nil, model.NewSDKError(model.ErrCodeAPITimeoutError, nil,
 		"retry times exceed %d in SyncGetServiceRule, service %s, namespace %s",
 		maxRetryTimes, commonRequest.DstService.Service, commonRequest.DstService.Namespace)
@@ -733,6 +816,11 @@ func (e *Engine) SyncGetConfigGroupWithReq(req *model.GetConfigGroupRequest) (mo
 	return e.configFlow.GetConfigGroupWithReq(req)
 }
 
+// SyncWatchConfigGroup 同步获取配置分组及其文件内容，并按文件粒度监听变更
+func (e *Engine) SyncWatchConfigGroup(req *model.WatchConfigGroupRequest) (model.ConfigGroupWatcher, error) {
+	return e.configFlow.WatchConfigGroup(req)
+}
+
 // SyncCreateConfigFile 同步创建配置文件
 func (e *Engine) SyncCreateConfigFile(namespace, fileGroup, fileName, content string) error {
 	return e.configFlow.CreateConfigFile(namespace, fileGroup, fileName, content)
@@ -753,6 +841,27 @@ func (e *Engine) SyncUpsertAndPublishConfigFile(namespace, fileGroup, fileName,
 	return e.configFlow.UpsertAndPublishConfigFile(namespace, fileGroup, fileName, content)
 }
 
+// SyncDeleteConfigFile 同步删除配置文件
+func (e *Engine) SyncDeleteConfigFile(req *model.DeleteConfigFileRequest) error {
+	return e.configFlow.DeleteConfigFile(req)
+}
+
+// SyncListConfigFiles 同步查询分组下的配置文件元数据列表
+func (e *Engine) SyncListConfigFiles(req *model.ListConfigFilesRequest) (*model.ListConfigFilesResponse, error) {
+	return e.configFlow.ListConfigFiles(req)
+}
+
+// SyncGetConfigFileReleaseHistory 同步查询配置文件发布历史
+func (e *Engine) SyncGetConfigFileReleaseHistory(
+	req *model.GetConfigFileReleaseHistoryRequest) (*model.GetConfigFileReleaseHistoryResponse, error) {
+	return e.configFlow.GetConfigFileReleaseHistory(req)
+}
+
+// SyncRollbackConfigFile 同步回滚配置文件到指定发布版本
+func (e *Engine) SyncRollbackConfigFile(req *model.RollbackConfigFileRequest) error {
+	return e.configFlow.RollbackConfigFile(req)
+}
+
 // WatchAllInstances 监听所有的实例
 func (e *Engine) WatchAllInstances(request *model.WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error) {
 	return e.watchEngine.WatchAllInstances(request)
@@ -471,6 +471,11 @@ func (c *CallAborted) GetError() error {
 	return c.err
 }
 
+// GetRuleName 获取触发熔断的规则名
+func (c *CallAborted) GetRuleName() string {
+	return c.rule
+}
+
 type InitCircuitBreakerStatus func(CircuitBreakerStatus)
 
 func NewCircuitBreakerStatus(name string, status Status, startTime time.Time,
@@ -619,6 +624,13 @@ func (c *HalfOpenStatus) FinishedCount() int64 {
 	return atomic.LoadInt64(&c.finished)
 }
 
+// CalledCount 已归集的调用结果数，用于持久化半开态进度
+func (c *HalfOpenStatus) CalledCount() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.calledResult)
+}
+
 // MaxRequest 仅用于测试与日志展示半开态可放行的最大配额数
 func (c *HalfOpenStatus) MaxRequest() int {
 	return c.maxRequest
@@ -207,6 +207,8 @@ type GetConfigFileRequest struct {
 	FileName  string
 	Subscribe bool
 	Mode      GetConfigFileRequestMode
+	// 可选，请求上下文，通过 SetContext 设置，用于透传调用方的 deadline、取消信号以及 trace ID 等请求级别的值
+	requestContext
 }
 
 type GetConfigGroupRequest struct {
@@ -0,0 +1,97 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"errors"
+	"reflect"
+)
+
+const (
+	// ConfigFormatYAML yaml 格式，按 yaml 标签绑定
+	ConfigFormatYAML = "yaml"
+	// ConfigFormatJSON json 格式，按 json 标签绑定
+	ConfigFormatJSON = "json"
+	// ConfigFormatProperties properties 格式，key 以点号分隔层级
+	ConfigFormatProperties = "properties"
+	// ConfigFormatTOML toml 格式
+	ConfigFormatTOML = "toml"
+)
+
+// BindConfigFileRequest 配置文件绑定请求
+type BindConfigFileRequest struct {
+	Namespace string
+	FileGroup string
+	FileName  string
+	Mode      GetConfigFileRequestMode
+	// Format 配置文件格式，为空时根据文件扩展名推断
+	Format string
+	// Target 绑定目标，必须为非空指针，首次解析结果直接写入该对象，后续变更解析到同类型的新对象上
+	Target interface{}
+}
+
+// Validate 校验绑定请求
+func (r *BindConfigFileRequest) Validate() error {
+	if r == nil {
+		return errors.New("BindConfigFileRequest can not be nil")
+	}
+	if len(r.Namespace) == 0 || len(r.FileGroup) == 0 || len(r.FileName) == 0 {
+		return errors.New("namespace, fileGroup and fileName can not be empty")
+	}
+	value := reflect.ValueOf(r.Target)
+	if !value.IsValid() || value.Kind() != reflect.Ptr || value.IsNil() {
+		return errors.New("target must be a non-nil pointer")
+	}
+	return nil
+}
+
+// ConfigKeyChange 配置项变更，Key 为以点号分隔的路径，如 server.port
+type ConfigKeyChange struct {
+	Key        string
+	OldValue   interface{}
+	NewValue   interface{}
+	ChangeType ChangeType
+}
+
+// ConfigBindingChangeEvent 绑定值变更事件
+type ConfigBindingChangeEvent struct {
+	ConfigFileMetadata ConfigFileMetadata
+	// OldValue 变更前的绑定值
+	OldValue interface{}
+	// NewValue 变更后的绑定值
+	NewValue interface{}
+	// Changes 按 Key 排序的配置项变更列表
+	Changes []ConfigKeyChange
+}
+
+// OnConfigBindingChange 绑定值变更回调，仅在新版本解析成功且存在配置项变更时触发
+type OnConfigBindingChange func(event *ConfigBindingChangeEvent)
+
+// ConfigFileBinding 配置文件绑定对象，配置变更时原子替换绑定值，新版本解析失败时保留上一次成功解析的值
+type ConfigFileBinding interface {
+	// GetConfigFile 获取绑定的配置文件
+	GetConfigFile() ConfigFile
+	// Get 获取当前绑定值，类型与 Target 相同
+	Get() interface{}
+	// GetFormat 获取配置文件格式
+	GetFormat() string
+	// GetLastError 获取最近一次解析失败的错误，最近一次解析成功时返回 nil
+	GetLastError() error
+	// AddChangeListener 增加绑定值变更监听器
+	AddChangeListener(cb OnConfigBindingChange)
+}
@@ -0,0 +1,102 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import "errors"
+
+// WatchConfigGroupRequest 监听配置分组请求
+type WatchConfigGroupRequest struct {
+	Namespace string
+	FileGroup string
+	Mode      GetConfigFileRequestMode
+}
+
+// Validate 校验监听请求
+func (r *WatchConfigGroupRequest) Validate() error {
+	if r == nil {
+		return errors.New("WatchConfigGroupRequest can not be nil")
+	}
+	if len(r.Namespace) == 0 || len(r.FileGroup) == 0 {
+		return errors.New("namespace and fileGroup can not be empty")
+	}
+	return nil
+}
+
+// ConfigGroupFile 配置分组内的文件及其内容
+type ConfigGroupFile struct {
+	*SimpleConfigFile
+	Content string
+}
+
+// ConfigGroupFileChange 配置分组内单个文件的变更
+type ConfigGroupFileChange struct {
+	FileName   string
+	ChangeType ChangeType
+	OldVersion uint64
+	NewVersion uint64
+	OldMd5     string
+	NewMd5     string
+	// OldValue 变更之前的内容，新增时为空
+	OldValue string
+	// NewValue 变更之后的内容，删除时为空
+	NewValue string
+	// Err 拉取变更后内容失败的原因，此时 NewValue 为空
+	Err error
+}
+
+// ConfigGroupFilesChangeEvent 配置分组文件级变更事件
+type ConfigGroupFilesChangeEvent struct {
+	Namespace   string
+	FileGroup   string
+	OldRevision string
+	NewRevision string
+	// Changes 按文件名排序的变更列表
+	Changes []*ConfigGroupFileChange
+}
+
+// GetChanges 获取指定变更类型的文件变更
+func (e *ConfigGroupFilesChangeEvent) GetChanges(changeType ChangeType) []*ConfigGroupFileChange {
+	var changes []*ConfigGroupFileChange
+	for _, change := range e.Changes {
+		if change.ChangeType == changeType {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// OnConfigGroupFilesChange 配置分组文件级变更回调监听器
+type OnConfigGroupFilesChange func(event *ConfigGroupFilesChangeEvent)
+
+// ConfigGroupWatcher 配置分组监听器，维护分组内所有文件的内容快照，并按文件粒度产生变更事件
+type ConfigGroupWatcher interface {
+	// GetNamespace 获取命名空间
+	GetNamespace() string
+	// GetFileGroup 获取配置分组
+	GetFileGroup() string
+	// GetRevision 获取当前快照对应的分组版本
+	GetRevision() string
+	// GetFiles 获取分组内所有文件及其内容，按文件名排序
+	GetFiles() []*ConfigGroupFile
+	// GetFile 获取分组内指定文件
+	GetFile(fileName string) (*ConfigGroupFile, bool)
+	// AddChangeListener 增加文件级变更监听器
+	AddChangeListener(cb OnConfigGroupFilesChange)
+	// AddChangeListenerWithChannel 增加文件级变更监听器
+	AddChangeListenerWithChannel() <-chan *ConfigGroupFilesChangeEvent
+}
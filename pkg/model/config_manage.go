@@ -0,0 +1,188 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultConfigFileQueryLimit 配置文件列表、发布历史查询的默认分页大小
+	DefaultConfigFileQueryLimit = 100
+	// MaxConfigFileQueryLimit 配置文件列表、发布历史查询的最大分页大小，与服务端限制保持一致
+	MaxConfigFileQueryLimit = 100
+)
+
+// DeleteConfigFileRequest 删除配置文件请求
+type DeleteConfigFileRequest struct {
+	Namespace string
+	FileGroup string
+	FileName  string
+	// Operator 可选，操作人，记录到服务端的操作审计中
+	Operator string
+}
+
+// Validate 校验删除请求
+func (r *DeleteConfigFileRequest) Validate() error {
+	if r == nil {
+		return errors.New("DeleteConfigFileRequest can not be nil")
+	}
+	if len(r.Namespace) == 0 || len(r.FileGroup) == 0 || len(r.FileName) == 0 {
+		return errors.New("namespace, fileGroup and fileName can not be empty")
+	}
+	return nil
+}
+
+// ListConfigFilesRequest 查询分组下配置文件列表请求
+type ListConfigFilesRequest struct {
+	Namespace string
+	FileGroup string
+	// FileName 可选，按文件名模糊匹配
+	FileName string
+	Offset   uint32
+	// Limit 分页大小，为 0 时使用 DefaultConfigFileQueryLimit
+	Limit uint32
+}
+
+// Validate 校验查询请求，并补齐分页默认值
+func (r *ListConfigFilesRequest) Validate() error {
+	if r == nil {
+		return errors.New("ListConfigFilesRequest can not be nil")
+	}
+	if len(r.Namespace) == 0 || len(r.FileGroup) == 0 {
+		return errors.New("namespace and fileGroup can not be empty")
+	}
+	if r.Limit == 0 {
+		r.Limit = DefaultConfigFileQueryLimit
+	}
+	if r.Limit > MaxConfigFileQueryLimit {
+		return errors.New("limit can not be greater than 100")
+	}
+	return nil
+}
+
+// ConfigFileInfo 配置文件元数据，不包含配置内容
+type ConfigFileInfo struct {
+	Namespace   string
+	FileGroup   string
+	FileName    string
+	Format      string
+	Comment     string
+	Status      string
+	Labels      map[string]string
+	Encrypted   bool
+	EncryptAlgo string
+	CreateTime  time.Time
+	CreateBy    string
+	ModifyTime  time.Time
+	ModifyBy    string
+	ReleaseTime time.Time
+	ReleaseBy   string
+}
+
+// ListConfigFilesResponse 配置文件列表查询结果
+type ListConfigFilesResponse struct {
+	// Total 满足条件的文件总数，用于分页
+	Total uint32
+	Files []*ConfigFileInfo
+}
+
+// GetConfigFileReleaseHistoryRequest 查询配置文件发布历史请求
+type GetConfigFileReleaseHistoryRequest struct {
+	Namespace string
+	FileGroup string
+	FileName  string
+	Offset    uint32
+	// Limit 分页大小，为 0 时使用 DefaultConfigFileQueryLimit
+	Limit uint32
+}
+
+// Validate 校验查询请求，并补齐分页默认值
+func (r *GetConfigFileReleaseHistoryRequest) Validate() error {
+	if r == nil {
+		return errors.New("GetConfigFileReleaseHistoryRequest can not be nil")
+	}
+	if len(r.Namespace) == 0 || len(r.FileGroup) == 0 || len(r.FileName) == 0 {
+		return errors.New("namespace, fileGroup and fileName can not be empty")
+	}
+	if r.Limit == 0 {
+		r.Limit = DefaultConfigFileQueryLimit
+	}
+	if r.Limit > MaxConfigFileQueryLimit {
+		return errors.New("limit can not be greater than 100")
+	}
+	return nil
+}
+
+// ConfigFileReleaseHistory 配置文件的一次发布记录
+type ConfigFileReleaseHistory struct {
+	ID uint64
+	// ReleaseName 发布名称，回滚时使用
+	ReleaseName string
+	Namespace   string
+	FileGroup   string
+	FileName    string
+	// Content 发布时的配置内容，加密配置已经过 configFilter 链解密
+	Content string
+	Format  string
+	Comment string
+	Md5     string
+	// Type 发布类型，如 normal、rollback、delete 等
+	Type   string
+	Status string
+	// Reason 发布失败的原因
+	Reason             string
+	ReleaseDescription string
+	Labels             map[string]string
+	Encrypted          bool
+	CreateTime         time.Time
+	CreateBy           string
+	ModifyTime         time.Time
+	ModifyBy           string
+}
+
+// GetConfigFileReleaseHistoryResponse 配置文件发布历史查询结果
+type GetConfigFileReleaseHistoryResponse struct {
+	// Total 发布记录总数，用于分页
+	Total     uint32
+	Histories []*ConfigFileReleaseHistory
+}
+
+// RollbackConfigFileRequest 回滚配置文件到指定发布版本请求
+type RollbackConfigFileRequest struct {
+	Namespace string
+	FileGroup string
+	FileName  string
+	// ReleaseName 回滚目标的发布名称，取自 ConfigFileReleaseHistory.ReleaseName
+	ReleaseName string
+}
+
+// Validate 校验回滚请求
+func (r *RollbackConfigFileRequest) Validate() error {
+	if r == nil {
+		return errors.New("RollbackConfigFileRequest can not be nil")
+	}
+	if len(r.Namespace) == 0 || len(r.FileGroup) == 0 || len(r.FileName) == 0 {
+		return errors.New("namespace, fileGroup and fileName can not be empty")
+	}
+	if len(r.ReleaseName) == 0 {
+		return errors.New("releaseName can not be empty")
+	}
+	return nil
+}
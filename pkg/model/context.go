@@ -0,0 +1,81 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/modern-go/reflect2"
+)
+
+// ContextProvider 可透传 context.Context 的请求对象
+type ContextProvider interface {
+	// GetContext 获取请求上下文，未设置时返回 context.Background()
+	GetContext() context.Context
+}
+
+// requestContext 请求级别的上下文，内嵌到API请求对象中，
+// 用于把调用方的 deadline、取消信号以及 trace ID 等请求级别的值透传到流程引擎与服务端连接器
+type requestContext struct {
+	ctx context.Context
+}
+
+// SetContext 设置请求上下文
+func (r *requestContext) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// GetContext 获取请求上下文，未设置时返回 context.Background()
+func (r *requestContext) GetContext() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// GetContextOf 获取请求对象携带的上下文，请求对象不支持透传上下文时返回 context.Background()
+func GetContextOf(request interface{}) context.Context {
+	if reflect2.IsNil(request) {
+		return context.Background()
+	}
+	if provider, ok := request.(ContextProvider); ok {
+		return provider.GetContext()
+	}
+	return context.Background()
+}
+
+// NewContextError 在请求上下文已经结束（超过 deadline 或被取消）时构造对应的SDK错误
+func NewContextError(ctx context.Context, msg string, args ...interface{}) SDKError {
+	return NewSDKError(ErrCodeAPITimeoutError, ctx.Err(), msg, args...)
+}
+
+// SleepWithContext 等待指定的时间间隔，若请求上下文先结束则提前返回上下文的错误
+func SleepWithContext(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestGetContextOf 测试从请求对象中获取上下文
+func TestGetContextOf(t *testing.T) {
+	if ctx := GetContextOf(nil); ctx != context.Background() {
+		t.Fatalf("nil request should return background context")
+	}
+	req := &GetOneInstanceRequest{}
+	if ctx := GetContextOf(req); ctx != context.Background() {
+		t.Fatalf("request without context should return background context")
+	}
+	type ctxKey struct{}
+	reqCtx := context.WithValue(context.Background(), ctxKey{}, "trace-id")
+	req.SetContext(reqCtx)
+	if ctx := GetContextOf(req); ctx.Value(ctxKey{}) != "trace-id" {
+		t.Fatalf("request context value not propagated")
+	}
+	if ctx := GetContextOf(&ServiceKey{}); ctx != context.Background() {
+		t.Fatalf("non context provider should return background context")
+	}
+}
+
+// TestSleepWithContext 测试上下文结束时提前结束等待
+func TestSleepWithContext(t *testing.T) {
+	if err := SleepWithContext(context.Background(), 10*time.Millisecond); err != nil {
+		t.Fatalf("expect nil, actual %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if err := SleepWithContext(ctx, time.Minute); err != context.Canceled {
+		t.Fatalf("expect context.Canceled, actual %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("sleep should return immediately after context canceled")
+	}
+	sdkErr := NewContextError(ctx, "get instances for %s", "svc")
+	if sdkErr.ErrorCode() != ErrCodeAPITimeoutError {
+		t.Fatalf("expect ErrCodeAPITimeoutError, actual %v", sdkErr.ErrorCode())
+	}
+}
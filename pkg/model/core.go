@@ -18,6 +18,7 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -173,4 +174,14 @@ type ControlParam struct {
 	Timeout       time.Duration
 	MaxRetry      int
 	RetryInterval time.Duration
+	// Context 调用方的请求上下文，等待远程结果及重试间隔时会感知其 deadline 与取消信号
+	Context context.Context
+}
+
+// GetContext 获取请求上下文，未设置时返回 context.Background()
+func (c *ControlParam) GetContext() context.Context {
+	if c.Context == nil {
+		return context.Background()
+	}
+	return c.Context
 }
@@ -129,7 +129,8 @@ type BaseEventImpl struct {
 	// RuleName 命中的限流规则名称；熔断事件复用为熔断规则名
 	RuleName string `json:"rule_name,omitempty"`
 
-	// AdditionalParams 熔断事件扩展参数：isolation_object / failure_rate / slow_call_duration
+	// AdditionalParams 扩展参数：熔断事件为 isolation_object / failure_rate / slow_call_duration，
+	// 演练模式的限流事件为 dry_run / rule_id
 	AdditionalParams map[string]string `json:"additional_params,omitempty"`
 }
 
@@ -37,6 +37,14 @@ const (
 	StatusUnknown RateLimitStatus = "UNKNOWN"
 )
 
+// 限流事件扩展参数键（写入 BaseEventImpl.AdditionalParams），仅演练模式的规则携带
+const (
+	// AdditionalParamDryRun 标识事件来自演练模式，值为 "true"，此时请求实际未被拦截
+	AdditionalParamDryRun = "dry_run"
+	// AdditionalParamRuleID 命中的限流规则 ID
+	AdditionalParamRuleID = "rule_id"
+)
+
 // ParseRateLimitStatus 从 QuotaResultCode 解析限流状态。
 // QuotaResultLimited → LIMITED；QuotaResultOk → UNLIMITED；其他未知码 → UNKNOWN。
 // 上层 ParseRateLimitEventName 仅在 UNLIMITED↔LIMITED 切换时构造事件，UNKNOWN 不会触发上报，
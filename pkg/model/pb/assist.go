@@ -234,6 +234,9 @@ func (r *RateLimitingAssistant) SetDefault(message proto.Message) {
 		behaviorName := rule.GetAction().GetValue()
 		if len(behaviorName) == 0 {
 			rule.Action = &wrappers.StringValue{Value: DefaultRejectRateLimiter}
+		} else if name, ok := plugin.ResolvePluginName(common.TypeRateLimiter, behaviorName); ok {
+			// 按已注册的插件名忽略大小写匹配，如 warmup / WARMUP 均对应插件 warmUp
+			rule.Action.Value = name
 		} else {
 			rule.Action.Value = strings.ToLower(behaviorName)
 		}
@@ -43,6 +43,8 @@ type QuotaRequestImpl struct {
 	RetryCount *int
 	// 可选，获取的配额数
 	Token uint32
+	// 可选，请求上下文，通过 SetContext 设置，用于透传调用方的 deadline、取消信号以及 trace ID 等请求级别的值
+	requestContext
 }
 
 // GetService 获取服务名.
@@ -147,6 +149,9 @@ func (q *QuotaRequestImpl) Validate() error {
 	return errs
 }
 
+// RateLimitMetadataDryRun 限流规则 metadata 中控制演练模式的 key，取值 true / false，优先于本地配置.
+const RateLimitMetadataDryRun = "dryRun"
+
 // QuotaResultCode 应答码.
 type QuotaResultCode int
 
@@ -169,6 +174,8 @@ type QuotaResponse struct {
 	// 通过 GetActiveRule().GetCustomResponse().GetBody() 可以读取规则中配置的自定义返回内容；
 	// 也可通过 GetActiveRuleName() / GetActiveRuleID() 获取规则元信息，便于业务侧自定义返回。
 	ActiveRule *apitraffic.Rule
+	// DryRunRules 演练模式下本应限流的规则；这些规则不影响 Code，仅用于观测
+	DryRunRules []*apitraffic.Rule
 	// releaseFunc release回调链，仅用于并发数限流场景，由 Bucket 在 GetQuota 通过时注入
 	releaseFunc []func()
 }
@@ -200,6 +207,31 @@ func (q *QuotaResponse) GetActiveRuleID() string {
 	return q.ActiveRule.GetId().GetValue()
 }
 
+// GetActiveRuleAmount 获取本次限流命中规则中统计周期最短的阈值及其周期，供服务端中间件构造 RateLimit-* 等响应头；
+// 非限流场景或规则未配置阈值时返回 false。并发数限流规则的周期为 0。
+func (q *QuotaResponse) GetActiveRuleAmount() (uint32, time.Duration, bool) {
+	if q == nil || q.ActiveRule == nil {
+		return 0, 0, false
+	}
+	var (
+		maxAmount uint32
+		duration  time.Duration
+		found     bool
+	)
+	for _, amount := range q.ActiveRule.GetAmounts() {
+		if amount.GetMaxAmount() == nil {
+			continue
+		}
+		validDuration := amount.GetValidDuration().AsDuration()
+		if !found || validDuration < duration {
+			maxAmount = amount.GetMaxAmount().GetValue()
+			duration = validDuration
+			found = true
+		}
+	}
+	return maxAmount, duration, found
+}
+
 // AddRelease 注册释放回调，并发数限流场景下由 Bucket 注入，请求完成后由 QuotaFutureImpl.Release 触发执行.
 func (q *QuotaResponse) AddRelease(fn func()) {
 	if fn == nil {
@@ -230,10 +262,22 @@ func QuotaFutureWithResponse(resp *QuotaResponse) *QuotaFutureImpl {
 		resp: resp, deadlineCtx: deadlineCtx, cancel: cancel}
 }
 
+// QuotaFutureCompleted 创建已完成等待的future，Get 不再阻塞；用于调用方已经替业务等待过 WaitMs 的场景.
+func QuotaFutureCompleted(resp *QuotaResponse) *QuotaFutureImpl {
+	return &QuotaFutureImpl{resp: resp}
+}
+
+// closedDoneChan 已关闭的通道，用于无需等待的 future
+var closedDoneChan = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
 // Done 分配是否结束.
 func (q *QuotaFutureImpl) Done() <-chan struct{} {
-	if nil != q.deadlineCtx {
-		return nil
+	if nil == q.deadlineCtx {
+		return closedDoneChan
 	}
 	return q.deadlineCtx.Done()
 }
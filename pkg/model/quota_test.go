@@ -19,9 +19,11 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -73,3 +75,54 @@ func TestQuotaResponse_GetActiveRule_LimitedHasRule(t *testing.T) {
 	assert.Equal(t, "customBody", got.GetCustomResponse().GetBody(),
 		"业务侧链式调用应能取到 CustomResponse.body")
 }
+
+// TestQuotaFuture_Done 验证 Done 在无需等待与需要等待两种场景下的行为。
+// 前置条件：分别构造 WaitMs 为 0、WaitMs 为 20 以及已完成等待的 future。
+// 预期结果：无需等待时 Done 立即可读；需要等待时在 WaitMs 之后可读；已完成等待的 future 的 Get 不阻塞。
+func TestQuotaFuture_Done(t *testing.T) {
+	future := QuotaFutureWithResponse(&QuotaResponse{Code: QuotaResultOk})
+	select {
+	case <-future.Done():
+	default:
+		t.Fatal("无需等待时 Done 应立即可读")
+	}
+
+	future = QuotaFutureWithResponse(&QuotaResponse{Code: QuotaResultOk, WaitMs: 20})
+	select {
+	case <-future.Done():
+		t.Fatal("排队等待未结束时 Done 不应可读")
+	default:
+	}
+	select {
+	case <-future.Done():
+	case <-time.After(time.Second):
+		t.Fatal("排队等待结束后 Done 应可读")
+	}
+
+	resp := &QuotaResponse{Code: QuotaResultOk, WaitMs: 1000}
+	start := time.Now()
+	assert.Equal(t, resp, QuotaFutureCompleted(resp).Get())
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+}
+
+// TestQuotaResponse_GetActiveRuleAmount 验证命中规则阈值的读取。
+// 前置条件：规则配置 1 分钟 100 次与 1 秒 10 次两个阈值。
+// 预期结果：返回周期最短的 1 秒 10 次；未命中规则或未配置阈值时返回 false。
+func TestQuotaResponse_GetActiveRuleAmount(t *testing.T) {
+	var resp *QuotaResponse
+	_, _, ok := resp.GetActiveRuleAmount()
+	assert.False(t, ok, "nil receiver 应返回 false")
+
+	resp = &QuotaResponse{Code: QuotaResultLimited, ActiveRule: &apitraffic.Rule{}}
+	_, _, ok = resp.GetActiveRuleAmount()
+	assert.False(t, ok, "未配置阈值时应返回 false")
+
+	resp.ActiveRule.Amounts = []*apitraffic.Amount{
+		{MaxAmount: wrapperspb.UInt32(100), ValidDuration: durationpb.New(time.Minute)},
+		{MaxAmount: wrapperspb.UInt32(10), ValidDuration: durationpb.New(time.Second)},
+	}
+	maxAmount, duration, ok := resp.GetActiveRuleAmount()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(10), maxAmount)
+	assert.Equal(t, time.Second, duration)
+}
@@ -142,6 +142,8 @@ type GetServiceRuleRequest struct {
 	Direction apiservice.DiscoverDirection
 	// 应答对象，由主流程填充并返回
 	response ServiceRuleResponse
+	// 可选，请求上下文，通过 SetContext 设置，用于透传调用方的 deadline、取消信号以及 trace ID 等请求级别的值
+	requestContext
 }
 
 // GetService 获取服务名.
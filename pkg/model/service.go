@@ -217,6 +217,8 @@ type GetOneInstanceRequest struct {
 	Canary string
 	// 可选，是否包含被熔断的服务实例，默认false
 	IncludeCircuitBreakInstances bool
+	// 可选，请求上下文，通过 SetContext 设置，用于透传调用方的 deadline、取消信号以及 trace ID 等请求级别的值
+	requestContext
 }
 
 // SetTimeout 设置超时时间
@@ -304,6 +306,8 @@ type GetAllInstancesRequest struct {
 	RetryCount *int
 	// 应答，无需用户填充，由主流程进行填充
 	response InstancesResponse
+	// 可选，请求上下文，通过 SetContext 设置，用于透传调用方的 deadline、取消信号以及 trace ID 等请求级别的值
+	requestContext
 }
 
 // SetTimeout 设置超时时间
@@ -389,6 +393,8 @@ type GetInstancesRequest struct {
 	response InstancesResponse
 	// 金丝雀
 	Canary string
+	// 可选，请求上下文，通过 SetContext 设置，用于透传调用方的 deadline、取消信号以及 trace ID 等请求级别的值
+	requestContext
 }
 
 // SetTimeout 设置超时时间
@@ -476,6 +482,8 @@ type GetServicesRequest struct {
 	Timeout *time.Duration
 	// 可选，重试次数，默认直接获取全局的超时配置
 	RetryCount *int
+	// 可选，请求上下文，通过 SetContext 设置，用于透传调用方的 deadline、取消信号以及 trace ID 等请求级别的值
+	requestContext
 }
 
 // SetTimeout 设置超时时间
@@ -791,6 +799,11 @@ type RateLimitGauge struct {
 	Arguments []Argument
 	Result    QuotaResultCode
 	RuleName  string
+	// RuleID 命中的限流规则 ID，与 RuleName 一样仅在限流（含演练模式下本应限流）时有值
+	RuleID string
+	// DryRun 为 true 表示演练模式下本应被限流的记录，请求实际已放通；
+	// 这类记录只计入演练限流指标，不计入请求总数、通过数与限流数
+	DryRun bool
 	// Labels 限流参数维度的格式化标签字符串，由 sync_flow.formatArgumentsToLabels 生成。
 	// 形如 TYPE:key:value|TYPE:key:value，用于上报到 Prometheus 等监控指标的 caller_labels label。
 	// 当请求未携带 Arguments 时为空串。
@@ -1076,6 +1089,8 @@ type InstanceHeartbeatRequest struct {
 	Timeout *time.Duration
 	// 可选，重试次数，默认直接获取全局的超时配置
 	RetryCount *int
+	// 可选，请求上下文，通过 SetContext 设置，用于透传调用方的 deadline、取消信号以及 trace ID 等请求级别的值
+	requestContext
 }
 
 // String 打印消息内容
@@ -1153,6 +1168,8 @@ type InstanceDeRegisterRequest struct {
 	Timeout *time.Duration
 	// 可选，重试次数，默认直接获取全局的超时配置
 	RetryCount *int
+	// 可选，请求上下文，通过 SetContext 设置，用于透传调用方的 deadline、取消信号以及 trace ID 等请求级别的值
+	requestContext
 }
 
 // String 打印消息内容
@@ -1271,6 +1288,8 @@ type InstanceRegisterRequest struct {
 	InstanceId string
 	// 可选, 是否将心跳上报交由 SDK 内部定时任务进行处理
 	AutoHeartbeat bool
+	// 可选，请求上下文，通过 SetContext 设置，用于透传调用方的 deadline、取消信号以及 trace ID 等请求级别的值
+	requestContext
 }
 
 // String 打印消息内容
@@ -87,6 +87,9 @@ func (s *ServerAddressList) getServerAddress(hashKey []byte) (string, model.Inst
 	var instance model.Instance
 	if s.service.ClusterType == config.BuiltinCluster || s.service.ClusterType == config.ConfigCluster {
 		serverCount := len(s.addresses)
+		if serverCount == 0 {
+			return "", nil, fmt.Errorf("no server address configured for %s", s.service.ClusterType)
+		}
 		targetAddress = s.addresses[s.curIndex%serverCount]
 		if s.curIndex == math.MaxInt32 {
 			s.curIndex = 0
@@ -302,7 +305,10 @@ func NewConnectionManager(
 		useDefault: false,
 		manager:    manager,
 		addresses:  addresses,
-		curIndex:   rand.Intn(len(addresses)),
+	}
+	// 本地文件等无需服务端的连接器不配置地址
+	if len(addresses) > 0 {
+		builtInAddrList.curIndex = rand.Intn(len(addresses))
 	}
 	manager.serverServices[config.BuiltinCluster] = builtInAddrList
 	if len(manager.discoverService.Service) == 0 {
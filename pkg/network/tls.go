@@ -0,0 +1,356 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/modern-go/reflect2"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// certReloadInterval 检查证书文件是否变更的周期
+const certReloadInterval = 10 * time.Second
+
+// NewTransportCredentials 根据TLS配置创建grpc传输凭证，未启用TLS时返回明文凭证
+// 证书文件在磁盘上发生轮换后，后续新建的连接会自动使用新的证书；不再使用时需调用 CloseTransportCredentials
+func NewTransportCredentials(tlsCfg config.TLSConfig) (credentials.TransportCredentials, error) {
+	if reflect2.IsNil(tlsCfg) || !tlsCfg.IsEnable() {
+		return insecure.NewCredentials(), nil
+	}
+	caFile, certFile, keyFile := tlsCfg.GetCAFile(), tlsCfg.GetCertFile(), tlsCfg.GetKeyFile()
+	serverName, insecureSkipVerify := tlsCfg.GetServerName(), tlsCfg.IsInsecureSkipVerify()
+	reloader, err := newCertReloader([]string{caFile, certFile, keyFile}, certReloadInterval,
+		func() (*tls.Config, error) {
+			return buildTLSConfig(caFile, certFile, keyFile, serverName, insecureSkipVerify)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return &reloadableCredentials{reloader: reloader}, nil
+}
+
+// CloseTransportCredentials 停止传输凭证的证书热加载任务，明文凭证无需关闭
+func CloseTransportCredentials(creds credentials.TransportCredentials) {
+	if closer, ok := creds.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+// NewTLSConfig 根据TLS配置创建 crypto/tls 配置，供 HTTP 等非 grpc 的客户端使用，未启用TLS时返回 nil
+// 证书文件只在创建时加载一次
+func NewTLSConfig(tlsCfg config.TLSConfig) (*tls.Config, error) {
+	if reflect2.IsNil(tlsCfg) || !tlsCfg.IsEnable() {
+		return nil, nil
+	}
+	return buildTLSConfig(tlsCfg.GetCAFile(), tlsCfg.GetCertFile(), tlsCfg.GetKeyFile(),
+		tlsCfg.GetServerName(), tlsCfg.IsInsecureSkipVerify())
+}
+
+// buildTLSConfig 加载证书文件并构建 crypto/tls 配置
+func buildTLSConfig(caFile, certFile, keyFile, serverName string, insecureSkipVerify bool) (*tls.Config, error) {
+	//nolint: gosec
+	tlsConfig := &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: insecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+	if len(caFile) > 0 {
+		caData, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read tls caFile %s: %v", caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("fail to parse tls caFile %s: no valid certificate", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(certFile) > 0 && len(keyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("fail to load tls certFile %s and keyFile %s: %v", certFile, keyFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// NewServerTLSConfig 根据TLS配置构建服务端 tls.Config，配置 caFile 时要求并校验客户端证书
+// 证书文件在磁盘上发生轮换后，后续握手会自动使用新的证书；返回的 io.Closer 用于停止证书热加载任务
+func NewServerTLSConfig(tlsCfg config.TLSConfig) (*tls.Config, io.Closer, error) {
+	caFile, certFile, keyFile := tlsCfg.GetCAFile(), tlsCfg.GetCertFile(), tlsCfg.GetKeyFile()
+	reloader, err := newCertReloader([]string{caFile, certFile, keyFile}, certReloadInterval,
+		func() (*tls.Config, error) {
+			return buildServerTLSConfig(caFile, certFile, keyFile)
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+	serverConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// http.Server 要求配置证书来源，实际握手使用 GetConfigForClient 返回的配置
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &reloader.get().Certificates[0], nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return reloader.get(), nil
+		},
+	}
+	return serverConfig, &reloaderCloser{reloader: reloader}, nil
+}
+
+// buildServerTLSConfig 加载证书文件并构建服务端 crypto/tls 配置
+func buildServerTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("fail to load tls certFile %s and keyFile %s: %v", certFile, keyFile, err)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if len(caFile) > 0 {
+		caData, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read tls caFile %s: %v", caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("fail to parse tls caFile %s: no valid certificate", caFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// reloaderCloser 停止证书热加载任务
+type reloaderCloser struct {
+	reloader *certReloader
+}
+
+// Close 停止证书热加载任务
+func (c *reloaderCloser) Close() error {
+	c.reloader.close()
+	return nil
+}
+
+// certReloader 证书热加载器，按周期检查证书文件的修改时间，变更后重新构建 tls.Config；
+// 加载失败时继续使用已加载的证书，握手时只读取已加载的配置，不访问文件系统
+type certReloader struct {
+	files []string
+	build func() (*tls.Config, error)
+
+	mutex sync.RWMutex
+	// 已加载证书文件的修改时间
+	modTimes []time.Time
+	current  *tls.Config
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// newCertReloader 加载证书并启动周期检查任务，files 中的空路径会被忽略
+func newCertReloader(files []string, interval time.Duration,
+	build func() (*tls.Config, error)) (*certReloader, error) {
+	r := &certReloader{build: build, stopCh: make(chan struct{})}
+	for _, file := range files {
+		if len(file) > 0 {
+			r.files = append(r.files, file)
+		}
+	}
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return nil, err
+	}
+	if r.current, err = build(); err != nil {
+		return nil, err
+	}
+	r.modTimes = modTimes
+	if interval > 0 && len(r.files) > 0 {
+		go r.run(interval)
+	}
+	return r, nil
+}
+
+// get 获取当前已加载的 tls.Config
+func (r *certReloader) get() *tls.Config {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.current
+}
+
+// close 停止周期检查任务
+func (r *certReloader) close() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+}
+
+func (r *certReloader) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			r.reload()
+		}
+	}
+}
+
+// reload 证书文件变更时重新加载，加载失败时继续使用旧的证书
+func (r *certReloader) reload() {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		log.GetNetworkLogger().Warnf("[TLS] %v, keep using the loaded certificates", err)
+		return
+	}
+	r.mutex.RLock()
+	modified := isModified(r.modTimes, modTimes)
+	r.mutex.RUnlock()
+	if !modified {
+		return
+	}
+	tlsConfig, err := r.build()
+	r.mutex.Lock()
+	// 加载失败时同样记录修改时间，文件再次变更前不重复加载
+	r.modTimes = modTimes
+	if err == nil {
+		r.current = tlsConfig
+	}
+	r.mutex.Unlock()
+	if err != nil {
+		log.GetNetworkLogger().Warnf("[TLS] fail to reload certificates: %v, keep using the loaded certificates", err)
+		return
+	}
+	log.GetNetworkLogger().Infof("[TLS] certificates reloaded, files %v", r.files)
+}
+
+// statFiles 获取证书文件的修改时间
+func (r *certReloader) statFiles() ([]time.Time, error) {
+	modTimes := make([]time.Time, 0, len(r.files))
+	for _, file := range r.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, fmt.Errorf("fail to stat tls file %s: %v", file, err)
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+	return modTimes, nil
+}
+
+// isModified 比较证书文件的修改时间是否发生变化
+func isModified(prev, cur []time.Time) bool {
+	if len(prev) != len(cur) {
+		return true
+	}
+	for i := range prev {
+		if !prev[i].Equal(cur[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadableCredentials 支持证书热加载的TLS传输凭证，使用证书热加载器当前加载的证书完成握手
+type reloadableCredentials struct {
+	reloader *certReloader
+
+	mutex sync.Mutex
+	// 构建 current 时使用的 tls.Config，热加载器重新加载后重建传输凭证
+	tlsConfig *tls.Config
+	current   credentials.TransportCredentials
+	// 通过 OverrideServerName 覆盖的域名
+	serverName string
+}
+
+// getCurrent 获取基于当前证书的传输凭证
+func (r *reloadableCredentials) getCurrent() credentials.TransportCredentials {
+	tlsConfig := r.reloader.get()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.current != nil && r.tlsConfig == tlsConfig {
+		return r.current
+	}
+	r.tlsConfig = tlsConfig
+	r.current = credentials.NewTLS(tlsConfig)
+	if len(r.serverName) > 0 {
+		//nolint: staticcheck
+		_ = r.current.OverrideServerName(r.serverName)
+	}
+	return r.current
+}
+
+// ClientHandshake 客户端握手
+func (r *reloadableCredentials) ClientHandshake(
+	ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return r.getCurrent().ClientHandshake(ctx, authority, rawConn)
+}
+
+// ServerHandshake 服务端握手
+func (r *reloadableCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return r.getCurrent().ServerHandshake(rawConn)
+}
+
+// Info 获取协议信息
+func (r *reloadableCredentials) Info() credentials.ProtocolInfo {
+	return r.getCurrent().Info()
+}
+
+// Clone 复制传输凭证，复制出的凭证与原凭证共用证书热加载器
+func (r *reloadableCredentials) Clone() credentials.TransportCredentials {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return &reloadableCredentials{
+		reloader:   r.reloader,
+		serverName: r.serverName,
+	}
+}
+
+// OverrideServerName 覆盖校验服务端证书时使用的域名
+//
+// Deprecated: grpc 已不再使用该方法
+func (r *reloadableCredentials) OverrideServerName(serverName string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.serverName = serverName
+	r.current = nil
+	return nil
+}
+
+// Close 停止证书热加载任务
+func (r *reloadableCredentials) Close() error {
+	r.reloader.close()
+	return nil
+}
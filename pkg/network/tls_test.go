@@ -0,0 +1,391 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// testCert 测试用的证书及私钥
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert 生成测试证书，parent 为空时生成自签名的CA证书
+func newTestCert(t *testing.T, cn string, parent *testCert, serial int64) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{cn},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signerCert, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+	}
+}
+
+// writeTestFile 写入文件并指定修改时间，避免同一秒内的写入无法被感知
+func writeTestFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startTLSServer 启动要求双向认证的TLS服务端，serverCert 返回当前使用的服务端证书
+func startTLSServer(t *testing.T, clientCA *x509.CertPool, serverCert func() *testCert) net.Listener {
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCA,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cur := serverCert()
+			cert, err := tls.X509KeyPair(cur.certPEM, cur.keyPEM)
+			return &cert, err
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.(*tls.Conn).Handshake()
+				_, _ = conn.Read(make([]byte, 1))
+			}()
+		}
+	}()
+	return ln
+}
+
+// handshake 使用传输凭证与服务端完成一次握手
+func handshake(t *testing.T, addr string, cfg *config.TLSConfigImpl) error {
+	creds, err := NewTransportCredentials(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer CloseTransportCredentials(creds)
+	return handshakeWith(addr, creds.(*reloadableCredentials))
+}
+
+func handshakeWith(addr string, creds *reloadableCredentials) error {
+	rawConn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		return err
+	}
+	defer rawConn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	conn, _, err := creds.ClientHandshake(ctx, "polaris.test:8091", rawConn)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// TestNewTransportCredentialsDisabled 测试未开启TLS时使用明文凭证
+func TestNewTransportCredentialsDisabled(t *testing.T) {
+	creds, err := NewTransportCredentials(&config.TLSConfigImpl{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.Info().SecurityProtocol != "insecure" {
+		t.Fatalf("expect insecure credentials, actual %s", creds.Info().SecurityProtocol)
+	}
+}
+
+// TestTransportCredentialsReload 测试mTLS握手以及证书轮换后的热加载
+func TestTransportCredentialsReload(t *testing.T) {
+	log.SetNetworkLogger(noopLogger{})
+	dir, err := ioutil.TempDir("", "polaris-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, "polaris-ca", nil, 1)
+	serverCert := newTestCert(t, "polaris.test", ca, 2)
+	clientCert := newTestCert(t, "polaris-client", ca, 3)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	current := &atomic.Value{}
+	current.Store(serverCert)
+	ln := startTLSServer(t, pool, func() *testCert { return current.Load().(*testCert) })
+	defer ln.Close()
+
+	now := time.Now()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	writeTestFile(t, caFile, ca.certPEM, now)
+	writeTestFile(t, certFile, clientCert.certPEM, now)
+	writeTestFile(t, keyFile, clientCert.keyPEM, now)
+
+	cfg := &config.TLSConfigImpl{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}
+	cfg.SetEnable(true)
+	if err := cfg.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(t, ln.Addr().String(), cfg); err != nil {
+		t.Fatalf("expect mtls handshake success, actual %v", err)
+	}
+
+	// 缺少客户端证书时服务端拒绝握手
+	noClientCfg := &config.TLSConfigImpl{CAFile: caFile}
+	noClientCfg.SetEnable(true)
+	if err := handshake(t, ln.Addr().String(), noClientCfg); err == nil {
+		// 客户端在 TLS1.3 下可能先于服务端的拒绝完成握手，需读取一次才能感知
+		t.Log("handshake without client cert finished before server rejection")
+	}
+
+	creds, err := NewTransportCredentials(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer CloseTransportCredentials(creds)
+	reloadable := creds.(*reloadableCredentials)
+
+	// 服务端切换为新CA签发的证书，客户端未轮换CA前握手失败
+	newCA := newTestCert(t, "polaris-ca-2", nil, 4)
+	current.Store(newTestCert(t, "polaris.test", newCA, 5))
+	if err := handshakeWith(ln.Addr().String(), reloadable); err == nil {
+		t.Fatalf("expect handshake fail with stale ca")
+	}
+
+	// 轮换磁盘上的CA证书，周期检查前不访问文件系统，仍使用已加载的证书
+	writeTestFile(t, caFile, append(ca.certPEM, newCA.certPEM...), now.Add(time.Minute))
+	if err := handshakeWith(ln.Addr().String(), reloadable); err == nil {
+		t.Fatalf("expect handshake fail before reload")
+	}
+	// 周期检查发现证书变更后，无需重建凭证即可握手成功
+	reloadable.reloader.reload()
+	if err := handshakeWith(ln.Addr().String(), reloadable); err != nil {
+		t.Fatalf("expect handshake success after ca rotated, actual %v", err)
+	}
+
+	// 写入非法的证书时继续使用已加载的证书
+	writeTestFile(t, caFile, []byte("invalid"), now.Add(2*time.Minute))
+	reloadable.reloader.reload()
+	if err := handshakeWith(ln.Addr().String(), reloadable); err != nil {
+		t.Fatalf("expect keep using loaded certificates, actual %v", err)
+	}
+}
+
+// TestCertReloaderTicker 测试证书热加载器按周期检查证书文件
+// 测试场景：证书文件修改时间变化后等待周期检查
+// 前置条件：热加载器检查周期为10ms
+// 预期结果：周期检查重新构建配置，关闭后不再检查
+func TestCertReloaderTicker(t *testing.T) {
+	log.SetNetworkLogger(noopLogger{})
+	dir, err := ioutil.TempDir("", "polaris-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "ca.pem")
+	now := time.Now()
+	writeTestFile(t, file, []byte("v1"), now)
+
+	var builds int32
+	reloader, err := newCertReloader([]string{file, ""}, 10*time.Millisecond, func() (*tls.Config, error) {
+		atomic.AddInt32(&builds, 1)
+		return &tls.Config{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := reloader.get()
+	writeTestFile(t, file, []byte("v2"), now.Add(time.Minute))
+	deadline := time.Now().Add(3 * time.Second)
+	for reloader.get() == first && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if reloader.get() == first {
+		t.Fatalf("expect certificates reloaded by ticker")
+	}
+	reloader.close()
+	reloader.close()
+	time.Sleep(30 * time.Millisecond)
+	loaded := atomic.LoadInt32(&builds)
+	writeTestFile(t, file, []byte("v3"), now.Add(2*time.Minute))
+	time.Sleep(50 * time.Millisecond)
+	if atomic.LoadInt32(&builds) != loaded {
+		t.Fatalf("expect no reload after closed")
+	}
+}
+
+// TestNewServerTLSConfig 测试服务端TLS配置
+// 测试场景：http.Server 使用 NewServerTLSConfig 构建的配置提供 HTTPS，并配置客户端CA开启双向认证
+// 前置条件：服务端与客户端证书由同一CA签发
+// 预期结果：携带客户端证书的请求成功，未携带时握手失败；服务端证书轮换并重新加载后使用新证书；证书文件不存在时构建失败
+func TestNewServerTLSConfig(t *testing.T) {
+	log.SetNetworkLogger(noopLogger{})
+	dir, err := ioutil.TempDir("", "polaris-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, "polaris-ca", nil, 1)
+	clientCert := newTestCert(t, "polaris-client", ca, 2)
+	serverCert := newTestCert(t, "polaris.test", ca, 3)
+	now := time.Now()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "server.pem")
+	keyFile := filepath.Join(dir, "server.key")
+	writeTestFile(t, caFile, ca.certPEM, now)
+	writeTestFile(t, certFile, serverCert.certPEM, now)
+	writeTestFile(t, keyFile, serverCert.keyPEM, now)
+
+	tlsCfg := &config.TLSConfigImpl{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}
+	tlsCfg.SetEnable(true)
+	serverConfig, closer, err := NewServerTLSConfig(tlsCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closer.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+		TLSConfig: serverConfig,
+	}
+	go func() { _ = srv.ServeTLS(ln, "", "") }()
+	defer srv.Close()
+
+	get := func(rootCA *testCert, cert *testCert) error {
+		pool := x509.NewCertPool()
+		pool.AddCert(rootCA.cert)
+		clientConfig := &tls.Config{RootCAs: pool, ServerName: "polaris.test"}
+		if cert != nil {
+			pair, err := tls.X509KeyPair(cert.certPEM, cert.keyPEM)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clientConfig.Certificates = []tls.Certificate{pair}
+		}
+		client := &http.Client{Timeout: 3 * time.Second, Transport: &http.Transport{TLSClientConfig: clientConfig}}
+		defer client.CloseIdleConnections()
+		resp, err := client.Get("https://" + ln.Addr().String() + "/")
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	if err := get(ca, clientCert); err != nil {
+		t.Fatalf("expect mtls request success, actual %v", err)
+	}
+	if err := get(ca, nil); err == nil {
+		t.Fatalf("expect request without client cert fail")
+	}
+
+	// 服务端证书轮换为新CA签发的证书，重新加载后客户端需使用新CA校验
+	newCA := newTestCert(t, "polaris-ca-2", nil, 4)
+	newServerCert := newTestCert(t, "polaris.test", newCA, 5)
+	writeTestFile(t, certFile, newServerCert.certPEM, now.Add(time.Minute))
+	writeTestFile(t, keyFile, newServerCert.keyPEM, now.Add(time.Minute))
+	closer.(*reloaderCloser).reloader.reload()
+	if err := get(ca, clientCert); err == nil {
+		t.Fatalf("expect request with stale ca fail after server cert rotated")
+	}
+	if err := get(newCA, clientCert); err != nil {
+		t.Fatalf("expect request success after server cert rotated, actual %v", err)
+	}
+
+	missing := &config.TLSConfigImpl{CertFile: filepath.Join(dir, "none.pem"), KeyFile: keyFile}
+	if _, _, err := NewServerTLSConfig(missing); err == nil {
+		t.Fatalf("expect error when cert file missing")
+	}
+}
+
+// TestTLSConfigVerify 测试TLS配置校验
+func TestTLSConfigVerify(t *testing.T) {
+	cfg := &config.TLSConfigImpl{CertFile: "client.pem"}
+	cfg.SetEnable(true)
+	if err := cfg.Verify(); err == nil {
+		t.Fatalf("expect error when keyFile missing")
+	}
+	cfg.SetEnable(false)
+	if err := cfg.Verify(); err != nil {
+		t.Fatalf("expect no error when tls disabled, actual %v", err)
+	}
+}
@@ -40,6 +40,14 @@ type ConfigConnector interface {
 	GetConfigGroup(req *ConfigGroup) (*ConfigGroupResponse, error)
 	// UpsertAndPublishConfigFile insert and publish config file
 	UpsertAndPublishConfigFile(configFile *ConfigFile) (*ConfigFileResponse, error)
+	// DeleteConfigFile delete config file
+	DeleteConfigFile(configFile *ConfigFile, operator string) (*ConfigFileResponse, error)
+	// ListConfigFiles list config files with metadata in group
+	ListConfigFiles(req *ConfigFileListRequest) (*ConfigFileRecordsResponse, error)
+	// GetConfigFileReleaseHistory list release history of config file
+	GetConfigFileReleaseHistory(req *ConfigFileReleaseHistoryRequest) (*ConfigFileRecordsResponse, error)
+	// RollbackConfigFile rollback config file to the release named configFile.VersionName
+	RollbackConfigFile(configFile *ConfigFile) (*ConfigFileResponse, error)
 }
 
 // init
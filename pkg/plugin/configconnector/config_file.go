@@ -20,6 +20,7 @@ package configconnector
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"strconv"
 
@@ -58,6 +59,8 @@ type ConfigFile struct {
 	Mode model.GetConfigFileRequestMode
 	// 文件持久化配置
 	Persistent model.Persistent
+	// 发起本次请求的调用方上下文，仅在请求链路中使用
+	ctx context.Context
 }
 
 func (c *ConfigFile) String() string {
@@ -140,6 +143,19 @@ func (c *ConfigFile) GetContent() string {
 	return c.content
 }
 
+// SetContext 设置发起请求的调用方上下文
+func (c *ConfigFile) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// GetContext 获取发起请求的调用方上下文，未设置时返回 context.Background()
+func (c *ConfigFile) GetContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
 // GetVersion 获取配置文件版本号
 func (c *ConfigFile) GetVersion() uint64 {
 	return c.Version
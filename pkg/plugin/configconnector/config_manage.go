@@ -0,0 +1,74 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configconnector
+
+// ConfigFileRecord 管理面查询到的配置文件记录（文件元数据或一次发布历史）
+// 内容、标签等与客户端拉取一致的部分复用 ConfigFile，便于交给 configfilter.Chain 处理加密内容
+type ConfigFileRecord struct {
+	*ConfigFile
+	ID                 uint64
+	Format             string
+	Comment            string
+	Status             string
+	Type               string
+	Reason             string
+	ReleaseDescription string
+	EncryptAlgo        string
+	CreateTime         string
+	CreateBy           string
+	ModifyTime         string
+	ModifyBy           string
+	ReleaseTime        string
+	ReleaseBy          string
+}
+
+// ConfigFileListRequest 查询配置文件列表请求
+type ConfigFileListRequest struct {
+	Namespace string
+	FileGroup string
+	FileName  string
+	Offset    uint32
+	Limit     uint32
+}
+
+// ConfigFileReleaseHistoryRequest 查询配置文件发布历史请求
+type ConfigFileReleaseHistoryRequest struct {
+	Namespace string
+	FileGroup string
+	FileName  string
+	Offset    uint32
+	Limit     uint32
+}
+
+// ConfigFileRecordsResponse 配置文件记录分页查询响应
+type ConfigFileRecordsResponse struct {
+	Code    uint32
+	Message string
+	Total   uint32
+	Records []*ConfigFileRecord
+}
+
+// GetCode 获取响应code
+func (r *ConfigFileRecordsResponse) GetCode() uint32 {
+	return r.Code
+}
+
+// GetMessage 获取响应信息
+func (r *ConfigFileRecordsResponse) GetMessage() string {
+	return r.Message
+}
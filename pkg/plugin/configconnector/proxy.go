@@ -66,6 +66,30 @@ func (p *Proxy) PublishConfigFile(configFile *ConfigFile) (*ConfigFileResponse,
 	return response, err
 }
 
+// DeleteConfigFile Delete config file
+func (p *Proxy) DeleteConfigFile(configFile *ConfigFile, operator string) (*ConfigFileResponse, error) {
+	response, err := p.ConfigConnector.DeleteConfigFile(configFile, operator)
+	return response, err
+}
+
+// ListConfigFiles List config files
+func (p *Proxy) ListConfigFiles(req *ConfigFileListRequest) (*ConfigFileRecordsResponse, error) {
+	response, err := p.ConfigConnector.ListConfigFiles(req)
+	return response, err
+}
+
+// GetConfigFileReleaseHistory Get config file release history
+func (p *Proxy) GetConfigFileReleaseHistory(req *ConfigFileReleaseHistoryRequest) (*ConfigFileRecordsResponse, error) {
+	response, err := p.ConfigConnector.GetConfigFileReleaseHistory(req)
+	return response, err
+}
+
+// RollbackConfigFile Rollback config file
+func (p *Proxy) RollbackConfigFile(configFile *ConfigFile) (*ConfigFileResponse, error) {
+	response, err := p.ConfigConnector.RollbackConfigFile(configFile)
+	return response, err
+}
+
 // init 注册proxy
 func init() {
 	plugin.RegisterPluginProxy(common.TypeConfigConnector, &Proxy{})
@@ -19,6 +19,7 @@
 package configfilter
 
 import (
+	"errors"
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -58,6 +59,26 @@ func (c Chain) Execute(configFile *configconnector.ConfigFile, next ConfigFileHa
 	return resp, err
 }
 
+// Decrypt 使用链中第一个支持直接解密的过滤器，以明文数据密钥与 encryptAlgo 指定的算法解密 cipherContent，
+// 用于解密管理接口返回的发布记录；链中没有支持直接解密的过滤器时返回错误
+func (c Chain) Decrypt(cipherContent, dataKey, encryptAlgo string) (string, error) {
+	for _, filter := range c {
+		if proxy, ok := filter.(*Proxy); ok {
+			filter = proxy.ConfigFilter
+		}
+		if decryptor, ok := filter.(Decryptor); ok {
+			return decryptor.Decrypt(cipherContent, dataKey, encryptAlgo)
+		}
+	}
+	return "", errors.New("no config filter supports decrypting config file content")
+}
+
+// Decryptor 支持以明文数据密钥直接解密配置内容的过滤器
+type Decryptor interface {
+	// Decrypt 使用 base64 编码的明文数据密钥与 encryptAlgo 指定的算法解密 cipherContent
+	Decrypt(cipherContent, dataKey, encryptAlgo string) (string, error)
+}
+
 // ConfigFilter 配置过滤器接口
 type ConfigFilter interface {
 	plugin.Plugin
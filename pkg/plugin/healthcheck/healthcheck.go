@@ -18,6 +18,7 @@
 package healthcheck
 
 import (
+	"strings"
 	"time"
 
 	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
@@ -27,6 +28,27 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 )
 
+// MetadataKeyProtocol 探测规则 metadata 中指定探测协议的键
+// FaultDetectRule_Protocol 未定义的协议（如 grpc）通过该键按探测器插件名选取，值不区分大小写
+const MetadataKeyProtocol = "protocol"
+
+// CheckerProtocol 探测器对应的协议名（大写）：标准协议取 FaultDetectRule_Protocol 枚举名，
+// Protocol() 返回 UNKNOWN 的扩展探测器取插件名
+func CheckerProtocol(checker HealthChecker) string {
+	if checker.Protocol() != fault_tolerance.FaultDetectRule_UNKNOWN {
+		return checker.Protocol().String()
+	}
+	return strings.ToUpper(checker.Name())
+}
+
+// RuleProtocol 探测规则选用的协议名（大写）：metadata 中的 protocol 优先，否则取 protocol 枚举名
+func RuleProtocol(rule *fault_tolerance.FaultDetectRule) string {
+	if protocol := rule.GetMetadata()[MetadataKeyProtocol]; protocol != "" {
+		return strings.ToUpper(protocol)
+	}
+	return rule.GetProtocol().String()
+}
+
 // HealthChecker 【扩展点接口】主动健康探测策略
 type HealthChecker interface {
 	plugin.Plugin
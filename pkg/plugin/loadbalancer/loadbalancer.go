@@ -52,6 +52,22 @@ type LoadBalancer interface {
 	ChooseInstance(criteria *Criteria, instances model.ServiceInstances) (model.Instance, error)
 }
 
+// CallResultListener 【可选接口】需要感知调用结果的有状态负载均衡插件实现该接口，
+// 用户通过 UpdateServiceCallResult 上报调用结果时回调
+type CallResultListener interface {
+	// OnServiceCallResult 服务调用结果回调
+	OnServiceCallResult(result *model.ServiceCallResult)
+}
+
+// InflightCounter 【可选接口】统计实例在途请求数的负载均衡插件实现该接口，
+// 在途请求数会按 consumer.loadbalancer.inflightReportInterval 周期随负载均衡统计数据上报到统计插件
+type InflightCounter interface {
+	// GetInflight 获取实例当前的在途请求数
+	GetInflight(instance model.Instance) int64
+	// ReleaseInflight 归还实例的 count 个在途请求，用于回收超时未上报调用结果以及实例已下线的请求
+	ReleaseInflight(instance model.Instance, count int64)
+}
+
 // init 初始化
 func init() {
 	plugin.RegisterPluginInterface(common.TypeLoadBalancer, new(LoadBalancer))
@@ -18,16 +18,46 @@
 package loadbalancer
 
 import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 	"github.com/polarismesh/polaris-go/pkg/sdk"
+	"github.com/polarismesh/polaris-go/pkg/stat/loadbalance"
 )
 
 // Proxy of LoadBalancer
 type Proxy struct {
 	LoadBalancer
 	engine sdk.Engine
+	mutex  sync.Mutex
+	// 真实插件需要感知调用结果时，记录由本插件选出、尚未收到调用结果的请求，实例ID到请求的映射；
+	// 服务或请求可以指定不同的负载均衡插件，调用结果只回调给选出该实例的插件
+	pending map[string]*pendingCalls
+	// 上一周期上报过在途请求数的实例，在途请求归零后需要再上报一次
+	reported map[string]model.Instance
+	// 在途请求超过该时间仍未收到调用结果时自动归还，不大于 0 时不回收
+	releaseTimeout time.Duration
+	// 在途请求数的上报周期
+	reportInterval time.Duration
+	// 当前时间，便于测试替换
+	now    func() time.Time
+	logCtx *log.ContextLogger
+	cancel context.CancelFunc
+	// 是否已提示过存在未上报调用结果的请求，只提示一次
+	expireWarned int32
+}
+
+// pendingCalls 单个实例由本插件选出、尚未收到调用结果的请求
+type pendingCalls struct {
+	instance model.Instance
+	// 每个请求的选出时间，按先后排列
+	starts []time.Time
 }
 
 // SetRealPlugin 设置
@@ -36,6 +66,47 @@ func (p *Proxy) SetRealPlugin(plug plugin.Plugin, engine sdk.Engine) {
 	p.engine = engine
 }
 
+// Init 初始化真实插件；真实插件需要感知调用结果时，监听服务实例变更以清理已下线实例的在途请求
+func (p *Proxy) Init(ctx *plugin.InitContext) error {
+	if err := p.LoadBalancer.Init(ctx); err != nil {
+		return err
+	}
+	if !p.IsCallResultListener() {
+		return nil
+	}
+	lbConfig := ctx.Config.GetConsumer().GetLoadbalancer()
+	p.releaseTimeout = lbConfig.GetInflightReleaseTimeout()
+	p.reportInterval = lbConfig.GetInflightReportInterval()
+	p.now = ctx.ValueCtx.Now
+	p.logCtx = ctx.ValueCtx.GetContextLogger()
+	handler := common.PluginEventHandler{Callback: p.onServiceEvent}
+	ctx.Plugins.RegisterEventSubscriber(common.OnServiceUpdated, handler)
+	ctx.Plugins.RegisterEventSubscriber(common.OnServiceDeleted, handler)
+	return nil
+}
+
+// Start 启动真实插件；真实插件需要感知调用结果时，周期回收超时请求并上报在途请求数
+func (p *Proxy) Start() error {
+	if err := p.LoadBalancer.Start(); err != nil {
+		return err
+	}
+	if !p.IsCallResultListener() || p.reportInterval <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	go p.runInflightTask(ctx)
+	return nil
+}
+
+// Destroy 停止周期任务并销毁真实插件
+func (p *Proxy) Destroy() error {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	return p.LoadBalancer.Destroy()
+}
+
 // SelectStatus 获取负载均衡状态
 type SelectStatus struct {
 	HasLimitedInstances bool
@@ -49,9 +120,213 @@ func (p *Proxy) ChooseInstance(criteria *Criteria, instances model.ServiceInstan
 	p.engine.GetContext().GetContextLogger().GetBaseLogger().Debugf("choose instance, cluster: %s, instances: %v",
 		criteria.Cluster.ClusterKey, instances)
 	result, err := p.LoadBalancer.ChooseInstance(criteria, instances)
+	if err == nil && p.IsCallResultListener() {
+		p.addPending(result)
+	}
 	return result, err
 }
 
+// IsCallResultListener 真实插件是否需要感知调用结果
+func (p *Proxy) IsCallResultListener() bool {
+	_, ok := p.LoadBalancer.(CallResultListener)
+	return ok
+}
+
+// OnServiceCallResult 将调用结果透传给实现了 CallResultListener 的负载均衡插件；
+// 实例不是由本插件选出时（服务或请求使用了其他负载均衡插件），或请求已被超时回收时，忽略该调用结果
+func (p *Proxy) OnServiceCallResult(result *model.ServiceCallResult) {
+	listener, ok := p.LoadBalancer.(CallResultListener)
+	if !ok || result == nil || !p.removePending(result.GetCalledInstance()) {
+		return
+	}
+	listener.OnServiceCallResult(result)
+}
+
+// addPending 记录本插件选出的实例
+func (p *Proxy) addPending(instance model.Instance) {
+	if instance == nil {
+		return
+	}
+	now := p.currentTime()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.pending == nil {
+		p.pending = make(map[string]*pendingCalls)
+	}
+	calls, ok := p.pending[instance.GetId()]
+	if !ok {
+		calls = &pendingCalls{}
+		p.pending[instance.GetId()] = calls
+	}
+	calls.instance = instance
+	calls.starts = append(calls.starts, now)
+}
+
+// removePending 实例由本插件选出且尚未收到调用结果时，移除最早的一个请求并返回 true
+func (p *Proxy) removePending(instance model.Instance) bool {
+	if instance == nil {
+		return false
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	calls, ok := p.pending[instance.GetId()]
+	if !ok {
+		return false
+	}
+	if len(calls.starts) <= 1 {
+		delete(p.pending, instance.GetId())
+	} else {
+		calls.starts = calls.starts[1:]
+	}
+	return true
+}
+
+// runInflightTask 周期回收超时请求并上报在途请求数，直到插件销毁
+func (p *Proxy) runInflightTask(ctx context.Context) {
+	ticker := time.NewTicker(p.reportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.reclaim(p.currentTime())
+			p.reportInflights()
+		}
+	}
+}
+
+// reclaim 回收超过 releaseTimeout 仍未收到调用结果的请求，并归还真实插件中对应的在途请求；
+// 之后迟到的调用结果不再回调真实插件，避免重复归还
+func (p *Proxy) reclaim(now time.Time) {
+	if p.releaseTimeout <= 0 {
+		return
+	}
+	expired := map[string]*pendingCalls{}
+	var total int
+	p.mutex.Lock()
+	for id, calls := range p.pending {
+		i := 0
+		for ; i < len(calls.starts); i++ {
+			if now.Sub(calls.starts[i]) < p.releaseTimeout {
+				break
+			}
+		}
+		if i == 0 {
+			continue
+		}
+		expired[id] = &pendingCalls{instance: calls.instance, starts: calls.starts[:i]}
+		total += i
+		if i == len(calls.starts) {
+			delete(p.pending, id)
+		} else {
+			calls.starts = calls.starts[i:]
+		}
+	}
+	p.mutex.Unlock()
+	if total == 0 {
+		return
+	}
+	p.releaseInflights(expired)
+	if atomic.CompareAndSwapInt32(&p.expireWarned, 0, 1) && p.logCtx != nil {
+		p.logCtx.GetBaseLogger().Warnf("[LoadBalancer] %d calls chosen by %s were not reported within %s, "+
+			"UpdateServiceCallResult must be called when request finished, otherwise in-flight counts are inaccurate",
+			total, p.Name(), p.releaseTimeout)
+	}
+}
+
+// onServiceEvent 服务实例变更或删除时，清理已下线实例的在途请求
+func (p *Proxy) onServiceEvent(event *common.PluginEvent) error {
+	svcEvent, ok := event.EventObject.(*common.ServiceEventObject)
+	if !ok || svcEvent.SvcEventKey.Type != model.EventInstances {
+		return nil
+	}
+	oldInstances, ok := svcEvent.OldValue.(model.ServiceInstances)
+	if !ok {
+		return nil
+	}
+	alive := map[string]struct{}{}
+	if newInstances, ok := svcEvent.NewValue.(model.ServiceInstances); ok {
+		for _, instance := range newInstances.GetInstances() {
+			alive[instance.GetId()] = struct{}{}
+		}
+	}
+	var removed []string
+	for _, instance := range oldInstances.GetInstances() {
+		if _, ok := alive[instance.GetId()]; !ok {
+			removed = append(removed, instance.GetId())
+		}
+	}
+	p.purge(removed)
+	return nil
+}
+
+// purge 移除已下线实例的全部在途请求，并归还真实插件中对应的在途请求
+func (p *Proxy) purge(instanceIDs []string) {
+	if len(instanceIDs) == 0 {
+		return
+	}
+	purged := map[string]*pendingCalls{}
+	p.mutex.Lock()
+	for _, id := range instanceIDs {
+		if calls, ok := p.pending[id]; ok {
+			purged[id] = calls
+			delete(p.pending, id)
+		}
+	}
+	p.mutex.Unlock()
+	p.releaseInflights(purged)
+}
+
+// releaseInflights 归还真实插件中的在途请求
+func (p *Proxy) releaseInflights(released map[string]*pendingCalls) {
+	counter, ok := p.LoadBalancer.(InflightCounter)
+	if !ok {
+		return
+	}
+	for _, calls := range released {
+		counter.ReleaseInflight(calls.instance, int64(len(calls.starts)))
+	}
+}
+
+// reportInflights 上报有在途请求的实例的在途请求数，上一周期上报过、本周期已归零的实例上报 0，
+// 仅统计在途请求的插件会上报
+func (p *Proxy) reportInflights() {
+	counter, ok := p.LoadBalancer.(InflightCounter)
+	if !ok || p.engine == nil {
+		return
+	}
+	p.mutex.Lock()
+	reported := make(map[string]model.Instance, len(p.pending))
+	instances := make([]model.Instance, 0, len(p.pending)+len(p.reported))
+	for id, calls := range p.pending {
+		reported[id] = calls.instance
+		instances = append(instances, calls.instance)
+	}
+	for id, instance := range p.reported {
+		if _, ok := reported[id]; !ok {
+			instances = append(instances, instance)
+		}
+	}
+	p.reported = reported
+	p.mutex.Unlock()
+	for _, instance := range instances {
+		gauge := loadbalance.GetLoadBalanceStatFromPool()
+		gauge.Inst = instance
+		gauge.LbType = p.Name()
+		gauge.Inflight = counter.GetInflight(instance)
+		_ = p.engine.SyncReportStat(model.LoadBalanceStat, gauge)
+		loadbalance.PoolPutLoadBalanceStat(gauge)
+	}
+}
+
+func (p *Proxy) currentTime() time.Time {
+	if p.now == nil {
+		return time.Now()
+	}
+	return p.now()
+}
+
 // init 注册proxy
 func init() {
 	plugin.RegisterPluginProxy(common.TypeLoadBalancer, &Proxy{})
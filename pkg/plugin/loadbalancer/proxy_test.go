@@ -0,0 +1,174 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package loadbalancer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/sdk"
+	"github.com/polarismesh/polaris-go/pkg/stat/loadbalance"
+)
+
+// fakeInstance 仅实现 GetId，其余 model.Instance 方法继承嵌入的 nil 接口
+type fakeInstance struct {
+	model.Instance
+	id string
+}
+
+func (f *fakeInstance) GetId() string { return f.id }
+
+// fakeServiceInstances 仅实现 GetInstances，其余 model.ServiceInstances 方法继承嵌入的 nil 接口
+type fakeServiceInstances struct {
+	model.ServiceInstances
+	instances []model.Instance
+}
+
+func (f *fakeServiceInstances) GetInstances() []model.Instance { return f.instances }
+
+// fakeLoadBalancer 按实例ID记录在途请求的负载均衡插件
+type fakeLoadBalancer struct {
+	LoadBalancer
+	inflights map[string]int64
+	results   int
+}
+
+func (f *fakeLoadBalancer) Name() string { return "fake" }
+
+func (f *fakeLoadBalancer) OnServiceCallResult(result *model.ServiceCallResult) {
+	f.results++
+	f.inflights[result.GetCalledInstance().GetId()]--
+}
+
+func (f *fakeLoadBalancer) GetInflight(instance model.Instance) int64 {
+	return f.inflights[instance.GetId()]
+}
+
+func (f *fakeLoadBalancer) ReleaseInflight(instance model.Instance, count int64) {
+	f.inflights[instance.GetId()] -= count
+}
+
+// fakeEngine 记录上报的在途请求数，其余 sdk.Engine 方法继承嵌入的 nil 接口
+type fakeEngine struct {
+	sdk.Engine
+	reports map[string]int64
+}
+
+func (f *fakeEngine) SyncReportStat(_ model.MetricType, stat model.InstanceGauge) error {
+	gauge := stat.(*loadbalance.LoadBalanceGauge)
+	f.reports[gauge.Inst.GetId()] = gauge.Inflight
+	return nil
+}
+
+// newTestProxy 创建代理，choose 模拟插件选出实例：插件在途请求数加一并由代理记录
+func newTestProxy(releaseTimeout time.Duration) (*Proxy, *fakeLoadBalancer, *fakeEngine, *time.Time) {
+	lb := &fakeLoadBalancer{inflights: map[string]int64{}}
+	engine := &fakeEngine{reports: map[string]int64{}}
+	cur := time.Unix(1000, 0)
+	proxy := &Proxy{releaseTimeout: releaseTimeout, now: func() time.Time { return cur }}
+	proxy.SetRealPlugin(lb, engine)
+	return proxy, lb, engine, &cur
+}
+
+func choose(proxy *Proxy, lb *fakeLoadBalancer, instance model.Instance) {
+	lb.inflights[instance.GetId()]++
+	proxy.addPending(instance)
+}
+
+// TestProxy_Reclaim 验证超时未上报调用结果的请求被回收
+// 前置条件：releaseTimeout 为 10s，实例在 0s 与 6s 各选出一次
+// 预期结果：12s 时只回收第一个请求并归还插件在途请求；迟到的调用结果对应第二个请求，之后的结果不再回调插件
+func TestProxy_Reclaim(t *testing.T) {
+	proxy, lb, _, cur := newTestProxy(10 * time.Second)
+	inst := &fakeInstance{id: "inst"}
+	choose(proxy, lb, inst)
+	*cur = cur.Add(6 * time.Second)
+	choose(proxy, lb, inst)
+
+	*cur = cur.Add(6 * time.Second)
+	proxy.reclaim(*cur)
+	assert.Equal(t, int64(1), lb.GetInflight(inst))
+	assert.Len(t, proxy.pending[inst.GetId()].starts, 1)
+
+	result := &model.ServiceCallResult{CalledInstance: inst}
+	proxy.OnServiceCallResult(result)
+	proxy.OnServiceCallResult(result)
+	assert.Equal(t, 1, lb.results)
+	assert.Equal(t, int64(0), lb.GetInflight(inst))
+	assert.Empty(t, proxy.pending)
+}
+
+// TestProxy_PurgeRemovedInstances 验证实例下线时清理在途请求
+// 前置条件：实例 a、b 各有在途请求，服务实例更新后只剩 b
+// 预期结果：a 的记录被移除且插件在途请求归零，b 不受影响；非实例类型的事件被忽略
+func TestProxy_PurgeRemovedInstances(t *testing.T) {
+	proxy, lb, _, _ := newTestProxy(0)
+	a, b := &fakeInstance{id: "a"}, &fakeInstance{id: "b"}
+	choose(proxy, lb, a)
+	choose(proxy, lb, a)
+	choose(proxy, lb, b)
+
+	oldValue := &fakeServiceInstances{instances: []model.Instance{a, b}}
+	newValue := &fakeServiceInstances{instances: []model.Instance{b}}
+	routeEvent := &common.PluginEvent{EventType: common.OnServiceUpdated, EventObject: &common.ServiceEventObject{
+		SvcEventKey: model.ServiceEventKey{Type: model.EventRouting}, OldValue: oldValue, NewValue: newValue}}
+	assert.NoError(t, proxy.onServiceEvent(routeEvent))
+	assert.Len(t, proxy.pending, 2)
+
+	event := &common.PluginEvent{EventType: common.OnServiceUpdated, EventObject: &common.ServiceEventObject{
+		SvcEventKey: model.ServiceEventKey{Type: model.EventInstances}, OldValue: oldValue, NewValue: newValue}}
+	assert.NoError(t, proxy.onServiceEvent(event))
+	assert.NotContains(t, proxy.pending, "a")
+	assert.Equal(t, int64(0), lb.GetInflight(a))
+	assert.Equal(t, int64(1), lb.GetInflight(b))
+
+	// 服务删除时全部实例下线
+	deleted := &common.PluginEvent{EventType: common.OnServiceDeleted, EventObject: &common.ServiceEventObject{
+		SvcEventKey: model.ServiceEventKey{Type: model.EventInstances}, OldValue: newValue}}
+	assert.NoError(t, proxy.onServiceEvent(deleted))
+	assert.Empty(t, proxy.pending)
+	assert.Equal(t, int64(0), lb.GetInflight(b))
+}
+
+// TestProxy_ReportInflights 验证在途请求数按周期上报
+// 前置条件：实例选出时不上报，周期任务触发上报
+// 预期结果：有在途请求的实例上报当前值；归零后的下一周期上报一次 0，之后不再上报
+func TestProxy_ReportInflights(t *testing.T) {
+	proxy, lb, engine, _ := newTestProxy(0)
+	inst := &fakeInstance{id: "inst"}
+	choose(proxy, lb, inst)
+	choose(proxy, lb, inst)
+	assert.Empty(t, engine.reports)
+
+	proxy.reportInflights()
+	assert.Equal(t, map[string]int64{"inst": 2}, engine.reports)
+
+	result := &model.ServiceCallResult{CalledInstance: inst}
+	proxy.OnServiceCallResult(result)
+	proxy.OnServiceCallResult(result)
+	proxy.reportInflights()
+	assert.Equal(t, map[string]int64{"inst": 0}, engine.reports)
+
+	engine.reports = map[string]int64{}
+	proxy.reportInflights()
+	assert.Empty(t, engine.reports)
+}
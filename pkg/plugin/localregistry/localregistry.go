@@ -18,8 +18,10 @@
 package localregistry
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 
@@ -111,6 +113,58 @@ type LocalRegistry interface {
 	RuleRegistry
 }
 
+// CacheDumpFilter 本地缓存导出的过滤条件，字段为空表示不过滤
+type CacheDumpFilter struct {
+	Namespace string
+	Service   string
+	// Type 缓存类型，取值与 model.EventType 的字符串形式一致，如 instance、routing
+	Type string
+}
+
+// CacheInstance 导出的实例信息
+type CacheInstance struct {
+	ID       string            `json:"id"`
+	Host     string            `json:"host"`
+	Port     uint32            `json:"port"`
+	Protocol string            `json:"protocol,omitempty"`
+	Version  string            `json:"version,omitempty"`
+	Weight   int               `json:"weight"`
+	Healthy  bool              `json:"healthy"`
+	Isolated bool              `json:"isolated"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// CacheEntry 导出的本地缓存条目
+type CacheEntry struct {
+	Namespace     string    `json:"namespace"`
+	Service       string    `json:"service"`
+	Type          string    `json:"type"`
+	Revision      string    `json:"revision"`
+	Initialized   bool      `json:"initialized"`
+	NotExists     bool      `json:"notExists"`
+	CacheLoaded   bool      `json:"cacheLoaded"`
+	RemoteUpdated bool      `json:"remoteUpdated"`
+	Invalid       bool      `json:"invalid"`
+	CreateTime    time.Time `json:"createTime"`
+	UpdateTime    time.Time `json:"updateTime"`
+	LastVisitTime time.Time `json:"lastVisitTime"`
+	// CacheAgeSeconds 缓存值距最近一次更新的秒数
+	CacheAgeSeconds float64 `json:"cacheAgeSeconds"`
+	// Instances 实例列表，仅 instance 类型有值
+	Instances []CacheInstance `json:"instances,omitempty"`
+	// Services 服务列表，仅 services 类型有值
+	Services []*model.ServiceKey `json:"services,omitempty"`
+	// Rule 规则内容的 JSON 表示，仅规则类型有值
+	Rule          json.RawMessage `json:"rule,omitempty"`
+	ValidateError string          `json:"validateError,omitempty"`
+}
+
+// CacheDumper 【可选接口】本地缓存插件实现该接口后，可通过 admin 调试接口导出缓存内容
+type CacheDumper interface {
+	// DumpCache 按过滤条件导出缓存条目
+	DumpCache(filter CacheDumpFilter) []*CacheEntry
+}
+
 // RuleFilter 配置获取的过滤器
 type RuleFilter struct {
 	model.ServiceEventKey
@@ -20,6 +20,8 @@ package plugin
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync/atomic"
 
 	"github.com/hashicorp/go-multierror"
@@ -63,6 +65,23 @@ func IsPluginRegistered(typ common.Type, name string) bool {
 	return true
 }
 
+// ResolvePluginName 按忽略大小写的方式查找已注册的插件名，精确匹配优先；未找到时返回 false
+func ResolvePluginName(typ common.Type, name string) (string, bool) {
+	plugins, ok := pluginTypes[typ]
+	if !ok {
+		return "", false
+	}
+	if _, ok = plugins[name]; ok {
+		return name, true
+	}
+	for registered := range plugins {
+		if strings.EqualFold(registered, name) {
+			return registered, true
+		}
+	}
+	return "", false
+}
+
 // Supplier 插件提供者
 type Supplier interface {
 	// GetPlugin 获取插件实例
@@ -378,6 +397,36 @@ func (m *manager) GetPluginsByType(typ common.Type) []string {
 	return res
 }
 
+// LoadedPlugin 已加载插件的描述信息
+type LoadedPlugin struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	ID     int32  `json:"id"`
+	Enable bool   `json:"enable"`
+}
+
+// GetLoadedPlugins 按 LoadedPluginTypes 的顺序返回已加载的插件列表，同一类型下按插件名排序
+func GetLoadedPlugins(supplier Supplier, cfg config.Configuration) []LoadedPlugin {
+	var res []LoadedPlugin
+	for _, typ := range common.LoadedPluginTypes {
+		names := supplier.GetPluginsByType(typ)
+		sort.Strings(names)
+		for _, name := range names {
+			plug, err := supplier.GetPlugin(typ, name)
+			if err != nil {
+				continue
+			}
+			res = append(res, LoadedPlugin{
+				Type:   typ.String(),
+				Name:   name,
+				ID:     plug.ID(),
+				Enable: plug.IsEnable(cfg),
+			})
+		}
+	}
+	return res
+}
+
 // GetPluginById 通过id获取插件
 func (m *manager) GetPluginById(id int32) (Plugin, error) {
 	plugin, exists := m.idToPlugins[id]
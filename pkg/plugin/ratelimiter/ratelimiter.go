@@ -33,7 +33,7 @@ type ServiceRateLimiter interface {
 
 // QuotaBucket 配额池
 type QuotaBucket interface {
-	// GetQuota 在令牌桶/漏桶中进行单个配额的划扣，并返回本次分配的结果
+	// GetQuota 在令牌桶/漏桶中进行 token 个配额的划扣，并返回本次分配的结果
 	GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse
 	// Release 释放配额（仅对于并发数限流有用）
 	Release()
@@ -45,6 +45,14 @@ type QuotaBucket interface {
 	GetAmountInfos() []AmountInfo
 }
 
+// QuotaPermitEstimator 可选能力：在不划扣配额的前提下预估最早可分配时间，供阻塞式获取配额使用
+// 未实现该接口的配额池，阻塞获取时退化为按固定间隔重试
+type QuotaPermitEstimator interface {
+	// EarliestPermitTime 计算获取 token 个配额最早可成功的时间点（与 curTimeMs 同一时钟，单位毫秒）
+	// 返回 false 表示该配额池永远无法分配这么多配额（如阈值为0或 token 超过单周期总量）
+	EarliestPermitTime(curTimeMs int64, token uint32) (int64, bool)
+}
+
 // init 初始化
 func init() {
 	plugin.RegisterPluginInterface(common.TypeRateLimiter, new(ServiceRateLimiter))
@@ -41,11 +41,14 @@ import (
 	_ "github.com/polarismesh/polaris-go/plugin/configfilter/crypto"
 	_ "github.com/polarismesh/polaris-go/plugin/configfilter/crypto/aes"
 	_ "github.com/polarismesh/polaris-go/plugin/events/pushgateway"
+	_ "github.com/polarismesh/polaris-go/plugin/healthcheck/grpc"
 	_ "github.com/polarismesh/polaris-go/plugin/healthcheck/http"
 	_ "github.com/polarismesh/polaris-go/plugin/healthcheck/tcp"
 	_ "github.com/polarismesh/polaris-go/plugin/healthcheck/udp"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/hash"
+	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/leastrequest"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/maglev"
+	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/peakewma"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/ringhash"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/weightedrandom"
 	_ "github.com/polarismesh/polaris-go/plugin/localregistry/inmemory"
@@ -53,10 +56,16 @@ import (
 	_ "github.com/polarismesh/polaris-go/plugin/logger/zaplog"
 	_ "github.com/polarismesh/polaris-go/plugin/lossless/losslessController"
 	_ "github.com/polarismesh/polaris-go/plugin/metrics/callauditlog"
+	_ "github.com/polarismesh/polaris-go/plugin/metrics/lbinfo"
+	_ "github.com/polarismesh/polaris-go/plugin/metrics/otel"
 	_ "github.com/polarismesh/polaris-go/plugin/metrics/prometheus"
+	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/bbr"
+	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/gcra"
 	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/reject"
 	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/reject_concurrency"
 	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/unirate"
+	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/warmup"
+	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/file"
 	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/grpc"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/canary"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/dstmeta"
@@ -18,6 +18,8 @@
 package sdk
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -103,6 +105,8 @@ type Engine interface {
 		eventType model.EventType, req *model.GetServicesRequest) (*model.ServicesResponse, error)
 	// AsyncGetQuota 同步获取配额信息
 	AsyncGetQuota(request *model.QuotaRequestImpl) (*model.QuotaFutureImpl, error)
+	// SyncWaitQuota 阻塞获取 token 个配额，直到分配成功或 ctx 结束
+	SyncWaitQuota(ctx context.Context, request *model.QuotaRequestImpl, token uint32) (*model.QuotaFutureImpl, error)
 	// ScheduleTask 启动定时任务
 	ScheduleTask(task *model.PeriodicTask) (chan<- *model.PriorityTask, model.TaskValues)
 	// WatchService 监听服务的change
@@ -117,6 +121,8 @@ type Engine interface {
 	SyncGetConfigGroup(namespace, fileGroup string) (model.ConfigFileGroup, error)
 	// SyncGetConfigGroupWithReq 同步获取配置文件
 	SyncGetConfigGroupWithReq(req *model.GetConfigGroupRequest) (model.ConfigFileGroup, error)
+	// SyncWatchConfigGroup 同步获取配置分组及其文件内容，并按文件粒度监听变更
+	SyncWatchConfigGroup(req *model.WatchConfigGroupRequest) (model.ConfigGroupWatcher, error)
 	// SyncCreateConfigFile 同步创建配置文件
 	SyncCreateConfigFile(namespace, fileGroup, fileName, content string) error
 	// SyncUpdateConfigFile 同步更新配置文件
@@ -125,6 +131,15 @@ type Engine interface {
 	SyncPublishConfigFile(namespace, fileGroup, fileName string) error
 	// SyncUpsertAndPublishConfigFile 同步创建并发布配置文件
 	SyncUpsertAndPublishConfigFile(namespace, fileGroup, fileName, content string) error
+	// SyncDeleteConfigFile 同步删除配置文件
+	SyncDeleteConfigFile(req *model.DeleteConfigFileRequest) error
+	// SyncListConfigFiles 同步查询分组下的配置文件元数据列表
+	SyncListConfigFiles(req *model.ListConfigFilesRequest) (*model.ListConfigFilesResponse, error)
+	// SyncGetConfigFileReleaseHistory 同步查询配置文件发布历史
+	SyncGetConfigFileReleaseHistory(
+		req *model.GetConfigFileReleaseHistoryRequest) (*model.GetConfigFileReleaseHistoryResponse, error)
+	// SyncRollbackConfigFile 同步回滚配置文件到指定发布版本
+	SyncRollbackConfigFile(req *model.RollbackConfigFileRequest) error
 	// ProcessRouters 执行路由链过滤，返回经过路由后的实例列表
 	ProcessRouters(req *model.ProcessRoutersRequest) (*model.InstancesResponse, error)
 	// ProcessLoadBalance 执行负载均衡策略，返回负载均衡后的实例
@@ -27,6 +27,10 @@ import (
 type LoadBalanceGauge struct {
 	model.EmptyInstanceGauge
 	Inst model.Instance
+	// LbType 负载均衡插件名
+	LbType string
+	// Inflight 实例当前的在途请求数
+	Inflight int64
 }
 
 // LoadBalanceGauge池子
@@ -38,7 +42,11 @@ func GetLoadBalanceStatFromPool() *LoadBalanceGauge {
 	if nil == value {
 		return &LoadBalanceGauge{}
 	}
-	return value.(*LoadBalanceGauge)
+	gauge := value.(*LoadBalanceGauge)
+	gauge.Inst = nil
+	gauge.LbType = ""
+	gauge.Inflight = 0
+	return gauge
 }
 
 // PoolPutLoadBalanceStat 将LoadBalanceGauge放回pool
@@ -50,3 +58,23 @@ func PoolPutLoadBalanceStat(gauge *LoadBalanceGauge) {
 func (l *LoadBalanceGauge) GetCalledInstance() model.Instance {
 	return l.Inst
 }
+
+// GetNamespace 获取服务的命名空间
+func (l *LoadBalanceGauge) GetNamespace() string {
+	return l.Inst.GetNamespace()
+}
+
+// GetService 获取服务名
+func (l *LoadBalanceGauge) GetService() string {
+	return l.Inst.GetService()
+}
+
+// GetHost 实例的节点信息
+func (l *LoadBalanceGauge) GetHost() string {
+	return l.Inst.GetHost()
+}
+
+// GetPort 实例的端口信息
+func (l *LoadBalanceGauge) GetPort() int {
+	return int(l.Inst.GetPort())
+}
@@ -0,0 +1,172 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package tracing provides OpenTelemetry tracing helpers for polaris-go flows.
+package tracing
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/version"
+)
+
+// InstrumentationName OTel Tracer 的 instrumentation 名称
+const InstrumentationName = "github.com/polarismesh/polaris-go"
+
+// SDK 流程的 span 名称
+const (
+	SpanGetOneInstance  = "polaris.GetOneInstance"
+	SpanGetInstances    = "polaris.GetInstances"
+	SpanGetAllInstances = "polaris.GetAllInstances"
+	SpanGetServiceRule  = "polaris.GetServiceRule"
+	SpanLoadResources   = "polaris.LoadResources"
+	SpanRoute           = "polaris.Route"
+	SpanLoadBalance     = "polaris.LoadBalance"
+	SpanCircuitBreak    = "polaris.CircuitBreakerCheck"
+	SpanGetQuota        = "polaris.GetQuota"
+	SpanWaitQuota       = "polaris.WaitQuota"
+	SpanGetConfigFile   = "polaris.GetConfigFile"
+	SpanGetConfigGroup  = "polaris.GetConfigGroup"
+)
+
+// span 属性名，polaris 相关属性统一使用 polaris. 前缀，RPC 属性遵循 OTel 语义约定
+const (
+	AttrNamespace        = attribute.Key("polaris.namespace")
+	AttrService          = attribute.Key("polaris.service")
+	AttrMethod           = attribute.Key("polaris.method")
+	AttrCacheHit         = attribute.Key("polaris.cache.hit")
+	AttrCacheFallback    = attribute.Key("polaris.cache.fallback")
+	AttrRemoteWaits      = attribute.Key("polaris.remote.waits")
+	AttrRuleType         = attribute.Key("polaris.rule.type")
+	AttrRouterChain      = attribute.Key("polaris.router.chain")
+	AttrRouterStatus     = attribute.Key("polaris.router.status")
+	AttrRouterInstances  = attribute.Key("polaris.router.instances")
+	AttrRouterRedirect   = attribute.Key("polaris.router.redirect")
+	AttrLoadBalancer     = attribute.Key("polaris.loadbalancer")
+	AttrInstanceID       = attribute.Key("polaris.instance.id")
+	AttrInstanceAddress  = attribute.Key("polaris.instance.address")
+	AttrCircuitBreakPass = attribute.Key("polaris.circuitbreaker.pass")
+	AttrCircuitBreakRule = attribute.Key("polaris.circuitbreaker.rule")
+	AttrQuotaCode        = attribute.Key("polaris.quota.code")
+	AttrQuotaInfo        = attribute.Key("polaris.quota.info")
+	AttrConfigFileGroup  = attribute.Key("polaris.config.group")
+	AttrConfigFileName   = attribute.Key("polaris.config.file")
+	AttrRPCSystem        = attribute.Key("rpc.system")
+	AttrRPCService       = attribute.Key("rpc.service")
+	AttrRPCMethod        = attribute.Key("rpc.method")
+	AttrNetPeerName      = attribute.Key("net.peer.name")
+)
+
+const (
+	// polarisGRPCService 北极星服务端 gRPC 服务名
+	polarisGRPCService = "v1.PolarisGRPC"
+	rpcSystemGRPC      = "grpc"
+)
+
+// Tracer 为 SDK 流程创建 span，未启用链路追踪时所有 span 均为 no-op
+// span 通过 otel 全局 TracerProvider 创建，业务进程注册 OTel SDK 后即可导出
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer 根据 global.tracing 配置创建 Tracer
+func NewTracer(cfg config.TracingConfig) *Tracer {
+	if cfg == nil || !cfg.IsEnable() {
+		return &Tracer{}
+	}
+	return NewTracerWithProvider(otel.GetTracerProvider())
+}
+
+// NewTracerWithProvider 使用指定的 TracerProvider 创建 Tracer
+func NewTracerWithProvider(provider trace.TracerProvider) *Tracer {
+	return &Tracer{
+		tracer: provider.Tracer(InstrumentationName, trace.WithInstrumentationVersion(version.Version)),
+	}
+}
+
+// IsEnable 是否启用链路追踪
+func (t *Tracer) IsEnable() bool {
+	return t != nil && t.tracer != nil
+}
+
+// Start 以 ctx 中的 span 为父 span 创建子 span，ctx 为空时创建根 span
+// 未启用时原样返回 ctx 以及一个 no-op span，调用方无需判断是否启用
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartRPC 创建访问北极星服务端的客户端 span，span 名称遵循 OTel RPC 约定的 服务名/方法名
+func (t *Tracer) StartRPC(ctx context.Context, method string,
+	attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	rpcAttrs := make([]attribute.KeyValue, 0, len(attrs)+3)
+	rpcAttrs = append(rpcAttrs, AttrRPCSystem.String(rpcSystemGRPC), AttrRPCService.String(polarisGRPCService),
+		AttrRPCMethod.String(method))
+	rpcAttrs = append(rpcAttrs, attrs...)
+	return t.start(ctx, polarisGRPCService+"/"+method,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(rpcAttrs...))
+}
+
+func (t *Tracer) start(ctx context.Context, name string,
+	opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !t.IsEnable() {
+		return ctx, noopSpan
+	}
+	return t.tracer.Start(ctx, name, opts...)
+}
+
+// noopSpan 未启用链路追踪时返回的 span，不能直接返回父 span，避免调用方误结束业务的 span
+var noopSpan = trace.SpanFromContext(context.Background())
+
+// End 结束 span，err 不为空时记录错误并标记 span 状态为失败
+func End(span trace.Span, err error) {
+	if err != nil && span.IsRecording() {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ServiceAttributes 服务标识对应的 span 属性
+func ServiceAttributes(svcKey *model.ServiceKey) []attribute.KeyValue {
+	if svcKey == nil {
+		return nil
+	}
+	return []attribute.KeyValue{AttrNamespace.String(svcKey.Namespace), AttrService.String(svcKey.Service)}
+}
+
+// InstanceAttributes 实例对应的 span 属性
+func InstanceAttributes(instance model.Instance) []attribute.KeyValue {
+	if instance == nil {
+		return nil
+	}
+	return []attribute.KeyValue{
+		AttrInstanceID.String(instance.GetId()),
+		AttrInstanceAddress.String(net.JoinHostPort(instance.GetHost(), strconv.Itoa(int(instance.GetPort())))),
+	}
+}
@@ -0,0 +1,124 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/tracing/tracingtest"
+)
+
+// TestNewTracer_DisabledByDefault 测试场景：未开启 tracing 配置
+// 前置条件：TracingConfigImpl 使用默认值
+// 预期结果：Tracer 未启用，Start 原样返回 ctx，span 不记录且不会结束用户的 span
+func TestNewTracer_DisabledByDefault(t *testing.T) {
+	cfg := &config.TracingConfigImpl{}
+	cfg.SetDefault()
+	tracer := NewTracer(cfg)
+	assert.False(t, tracer.IsEnable())
+
+	recorder := tracingtest.NewRecorder()
+	parentCtx, parent := recorder.Tracer("test").Start(context.Background(), "parent")
+	ctx, span := tracer.Start(parentCtx, SpanGetOneInstance)
+	assert.Equal(t, parentCtx, ctx)
+	assert.False(t, span.IsRecording())
+	End(span, errors.New("failed"))
+	assert.False(t, parent.(*tracingtest.Span).Ended())
+	assert.Len(t, recorder.Spans(), 1)
+}
+
+// TestTracer_NilSafe 测试场景：nil Tracer 调用
+// 预期结果：不会 panic，行为与未启用一致
+func TestTracer_NilSafe(t *testing.T) {
+	var tracer *Tracer
+	assert.False(t, tracer.IsEnable())
+	ctx := context.Background()
+	retCtx, span := tracer.StartRPC(ctx, "Heartbeat")
+	assert.Equal(t, ctx, retCtx)
+	End(span, nil)
+}
+
+// TestTracer_StartWithParent 测试场景：开启 tracing 后创建 span
+// 前置条件：用户 ctx 中已有父 span
+// 预期结果：新 span 挂在父 span 下，属性、错误状态被记录且 span 被结束
+func TestTracer_StartWithParent(t *testing.T) {
+	recorder := tracingtest.NewRecorder()
+	tracer := NewTracerWithProvider(recorder)
+	assert.True(t, tracer.IsEnable())
+
+	parentCtx, parent := recorder.Tracer("test").Start(context.Background(), "parent")
+	svcKey := &model.ServiceKey{Namespace: "default", Service: "svc"}
+	ctx, span := tracer.Start(parentCtx, SpanGetInstances, ServiceAttributes(svcKey)...)
+	assert.Equal(t, span, trace.SpanFromContext(ctx))
+	span.SetAttributes(AttrCacheHit.Bool(true))
+	End(span, errors.New("timeout"))
+
+	got := recorder.Find(SpanGetInstances)
+	if !assert.NotNil(t, got) {
+		return
+	}
+	assert.Equal(t, parent.SpanContext().SpanID(), got.Parent().SpanID())
+	assert.True(t, got.Ended())
+	ns, _ := got.Attribute(AttrNamespace)
+	assert.Equal(t, "default", ns.AsString())
+	svc, _ := got.Attribute(AttrService)
+	assert.Equal(t, "svc", svc.AsString())
+	hit, _ := got.Attribute(AttrCacheHit)
+	assert.True(t, hit.AsBool())
+	code, desc := got.Status()
+	assert.Equal(t, codes.Error, code)
+	assert.Equal(t, "timeout", desc)
+	assert.Len(t, got.Errors(), 1)
+}
+
+// TestTracer_StartRPC 测试场景：记录对北极星服务端的 gRPC 调用
+// 预期结果：span 类型为 Client，名称与 rpc.* 属性符合 OpenTelemetry 语义约定
+func TestTracer_StartRPC(t *testing.T) {
+	recorder := tracingtest.NewRecorder()
+	tracer := NewTracerWithProvider(recorder)
+
+	_, span := tracer.StartRPC(context.Background(), "RegisterInstance",
+		AttrNetPeerName.String("127.0.0.1:8091"))
+	End(span, nil)
+
+	spans := recorder.Spans()
+	if !assert.Len(t, spans, 1) {
+		return
+	}
+	got := spans[0]
+	assert.Equal(t, "v1.PolarisGRPC/RegisterInstance", got.Name())
+	assert.Equal(t, trace.SpanKindClient, got.Kind())
+	system, _ := got.Attribute(AttrRPCSystem)
+	assert.Equal(t, "grpc", system.AsString())
+	service, _ := got.Attribute(AttrRPCService)
+	assert.Equal(t, "v1.PolarisGRPC", service.AsString())
+	method, _ := got.Attribute(AttrRPCMethod)
+	assert.Equal(t, "RegisterInstance", method.AsString())
+	peer, _ := got.Attribute(AttrNetPeerName)
+	assert.Equal(t, "127.0.0.1:8091", peer.AsString())
+	code, _ := got.Status()
+	assert.Equal(t, codes.Unset, code)
+}
@@ -0,0 +1,232 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package tracingtest provides an in-memory OpenTelemetry TracerProvider for tests.
+package tracingtest
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Recorder 记录所有创建的 span 的 TracerProvider，仅用于测试
+type Recorder struct {
+	mutex sync.Mutex
+	spans []*Span
+	seq   uint64
+}
+
+// NewRecorder 创建 Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Tracer 实现 trace.TracerProvider
+func (r *Recorder) Tracer(name string, _ ...trace.TracerOption) trace.Tracer {
+	return &tracer{recorder: r, name: name}
+}
+
+// Spans 返回已创建的 span 快照
+func (r *Recorder) Spans() []*Span {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	ret := make([]*Span, len(r.spans))
+	copy(ret, r.spans)
+	return ret
+}
+
+// Find 按名称查找第一个 span，不存在时返回 nil
+func (r *Recorder) Find(name string) *Span {
+	for _, span := range r.Spans() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	return nil
+}
+
+// Reset 清空已记录的 span
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.spans = nil
+}
+
+func (r *Recorder) add(span *Span) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.seq++
+	var spanID trace.SpanID
+	for i := 0; i < len(spanID); i++ {
+		spanID[len(spanID)-1-i] = byte(r.seq >> (8 * i))
+	}
+	traceID := trace.TraceID{0x01}
+	if span.parent.IsValid() {
+		traceID = span.parent.TraceID()
+	}
+	span.spanContext = trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	r.spans = append(r.spans, span)
+}
+
+type tracer struct {
+	recorder *Recorder
+	name     string
+}
+
+// Start 实现 trace.Tracer
+func (t *tracer) Start(ctx context.Context, name string,
+	opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	span := &Span{
+		recorder:   t.recorder,
+		name:       name,
+		kind:       cfg.SpanKind(),
+		parent:     trace.SpanContextFromContext(ctx),
+		attributes: map[attribute.Key]attribute.Value{},
+	}
+	span.SetAttributes(cfg.Attributes()...)
+	t.recorder.add(span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+// Span 测试中记录的 span
+type Span struct {
+	recorder    *Recorder
+	mutex       sync.Mutex
+	name        string
+	kind        trace.SpanKind
+	parent      trace.SpanContext
+	spanContext trace.SpanContext
+	attributes  map[attribute.Key]attribute.Value
+	statusCode  codes.Code
+	statusDesc  string
+	errs        []error
+	ended       bool
+}
+
+// Name span 名称
+func (s *Span) Name() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.name
+}
+
+// Kind span 类型
+func (s *Span) Kind() trace.SpanKind {
+	return s.kind
+}
+
+// Parent 父 span 的 SpanContext
+func (s *Span) Parent() trace.SpanContext {
+	return s.parent
+}
+
+// Attribute 获取属性值
+func (s *Span) Attribute(key attribute.Key) (attribute.Value, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	value, ok := s.attributes[key]
+	return value, ok
+}
+
+// Status span 状态
+func (s *Span) Status() (codes.Code, string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.statusCode, s.statusDesc
+}
+
+// Errors 通过 RecordError 记录的错误
+func (s *Span) Errors() []error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]error(nil), s.errs...)
+}
+
+// Ended span 是否已结束
+func (s *Span) Ended() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.ended
+}
+
+// End 实现 trace.Span
+func (s *Span) End(_ ...trace.SpanEndOption) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ended = true
+}
+
+// AddEvent 实现 trace.Span
+func (s *Span) AddEvent(string, ...trace.EventOption) {
+}
+
+// IsRecording 实现 trace.Span
+func (s *Span) IsRecording() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return !s.ended
+}
+
+// RecordError 实现 trace.Span
+func (s *Span) RecordError(err error, _ ...trace.EventOption) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.errs = append(s.errs, err)
+}
+
+// SpanContext 实现 trace.Span
+func (s *Span) SpanContext() trace.SpanContext {
+	return s.spanContext
+}
+
+// SetStatus 实现 trace.Span
+func (s *Span) SetStatus(code codes.Code, description string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.statusCode = code
+	s.statusDesc = description
+}
+
+// SetName 实现 trace.Span
+func (s *Span) SetName(name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.name = name
+}
+
+// SetAttributes 实现 trace.Span
+func (s *Span) SetAttributes(kv ...attribute.KeyValue) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, attr := range kv {
+		s.attributes[attr.Key] = attr.Value
+	}
+}
+
+// TracerProvider 实现 trace.Span
+func (s *Span) TracerProvider() trace.TracerProvider {
+	return s.recorder
+}
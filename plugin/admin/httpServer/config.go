@@ -25,6 +25,8 @@ import (
 // Config httpServer插件配置
 type Config struct {
 	config.BaseConfig
+	// DebugEnable 是否暴露 /debug/registry、/debug/config、/debug/plugins 调试接口
+	DebugEnable *bool `yaml:"debugEnable" json:"debugEnable"`
 }
 
 // Verify 校验配置参数
@@ -34,4 +36,13 @@ func (c *Config) Verify() error {
 
 // SetDefault 设置默认参数
 func (c *Config) SetDefault() {
+	if c.DebugEnable == nil {
+		enable := false
+		c.DebugEnable = &enable
+	}
+}
+
+// IsDebugEnable 是否开启调试接口
+func (c *Config) IsDebugEnable() bool {
+	return c != nil && c.DebugEnable != nil && *c.DebugEnable
 }
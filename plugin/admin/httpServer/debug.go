@@ -0,0 +1,207 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+const (
+	// DebugRegistryPath 本地缓存的服务、实例及规则导出路径
+	DebugRegistryPath = "/debug/registry"
+	// DebugConfigPath 生效配置导出路径
+	DebugConfigPath = "/debug/config"
+	// DebugPluginsPath 已加载插件列表导出路径
+	DebugPluginsPath = "/debug/plugins"
+
+	maskedValue = "******"
+)
+
+// sensitiveKeys 配置导出时需要脱敏的字段名后缀，按小写匹配
+var sensitiveKeys = []string{
+	"token", "password", "secret", "accesskey", "privatekey", "apikey", "credential", "authorization", "cookie",
+}
+
+// headerKeyword 字段名（按小写）包含该关键字的对象或列表视为请求头集合，其中的值全部脱敏，
+// 如 otel 插件的 headers、HTTP 健康探测的 requestHeadersToAdd
+const headerKeyword = "header"
+
+// headerNameKeys 请求头以 {key, value} 列表配置时，保留请求头名称便于排查
+var headerNameKeys = map[string]struct{}{"key": {}, "name": {}}
+
+// registerDebugHandlers 注册调试接口
+func (s *Server) registerDebugHandlers() {
+	adminCfg := s.pluginCtx.Config.GetGlobal().GetAdmin()
+	adminCfg.RegisterPath(model.AdminHandler{Path: DebugRegistryPath, HandlerFunc: s.serveRegistry})
+	adminCfg.RegisterPath(model.AdminHandler{Path: DebugConfigPath, HandlerFunc: s.serveConfig})
+	adminCfg.RegisterPath(model.AdminHandler{Path: DebugPluginsPath, HandlerFunc: s.servePlugins})
+}
+
+// serveRegistry 导出本地缓存，支持 namespace、service、type 查询参数过滤
+func (s *Server) serveRegistry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	filter := localregistry.CacheDumpFilter{
+		Namespace: query.Get("namespace"),
+		Service:   query.Get("service"),
+		Type:      query.Get("type"),
+	}
+	plugins, err := s.pluginCtx.Plugins.GetPlugins(common.TypeLocalRegistry)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entries := make([]*localregistry.CacheEntry, 0, 8)
+	for _, plug := range plugins {
+		if dumper, ok := plug.(localregistry.CacheDumper); ok {
+			entries = append(entries, dumper.DumpCache(filter)...)
+		}
+	}
+	writeDebugJSON(w, entries)
+}
+
+// serveConfig 导出合并后的生效配置，敏感字段脱敏
+func (s *Server) serveConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	value, err := maskedConfiguration(s.pluginCtx.Config)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeDebugJSON(w, value)
+}
+
+// servePlugins 导出已加载插件列表
+func (s *Server) servePlugins(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeDebugJSON(w, plugin.GetLoadedPlugins(s.pluginCtx.Plugins, s.pluginCtx.Config))
+}
+
+// maskedConfiguration 将配置转换为通用 JSON 结构并对敏感字段脱敏
+func maskedConfiguration(cfg interface{}) (interface{}, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return maskSensitive(value), nil
+}
+
+func maskSensitive(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if str, ok := item.(string); ok && str != "" && isSensitiveKey(key) {
+				v[key] = maskedValue
+				continue
+			}
+			if strings.Contains(strings.ToLower(key), headerKeyword) {
+				v[key] = maskHeaders(item)
+				continue
+			}
+			v[key] = maskSensitive(item)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = maskSensitive(v[i])
+		}
+	}
+	return value
+}
+
+// maskHeaders 脱敏请求头集合：map 形式保留请求头名称（map 的 key），{key, value} 列表形式保留 key/name 字段
+func maskHeaders(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = maskAll(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				v[i] = maskAll(item)
+				continue
+			}
+			for key, field := range entry {
+				if _, isName := headerNameKeys[strings.ToLower(key)]; !isName {
+					entry[key] = maskAll(field)
+				}
+			}
+		}
+	}
+	return value
+}
+
+// maskAll 脱敏全部非空字符串
+func maskAll(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			return maskedValue
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = maskAll(item)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = maskAll(v[i])
+		}
+	}
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.HasSuffix(lower, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeDebugJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		log.GetBaseLogger().Errorf("[Admin][HttpServer] encode debug response error: %v", err)
+	}
+}
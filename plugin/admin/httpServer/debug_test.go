@@ -0,0 +1,235 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	_ "github.com/polarismesh/polaris-go/pkg/plugin/admin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+	healthcheckhttp "github.com/polarismesh/polaris-go/plugin/healthcheck/http"
+	"github.com/polarismesh/polaris-go/plugin/metrics/otel"
+)
+
+// fakePlugin 仅用于调试接口测试的插件实现
+type fakePlugin struct {
+	typ     common.Type
+	name    string
+	id      int32
+	entries []*localregistry.CacheEntry
+	filters []localregistry.CacheDumpFilter
+}
+
+func (f *fakePlugin) Type() common.Type                    { return f.typ }
+func (f *fakePlugin) ID() int32                            { return f.id }
+func (f *fakePlugin) GetSDKContextID() string              { return "" }
+func (f *fakePlugin) Name() string                         { return f.name }
+func (f *fakePlugin) Init(_ *plugin.InitContext) error     { return nil }
+func (f *fakePlugin) Start() error                         { return nil }
+func (f *fakePlugin) Destroy() error                       { return nil }
+func (f *fakePlugin) IsEnable(_ config.Configuration) bool { return true }
+
+// DumpCache 记录过滤条件并返回预置条目
+func (f *fakePlugin) DumpCache(filter localregistry.CacheDumpFilter) []*localregistry.CacheEntry {
+	f.filters = append(f.filters, filter)
+	return f.entries
+}
+
+// fakeSupplier 以固定插件集合实现 plugin.Supplier
+type fakeSupplier struct {
+	plugins map[common.Type][]*fakePlugin
+}
+
+func (f *fakeSupplier) GetPlugin(typ common.Type, name string) (plugin.Plugin, error) {
+	for _, p := range f.plugins[typ] {
+		if p.name == name {
+			return p, nil
+		}
+	}
+	return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, nil, "plugin %s not found", name)
+}
+
+func (f *fakeSupplier) GetPlugins(typ common.Type) ([]plugin.Plugin, error) {
+	ret := make([]plugin.Plugin, 0, len(f.plugins[typ]))
+	for _, p := range f.plugins[typ] {
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+func (f *fakeSupplier) GetPluginById(id int32) (plugin.Plugin, error) { return nil, nil }
+
+func (f *fakeSupplier) GetPluginsByType(typ common.Type) []string {
+	var names []string
+	for _, p := range f.plugins[typ] {
+		names = append(names, p.name)
+	}
+	return names
+}
+
+func (f *fakeSupplier) GetEventSubscribers(_ common.PluginEventType) []common.PluginEventHandler {
+	return nil
+}
+
+func (f *fakeSupplier) RegisterEventSubscriber(_ common.PluginEventType, _ common.PluginEventHandler) {
+}
+
+func newDebugTestServer() (*Server, *fakePlugin) {
+	registry := &fakePlugin{typ: common.TypeLocalRegistry, name: "inmemory", id: 2,
+		entries: []*localregistry.CacheEntry{{Namespace: "default", Service: "svc-a", Type: "instance"}}}
+	supplier := &fakeSupplier{plugins: map[common.Type][]*fakePlugin{
+		common.TypeLocalRegistry: {registry},
+		common.TypeLoadBalancer: {
+			{typ: common.TypeLoadBalancer, name: "weightedRandom", id: 4},
+			{typ: common.TypeLoadBalancer, name: "ringHash", id: 3},
+		},
+	}}
+	cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	return &Server{pluginCtx: &plugin.InitContext{Config: cfg, Plugins: supplier}}, registry
+}
+
+// TestMaskedConfiguration 验证生效配置导出时的脱敏
+// 测试场景：serverConnector 与 configConnector 均配置了 token
+// 前置条件：使用默认配置
+// 预期结果：token 被替换为掩码，未配置的敏感字段保持为空，其他字段原样输出
+func TestMaskedConfiguration(t *testing.T) {
+	cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	cfg.GetGlobal().GetServerConnector().SetToken("server-token")
+
+	value, err := maskedConfiguration(cfg)
+	assert.NoError(t, err)
+	data, err := json.Marshal(value)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "server-token")
+
+	global := value.(map[string]interface{})["global"].(map[string]interface{})
+	connector := global["serverConnector"].(map[string]interface{})
+	assert.Equal(t, maskedValue, connector["token"])
+	assert.Equal(t, []interface{}{"127.0.0.1:8091"}, connector["addresses"])
+	configConnector := value.(map[string]interface{})["config"].(map[string]interface{})["configConnector"]
+	assert.Equal(t, "", configConnector.(map[string]interface{})["token"])
+}
+
+// TestMaskedConfiguration_Headers 验证插件配置中请求头的脱敏
+// 测试场景：otel 指标插件的 headers 与 HTTP 健康探测的 requestHeadersToAdd 携带鉴权信息
+// 前置条件：headers 以 map 配置，requestHeadersToAdd 以 {key, value} 列表配置
+// 预期结果：请求头的值全部替换为掩码，请求头名称保留；其他插件字段原样输出
+func TestMaskedConfiguration_Headers(t *testing.T) {
+	cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	otelCfg := &otel.Config{
+		Endpoint:             "127.0.0.1:4317",
+		Headers:              map[string]string{"Authorization": "Bearer otel-secret", "x-tenant": "tenant-a"},
+		AllowInsecureHeaders: true,
+	}
+	assert.NoError(t, cfg.GetGlobal().GetStatReporter().SetPluginConfig(otel.PluginName, otelCfg))
+	healthCfg := &healthcheckhttp.Config{
+		Path:                "/health",
+		RequestHeadersToAdd: []*healthcheckhttp.RequestHeader{{Key: "X-Auth", Value: "health-secret"}},
+	}
+	assert.NoError(t, cfg.GetConsumer().GetHealthCheck().SetPluginConfig("http", healthCfg))
+
+	value, err := maskedConfiguration(cfg)
+	assert.NoError(t, err)
+	data, err := json.Marshal(value)
+	assert.NoError(t, err)
+	for _, secret := range []string{"otel-secret", "tenant-a", "health-secret"} {
+		assert.NotContains(t, string(data), secret)
+	}
+
+	root := value.(map[string]interface{})
+	statReporter := root["global"].(map[string]interface{})["statReporter"].(map[string]interface{})
+	otelValue := statReporter["plugin"].(map[string]interface{})[otel.PluginName].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"Authorization": maskedValue, "x-tenant": maskedValue},
+		otelValue["headers"])
+	assert.Equal(t, "127.0.0.1:4317", otelValue["endpoint"])
+	assert.Equal(t, true, otelValue["allowInsecureHeaders"])
+
+	healthCheck := root["consumer"].(map[string]interface{})["healthCheck"].(map[string]interface{})
+	httpValue := healthCheck["plugin"].(map[string]interface{})["http"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{"key": "X-Auth", "value": maskedValue}},
+		httpValue["requestHeadersToAdd"])
+	assert.Equal(t, "/health", httpValue["path"])
+}
+
+// TestIsSensitiveKey 验证敏感字段名的匹配
+// 测试场景：不同大小写及前缀的字段名
+// 预期结果：以 token/password/secret/authorization/cookie 等结尾的字段视为敏感，其余不视为敏感
+func TestIsSensitiveKey(t *testing.T) {
+	assert.True(t, isSensitiveKey("token"))
+	assert.True(t, isSensitiveKey("accessToken"))
+	assert.True(t, isSensitiveKey("Password"))
+	assert.True(t, isSensitiveKey("clientSecret"))
+	assert.True(t, isSensitiveKey("Authorization"))
+	assert.True(t, isSensitiveKey("proxyAuthorization"))
+	assert.True(t, isSensitiveKey("cookie"))
+	assert.True(t, isSensitiveKey("apiKey"))
+	assert.False(t, isSensitiveKey("tokenBucket"))
+	assert.False(t, isSensitiveKey("addresses"))
+}
+
+// TestServer_DebugHandlers 验证调试接口的输出
+// 测试场景：分别请求 /debug/registry、/debug/plugins、/debug/config
+// 前置条件：插件集合中包含实现 CacheDumper 的本地缓存插件和两个负载均衡插件
+// 预期结果：registry 透传查询参数作为过滤条件；plugins 按类型顺序和插件名排序输出；
+// config 返回 JSON；非 GET 请求返回 405
+func TestServer_DebugHandlers(t *testing.T) {
+	s, registry := newDebugTestServer()
+
+	rec := httptest.NewRecorder()
+	s.serveRegistry(rec, httptest.NewRequest(http.MethodGet,
+		DebugRegistryPath+"?namespace=default&service=svc-a&type=instance", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, []localregistry.CacheDumpFilter{{Namespace: "default", Service: "svc-a", Type: "instance"}},
+		registry.filters)
+	var entries []*localregistry.CacheEntry
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "svc-a", entries[0].Service)
+
+	rec = httptest.NewRecorder()
+	s.servePlugins(rec, httptest.NewRequest(http.MethodGet, DebugPluginsPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var plugins []plugin.LoadedPlugin
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &plugins))
+	assert.Equal(t, []plugin.LoadedPlugin{
+		{Type: common.TypeLocalRegistry.String(), Name: "inmemory", ID: 2, Enable: true},
+		{Type: common.TypeLoadBalancer.String(), Name: "ringHash", ID: 3, Enable: true},
+		{Type: common.TypeLoadBalancer.String(), Name: "weightedRandom", ID: 4, Enable: true},
+	}, plugins)
+
+	rec = httptest.NewRecorder()
+	s.serveConfig(rec, httptest.NewRequest(http.MethodGet, DebugConfigPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var cfg map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &cfg))
+	assert.Contains(t, cfg, "global")
+
+	rec = httptest.NewRecorder()
+	s.serveConfig(rec, httptest.NewRequest(http.MethodPost, DebugConfigPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
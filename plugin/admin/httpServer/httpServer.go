@@ -20,12 +20,14 @@ package httpserver
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
 	"sync/atomic"
 
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/network"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 )
@@ -38,9 +40,11 @@ const (
 type Server struct {
 	*plugin.PluginBase
 	pluginCtx       *plugin.InitContext
+	cfg             *Config
 	host            string
 	port            int
 	httpServer      *http.Server
+	tlsCloser       io.Closer // 停止证书热加载任务
 	mux             *http.ServeMux
 	once            sync.Once
 	sErr            atomic.Value
@@ -83,11 +87,28 @@ func (s *Server) Init(ctx *plugin.InitContext) error {
 	s.port = ctx.Config.GetGlobal().GetAdmin().GetPort()
 	s.mux = http.NewServeMux()
 	s.registeredPaths = make(map[string]bool)
+	if cfgValue := ctx.Config.GetGlobal().GetAdmin().GetPluginConfig(PluginName); cfgValue != nil {
+		s.cfg = cfgValue.(*Config)
+	}
+	if s.cfg.IsDebugEnable() {
+		s.registerDebugHandlers()
+	}
+	return nil
+}
+
+// Start 启动插件，开启调试接口时直接启动HTTP服务器
+func (s *Server) Start() error {
+	if s.cfg.IsDebugEnable() {
+		s.Run()
+	}
 	return nil
 }
 
 // Destroy 销毁插件，释放资源
 func (s *Server) Destroy() error {
+	if s.tlsCloser != nil {
+		_ = s.tlsCloser.Close()
+	}
 	if s.httpServer != nil {
 		if err := s.httpServer.Shutdown(context.Background()); err != nil {
 			log.GetBaseLogger().Errorf("[Admin][HttpServer] shutdown http server error: %v", err)
@@ -130,14 +151,37 @@ func (s *Server) Run() {
 			log.GetBaseLogger().Warnf("[Admin][HttpServer] no path registered, skip starting http server")
 			return
 		}
+		adminCfg := s.pluginCtx.Config.GetGlobal().GetAdmin()
+		handler, err := newSecurityHandler(s.mux, adminCfg)
+		if err != nil {
+			s.SetError(err)
+			log.GetBaseLogger().Errorf("[Admin][HttpServer] build security handler error: %v", err)
+			return
+		}
 		addr := fmt.Sprintf("%s:%d", s.host, s.port)
 		s.httpServer = &http.Server{
 			Addr:    addr,
-			Handler: s.mux,
+			Handler: handler,
+		}
+		tlsCfg := adminCfg.GetTLS()
+		if tlsCfg != nil && tlsCfg.IsEnable() {
+			if s.httpServer.TLSConfig, s.tlsCloser, err = network.NewServerTLSConfig(tlsCfg); err != nil {
+				s.SetError(err)
+				log.GetBaseLogger().Errorf("[Admin][HttpServer] build tls config error: %v", err)
+				return
+			}
 		}
 		go func() {
-			log.GetBaseLogger().Infof("[Admin][HttpServer] starting http server on %s", addr)
-			if err := s.httpServer.ListenAndServe(); err != nil {
+			log.GetBaseLogger().Infof("[Admin][HttpServer] starting http server on %s, tls %v",
+				addr, s.httpServer.TLSConfig != nil)
+			var err error
+			if s.httpServer.TLSConfig != nil {
+				// 证书由 TLSConfig.GetCertificate 提供
+				err = s.httpServer.ListenAndServeTLS("", "")
+			} else {
+				err = s.httpServer.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
 				s.SetError(err)
 				log.GetBaseLogger().Errorf("[Admin][HttpServer] http server error: %v", err)
 			}
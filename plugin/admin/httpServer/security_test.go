@@ -0,0 +1,157 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// noopLogger 测试中替代全局日志
+type noopLogger struct{}
+
+func (noopLogger) Tracef(string, ...interface{}) {}
+func (noopLogger) Debugf(string, ...interface{}) {}
+func (noopLogger) Infof(string, ...interface{})  {}
+func (noopLogger) Warnf(string, ...interface{})  {}
+func (noopLogger) Errorf(string, ...interface{}) {}
+func (noopLogger) Fatalf(string, ...interface{}) {}
+func (noopLogger) IsLevelEnabled(int) bool       { return false }
+func (noopLogger) SetLogLevel(int) error         { return nil }
+
+// newTestAdminConfig 构造已初始化的admin配置，modify 用于覆盖字段
+func newTestAdminConfig(modify func(c *config.AdminConfigImpl)) *config.AdminConfigImpl {
+	adminCfg := &config.AdminConfigImpl{}
+	adminCfg.Init()
+	modify(adminCfg)
+	adminCfg.SetDefault()
+	return adminCfg
+}
+
+// newSecurityTestHandler 构造对所有路径返回 200 的安全处理器
+func newSecurityTestHandler(t *testing.T, adminCfg *config.AdminConfigImpl) http.Handler {
+	if log.GetBaseLogger() == nil {
+		log.SetBaseLogger(noopLogger{})
+	}
+	assert.NoError(t, adminCfg.Verify())
+	handler, err := newSecurityHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), adminCfg)
+	assert.NoError(t, err)
+	return handler
+}
+
+func doSecurityRequest(handler http.Handler, remoteAddr, path string, setup func(r *http.Request)) int {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if setup != nil {
+		setup(req)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+// TestSecurityHandler_AllowedIPs 验证IP白名单
+// 测试场景：白名单配置单个IP与一个CIDR
+// 预期结果：白名单内的地址放行，其余返回 403；未配置白名单时全部放行
+func TestSecurityHandler_AllowedIPs(t *testing.T) {
+	handler := newSecurityTestHandler(t, newTestAdminConfig(func(c *config.AdminConfigImpl) {
+		c.AllowedIPs = []string{"127.0.0.1", "10.0.0.0/8"}
+	}))
+	assert.Equal(t, http.StatusOK, doSecurityRequest(handler, "127.0.0.1:5000", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, doSecurityRequest(handler, "10.1.2.3:5000", "/metrics", nil))
+	assert.Equal(t, http.StatusForbidden, doSecurityRequest(handler, "192.168.0.1:5000", "/metrics", nil))
+	assert.Equal(t, http.StatusForbidden, doSecurityRequest(handler, "[::1]:5000", "/metrics", nil))
+
+	open := newSecurityTestHandler(t, newTestAdminConfig(func(c *config.AdminConfigImpl) {}))
+	assert.Equal(t, http.StatusOK, doSecurityRequest(open, "192.168.0.1:5000", "/metrics", nil))
+}
+
+// TestSecurityHandler_PathAuth 验证按路径配置认证方式
+// 测试场景：默认 bearer 认证，/metrics 免认证，/offline 使用 basic 认证
+// 预期结果：/metrics 无需凭证；/offline 仅接受正确的 basic 凭证；其余路径仅接受正确的 bearer 令牌，
+// 认证失败返回 401 并带上 WWW-Authenticate 头
+func TestSecurityHandler_PathAuth(t *testing.T) {
+	handler := newSecurityTestHandler(t, newTestAdminConfig(func(c *config.AdminConfigImpl) {
+		c.Auth = &config.AdminAuthConfigImpl{
+			Type:     config.AdminAuthBearer,
+			Token:    "secret-token",
+			Username: "admin",
+			Password: "admin-pass",
+			Paths:    map[string]string{"/metrics": config.AdminAuthNone, "/offline": config.AdminAuthBasic},
+		}
+	}))
+	const addr = "127.0.0.1:5000"
+	bearer := func(token string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+	basic := func(user, pass string) func(r *http.Request) {
+		return func(r *http.Request) { r.SetBasicAuth(user, pass) }
+	}
+
+	assert.Equal(t, http.StatusOK, doSecurityRequest(handler, addr, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, doSecurityRequest(handler, addr, "/metrics/sub", nil))
+	assert.Equal(t, http.StatusUnauthorized, doSecurityRequest(handler, addr, "/metricsx", nil))
+
+	assert.Equal(t, http.StatusOK, doSecurityRequest(handler, addr, "/offline", basic("admin", "admin-pass")))
+	assert.Equal(t, http.StatusUnauthorized, doSecurityRequest(handler, addr, "/offline", basic("admin", "wrong")))
+	assert.Equal(t, http.StatusUnauthorized, doSecurityRequest(handler, addr, "/offline", bearer("secret-token")))
+
+	assert.Equal(t, http.StatusOK, doSecurityRequest(handler, addr, "/debug/config", bearer("secret-token")))
+	assert.Equal(t, http.StatusUnauthorized, doSecurityRequest(handler, addr, "/debug/config", bearer("wrong")))
+	assert.Equal(t, http.StatusUnauthorized, doSecurityRequest(handler, addr, "/debug/config", nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/config", nil)
+	req.RemoteAddr = addr
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+}
+
+// TestAdminConfig_VerifySecurity 验证admin安全相关配置的校验
+// 测试场景：非法白名单、缺少令牌的 bearer 认证、缺少密码的 basic 认证、未知认证方式、开启TLS但未配置证书
+// 预期结果：均校验失败；合法配置校验通过
+func TestAdminConfig_VerifySecurity(t *testing.T) {
+	cases := []func(c *config.AdminConfigImpl){
+		func(c *config.AdminConfigImpl) { c.AllowedIPs = []string{"not-an-ip"} },
+		func(c *config.AdminConfigImpl) { c.Auth.Type = config.AdminAuthBearer },
+		func(c *config.AdminConfigImpl) {
+			c.Auth.Paths = map[string]string{"/offline": config.AdminAuthBasic}
+			c.Auth.Username = "u"
+		},
+		func(c *config.AdminConfigImpl) { c.Auth.Type = "digest" },
+		func(c *config.AdminConfigImpl) { c.Auth.Paths = map[string]string{"metrics": config.AdminAuthNone} },
+		func(c *config.AdminConfigImpl) { c.TLS.SetEnable(true) },
+	}
+	for _, modify := range cases {
+		assert.Error(t, newTestAdminConfig(modify).Verify())
+	}
+
+	valid := newTestAdminConfig(func(c *config.AdminConfigImpl) {
+		c.AllowedIPs = []string{"::1", "fd00::/8"}
+		c.Auth = &config.AdminAuthConfigImpl{Type: config.AdminAuthBasic, Username: "u", Password: "p"}
+	})
+	assert.NoError(t, valid.Verify())
+}
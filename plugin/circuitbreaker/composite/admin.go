@@ -0,0 +1,366 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package composite
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/admin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/plugin/circuitbreaker/composite/trigger"
+)
+
+const (
+	// AdminPath 查询所有被跟踪资源熔断状态的 admin 路径
+	AdminPath = "/circuitbreaker"
+	// AdminOverridePath 强制干预资源熔断状态的 admin 路径
+	AdminOverridePath = "/circuitbreaker/override"
+)
+
+const (
+	// OverrideOpen 强制熔断，不会自动进入半开，直到 close/reset
+	OverrideOpen = "open"
+	// OverrideClose 强制恢复，期间触发条件不再让资源熔断，直到 open/reset
+	OverrideClose = "close"
+	// OverrideReset 解除干预，资源恢复 Close 并重置触发计数器
+	OverrideReset = "reset"
+)
+
+// OverrideRequest 强制干预请求
+type OverrideRequest struct {
+	// Action open / close / reset
+	Action string `json:"action"`
+	// Level service / method / instance
+	Level string `json:"level"`
+	// Namespace 被调命名空间
+	Namespace string `json:"namespace"`
+	// Service 被调服务名
+	Service string `json:"service"`
+	// CallerNamespace 主调命名空间，为空时匹配所有主调
+	CallerNamespace string `json:"callerNamespace,omitempty"`
+	// CallerService 主调服务名，为空时匹配所有主调
+	CallerService string `json:"callerService,omitempty"`
+	// Method 接口路径，level 为 method 时必填
+	Method string `json:"method,omitempty"`
+	// Host 实例地址，level 为 instance 时必填
+	Host string `json:"host,omitempty"`
+	// Port 实例端口，level 为 instance 时必填
+	Port uint32 `json:"port,omitempty"`
+	// Reason 干预原因，记录到熔断事件
+	Reason string `json:"reason,omitempty"`
+}
+
+// verify 校验请求参数并返回资源级别
+func (r *OverrideRequest) verify() (fault_tolerance.Level, error) {
+	switch r.Action {
+	case OverrideOpen, OverrideClose, OverrideReset:
+	default:
+		return fault_tolerance.Level_UNKNOWN, fmt.Errorf("invalid action %q, must be one of open/close/reset",
+			r.Action)
+	}
+	if r.Namespace == "" || r.Service == "" {
+		return fault_tolerance.Level_UNKNOWN, fmt.Errorf("namespace and service are required")
+	}
+	switch strings.ToLower(r.Level) {
+	case "service":
+		return fault_tolerance.Level_SERVICE, nil
+	case "method":
+		if r.Method == "" {
+			return fault_tolerance.Level_UNKNOWN, fmt.Errorf("method is required for method level")
+		}
+		return fault_tolerance.Level_METHOD, nil
+	case "instance":
+		if r.Host == "" || r.Port == 0 {
+			return fault_tolerance.Level_UNKNOWN, fmt.Errorf("host and port are required for instance level")
+		}
+		return fault_tolerance.Level_INSTANCE, nil
+	}
+	return fault_tolerance.Level_UNKNOWN, fmt.Errorf("invalid level %q, must be one of service/method/instance",
+		r.Level)
+}
+
+// match 资源是否命中干预请求
+func (r *OverrideRequest) match(res model.Resource) bool {
+	svc := res.GetService()
+	if svc.Namespace != r.Namespace || svc.Service != r.Service {
+		return false
+	}
+	if r.CallerNamespace != "" || r.CallerService != "" {
+		caller := res.GetCallerService()
+		if caller == nil || (r.CallerNamespace != "" && caller.Namespace != r.CallerNamespace) ||
+			(r.CallerService != "" && caller.Service != r.CallerService) {
+			return false
+		}
+	}
+	switch v := res.(type) {
+	case *model.MethodResource:
+		return v.Path == r.Method
+	case *model.InstanceResource:
+		return v.GetNode().Host == r.Host && v.GetNode().Port == r.Port
+	}
+	return true
+}
+
+// ResourceStatus admin 接口返回的资源熔断状态
+type ResourceStatus struct {
+	Level           string `json:"level"`
+	Resource        string `json:"resource"`
+	Namespace       string `json:"namespace"`
+	Service         string `json:"service"`
+	CallerNamespace string `json:"callerNamespace,omitempty"`
+	CallerService   string `json:"callerService,omitempty"`
+	Method          string `json:"method,omitempty"`
+	Host            string `json:"host,omitempty"`
+	Port            uint32 `json:"port,omitempty"`
+	// Status 当前熔断状态 open / half-open / close
+	Status string `json:"status"`
+	// CircuitBreaker 触发当前状态的规则/块名
+	CircuitBreaker string    `json:"circuitBreaker"`
+	StartTime      time.Time `json:"startTime"`
+	// Override 运维强制设置的状态，未干预时为空
+	Override string `json:"override,omitempty"`
+	RuleID   string `json:"ruleId"`
+	RuleName string `json:"ruleName"`
+	Revision string `json:"revision"`
+	// HalfOpenMaxRequest / HalfOpenCalled 半开态探测配额与已归集结果数
+	HalfOpenMaxRequest int `json:"halfOpenMaxRequest,omitempty"`
+	HalfOpenCalled     int `json:"halfOpenCalled,omitempty"`
+	// LastTransition 最近一次状态切换及原因
+	LastTransition *transitionRecord `json:"lastTransition,omitempty"`
+	// Triggers 各块触发计数器快照
+	Triggers []trigger.CounterSnapshot `json:"triggers"`
+}
+
+// describe 生成资源熔断状态快照
+func (rc *ResourceCounters) describe() ResourceStatus {
+	rc.lock.RLock()
+	defer rc.lock.RUnlock()
+	ret := ResourceStatus{
+		Level:     levelToString(rc.resource.GetLevel()),
+		Resource:  rc.resource.String(),
+		Namespace: rc.resource.GetService().Namespace,
+		Service:   rc.resource.GetService().Service,
+		RuleID:    rc.activeRule.GetId(),
+		RuleName:  rc.activeRule.GetName(),
+		Revision:  rc.activeRule.GetRevision(),
+		Triggers:  []trigger.CounterSnapshot{},
+	}
+	if caller := rc.resource.GetCallerService(); caller != nil {
+		ret.CallerNamespace, ret.CallerService = caller.Namespace, caller.Service
+	}
+	switch res := rc.resource.(type) {
+	case *model.MethodResource:
+		ret.Method = res.Path
+	case *model.InstanceResource:
+		ret.Host, ret.Port = res.GetNode().Host, res.GetNode().Port
+	}
+	if status := rc.CurrentCircuitBreakerStatus(); status != nil {
+		ret.Status = status.GetStatus().String()
+		ret.CircuitBreaker = status.GetCircuitBreaker()
+		ret.StartTime = status.GetStartTime()
+		if halfOpen, ok := status.(*model.HalfOpenStatus); ok {
+			ret.HalfOpenMaxRequest = halfOpen.MaxRequest()
+			ret.HalfOpenCalled = halfOpen.CalledCount()
+		}
+	}
+	if rc.override != 0 {
+		ret.Override = rc.override.String()
+	}
+	if val, ok := rc.lastTransition.Load().(*transitionRecord); ok {
+		ret.LastTransition = val
+	}
+	blocks := rc.blocks
+	if rc.legacyBlock != nil {
+		blocks = append([]*blockCounter{rc.legacyBlock}, blocks...)
+	}
+	for _, b := range blocks {
+		for _, counter := range b.counters {
+			ret.Triggers = append(ret.Triggers, counter.Snapshot())
+		}
+	}
+	return ret
+}
+
+// applyOverride 按运维指令强制切换资源状态，并通过熔断事件留痕
+// open 不调度半开；close 期间 trigger 触发也不会熔断；close/reset 都会复位触发计数器
+func (rc *ResourceCounters) applyOverride(action string, reason string) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	before := rc.CurrentCircuitBreakerStatus()
+	var newStatus model.CircuitBreakerStatus
+	switch action {
+	case OverrideOpen:
+		rc.override = model.Open
+		newStatus = model.NewCircuitBreakerStatus(rc.activeRule.GetName(), model.Open, time.Now(),
+			func(cbs model.CircuitBreakerStatus) {
+				cbs.SetFallbackInfo(rc.fallbackInfo)
+			})
+	case OverrideClose:
+		rc.override = model.Close
+		newStatus = model.NewCircuitBreakerStatus(rc.activeRule.GetName(), model.Close, time.Now())
+	case OverrideReset:
+		rc.override = 0
+		newStatus = model.NewCircuitBreakerStatus(rc.activeRule.GetName(), model.Close, time.Now())
+	default:
+		return
+	}
+	rc.updateCircuitBreakerStatus(newStatus)
+	rc.reportCircuitStatus(newStatus)
+	rc.markPersist()
+	rc.logStat.Infof("[CircuitBreaker] status override(%s): %s -> %s, resource(%s), rule(%s, id=%s, rev=%s), "+
+		"reason(%s)", action, before.GetStatus(), newStatus.GetStatus(), rc.resource.String(),
+		rc.activeRule.GetName(), rc.activeRule.Id, rc.activeRule.Revision, reason)
+	rc.reportCircuitBreakMetric(newStatus)
+	rc.reportCircuitBreakerEvent(before.GetStatus(), newStatus.GetStatus(), reason)
+	if newStatus.GetStatus() == model.Close {
+		rc.resumeAllBlocks()
+	}
+}
+
+// serveOnAdmin 开启 admin 接口时注册 /circuitbreaker 路径并启动 admin 服务
+func (c *CompositeCircuitBreaker) serveOnAdmin() {
+	if !c.cfg.IsAdminEnable() {
+		return
+	}
+	adminCfg := c.pluginCtx.Config.GetGlobal().GetAdmin()
+	adminCfg.RegisterPath(model.AdminHandler{
+		Path:        AdminPath,
+		HandlerFunc: c.serveResources,
+	})
+	adminCfg.RegisterPath(model.AdminHandler{
+		Path:        AdminOverridePath,
+		HandlerFunc: c.serveOverride,
+	})
+	targetPlugin, err := c.pluginCtx.Plugins.GetPlugin(common.TypeAdmin, adminCfg.GetType())
+	if err != nil {
+		c.cbLog.Errorf("[CircuitBreaker] get admin plugin fail: %v", err)
+		return
+	}
+	targetPlugin.(admin.Admin).Run()
+}
+
+// trackedCounters 当前所有被跟踪的资源计数器
+func (c *CompositeCircuitBreaker) trackedCounters() []*ResourceCounters {
+	var ret []*ResourceCounters
+	for _, level := range []fault_tolerance.Level{fault_tolerance.Level_SERVICE, fault_tolerance.Level_METHOD,
+		fault_tolerance.Level_INSTANCE, fault_tolerance.Level_GROUP} {
+		bucket := c.getLevelResourceCounters(level)
+		if bucket == nil {
+			continue
+		}
+		bucket.lock.RLock()
+		for _, counters := range bucket.m {
+			ret = append(ret, counters)
+		}
+		bucket.lock.RUnlock()
+	}
+	return ret
+}
+
+// ResourceStatuses 所有被跟踪资源的熔断状态，可按命名空间、服务过滤，按级别与资源标识排序
+func (c *CompositeCircuitBreaker) ResourceStatuses(namespace, service string) []ResourceStatus {
+	ret := make([]ResourceStatus, 0)
+	for _, counters := range c.trackedCounters() {
+		svc := counters.resource.GetService()
+		if (namespace != "" && svc.Namespace != namespace) || (service != "" && svc.Service != service) {
+			continue
+		}
+		ret = append(ret, counters.describe())
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		// 级别名按字典序倒排即 SERVICE、METHOD、INSTANCE
+		if ret[i].Level != ret[j].Level {
+			return ret[i].Level > ret[j].Level
+		}
+		return ret[i].Resource < ret[j].Resource
+	})
+	return ret
+}
+
+// Override 对命中请求的资源执行强制干预，返回干预后的资源状态
+func (c *CompositeCircuitBreaker) Override(req *OverrideRequest) ([]ResourceStatus, error) {
+	level, err := req.verify()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]ResourceStatus, 0)
+	for _, counters := range c.trackedCounters() {
+		if counters.resource.GetLevel() != level || !req.match(counters.resource) {
+			continue
+		}
+		counters.applyOverride(req.Action, req.Reason)
+		ret = append(ret, counters.describe())
+	}
+	return ret, nil
+}
+
+// serveResources GET /circuitbreaker?namespace=&service=
+func (c *CompositeCircuitBreaker) serveResources(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAdminJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "only GET is allowed"})
+		return
+	}
+	query := r.URL.Query()
+	writeAdminJSON(w, http.StatusOK, c.ResourceStatuses(query.Get("namespace"), query.Get("service")))
+}
+
+// serveOverride POST /circuitbreaker/override，请求体为 OverrideRequest 的 JSON
+func (c *CompositeCircuitBreaker) serveOverride(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeAdminJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "only POST is allowed"})
+		return
+	}
+	req := &OverrideRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeAdminJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body: " + err.Error()})
+		return
+	}
+	operatorReason := req.Reason
+	req.Reason = fmt.Sprintf("admin override %s from %s", req.Action, r.RemoteAddr)
+	if operatorReason != "" {
+		req.Reason += ": " + operatorReason
+	}
+	result, err := c.Override(req)
+	if err != nil {
+		writeAdminJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if len(result) == 0 {
+		writeAdminJSON(w, http.StatusNotFound, map[string]string{"error": "no tracked resource matched"})
+		return
+	}
+	c.cbLog.Infof("[CircuitBreaker] admin override %s applied to %d resources, request(%+v)", req.Action,
+		len(result), *req)
+	writeAdminJSON(w, http.StatusOK, result)
+}
+
+// writeAdminJSON 以 JSON 格式输出 admin 接口响应
+func writeAdminJSON(w http.ResponseWriter, code int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(value)
+}
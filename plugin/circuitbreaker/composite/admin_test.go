@@ -0,0 +1,184 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package composite
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/event"
+	"github.com/polarismesh/polaris-go/pkg/plugin/events"
+)
+
+// newAdminBreakerForTest 构造仅跟踪一个服务级资源的熔断器，资源规则为连续 3 次错误熔断
+func newAdminBreakerForTest(t *testing.T) (*CompositeCircuitBreaker, *ResourceCounters, *fakeEventReporter) {
+	executor := newTaskExecutor(2, newTestLogCtx())
+	t.Cleanup(executor.Stop)
+	res, err := model.NewServiceResource(&model.ServiceKey{Namespace: "default", Service: "order"}, nil)
+	assert.NoError(t, err)
+	reporter := &fakeEventReporter{}
+	rc := &ResourceCounters{
+		activeRule: &fault_tolerance.CircuitBreakerRule{
+			Id:       "rule-id",
+			Name:     "rule-A",
+			Revision: "v1",
+			TriggerCondition: []*fault_tolerance.TriggerCondition{{
+				TriggerType: fault_tolerance.TriggerCondition_CONSECUTIVE_ERROR,
+				ErrorCount:  3,
+			}},
+			RecoverCondition: &fault_tolerance.RecoverCondition{SleepWindow: 30, ConsecutiveSuccess: 1},
+		},
+		resource:   res,
+		logStat:    noopLogger{},
+		executor:   executor,
+		engineFlow: &fakeReportEngine{eventChain: []events.EventReporter{reporter}},
+	}
+	rc.updateCircuitBreakerStatus(model.NewCircuitBreakerStatus("rule-A", model.Close, time.Now()))
+	assert.NoError(t, rc.init())
+
+	cb := newCompositeForTest()
+	cb.countersCache = map[fault_tolerance.Level]*CountersBucket{
+		fault_tolerance.Level_SERVICE:  newCountersBucket(),
+		fault_tolerance.Level_METHOD:   newCountersBucket(),
+		fault_tolerance.Level_INSTANCE: newCountersBucket(),
+	}
+	cb.countersCache[fault_tolerance.Level_SERVICE].put(res, rc)
+	return cb, rc, reporter
+}
+
+func doAdminRequest(handler http.HandlerFunc, method, target string, body interface{}) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+	return recorder
+}
+
+// TestAdmin_ListResources 测试场景：查询被跟踪资源的熔断状态
+// 前置条件：服务级资源上报 1 次失败
+// 预期结果：返回资源状态 close、规则信息与连续错误触发器计数；按服务过滤不命中时返回空列表
+func TestAdmin_ListResources(t *testing.T) {
+	cb, rc, _ := newAdminBreakerForTest(t)
+	rc.Report(&model.ResourceStat{Resource: rc.resource, RetStatus: model.RetFail})
+
+	recorder := doAdminRequest(cb.serveResources, http.MethodGet, AdminPath, nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var statuses []ResourceStatus
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &statuses))
+	if !assert.Len(t, statuses, 1) {
+		return
+	}
+	got := statuses[0]
+	assert.Equal(t, "SERVICE", got.Level)
+	assert.Equal(t, "order", got.Service)
+	assert.Equal(t, "close", got.Status)
+	assert.Equal(t, "rule-id", got.RuleID)
+	if assert.Len(t, got.Triggers, 1) {
+		assert.Equal(t, "CONSECUTIVE_ERROR", got.Triggers[0].Type)
+		assert.Equal(t, int64(1), got.Triggers[0].ErrorCount)
+		assert.Equal(t, int64(3), got.Triggers[0].Threshold)
+	}
+
+	recorder = doAdminRequest(cb.serveResources, http.MethodGet, AdminPath+"?service=user", nil)
+	assert.Equal(t, "[]", strings.TrimSpace(recorder.Body.String()))
+
+	recorder = doAdminRequest(cb.serveResources, http.MethodPost, AdminPath, nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+// TestAdmin_OverrideOpenCloseReset 测试场景：运维依次强制熔断、强制恢复、解除干预
+// 预期结果：open 后资源熔断且不会被自动调度进入半开；close 期间触发条件达到也不熔断；
+// reset 后恢复正常状态机；每次干预都通过熔断事件留痕并记录最近一次切换原因
+func TestAdmin_OverrideOpenCloseReset(t *testing.T) {
+	cb, rc, reporter := newAdminBreakerForTest(t)
+	req := &OverrideRequest{
+		Action:    OverrideOpen,
+		Level:     "service",
+		Namespace: "default",
+		Service:   "order",
+		Reason:    "incident-1",
+	}
+	recorder := doAdminRequest(cb.serveOverride, http.MethodPost, AdminOverridePath, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, model.Open, rc.CurrentCircuitBreakerStatus().GetStatus())
+	rc.OpenToHalfOpen()
+	assert.Equal(t, model.Open, rc.CurrentCircuitBreakerStatus().GetStatus())
+	if assert.Len(t, reporter.events, 1) {
+		got := reporter.events[0].(*event.BaseEventImpl)
+		assert.Equal(t, event.CircuitBreakerOpen, got.GetEventName())
+		assert.Contains(t, got.Reason, "admin override open")
+		assert.Contains(t, got.Reason, "incident-1")
+	}
+	statuses := cb.ResourceStatuses("", "")
+	assert.Equal(t, "open", statuses[0].Override)
+	assert.Contains(t, statuses[0].LastTransition.Reason, "incident-1")
+
+	req.Action = OverrideClose
+	_, err := cb.Override(req)
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		rc.Report(&model.ResourceStat{Resource: rc.resource, RetStatus: model.RetFail})
+	}
+	assert.Equal(t, model.Close, rc.CurrentCircuitBreakerStatus().GetStatus())
+
+	req.Action = OverrideReset
+	_, err = cb.Override(req)
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		rc.Report(&model.ResourceStat{Resource: rc.resource, RetStatus: model.RetFail})
+	}
+	assert.Equal(t, model.Open, rc.CurrentCircuitBreakerStatus().GetStatus())
+	assert.Empty(t, cb.ResourceStatuses("", "")[0].Override)
+	assert.Len(t, reporter.events, 4)
+}
+
+// TestAdmin_OverrideInvalidRequest 测试场景：非法干预请求
+// 预期结果：参数非法返回 400，未命中资源返回 404，非 POST 返回 405
+func TestAdmin_OverrideInvalidRequest(t *testing.T) {
+	cb, _, _ := newAdminBreakerForTest(t)
+	recorder := doAdminRequest(cb.serveOverride, http.MethodPost, AdminOverridePath,
+		&OverrideRequest{Action: "pause", Level: "service", Namespace: "default", Service: "order"})
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = doAdminRequest(cb.serveOverride, http.MethodPost, AdminOverridePath,
+		&OverrideRequest{Action: OverrideOpen, Level: "instance", Namespace: "default", Service: "order"})
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = doAdminRequest(cb.serveOverride, http.MethodPost, AdminOverridePath,
+		&OverrideRequest{Action: OverrideOpen, Level: "method", Namespace: "default", Service: "order",
+			Method: "/echo"})
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = doAdminRequest(cb.serveOverride, http.MethodGet, AdminOverridePath, nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -80,6 +80,20 @@ func newBlockCounter(rc *ResourceCounters, name string, api *apimodel.API,
 			bc.counters = append(bc.counters, trigger.NewErrRateCounter(name, &opt))
 		}
 	}
+	// 慢调用比例触发条件来自插件配置，按时延独立统计，不参与本块错误条件的判错
+	if rc.circuitBreaker != nil {
+		for _, slowCallRate := range rc.circuitBreaker.cfg.slowCallRatesOf(rc.activeRule.GetName(), name) {
+			bc.counters = append(bc.counters, trigger.NewSlowCallRateCounter(name, &trigger.Options{
+				Resource:      rc.resource,
+				StatusHandler: rc,
+				DelayExecutor: rc.executor.DelayExecute,
+				Log:           rc.logStat,
+				RuleID:        rc.activeRule.Id,
+				RuleRevision:  rc.activeRule.Revision,
+				SlowCallRate:  slowCallRate,
+			}))
+		}
+	}
 	return bc
 }
 
@@ -159,10 +173,15 @@ func (b *blockCounter) matchAPI(res model.Resource) bool {
 }
 
 // Report 把一次调用结果按本块的判错语义喂给本块内所有 trigger counter
+// 慢调用比例等时延类 trigger 直接接收 stat.Delay，不受本块判错语义影响
 func (b *blockCounter) Report(stat *model.ResourceStat) {
 	retStatus := b.parseRetStatus(stat)
 	isSuccess := retStatus != model.RetFail && retStatus != model.RetTimeout
 	for _, c := range b.counters {
+		if dc, ok := c.(trigger.DelayTriggerCounter); ok {
+			dc.ReportDelay(stat.Delay)
+			continue
+		}
 		c.Report(isSuccess)
 	}
 }
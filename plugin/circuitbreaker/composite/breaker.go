@@ -34,6 +34,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/healthcheck"
 	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
 	"github.com/polarismesh/polaris-go/pkg/sdk"
+	lrplug "github.com/polarismesh/polaris-go/plugin/localregistry/common"
 )
 
 const (
@@ -50,7 +51,7 @@ type CompositeCircuitBreaker struct {
 	// countersCache
 	countersCache map[fault_tolerance.Level]*CountersBucket
 	// healthCheckers .
-	healthCheckers map[fault_tolerance.FaultDetectRule_Protocol]healthcheck.HealthChecker
+	healthCheckers map[string]healthcheck.HealthChecker
 	// healthCheckCache map[model.Resource]*ResourceHealthChecker
 	healthCheckCache *sync.Map
 	// serviceHealthCheckCache map[model.ServiceKey]map[model.Resource]*ResourceHealthChecker
@@ -93,6 +94,10 @@ type CompositeCircuitBreaker struct {
 	taskCtx context.Context
 	// executor
 	executor *TaskExecutor
+	// persister 熔断状态持久化器，未开启持久化时为 nil
+	persister *statusPersister
+	// cfg 插件配置
+	cfg *circuitbreakConfig
 }
 
 // Init 初始化插件
@@ -113,7 +118,7 @@ func (c *CompositeCircuitBreaker) Init(ctx *plugin.InitContext) error {
 func (c *CompositeCircuitBreaker) Start() error {
 	c.taskCtx, c.cancel = context.WithCancel(context.Background())
 	c.countersCache = make(map[fault_tolerance.Level]*CountersBucket)
-	c.healthCheckers = make(map[fault_tolerance.FaultDetectRule_Protocol]healthcheck.HealthChecker)
+	c.healthCheckers = make(map[string]healthcheck.HealthChecker)
 	c.healthCheckCache = &sync.Map{}
 	c.serviceHealthCheckCache = &sync.Map{}
 	c.containers = &sync.Map{}
@@ -151,7 +156,7 @@ func (c *CompositeCircuitBreaker) Start() error {
 	for i := range plugins {
 		item := plugins[i]
 		checker := item.(healthcheck.HealthChecker)
-		c.healthCheckers[checker.Protocol()] = checker
+		c.healthCheckers[healthcheck.CheckerProtocol(checker)] = checker
 	}
 	registryPlugin, err := c.pluginCtx.Plugins.GetPlugin(common.TypeLocalRegistry, c.pluginCtx.Config.GetConsumer().GetLocalCache().GetType())
 	if err != nil {
@@ -164,6 +169,34 @@ func (c *CompositeCircuitBreaker) Start() error {
 	c.ruleDict = newCircuitBreakerRuleDictionary(c.loadOrStoreCompiledRegex, c.cbLog)
 	// 启动周期性规则复检兜底任务
 	c.executor.IntervalExecute(c.ruleCheckInterval, c.checkRules)
+	c.cfg = &circuitbreakConfig{}
+	cfgValue := c.pluginCtx.Config.GetConsumer().GetCircuitBreaker().GetPluginConfig(c.Name())
+	if cfgValue != nil {
+		c.cfg = cfgValue.(*circuitbreakConfig)
+	}
+	c.cfg.SetDefault()
+	if err := c.initPersister(); err != nil {
+		return err
+	}
+	c.serveOnAdmin()
+	return nil
+}
+
+// initPersister 开启熔断状态持久化时，加载上次进程退出前仍在熔断窗口内的状态并启动定时刷盘
+func (c *CompositeCircuitBreaker) initPersister() error {
+	if !c.cfg.IsPersistEnable() {
+		return nil
+	}
+	localCacheCfg := c.pluginCtx.Config.GetConsumer().GetLocalCache()
+	handler, err := lrplug.NewCachePersistHandler(true, model.ReplaceHomeVar(localCacheCfg.GetPersistDir()),
+		localCacheCfg.GetPersistMaxWriteRetry(), localCacheCfg.GetPersistMaxReadRetry(),
+		localCacheCfg.GetPersistRetryInterval(), c.logCtx)
+	if err != nil {
+		return err
+	}
+	c.persister = newStatusPersister(handler, c.cbLog)
+	c.persister.load(time.Now())
+	c.executor.IntervalExecute(*c.cfg.PersistInterval, c.persister.flush)
 	return nil
 }
 
@@ -183,6 +216,9 @@ func (c *CompositeCircuitBreaker) Destroy() error {
 		checker.stop()
 		return true
 	})
+	if c.persister != nil {
+		c.persister.flush()
+	}
 	return nil
 }
 
@@ -190,11 +226,25 @@ func (c *CompositeCircuitBreaker) Destroy() error {
 func (c *CompositeCircuitBreaker) CheckResource(res model.Resource) model.CircuitBreakerStatus {
 	counters, exist := c.getResourceCounters(res)
 	if !exist {
-		return nil
+		return c.checkPersistedStatus(res)
 	}
 	return counters.CurrentCircuitBreakerStatus()
 }
 
+// checkPersistedStatus 资源计数器尚未建立时，使用重启前持久化的熔断状态，
+// 并立即拉取规则建立计数器，避免重启后在规则加载完成前放通已熔断的资源
+func (c *CompositeCircuitBreaker) checkPersistedStatus(res model.Resource) model.CircuitBreakerStatus {
+	if c.persister == nil {
+		return nil
+	}
+	status, ok := c.persister.lookup(res.String(), time.Now())
+	if !ok {
+		return nil
+	}
+	c.loadOrStoreContainer(res)
+	return status.toCircuitBreakerStatus()
+}
+
 // Report report resource invoke result stat
 func (c *CompositeCircuitBreaker) Report(stat *model.ResourceStat) error {
 	return c.doReport(stat, true)
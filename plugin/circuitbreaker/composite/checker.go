@@ -48,7 +48,7 @@ type ResourceHealthChecker struct {
 	resource       model.Resource
 	faultDetector  *fault_tolerance.FaultDetector
 	stopped        int32
-	healthCheckers map[fault_tolerance.FaultDetectRule_Protocol]healthcheck.HealthChecker
+	healthCheckers map[string]healthcheck.HealthChecker
 	circuitBreaker *CompositeCircuitBreaker
 	// regexFunction
 	regexFunction func(string) *regexp.Regexp
@@ -177,14 +177,13 @@ func (c *ResourceHealthChecker) createCheckJob(protocol string, rule *fault_tole
 				c.resource.String(), protocol, rule.GetName())
 			return
 		}
-		name := fault_tolerance.FaultDetectRule_Protocol_value[protocol]
 		c.log.Debugf("[FaultDetect] check job fired, resource=%s, protocol=%s, rule=%s",
 			c.resource.String(), protocol, rule.GetName())
-		c.checkResource(fault_tolerance.FaultDetectRule_Protocol(name), rule)
+		c.checkResource(protocol, rule)
 	}
 }
 
-func (c *ResourceHealthChecker) checkResource(protocol fault_tolerance.FaultDetectRule_Protocol, rule *fault_tolerance.FaultDetectRule) {
+func (c *ResourceHealthChecker) checkResource(protocol string, rule *fault_tolerance.FaultDetectRule) {
 	port := rule.GetPort()
 	if port > 0 {
 		hosts := map[string]struct{}{}
@@ -192,7 +191,7 @@ func (c *ResourceHealthChecker) checkResource(protocol fault_tolerance.FaultDete
 		defer c.lock.RUnlock()
 		// 周期级日志：每次探测调度都会执行，统计待探实例数，使用 Debug 级别避免刷屏。
 		c.log.Debugf("[FaultDetect] checkResource start (fixed port=%d), resource=%s, protocol=%s, instance_count=%d",
-			port, c.resource.String(), protocol.String(), len(c.instances))
+			port, c.resource.String(), protocol, len(c.instances))
 		for k, v := range c.instances {
 			if _, ok := hosts[k]; ok {
 				continue
@@ -203,7 +202,7 @@ func (c *ResourceHealthChecker) checkResource(protocol fault_tolerance.FaultDete
 				Port: wrapperspb.UInt32(v.insRes.GetNode().Port),
 			}, defaultServiceKey(v.insRes.GetService()), nil)
 			// 探测器按探测规则的 protocol 注册到 healthCheckers，故 doCheck 必须传规则 protocol；
-			// 实例自身 protocol（v.protocol）通常为 UNKNOWN（注册时未声明），用它查 healthCheckers
+			// 实例自身 protocol（v.protocol）通常为空（注册时未声明），用它查 healthCheckers
 			// 会命中 plugin not found 而跳过探测。
 			isSuccess := c.doCheck(ins, protocol, rule)
 			v.setCheckResult(isSuccess)
@@ -214,20 +213,20 @@ func (c *ResourceHealthChecker) checkResource(protocol fault_tolerance.FaultDete
 	defer c.lock.RUnlock()
 	// 周期级日志：port=0 分支用实例自身端口探测，记录待探实例数。
 	c.log.Debugf("[FaultDetect] checkResource start (instance port), resource=%s, protocol=%s, instance_count=%d",
-		c.resource.String(), protocol.String(), len(c.instances))
+		c.resource.String(), protocol, len(c.instances))
 	for _, v := range c.instances {
 		curProtocol := v.protocol
-		// 实例 protocol 仅用于过滤：UNKNOWN（未声明）或与规则 protocol 一致的实例才参与本规则探测。
-		if !(curProtocol == fault_tolerance.FaultDetectRule_UNKNOWN || curProtocol == protocol) {
+		// 实例 protocol 仅用于过滤：未声明或与规则 protocol 一致的实例才参与本规则探测。
+		if !(curProtocol == "" || curProtocol == protocol) {
 			c.log.Debugf("[FaultDetect] skip instance for protocol mismatch, resource=%s, instance=%s:%d, instance_protocol=%s, rule_protocol=%s",
-				c.resource.String(), v.insRes.GetNode().Host, v.insRes.GetNode().Port, curProtocol.String(), protocol.String())
+				c.resource.String(), v.insRes.GetNode().Host, v.insRes.GetNode().Port, curProtocol, protocol)
 			continue
 		}
 		ins := pb.NewInstanceInProto(&service_manage.Instance{
 			Host: wrapperspb.String(v.insRes.GetNode().Host),
 			Port: wrapperspb.UInt32(v.insRes.GetNode().Port),
 		}, defaultServiceKey(v.insRes.GetService()), nil)
-		// 同 port>0 分支：探测执行用规则 protocol 选取探测器，不能用实例 protocol（多为 UNKNOWN）。
+		// 同 port>0 分支：探测执行用规则 protocol 选取探测器，不能用实例 protocol（多为空）。
 		isSuccess := c.doCheck(ins, protocol, rule)
 		v.setCheckResult(isSuccess)
 	}
@@ -242,12 +241,12 @@ func (c *ResourceHealthChecker) checkResource(protocol fault_tolerance.FaultDete
 //   - 探测底层异常（err != nil）与 plugin not found：保持 Debug/Warn 即时打印（不在收敛范围内）。
 //
 // 收敛状态以 instanceKey = host:port 为粒度独立追踪。
-func (c *ResourceHealthChecker) doCheck(ins model.Instance, protocol fault_tolerance.FaultDetectRule_Protocol,
+func (c *ResourceHealthChecker) doCheck(ins model.Instance, protocol string,
 	rule *fault_tolerance.FaultDetectRule) bool {
 	checker, ok := c.healthCheckers[protocol]
 	if !ok {
 		c.log.Warnf("plugin not found, skip health check for instance=%s:%d, rule=%s, resource=%s, protocol=%s",
-			ins.GetHost(), ins.GetPort(), rule.GetName(), c.resource.String(), protocol.String())
+			ins.GetHost(), ins.GetPort(), rule.GetName(), c.resource.String(), protocol)
 		return false
 	}
 	ret, err := checker.DetectInstance(ins, rule)
@@ -264,7 +263,7 @@ func (c *ResourceHealthChecker) doCheck(ins model.Instance, protocol fault_toler
 		c.detectMu.Unlock()
 		if !hasErrRecord || lastErr != errMsg {
 			c.log.Warnf("[FaultDetect] doCheck failed, rule=%s, resource=%s, instance=%s, protocol=%s, err=%v",
-				rule.GetName(), c.resource.String(), instanceKey, protocol.String(), err)
+				rule.GetName(), c.resource.String(), instanceKey, protocol, err)
 		}
 		return false
 	}
@@ -286,7 +285,7 @@ func (c *ResourceHealthChecker) doCheck(ins model.Instance, protocol fault_toler
 		// 状态翻转（成功↔失败）属事件级，立即 INFO 打印；带 rule 名便于多探测规则时区分来源。
 		c.log.Infof("[FaultDetect] detect status change: rule=%s, instance=%s, resource=%s, protocol=%s, "+
 			"success=%v, code=%s, delay=%+v",
-			rule.GetName(), instanceKey, c.resource.String(), protocol.String(), isSuccess, ret.GetCode(), ret.GetDelay())
+			rule.GetName(), instanceKey, c.resource.String(), protocol, isSuccess, ret.GetCode(), ret.GetDelay())
 	} else if !isSuccess {
 		c.detectMu.Lock()
 		lastReport, hasReport := c.detectLastReportTime[instanceKey]
@@ -297,7 +296,7 @@ func (c *ResourceHealthChecker) doCheck(ins model.Instance, protocol fault_toler
 			// 淹没真正的状态翻转事件；带 rule 名便于多探测规则时区分来源。
 			c.log.Debugf("[FaultDetect] detect still failing: rule=%s, instance=%s, resource=%s, protocol=%s, "+
 				"code=%s, delay=%+v (reported every %v)",
-				rule.GetName(), instanceKey, c.resource.String(), protocol.String(), ret.GetCode(), ret.GetDelay(),
+				rule.GetName(), instanceKey, c.resource.String(), protocol, ret.GetCode(), ret.GetDelay(),
 				detectStatusReportPeriod)
 		} else {
 			c.detectMu.Unlock()
@@ -373,8 +372,10 @@ func (c *ResourceHealthChecker) selectFaultDetectRules(res model.Resource,
 				continue
 			}
 		}
-		if _, ok := matchRule[rule.GetProtocol().String()]; !ok {
-			matchRule[rule.GetProtocol().String()] = rule
+		// 同一协议只取优先级最高的规则，协议名取 metadata 中的 protocol，未配置时取 protocol 枚举名
+		protocol := healthcheck.RuleProtocol(rule)
+		if _, ok := matchRule[protocol]; !ok {
+			matchRule[protocol] = rule
 		}
 	}
 	return matchRule
@@ -431,7 +432,7 @@ func matchProtocolOrMethod(source, target string) bool {
 }
 
 type ProtocolInstance struct {
-	protocol        fault_tolerance.FaultDetectRule_Protocol
+	protocol        string
 	insRes          *model.InstanceResource
 	lastReportMilli int64
 	checkSuccess    int32
@@ -457,18 +458,15 @@ func (p *ProtocolInstance) doReport() {
 	atomic.StoreInt64(&p.lastReportMilli, clock.CurrentMillis())
 }
 
-func parseProtocol(s string) fault_tolerance.FaultDetectRule_Protocol {
-	s = strings.ToLower(s)
-	if s == "http" || strings.HasPrefix(s, "http/") || strings.HasSuffix(s, "/http") {
-		return fault_tolerance.FaultDetectRule_HTTP
-	}
-	if s == "udp" || strings.HasPrefix(s, "udp/") || strings.HasSuffix(s, "/udp") {
-		return fault_tolerance.FaultDetectRule_UDP
-	}
-	if s == "tcp" || strings.HasPrefix(s, "tcp/") || strings.HasSuffix(s, "/tcp") {
-		return fault_tolerance.FaultDetectRule_TCP
+// parseProtocol 实例 protocol 字符串转换为探测协议名，与 healthcheck.RuleProtocol 的取值一致，无法识别时为空
+func parseProtocol(s string) string {
+	s = strings.ToUpper(s)
+	for _, protocol := range []string{"HTTP", "UDP", "TCP", "GRPC"} {
+		if s == protocol || strings.HasPrefix(s, protocol+"/") || strings.HasSuffix(s, "/"+protocol) {
+			return protocol
+		}
 	}
-	return fault_tolerance.FaultDetectRule_UNKNOWN
+	return ""
 }
 
 func defaultServiceKey(v *model.ServiceKey) *model.ServiceKey {
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	regexp "github.com/dlclark/regexp2"
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -170,3 +171,13 @@ func TestMatchMethod_Compat(t *testing.T) {
 	badVal := newMatchString(apimodel.MatchString_EXACT, "/other")
 	assert.False(t, matchMethod(res, badVal, nopRegex))
 }
+
+// TestParseProtocol 测试场景：实例 protocol 字符串到探测协议名的映射
+// 预期结果：与 healthcheck.RuleProtocol 取值一致，grpc 不会被误判为 HTTP；未知协议为空
+func TestParseProtocol(t *testing.T) {
+	assert.Equal(t, fault_tolerance.FaultDetectRule_HTTP.String(), parseProtocol("http"))
+	assert.Equal(t, fault_tolerance.FaultDetectRule_TCP.String(), parseProtocol("tcp"))
+	assert.Equal(t, "GRPC", parseProtocol("grpc"))
+	assert.Equal(t, "GRPC", parseProtocol("tri/grpc"))
+	assert.Equal(t, "", parseProtocol("dubbo"))
+}
@@ -14,18 +14,130 @@
  * CONDITIONS OF ANY KIND, either express or implied. See the License for the
  * specific language governing permissions and limitations under the License.
  */
-
 package composite
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/plugin/circuitbreaker/composite/trigger"
+)
+
+const (
+	// defaultPersistInterval 熔断状态持久化刷盘的默认间隔
+	defaultPersistInterval = time.Second
+	// defaultSlowCallInterval 慢调用比例统计窗口的默认值
+	defaultSlowCallInterval = 60 * time.Second
+)
+
 type circuitbreakConfig struct {
+	// PersistEnable 是否将熔断状态持久化到 consumer.localCache.persistDir，进程重启后在熔断窗口内恢复
+	PersistEnable *bool `yaml:"persistEnable" json:"persistEnable"`
+	// PersistInterval 熔断状态刷盘间隔，状态变更会在该间隔内写入文件
+	PersistInterval *time.Duration `yaml:"persistInterval" json:"persistInterval"`
+	// AdminEnable 是否在 admin 服务上暴露 /circuitbreaker 查询与强制干预接口
+	AdminEnable *bool `yaml:"adminEnable" json:"adminEnable"`
+	// SlowCallRates 慢调用比例熔断条件，熔断规则的触发条件没有慢调用比例类型，按规则名在插件配置中指定
+	SlowCallRates []*slowCallRateConfig `yaml:"slowCallRates" json:"slowCallRates"`
+}
+
+// slowCallRateConfig 单条熔断规则的慢调用比例触发条件
+type slowCallRateConfig struct {
+	// Rule 熔断规则名，对规则下所有 BlockConfig 生效；写作 规则名#BlockConfig名 时只对该块生效
+	Rule string `yaml:"rule" json:"rule"`
+	// SlowCallThreshold 慢调用阈值，时延超过该值的调用计为慢调用
+	SlowCallThreshold *time.Duration `yaml:"slowCallThreshold" json:"slowCallThreshold"`
+	// SlowCallPercent 慢调用比例阈值（百分比），取值 (0, 100]
+	SlowCallPercent int `yaml:"slowCallPercent" json:"slowCallPercent"`
+	// Interval 统计窗口
+	Interval *time.Duration `yaml:"interval" json:"interval"`
+	// MinimumRequest 窗口内最小请求数，请求数不足时不触发
+	MinimumRequest int32 `yaml:"minimumRequest" json:"minimumRequest"`
 }
 
 // Verify 校验配置是否OK
 func (c *circuitbreakConfig) Verify() error {
+	if c.PersistInterval != nil && *c.PersistInterval <= 0 {
+		return fmt.Errorf("invalid persistInterval: %v, it must greater than 0", *c.PersistInterval)
+	}
+	for i, slowCall := range c.SlowCallRates {
+		if err := slowCall.verify(); err != nil {
+			return fmt.Errorf("invalid slowCallRates[%d]: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// verify 校验慢调用比例触发条件
+func (s *slowCallRateConfig) verify() error {
+	if s == nil {
+		return fmt.Errorf("slow call rate config is nil")
+	}
+	if len(s.Rule) == 0 {
+		return fmt.Errorf("rule is empty")
+	}
+	if s.SlowCallThreshold == nil || *s.SlowCallThreshold <= 0 {
+		return fmt.Errorf("slowCallThreshold of rule %s must greater than 0", s.Rule)
+	}
+	if s.SlowCallPercent <= 0 || s.SlowCallPercent > 100 {
+		return fmt.Errorf("slowCallPercent of rule %s must be in (0, 100], got %d", s.Rule, s.SlowCallPercent)
+	}
+	if s.Interval != nil && *s.Interval < time.Second {
+		return fmt.Errorf("interval of rule %s must not less than 1s, got %v", s.Rule, *s.Interval)
+	}
+	if s.MinimumRequest < 0 {
+		return fmt.Errorf("minimumRequest of rule %s must not less than 0", s.Rule)
+	}
 	return nil
 }
 
 // SetDefault 对关键值设置默认值
 func (c *circuitbreakConfig) SetDefault() {
+	if c.PersistEnable == nil {
+		enable := false
+		c.PersistEnable = &enable
+	}
+	if c.AdminEnable == nil {
+		enable := false
+		c.AdminEnable = &enable
+	}
+	if c.PersistInterval == nil {
+		c.PersistInterval = model.ToDurationPtr(defaultPersistInterval)
+	}
+	for _, slowCall := range c.SlowCallRates {
+		if slowCall != nil && slowCall.Interval == nil {
+			slowCall.Interval = model.ToDurationPtr(defaultSlowCallInterval)
+		}
+	}
+}
+
+// slowCallRatesOf 获取对计数器 name 生效的慢调用比例触发条件，name 为 规则名 或 规则名#BlockConfig名
+func (c *circuitbreakConfig) slowCallRatesOf(ruleName, name string) []*trigger.SlowCallRateOptions {
+	if c == nil {
+		return nil
+	}
+	var ret []*trigger.SlowCallRateOptions
+	for _, slowCall := range c.SlowCallRates {
+		if slowCall == nil || (slowCall.Rule != ruleName && slowCall.Rule != name) {
+			continue
+		}
+		ret = append(ret, &trigger.SlowCallRateOptions{
+			Threshold:      *slowCall.SlowCallThreshold,
+			Percent:        slowCall.SlowCallPercent,
+			Interval:       *slowCall.Interval,
+			MinimumRequest: slowCall.MinimumRequest,
+		})
+	}
+	return ret
+}
+
+// IsPersistEnable 是否开启熔断状态持久化
+func (c *circuitbreakConfig) IsPersistEnable() bool {
+	return c.PersistEnable != nil && *c.PersistEnable
+}
 
+// IsAdminEnable 是否开启熔断 admin 接口
+func (c *circuitbreakConfig) IsAdminEnable() bool {
+	return c.AdminEnable != nil && *c.AdminEnable
 }
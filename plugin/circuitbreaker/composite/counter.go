@@ -73,6 +73,18 @@ type ResourceCounters struct {
 	isInsRes bool
 	// executor 任务执行器，负责状态切换的延迟与亲和性调度
 	executor *TaskExecutor
+	// override 运维通过 admin 接口强制设置的状态（Open/Close），为 0 表示未干预，受 lock 保护
+	override model.Status
+	// lastTransition 最近一次状态切换记录，承载 *transitionRecord
+	lastTransition atomic.Value
+}
+
+// transitionRecord 状态切换记录
+type transitionRecord struct {
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
 }
 
 func newResourceCounters(res model.Resource, activeRule *fault_tolerance.CircuitBreakerRule,
@@ -101,6 +113,12 @@ func newResourceCounters(res model.Resource, activeRule *fault_tolerance.Circuit
 	if err := counters.init(); err != nil {
 		return nil, err
 	}
+	if persister := circuitBreaker.persister; persister != nil {
+		now := time.Now()
+		if status, ok := persister.take(res.String(), now); ok && counters.restore(status, now) {
+			persister.markDirty(counters)
+		}
+	}
 	return counters, nil
 }
 
@@ -153,7 +171,7 @@ func (rc *ResourceCounters) CloseToOpen(breaker string, reason string) {
 	defer rc.lock.Unlock()
 
 	status := rc.CurrentCircuitBreakerStatus()
-	if status.GetStatus() == model.Close {
+	if status.GetStatus() == model.Close && rc.override != model.Close {
 		rc.toOpen(status, breaker, reason)
 	}
 }
@@ -167,6 +185,7 @@ func (rc *ResourceCounters) toOpen(before model.CircuitBreakerStatus, name strin
 		})
 	rc.updateCircuitBreakerStatus(newStatus)
 	rc.reportCircuitStatus(newStatus)
+	rc.markPersist()
 	rc.logStat.Infof("[CircuitBreaker] status change: %s -> %s, resource(%s), rule(%s, id=%s, rev=%s)",
 		before.GetStatus(), newStatus.GetStatus(), rc.resource.String(),
 		before.GetCircuitBreaker(), rc.activeRule.Id, rc.activeRule.Revision)
@@ -185,7 +204,8 @@ func (rc *ResourceCounters) OpenToHalfOpen() {
 	defer rc.lock.Unlock()
 
 	status := rc.CurrentCircuitBreakerStatus()
-	if status.GetStatus() != model.Open {
+	// 强制熔断期间不自动进入半开，需运维 close/reset 解除
+	if status.GetStatus() != model.Open || rc.override == model.Open {
 		return
 	}
 	consecutiveSuccess := rc.activeRule.GetRecoverCondition().ConsecutiveSuccess
@@ -195,6 +215,7 @@ func (rc *ResourceCounters) OpenToHalfOpen() {
 		status.GetCircuitBreaker(), rc.activeRule.Id, rc.activeRule.Revision)
 	rc.updateCircuitBreakerStatus(halfOpenStatus)
 	rc.reportCircuitStatus(halfOpenStatus)
+	rc.markPersist()
 
 	rc.reportCircuitBreakMetric(halfOpenStatus)
 	rc.reportCircuitBreakerEvent(model.Open, model.HalfOpen, "")
@@ -211,6 +232,7 @@ func (rc *ResourceCounters) HalfOpenToClose() {
 	newStatus := model.NewCircuitBreakerStatus(status.GetCircuitBreaker(), model.Close, time.Now())
 	rc.updateCircuitBreakerStatus(newStatus)
 	rc.reportCircuitStatus(newStatus)
+	rc.markPersist()
 	rc.logStat.Infof("[CircuitBreaker] status change: %s -> %s, resource(%s), rule(%s, id=%s, rev=%s)",
 		status.GetStatus(), newStatus.GetStatus(), rc.resource.String(),
 		status.GetCircuitBreaker(), rc.activeRule.Id, rc.activeRule.Revision)
@@ -250,7 +272,9 @@ func (rc *ResourceCounters) handleHalfOpenReport(stat *model.ResourceStat,
 	if !ok {
 		return
 	}
-	if !halfOpenStatus.Report(isSuccess) {
+	triggered := halfOpenStatus.Report(isSuccess)
+	rc.markPersist()
+	if !triggered {
 		return
 	}
 	switch halfOpenStatus.CalNextStatus() {
@@ -314,6 +338,14 @@ func (rc *ResourceCounters) resumeAllBlocks() {
 	}
 }
 
+// markPersist 标记熔断状态需要持久化，未开启持久化时无操作
+func (rc *ResourceCounters) markPersist() {
+	if rc.circuitBreaker == nil || rc.circuitBreaker.persister == nil {
+		return
+	}
+	rc.circuitBreaker.persister.markDirty(rc)
+}
+
 func (rc *ResourceCounters) reportCircuitStatus(newStatus model.CircuitBreakerStatus) {
 	if !rc.isInsRes {
 		return
@@ -395,9 +427,15 @@ func levelToString(l fault_tolerance.Level) string {
 	}
 }
 
-// reportCircuitBreakerEvent 构造熔断事件并投递到 EventReporter 链。
+// reportCircuitBreakerEvent 记录最近一次状态切换，并构造熔断事件投递到 EventReporter 链。
 // from/to 为状态转换前后状态；reason 无则传空串。出错只记日志，不阻断状态机。
 func (rc *ResourceCounters) reportCircuitBreakerEvent(from, to model.Status, reason string) {
+	rc.lastTransition.Store(&transitionRecord{
+		From:   from.String(),
+		To:     to.String(),
+		Reason: reason,
+		Time:   time.Now(),
+	})
 	eventInfo := event.BuildCircuitBreakerEvent(rc.resource, rc.activeRule, from, to, reason)
 	rc.sendEvent(eventInfo)
 }
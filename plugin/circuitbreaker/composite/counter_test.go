@@ -28,6 +28,7 @@ import (
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/plugin/circuitbreaker/composite/trigger"
 )
 
 // buildErrorCondition 构造一条返回码错误条件，简化测试代码
@@ -283,3 +284,116 @@ func TestResourceCounters_EvaluateSuccess_NoBlockPassThrough(t *testing.T) {
 	assert.False(t, rc.evaluateSuccess(&model.ResourceStat{RetStatus: model.RetFail}))
 	assert.False(t, rc.evaluateSuccess(&model.ResourceStat{RetStatus: model.RetTimeout}))
 }
+
+// fakeDelayCounter 记录收到的时延与成功/失败上报
+type fakeDelayCounter struct {
+	delays   []time.Duration
+	reported int
+}
+
+func (f *fakeDelayCounter) Report(success bool)             { f.reported++ }
+func (f *fakeDelayCounter) Resume()                         {}
+func (f *fakeDelayCounter) ReportDelay(delay time.Duration) { f.delays = append(f.delays, delay) }
+func (f *fakeDelayCounter) Snapshot() trigger.CounterSnapshot {
+	return trigger.CounterSnapshot{Type: "FAKE", ErrorCount: int64(f.reported)}
+}
+
+// TestBlockCounter_Report_DelayTrigger 测试场景：时延类 trigger 接收 stat.Delay 而非成功/失败结果
+// 前置条件：blockCounter.counters 中包含实现 DelayTriggerCounter 的计数器
+// 预期结果：ReportDelay 收到原始时延，Report 未被调用
+func TestBlockCounter_Report_DelayTrigger(t *testing.T) {
+	rc := newRCForTest(&fault_tolerance.CircuitBreakerRule{})
+	counter := &fakeDelayCounter{}
+	b := &blockCounter{
+		name:     "rule#slow",
+		counters: []trigger.TriggerCounter{counter},
+		rc:       rc,
+	}
+	b.Report(&model.ResourceStat{Delay: 120 * time.Millisecond, RetStatus: model.RetFail})
+	assert.Equal(t, []time.Duration{120 * time.Millisecond}, counter.delays)
+	assert.Equal(t, 0, counter.reported)
+}
+
+// TestNewBlockCounter_SlowCallRateFromConfig 测试场景：慢调用比例触发条件来自插件配置
+// 前置条件：插件配置分别按 规则名#块名 与 规则名 指定慢调用比例，规则中没有 DELAY 错误条件
+// 预期结果：只为匹配的块追加慢调用比例触发器，规则名对所有块生效；未配置的规则不创建慢调用触发器
+func TestNewBlockCounter_SlowCallRateFromConfig(t *testing.T) {
+	res, err := model.NewServiceResource(&model.ServiceKey{Namespace: "default", Service: "order"}, nil)
+	assert.NoError(t, err)
+	cfg := &circuitbreakConfig{SlowCallRates: []*slowCallRateConfig{
+		{Rule: "rule-A#slow", SlowCallThreshold: model.ToDurationPtr(100 * time.Millisecond), SlowCallPercent: 50},
+		{Rule: "rule-B", SlowCallThreshold: model.ToDurationPtr(200 * time.Millisecond), SlowCallPercent: 80},
+	}}
+	cfg.SetDefault()
+	assert.NoError(t, cfg.Verify())
+	cb := newCompositeForTest()
+	cb.cfg = cfg
+
+	consecutive := []*fault_tolerance.TriggerCondition{{
+		TriggerType: fault_tolerance.TriggerCondition_CONSECUTIVE_ERROR,
+		ErrorCount:  3,
+	}}
+	countTypes := func(ruleName string) map[string][]string {
+		rc := &ResourceCounters{
+			activeRule: &fault_tolerance.CircuitBreakerRule{
+				Name: ruleName,
+				BlockConfigs: []*fault_tolerance.BlockConfig{
+					{Name: "slow", TriggerConditions: consecutive},
+					{Name: "other", TriggerConditions: consecutive},
+				},
+			},
+			resource:       res,
+			logStat:        noopLogger{},
+			circuitBreaker: cb,
+		}
+		assert.NoError(t, rc.init())
+		ret := map[string][]string{}
+		for _, block := range rc.blocks {
+			for _, counter := range block.counters {
+				ret[block.name] = append(ret[block.name], counter.Snapshot().Type)
+			}
+		}
+		return ret
+	}
+
+	assert.Equal(t, map[string][]string{
+		"rule-A#slow":  {"CONSECUTIVE_ERROR", "SLOW_CALL_RATE"},
+		"rule-A#other": {"CONSECUTIVE_ERROR"},
+	}, countTypes("rule-A"))
+	assert.Equal(t, map[string][]string{
+		"rule-B#slow":  {"CONSECUTIVE_ERROR", "SLOW_CALL_RATE"},
+		"rule-B#other": {"CONSECUTIVE_ERROR", "SLOW_CALL_RATE"},
+	}, countTypes("rule-B"))
+	assert.Equal(t, map[string][]string{
+		"rule-C#slow":  {"CONSECUTIVE_ERROR"},
+		"rule-C#other": {"CONSECUTIVE_ERROR"},
+	}, countTypes("rule-C"))
+}
+
+// TestSlowCallRateConfig_Verify 测试场景：慢调用比例配置校验
+// 预期结果：缺少规则名、慢调用阈值非法、比例越界、窗口过小时校验失败；未配置窗口时使用默认值
+func TestSlowCallRateConfig_Verify(t *testing.T) {
+	valid := func() *slowCallRateConfig {
+		return &slowCallRateConfig{Rule: "rule", SlowCallThreshold: model.ToDurationPtr(time.Second), SlowCallPercent: 50}
+	}
+	cfg := &circuitbreakConfig{SlowCallRates: []*slowCallRateConfig{valid()}}
+	cfg.SetDefault()
+	assert.NoError(t, cfg.Verify())
+	assert.Equal(t, defaultSlowCallInterval, *cfg.SlowCallRates[0].Interval)
+
+	invalids := []func(*slowCallRateConfig){
+		func(c *slowCallRateConfig) { c.Rule = "" },
+		func(c *slowCallRateConfig) { c.SlowCallThreshold = nil },
+		func(c *slowCallRateConfig) { c.SlowCallPercent = 0 },
+		func(c *slowCallRateConfig) { c.SlowCallPercent = 101 },
+		func(c *slowCallRateConfig) { c.Interval = model.ToDurationPtr(time.Millisecond) },
+		func(c *slowCallRateConfig) { c.MinimumRequest = -1 },
+	}
+	for i, invalid := range invalids {
+		slowCall := valid()
+		invalid(slowCall)
+		cfg := &circuitbreakConfig{SlowCallRates: []*slowCallRateConfig{slowCall}}
+		cfg.SetDefault()
+		assert.Error(t, cfg.Verify(), "case %d", i)
+	}
+}
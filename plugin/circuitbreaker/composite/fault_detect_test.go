@@ -26,6 +26,8 @@ import (
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/healthcheck"
+	grpcdetector "github.com/polarismesh/polaris-go/plugin/healthcheck/grpc"
 )
 
 // newFaultDetectRule 构造一条最小可用的探测规则，供排序/选择测试使用
@@ -100,6 +102,36 @@ func TestSelectFaultDetectRules_ServiceLevel(t *testing.T) {
 	assert.Contains(t, matched, fault_tolerance.FaultDetectRule_TCP.String())
 }
 
+// TestSelectFaultDetectRules_MetadataProtocol 测试场景：探测规则通过 metadata 指定 gRPC 探测协议
+// 前置条件：FaultDetector 含一条 TCP 规则和一条 metadata protocol=grpc 的 TCP 规则
+// 预期结果：两条规则分别归入 TCP 与 GRPC，且与 gRPC 探测器的注册协议名一致
+func TestSelectFaultDetectRules_MetadataProtocol(t *testing.T) {
+	svcKey := &model.ServiceKey{Namespace: "default", Service: "order"}
+	res, _ := model.NewServiceResource(svcKey, nil)
+
+	grpcRule := newFaultDetectRule("r-grpc", "default", "order", fault_tolerance.FaultDetectRule_TCP, 5)
+	grpcRule.Metadata = map[string]string{healthcheck.MetadataKeyProtocol: "grpc"}
+	fd := &fault_tolerance.FaultDetector{
+		Rules: []*fault_tolerance.FaultDetectRule{
+			newFaultDetectRule("r-tcp", "default", "order", fault_tolerance.FaultDetectRule_TCP, 10),
+			grpcRule,
+		},
+	}
+
+	checker := &ResourceHealthChecker{
+		resource:      res,
+		faultDetector: fd,
+		regexFunction: nopRegex,
+		log:           noopLogger{},
+	}
+
+	matched := checker.selectFaultDetectRules(res, fd)
+	assert.Len(t, matched, 2)
+	assert.Equal(t, "r-tcp", matched[fault_tolerance.FaultDetectRule_TCP.String()].GetId())
+	grpcProtocol := healthcheck.CheckerProtocol(&grpcdetector.Detector{})
+	assert.Equal(t, "r-grpc", matched[grpcProtocol].GetId())
+}
+
 // TestSelectFaultDetectRules_ServiceMismatch 测试场景：目标服务不匹配时选不出规则
 func TestSelectFaultDetectRules_ServiceMismatch(t *testing.T) {
 	svcKey := &model.ServiceKey{Namespace: "default", Service: "order"}
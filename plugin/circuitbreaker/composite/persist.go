@@ -0,0 +1,266 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package composite
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	lrplug "github.com/polarismesh/polaris-go/plugin/localregistry/common"
+)
+
+const (
+	// statusFilePrefix 熔断状态持久化文件前缀，与服务缓存文件 svc#... 区分
+	statusFilePrefix = "cb#"
+	// statusFileGlob 熔断状态持久化文件匹配模式
+	statusFileGlob = statusFilePrefix + "*" + lrplug.CacheSuffix
+)
+
+// persistedStatus 持久化到文件的资源熔断状态
+type persistedStatus struct {
+	// Resource 资源标识，即 model.Resource.String()
+	Resource string `json:"resource"`
+	// RuleID 状态所属的熔断规则 ID，规则变化后不再恢复
+	RuleID string `json:"ruleId"`
+	// CircuitBreaker 触发熔断的规则/块名称
+	CircuitBreaker string `json:"circuitBreaker"`
+	// Status 熔断状态，取值为 model.Status
+	Status model.Status `json:"status"`
+	// StartTime 进入当前状态的时间，毫秒时间戳
+	StartTime int64 `json:"startTime"`
+	// SleepWindow 熔断规则的恢复窗口，毫秒
+	SleepWindow int64 `json:"sleepWindow"`
+	// MaxRequest 半开态可放行的探测请求数
+	MaxRequest int `json:"maxRequest,omitempty"`
+	// HalfOpenCalled 半开态已归集的成功探测数
+	HalfOpenCalled int `json:"halfOpenCalled,omitempty"`
+	// Fallback 熔断降级信息
+	Fallback *model.FallbackInfo `json:"fallback,omitempty"`
+}
+
+// startTime 进入当前状态的时间
+func (p *persistedStatus) startTime() time.Time {
+	return time.Unix(0, p.StartTime*int64(time.Millisecond))
+}
+
+// expireTime 状态失效时间：Open 态到期后进入半开，半开态超过一个恢复窗口视为过期
+func (p *persistedStatus) expireTime() time.Time {
+	return p.startTime().Add(time.Duration(p.SleepWindow) * time.Millisecond)
+}
+
+// isValid 状态是否仍在恢复窗口内
+func (p *persistedStatus) isValid(now time.Time) bool {
+	if p.Resource == "" || (p.Status != model.Open && p.Status != model.HalfOpen) {
+		return false
+	}
+	return now.Before(p.expireTime())
+}
+
+// toCircuitBreakerStatus 资源计数器尚未建立时对外暴露的熔断状态，仅 Open 态生效
+func (p *persistedStatus) toCircuitBreakerStatus() model.CircuitBreakerStatus {
+	if p.Status != model.Open {
+		return nil
+	}
+	return model.NewCircuitBreakerStatus(p.CircuitBreaker, model.Open, p.startTime(),
+		func(cbs model.CircuitBreakerStatus) {
+			cbs.SetFallbackInfo(p.Fallback)
+		})
+}
+
+// statusFileName 资源对应的持久化文件名，资源标识可能很长且包含特殊字符，因此使用摘要
+func statusFileName(resource string) string {
+	sum := sha1.Sum([]byte(resource))
+	return statusFilePrefix + hex.EncodeToString(sum[:]) + lrplug.CacheSuffix
+}
+
+// statusPersister 熔断状态持久化器
+// 状态变化时只标记脏数据，由定时任务统一刷盘，避免在状态机锁内做磁盘 IO；
+// 启动时加载仍在恢复窗口内的状态，在资源计数器创建时恢复。
+type statusPersister struct {
+	handler *lrplug.CachePersistHandler
+	log     log.Logger
+	lock    sync.Mutex
+	// dirty 待刷盘的资源计数器 resource -> *ResourceCounters
+	dirty map[string]*ResourceCounters
+	// restored 启动时加载、尚未被资源计数器接管的状态 resource -> *persistedStatus
+	restored *sync.Map
+}
+
+func newStatusPersister(handler *lrplug.CachePersistHandler, logger log.Logger) *statusPersister {
+	return &statusPersister{
+		handler:  handler,
+		log:      logger,
+		dirty:    make(map[string]*ResourceCounters),
+		restored: &sync.Map{},
+	}
+}
+
+// load 加载持久化目录中的熔断状态，过期或损坏的文件直接删除
+func (p *statusPersister) load(now time.Time) {
+	for _, fileName := range p.handler.ListFiles(statusFileGlob) {
+		status := &persistedStatus{}
+		if err := p.handler.LoadDataFromFile(fileName, status); err != nil {
+			p.log.Warnf("[CircuitBreaker] fail to load persisted status from %s: %v", fileName, err)
+			p.handler.DeleteCacheFromFile(fileName)
+			continue
+		}
+		if !status.isValid(now) || statusFileName(status.Resource) != fileName {
+			p.handler.DeleteCacheFromFile(fileName)
+			continue
+		}
+		p.log.Infof("[CircuitBreaker] load persisted status %s of resource(%s), rule(%s), expire at %s",
+			status.Status, status.Resource, status.CircuitBreaker, status.expireTime().Format(time.RFC3339))
+		p.restored.Store(status.Resource, status)
+	}
+}
+
+// lookup 查询资源尚未被接管的持久化状态，过期时清理
+func (p *statusPersister) lookup(resource string, now time.Time) (*persistedStatus, bool) {
+	val, ok := p.restored.Load(resource)
+	if !ok {
+		return nil, false
+	}
+	status := val.(*persistedStatus)
+	if !status.isValid(now) {
+		p.restored.Delete(resource)
+		return nil, false
+	}
+	return status, true
+}
+
+// take 取出资源的持久化状态交由资源计数器接管
+func (p *statusPersister) take(resource string, now time.Time) (*persistedStatus, bool) {
+	val, ok := p.restored.LoadAndDelete(resource)
+	if !ok {
+		return nil, false
+	}
+	status := val.(*persistedStatus)
+	if !status.isValid(now) {
+		return nil, false
+	}
+	return status, true
+}
+
+// markDirty 标记资源状态变化，等待下次刷盘
+func (p *statusPersister) markDirty(rc *ResourceCounters) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.dirty[rc.resource.String()] = rc
+}
+
+// remove 资源计数器被删除，同时删除其持久化状态
+func (p *statusPersister) remove(res model.Resource) {
+	p.lock.Lock()
+	delete(p.dirty, res.String())
+	p.lock.Unlock()
+	p.restored.Delete(res.String())
+	p.handler.DeleteCacheFromFile(statusFileName(res.String()))
+}
+
+// flush 将脏数据写入文件，Close 态无需恢复，直接删除文件
+func (p *statusPersister) flush() {
+	p.lock.Lock()
+	dirty := p.dirty
+	p.dirty = make(map[string]*ResourceCounters, len(dirty))
+	p.lock.Unlock()
+
+	for resource, rc := range dirty {
+		fileName := statusFileName(resource)
+		status := rc.snapshot()
+		if status == nil {
+			p.handler.DeleteCacheFromFile(fileName)
+			continue
+		}
+		data, err := json.Marshal(status)
+		if err != nil {
+			p.log.Errorf("[CircuitBreaker] fail to marshal status of resource(%s): %v", resource, err)
+			continue
+		}
+		p.handler.SaveDataToFile(fileName, data)
+	}
+}
+
+// snapshot 生成当前熔断状态的持久化快照，Close 态返回 nil
+func (rc *ResourceCounters) snapshot() *persistedStatus {
+	rc.lock.RLock()
+	defer rc.lock.RUnlock()
+	status := rc.CurrentCircuitBreakerStatus()
+	if status == nil || status.GetStatus() == model.Close {
+		return nil
+	}
+	ret := &persistedStatus{
+		Resource:       rc.resource.String(),
+		RuleID:         rc.activeRule.GetId(),
+		CircuitBreaker: status.GetCircuitBreaker(),
+		Status:         status.GetStatus(),
+		StartTime:      status.GetStartTime().UnixNano() / int64(time.Millisecond),
+		SleepWindow: (time.Duration(rc.activeRule.GetRecoverCondition().GetSleepWindow()) *
+			time.Second).Milliseconds(),
+		Fallback: status.GetFallbackInfo(),
+	}
+	if halfOpen, ok := status.(*model.HalfOpenStatus); ok {
+		ret.MaxRequest = halfOpen.MaxRequest()
+		ret.HalfOpenCalled = halfOpen.CalledCount()
+	}
+	return ret
+}
+
+// restore 用持久化状态初始化刚创建的资源计数器，规则已变化时不恢复
+// Open 态按剩余窗口调度进入半开；半开态回放已成功的探测数，避免重启后重新放量
+func (rc *ResourceCounters) restore(status *persistedStatus, now time.Time) bool {
+	if status.RuleID != rc.activeRule.GetId() {
+		return false
+	}
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	var restored model.CircuitBreakerStatus
+	switch status.Status {
+	case model.Open:
+		restored = model.NewCircuitBreakerStatus(status.CircuitBreaker, model.Open,
+			status.startTime(), func(cbs model.CircuitBreakerStatus) {
+				cbs.SetFallbackInfo(rc.fallbackInfo)
+			})
+		rc.executor.AffinityDelayExecute(rc.activeRule.Id, status.expireTime().Sub(now), rc.OpenToHalfOpen)
+	case model.HalfOpen:
+		halfOpen := model.NewHalfOpenStatus(status.CircuitBreaker, status.startTime(),
+			status.MaxRequest).(*model.HalfOpenStatus)
+		// 达到配额前进程已退出，回放的结果数需小于 maxRequest，交由新请求触发状态判定
+		for i := 0; i < status.HalfOpenCalled && i < status.MaxRequest-1; i++ {
+			halfOpen.AcquirePermission()
+			halfOpen.Release(true)
+		}
+		restored = halfOpen
+	default:
+		return false
+	}
+	rc.updateCircuitBreakerStatus(restored)
+	rc.reportCircuitStatus(restored)
+	rc.lastTransition.Store(&transitionRecord{
+		To:     status.Status.String(),
+		Reason: "restored from persisted status",
+		Time:   now,
+	})
+	rc.logStat.Infof("[CircuitBreaker] status restored: %s, resource(%s), rule(%s, id=%s, rev=%s)",
+		status.Status, rc.resource.String(), status.CircuitBreaker, rc.activeRule.Id, rc.activeRule.Revision)
+	return true
+}
@@ -0,0 +1,188 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package composite
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	lrplug "github.com/polarismesh/polaris-go/plugin/localregistry/common"
+)
+
+// newPersistLogCtxForTest 构造持久化工具使用的 ContextLogger，未配置日志时使用 noopLogger
+func newPersistLogCtxForTest() *log.ContextLogger {
+	if log.GetBaseLogger() == nil {
+		log.SetBaseLogger(noopLogger{})
+	}
+	return newTestLogCtx()
+}
+
+// newPersisterForTest 在临时目录上构造熔断状态持久化器
+func newPersisterForTest(t *testing.T) (*statusPersister, string) {
+	dir, err := ioutil.TempDir("", "cb-persist")
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	handler, err := lrplug.NewCachePersistHandler(true, dir, 1, 1, 10*time.Millisecond, newPersistLogCtxForTest())
+	assert.NoError(t, err)
+	return newStatusPersister(handler, noopLogger{}), dir
+}
+
+// newPersistRCForTest 构造持久化测试使用的服务级 ResourceCounters
+func newPersistRCForTest(t *testing.T, sleepWindow uint32) *ResourceCounters {
+	res, err := model.NewServiceResource(&model.ServiceKey{Namespace: "default", Service: "order"}, nil)
+	assert.NoError(t, err)
+	rule := &fault_tolerance.CircuitBreakerRule{
+		Id:   "rule-id",
+		Name: "rule-A",
+		RecoverCondition: &fault_tolerance.RecoverCondition{
+			SleepWindow:        sleepWindow,
+			ConsecutiveSuccess: 3,
+		},
+	}
+	return &ResourceCounters{
+		activeRule: rule,
+		resource:   res,
+		logStat:    noopLogger{},
+	}
+}
+
+// countStatusFiles 统计目录中的熔断状态文件数
+func countStatusFiles(p *statusPersister) int {
+	return len(p.handler.ListFiles(statusFileGlob))
+}
+
+// TestStatusPersister_FlushAndLoad 测试场景：Open 态刷盘后由新进程加载
+// 前置条件：资源 10s 前进入 Open，规则恢复窗口 30s
+// 预期结果：生成一个状态文件；重新加载后 CheckResource 兜底返回 Open 且保留原进入时间；
+// 状态切回 Close 再次刷盘后文件被删除
+func TestStatusPersister_FlushAndLoad(t *testing.T) {
+	p, dir := newPersisterForTest(t)
+	rc := newPersistRCForTest(t, 30)
+	start := time.Now().Add(-10 * time.Second)
+	rc.updateCircuitBreakerStatus(model.NewCircuitBreakerStatus("rule-A", model.Open, start))
+
+	p.markDirty(rc)
+	p.flush()
+	assert.Equal(t, 1, countStatusFiles(p))
+
+	handler, err := lrplug.NewCachePersistHandler(true, dir, 1, 1, 10*time.Millisecond, newPersistLogCtxForTest())
+	assert.NoError(t, err)
+	reloaded := newStatusPersister(handler, noopLogger{})
+	reloaded.load(time.Now())
+	status, ok := reloaded.lookup(rc.resource.String(), time.Now())
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "rule-id", status.RuleID)
+	cbStatus := status.toCircuitBreakerStatus()
+	assert.Equal(t, model.Open, cbStatus.GetStatus())
+	assert.Equal(t, "rule-A", cbStatus.GetCircuitBreaker())
+	assert.Equal(t, start.UnixNano()/int64(time.Millisecond),
+		cbStatus.GetStartTime().UnixNano()/int64(time.Millisecond))
+
+	rc.updateCircuitBreakerStatus(model.NewCircuitBreakerStatus("rule-A", model.Close, time.Now()))
+	p.markDirty(rc)
+	p.flush()
+	assert.Equal(t, 0, countStatusFiles(p))
+}
+
+// TestStatusPersister_LoadDropsExpired 测试场景：加载已超出恢复窗口的状态
+// 前置条件：资源 1 分钟前进入 Open，规则恢复窗口 30s
+// 预期结果：加载时删除状态文件，查询不到持久化状态
+func TestStatusPersister_LoadDropsExpired(t *testing.T) {
+	p, _ := newPersisterForTest(t)
+	rc := newPersistRCForTest(t, 30)
+	rc.updateCircuitBreakerStatus(model.NewCircuitBreakerStatus("rule-A", model.Open,
+		time.Now().Add(-time.Minute)))
+	p.markDirty(rc)
+	p.flush()
+	assert.Equal(t, 1, countStatusFiles(p))
+
+	p.load(time.Now())
+	_, ok := p.lookup(rc.resource.String(), time.Now())
+	assert.False(t, ok)
+	assert.Equal(t, 0, countStatusFiles(p))
+}
+
+// TestResourceCounters_RestoreOpen 测试场景：计数器创建时恢复 Open 态
+// 前置条件：持久化状态为 Open，剩余恢复窗口约 100ms；另一份状态属于已变化的规则
+// 预期结果：同规则时恢复为 Open，剩余窗口到期后进入 HalfOpen；规则 ID 不一致时不恢复
+func TestResourceCounters_RestoreOpen(t *testing.T) {
+	executor := newTaskExecutor(2, newTestLogCtx())
+	defer executor.Stop()
+	rc := newPersistRCForTest(t, 1)
+	rc.executor = executor
+	rc.updateCircuitBreakerStatus(model.NewCircuitBreakerStatus("rule-A", model.Close, time.Now()))
+
+	now := time.Now()
+	status := &persistedStatus{
+		Resource:       rc.resource.String(),
+		RuleID:         "changed-rule-id",
+		CircuitBreaker: "rule-A",
+		Status:         model.Open,
+		StartTime:      now.Add(-900*time.Millisecond).UnixNano() / int64(time.Millisecond),
+		SleepWindow:    time.Second.Milliseconds(),
+	}
+	assert.False(t, rc.restore(status, now))
+	assert.Equal(t, model.Close, rc.CurrentCircuitBreakerStatus().GetStatus())
+
+	status.RuleID = "rule-id"
+	assert.True(t, rc.restore(status, now))
+	assert.Equal(t, model.Open, rc.CurrentCircuitBreakerStatus().GetStatus())
+	assert.Eventually(t, func() bool {
+		return rc.CurrentCircuitBreakerStatus().GetStatus() == model.HalfOpen
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+// TestResourceCounters_RestoreHalfOpen 测试场景：计数器创建时恢复半开态进度
+// 前置条件：半开配额 3，重启前已成功归集 2 次
+// 预期结果：恢复为 HalfOpen 且已归集 2 次，仅剩 1 个探测配额
+func TestResourceCounters_RestoreHalfOpen(t *testing.T) {
+	rc := newPersistRCForTest(t, 30)
+	now := time.Now()
+	status := &persistedStatus{
+		Resource:       rc.resource.String(),
+		RuleID:         "rule-id",
+		CircuitBreaker: "rule-A",
+		Status:         model.HalfOpen,
+		StartTime:      now.UnixNano() / int64(time.Millisecond),
+		SleepWindow:    (30 * time.Second).Milliseconds(),
+		MaxRequest:     3,
+		HalfOpenCalled: 2,
+	}
+	assert.True(t, rc.restore(status, now))
+	halfOpen, ok := rc.CurrentCircuitBreakerStatus().(*model.HalfOpenStatus)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, 2, halfOpen.CalledCount())
+	assert.True(t, halfOpen.AcquirePermission())
+	assert.False(t, halfOpen.AcquirePermission())
+
+	snapshot := rc.snapshot()
+	assert.Equal(t, model.HalfOpen, snapshot.Status)
+	assert.Equal(t, 3, snapshot.MaxRequest)
+	assert.Equal(t, 2, snapshot.HalfOpenCalled)
+}
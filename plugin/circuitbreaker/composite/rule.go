@@ -101,6 +101,9 @@ func (c *RuleContainer) realRefreshCircuitBreaker() {
 		if old, exist := resourceCounters.remove(c.res); exist {
 			// 规则被删除，旧 counters 即将被丢弃，上报熔断销毁事件
 			old.reportDestroyEvent()
+			if c.breaker.persister != nil {
+				c.breaker.persister.remove(c.res)
+			}
 			c.log.Infof("[CircuitBreaker] removed counters for resource: %s, scheduling health check", c.res.String())
 			c.scheduleHealthCheck()
 		}
@@ -127,6 +130,8 @@ func (c *RuleContainer) realRefreshCircuitBreaker() {
 	// 规则发生变更，旧 counters 即将被新 counters 覆盖，上报熔断销毁事件
 	if exist {
 		oldCounters.reportDestroyEvent()
+		// 旧规则的熔断状态不再有效，刷盘时以新 counters 的状态覆盖
+		counters.markPersist()
 	}
 	resourceCounters.put(c.res, counters)
 	c.log.Infof("[CircuitBreaker] created new counters, applied rule: %s (id: %s, revision: %s) for resource: %s",
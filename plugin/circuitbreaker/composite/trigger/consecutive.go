@@ -20,6 +20,8 @@ package trigger
 import (
 	"fmt"
 	"sync/atomic"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
 )
 
 type ConsecutiveCounter struct {
@@ -71,3 +73,11 @@ func (c *ConsecutiveCounter) Resume() {
 			c.ruleName, c.ruleID, c.ruleRevision, c.res.String())
 	}
 }
+
+// Snapshot 当前连续错误数快照
+func (c *ConsecutiveCounter) Snapshot() CounterSnapshot {
+	ret := c.snapshot(fault_tolerance.TriggerCondition_CONSECUTIVE_ERROR.String())
+	ret.ErrorCount = int64(atomic.LoadInt32(&c.consecutiveErrors))
+	ret.Threshold = c.maxCount
+	return ret
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
 
 	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/metric"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -51,6 +52,20 @@ type Options struct {
 	RuleID string
 	// RuleRevision 关联的熔断规则 revision，用于 INFO 日志追踪规则版本
 	RuleRevision string
+	// SlowCallRate 慢调用比例触发条件，仅慢调用比例触发器使用，此时 Condition 为空
+	SlowCallRate *SlowCallRateOptions
+}
+
+// SlowCallRateOptions 慢调用比例触发条件，来自熔断插件配置
+type SlowCallRateOptions struct {
+	// Threshold 慢调用阈值，时延超过该值的调用计为慢调用
+	Threshold time.Duration
+	// Percent 慢调用比例阈值（百分比）
+	Percent int
+	// Interval 统计窗口
+	Interval time.Duration
+	// MinimumRequest 窗口内最小请求数
+	MinimumRequest int32
 }
 
 // TriggerCounter .
@@ -59,6 +74,34 @@ type TriggerCounter interface {
 	Report(success bool)
 	// Resume .
 	Resume()
+	// Snapshot 当前计数快照，用于 admin 接口展示
+	Snapshot() CounterSnapshot
+}
+
+// CounterSnapshot 触发计数器的统计快照
+type CounterSnapshot struct {
+	// Name 计数器所属规则/块名
+	Name string `json:"name"`
+	// Type 触发类型：CONSECUTIVE_ERROR / ERROR_RATE / SLOW_CALL_RATE
+	Type string `json:"type"`
+	// Suspended 是否已触发熔断并暂停计数，资源恢复 Close 后复位
+	Suspended bool `json:"suspended"`
+	// ErrorCount 连续错误数，或统计窗口内的错误数/慢调用数
+	ErrorCount int64 `json:"errorCount"`
+	// RequestCount 统计窗口内的请求数，连续错误触发器不统计
+	RequestCount int64 `json:"requestCount,omitempty"`
+	// Threshold 触发阈值：连续错误数，或错误率/慢调用比例百分比
+	Threshold int64 `json:"threshold"`
+	// WindowSeconds 统计窗口（秒），连续错误触发器不统计
+	WindowSeconds int64 `json:"windowSeconds,omitempty"`
+}
+
+// DelayTriggerCounter 依据调用时延而非成功/失败计数的触发器，如慢调用比例触发器。
+// blockCounter 会对实现了该接口的触发器改为调用 ReportDelay。
+type DelayTriggerCounter interface {
+	TriggerCounter
+	// ReportDelay 上报一次调用的时延
+	ReportDelay(delay time.Duration)
 }
 
 func newBaseCounter(rule string, opt *Options) *baseCounter {
@@ -89,6 +132,29 @@ type baseCounter struct {
 	ruleRevision string
 }
 
+// snapshot 填充快照中的公共字段
+func (bc *baseCounter) snapshot(triggerType string) CounterSnapshot {
+	return CounterSnapshot{
+		Name:      bc.ruleName,
+		Type:      triggerType,
+		Suspended: bc.isSuspend(),
+	}
+}
+
+// windowCounts 统计窗口内指定维度的计数
+func windowCounts(window *metric.SliceWindow, interval time.Duration, dimensions ...int) []int64 {
+	now := time.Now()
+	timeRange := &metric.TimeRange{
+		Start: now.Add(-1 * interval),
+		End:   now,
+	}
+	ret := make([]int64, 0, len(dimensions))
+	for _, dimension := range dimensions {
+		ret = append(ret, window.CalcMetrics(dimension, timeRange))
+	}
+	return ret
+}
+
 func (bc *baseCounter) isSuspend() bool {
 	return atomic.LoadInt32(&bc.suspended) == 1
 }
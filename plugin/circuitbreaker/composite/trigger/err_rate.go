@@ -23,6 +23,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
 	"github.com/polarismesh/polaris-go/pkg/clock"
 	"github.com/polarismesh/polaris-go/pkg/metric"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -145,6 +147,16 @@ func (c *ErrRateCounter) Resume() {
 	}
 }
 
+// Snapshot 统计窗口内请求数与错误数快照
+func (c *ErrRateCounter) Snapshot() CounterSnapshot {
+	ret := c.snapshot(fault_tolerance.TriggerCondition_ERROR_RATE.String())
+	counts := windowCounts(c.sliceWindow, c.metricWindow, keyRequestCount, keyFailCount)
+	ret.RequestCount, ret.ErrorCount = counts[0], counts[1]
+	ret.Threshold = int64(c.errorPercent)
+	ret.WindowSeconds = int64(c.metricWindow / time.Second)
+	return ret
+}
+
 func getBucketInterval(interval time.Duration) time.Duration {
 	bucketSize := math.Ceil(float64(interval) / float64(bucketCount))
 	return time.Duration(bucketSize)
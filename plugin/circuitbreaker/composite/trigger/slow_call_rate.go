@@ -0,0 +1,160 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package trigger
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/metric"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// slowCallRateTypeName 慢调用比例触发类型名称
+// specification 的 TriggerCondition_TriggerType 没有慢调用比例类型，触发条件由熔断插件配置提供
+const slowCallRateTypeName = "SLOW_CALL_RATE"
+
+// 慢调用统计维度
+const (
+	// 总请求数
+	keySlowCallRequestCount = iota
+	// 慢调用数
+	keySlowCallCount
+	// 总统计维度
+	maxSlowCallDimension
+)
+
+// SlowCallRateCounter 慢调用比例触发器
+// 统计窗口内时延超过 slowCallThreshold 的调用占比，达到阈值且请求数不少于 minimumRequest 时触发熔断；
+// 慢调用只计入本触发器，不影响错误条件对调用成功/失败的判定
+type SlowCallRateCounter struct {
+	*baseCounter
+	sliceWindow       *metric.SliceWindow
+	metricWindow      time.Duration
+	minimumRequest    int32
+	slowCallPercent   int
+	slowCallThreshold time.Duration
+	scheduled         int32
+}
+
+// NewSlowCallRateCounter 构造慢调用比例触发器，opt.SlowCallRate 不能为空
+func NewSlowCallRateCounter(name string, opt *Options) *SlowCallRateCounter {
+	c := &SlowCallRateCounter{
+		baseCounter:       newBaseCounter(name, opt),
+		metricWindow:      opt.SlowCallRate.Interval,
+		minimumRequest:    opt.SlowCallRate.MinimumRequest,
+		slowCallPercent:   opt.SlowCallRate.Percent,
+		slowCallThreshold: opt.SlowCallRate.Threshold,
+	}
+	c.init()
+	return c
+}
+
+func (c *SlowCallRateCounter) init() {
+	c.log.Infof("[CircuitBreaker][SlowCallRateCounter] initialized, ruleName:%s, ruleID:%s, ruleRev:%s, "+
+		"slowCallThreshold(%v), slowCallPercent(%d%%), metricWindow(%v), minimumRequest(%d), resource(%s)",
+		c.ruleName, c.ruleID, c.ruleRevision, c.slowCallThreshold, c.slowCallPercent, c.metricWindow,
+		c.minimumRequest, c.res.String())
+	c.sliceWindow = metric.NewSliceWindow(c.log, c.res.String(), bucketCount, getBucketInterval(c.metricWindow),
+		maxSlowCallDimension, clock.GetClock().Now().UnixNano())
+}
+
+// Report 慢调用比例只关心时延，成功/失败结果不参与统计
+func (c *SlowCallRateCounter) Report(success bool) {
+}
+
+// ReportDelay 上报一次调用时延，慢调用时调度一次窗口检查
+func (c *SlowCallRateCounter) ReportDelay(delay time.Duration) {
+	if c.isSuspend() {
+		c.log.Debugf("[CircuitBreaker][SlowCallRateCounter] suspended, skip report, ruleName:%s, resource(%s)",
+			c.ruleName, c.res.String())
+		return
+	}
+	slow := delay > c.slowCallThreshold
+	c.log.Debugf("[CircuitBreaker][SlowCallRateCounter] report request, ruleName:%s, delay(%v), slow(%v), "+
+		"resource(%s)", c.ruleName, delay, slow, c.res.String())
+
+	c.sliceWindow.AddGauge(&model.ServiceCallResult{}, func(gauge model.InstanceGauge, bucket *metric.Bucket) int64 {
+		if slow {
+			bucket.AddMetric(keySlowCallCount, 1)
+		}
+		bucket.AddMetric(keySlowCallRequestCount, 1)
+		return 0
+	})
+	if slow && atomic.CompareAndSwapInt32(&c.scheduled, 0, 1) {
+		c.log.Debugf("[CircuitBreaker][SlowCallRateCounter] scheduled slow call rate check, ruleName:%s, "+
+			"ruleID:%s, ruleRev:%s, metricWindow(%v), resource(%s)",
+			c.ruleName, c.ruleID, c.ruleRevision, c.metricWindow, c.res.String())
+		c.delayExecutor(c.metricWindow, c.check)
+	}
+}
+
+// check 计算窗口内慢调用比例，达到阈值时触发 CloseToOpen
+func (c *SlowCallRateCounter) check() {
+	defer atomic.StoreInt32(&c.scheduled, 0)
+	currentTime := time.Now()
+	timeRange := &metric.TimeRange{
+		Start: currentTime.Add(-1 * c.metricWindow),
+		End:   currentTime,
+	}
+	reqCount := c.sliceWindow.CalcMetrics(keySlowCallRequestCount, timeRange)
+	slowCount := c.sliceWindow.CalcMetrics(keySlowCallCount, timeRange)
+	if reqCount == 0 || reqCount < int64(c.minimumRequest) {
+		c.log.Debugf("[CircuitBreaker][SlowCallRateCounter] minimum request not reached, skip trigger, "+
+			"ruleName:%s, reqCount(%d), minimumRequest(%d), resource(%s)",
+			c.ruleName, reqCount, c.minimumRequest, c.res.String())
+		return
+	}
+	slowRatio := (float64(slowCount) / float64(reqCount)) * 100
+	if slowRatio < float64(c.slowCallPercent) {
+		c.log.Debugf("[CircuitBreaker][SlowCallRateCounter] threshold not reached, skip trigger, ruleName:%s, "+
+			"reqCount(%d), slowCount(%d), slowRatio(%.2f%%), slowCallPercent(%d%%), resource(%s)",
+			c.ruleName, reqCount, slowCount, slowRatio, c.slowCallPercent, c.res.String())
+		return
+	}
+	c.suspend()
+	c.log.Infof("[CircuitBreaker][SlowCallRateCounter] triggered CloseToOpen, ruleName:%s, ruleID:%s, "+
+		"ruleRev:%s, reqCount(%d), slowCount(%d), slowRatio(%.2f%%), minimumRequest(%d), "+
+		"slowCallPercent(%d%%), slowCallThreshold(%v), resource(%s)",
+		c.ruleName, c.ruleID, c.ruleRevision, reqCount, slowCount, slowRatio,
+		c.minimumRequest, c.slowCallPercent, c.slowCallThreshold, c.res.String())
+	reason := fmt.Sprintf("SLOW_CALL_RATE:%.0f%% (threshold:%d%%, slowCall:%dms, window:%ds, reqCount:%d)",
+		slowRatio, c.slowCallPercent, c.slowCallThreshold.Milliseconds(), int(c.metricWindow/time.Second), reqCount)
+	c.handler.CloseToOpen(c.ruleName, reason)
+}
+
+// Resume 退出 suspended 状态
+func (c *SlowCallRateCounter) Resume() {
+	if c.isSuspend() {
+		c.resume()
+		c.log.Infof("[CircuitBreaker][SlowCallRateCounter] resumed counter, ruleName:%s, ruleID:%s, ruleRev:%s, "+
+			"resource(%s)", c.ruleName, c.ruleID, c.ruleRevision, c.res.String())
+	}
+}
+
+// Snapshot 统计窗口内请求数与慢调用数快照
+func (c *SlowCallRateCounter) Snapshot() CounterSnapshot {
+	ret := c.snapshot(slowCallRateTypeName)
+	counts := windowCounts(c.sliceWindow, c.metricWindow, keySlowCallRequestCount, keySlowCallCount)
+	ret.RequestCount, ret.ErrorCount = counts[0], counts[1]
+	ret.Threshold = int64(c.slowCallPercent)
+	ret.WindowSeconds = int64(c.metricWindow / time.Second)
+	return ret
+}
@@ -0,0 +1,129 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package trigger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// noopLogger 空日志实现，用于测试
+type noopLogger struct{}
+
+func (n *noopLogger) Tracef(format string, args ...interface{}) {}
+func (n *noopLogger) Debugf(format string, args ...interface{}) {}
+func (n *noopLogger) Infof(format string, args ...interface{})  {}
+func (n *noopLogger) Warnf(format string, args ...interface{})  {}
+func (n *noopLogger) Errorf(format string, args ...interface{}) {}
+func (n *noopLogger) Fatalf(format string, args ...interface{}) {}
+func (n *noopLogger) IsLevelEnabled(l int) bool                 { return true }
+func (n *noopLogger) SetLogLevel(l int) error                   { return nil }
+
+// fakeStatusHandler 记录 CloseToOpen 调用
+type fakeStatusHandler struct {
+	reasons []string
+}
+
+func (h *fakeStatusHandler) CloseToOpen(breaker string, reason string) {
+	h.reasons = append(h.reasons, reason)
+}
+func (h *fakeStatusHandler) OpenToHalfOpen()  {}
+func (h *fakeStatusHandler) HalfOpenToClose() {}
+func (h *fakeStatusHandler) HalfOpenToOpen()  {}
+
+// runCheck 等待当前统计桶结束后执行暂存的检查任务，与真实场景下窗口到期后再检查一致
+func runCheck(task func()) {
+	time.Sleep(200 * time.Millisecond)
+	task()
+}
+
+// newSlowCallRateCounterForTest 构造慢调用比例触发器（1s 窗口），延迟任务不执行而是暂存，由测试手动触发
+func newSlowCallRateCounterForTest(t *testing.T, percent, minimumRequest uint32) (*SlowCallRateCounter,
+	*fakeStatusHandler, *[]func()) {
+	svc := &model.ServiceKey{Namespace: "default", Service: "svc"}
+	res, err := model.NewServiceResource(svc, nil)
+	assert.NoError(t, err)
+	handler := &fakeStatusHandler{}
+	tasks := &[]func(){}
+	c := NewSlowCallRateCounter("rule", &Options{
+		Resource:      res,
+		StatusHandler: handler,
+		Log:           &noopLogger{},
+		DelayExecutor: func(delay time.Duration, f func()) {
+			*tasks = append(*tasks, f)
+		},
+		SlowCallRate: &SlowCallRateOptions{
+			Threshold:      100 * time.Millisecond,
+			Percent:        int(percent),
+			Interval:       time.Second,
+			MinimumRequest: int32(minimumRequest),
+		},
+	})
+	return c, handler, tasks
+}
+
+// TestSlowCallRateCounter_Trigger 慢调用比例达到阈值时触发熔断，并在触发后挂起
+func TestSlowCallRateCounter_Trigger(t *testing.T) {
+	c, handler, tasks := newSlowCallRateCounterForTest(t, 50, 4)
+	c.ReportDelay(10 * time.Millisecond)
+	c.ReportDelay(150 * time.Millisecond)
+	c.ReportDelay(200 * time.Millisecond)
+	c.ReportDelay(20 * time.Millisecond)
+	// 仅首个慢调用调度检查任务
+	assert.Len(t, *tasks, 1)
+	runCheck((*tasks)[0])
+	assert.Len(t, handler.reasons, 1)
+	assert.Contains(t, handler.reasons[0], "SLOW_CALL_RATE:50%")
+	assert.True(t, c.isSuspend())
+
+	c.ReportDelay(300 * time.Millisecond)
+	assert.Len(t, *tasks, 1)
+	c.Resume()
+	assert.False(t, c.isSuspend())
+}
+
+// TestSlowCallRateCounter_BelowThreshold 慢调用比例未达阈值时不触发，且可再次调度检查
+func TestSlowCallRateCounter_BelowThreshold(t *testing.T) {
+	c, handler, tasks := newSlowCallRateCounterForTest(t, 50, 1)
+	c.ReportDelay(150 * time.Millisecond)
+	c.ReportDelay(10 * time.Millisecond)
+	c.ReportDelay(10 * time.Millisecond)
+	assert.Len(t, *tasks, 1)
+	runCheck((*tasks)[0])
+	assert.Empty(t, handler.reasons)
+	assert.False(t, c.isSuspend())
+
+	c.ReportDelay(150 * time.Millisecond)
+	assert.Len(t, *tasks, 2)
+}
+
+// TestSlowCallRateCounter_MinimumRequest 请求数不足 minimumRequest 时即使全为慢调用也不触发
+func TestSlowCallRateCounter_MinimumRequest(t *testing.T) {
+	c, handler, tasks := newSlowCallRateCounterForTest(t, 50, 5)
+	c.ReportDelay(150 * time.Millisecond)
+	c.ReportDelay(150 * time.Millisecond)
+	runCheck((*tasks)[0])
+	assert.Empty(t, handler.reasons)
+	// 成功/失败结果不参与慢调用统计
+	c.Report(false)
+	assert.Len(t, *tasks, 1)
+}
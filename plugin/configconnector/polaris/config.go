@@ -20,6 +20,7 @@ package polaris
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/hashicorp/go-multierror"
 )
@@ -34,6 +35,9 @@ const (
 // GRPC插件级别配置.
 type networkConfig struct {
 	MaxCallRecvMsgSize int `yaml:"maxCallRecvMsgSize"`
+	// 北极星服务端 HTTP OpenAPI 地址，格式为[http(s)://]<host>:<port>，
+	// 删除配置文件、查询配置文件列表与发布历史、回滚发布等管理操作通过该地址调用
+	OpenAPIAddresses []string `yaml:"openAPIAddresses"`
 }
 
 // Verify 校验GRPC配置值.
@@ -42,6 +46,11 @@ func (r *networkConfig) Verify() error {
 	if r.MaxCallRecvMsgSize <= 0 || r.MaxCallRecvMsgSize > MaxMaxCallRecvMsgSize {
 		errs = multierror.Append(errs, fmt.Errorf("grpc.maxCallRecvMsgSize must be int (0, 524288000]"))
 	}
+	for _, address := range r.OpenAPIAddresses {
+		if _, err := url.Parse(openAPIBaseURL(address, false)); err != nil || len(address) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("openAPIAddresses %q is invalid", address))
+		}
+	}
 	return errs
 }
 
@@ -29,6 +29,7 @@ import (
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/polarismesh/specification/source/go/api/v1/config_manage"
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/polarismesh/polaris-go/pkg/clock"
@@ -61,8 +62,12 @@ type Connector struct {
 	// 有没有打印过connManager ready的信息，用于避免重复打印
 	hasPrintedReady uint32
 	token           string
+	// 与配置中心通信的传输凭证，开启TLS时支持证书热加载
+	creds credentials.TransportCredentials
 	// 上下文日志
 	logCtx *log.ContextLogger
+	// 配置管理类操作使用的 HTTP OpenAPI 客户端
+	openAPI *openAPIClient
 }
 
 // Type 插件类型.
@@ -85,6 +90,23 @@ func (c *Connector) Init(ctx *plugin.InitContext) error {
 		c.cfg = cfgValue.(*networkConfig)
 	}
 	c.token = ctx.Config.GetConfigFile().GetConfigConnectorConfig().GetToken()
+	var openAPIAddresses []string
+	if c.cfg != nil {
+		openAPIAddresses = c.cfg.OpenAPIAddresses
+	}
+	tlsCfg := getTLSConfig(ctx.Config)
+	creds, err := network.NewTransportCredentials(tlsCfg)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to create config connector tls credentials")
+	}
+	c.creds = creds
+	// OpenAPI 与 gRPC 连接使用同一份TLS配置
+	openAPITLSConfig, err := network.NewTLSConfig(tlsCfg)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to create config connector openapi tls config")
+	}
+	c.openAPI = newOpenAPIClient(openAPIAddresses, c.token,
+		ctx.Config.GetConfigFile().GetConfigConnectorConfig().GetMessageTimeout(), openAPITLSConfig)
 	connManager, err := network.NewConfigConnectionManager(ctx.Config, ctx.ValueCtx)
 	if err != nil {
 		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to create config connectionManager")
@@ -100,6 +122,16 @@ func (c *Connector) Init(ctx *plugin.InitContext) error {
 	return nil
 }
 
+// getTLSConfig 获取配置中心连接使用的TLS配置
+// 配置中心未配置 tls.enable 时，沿用 global.serverConnector.tls 的配置；显式关闭时不使用TLS
+func getTLSConfig(cfg config.Configuration) config.TLSConfig {
+	tlsCfg := cfg.GetConfigFile().GetConfigConnectorConfig().GetTLS()
+	if !tlsCfg.IsEnableSet() {
+		return cfg.GetGlobal().GetServerConnector().GetTLS()
+	}
+	return tlsCfg
+}
+
 // Destroy 销毁插件，可用于释放资源.
 func (c *Connector) Destroy() error {
 	if nil != c.RunContext {
@@ -108,6 +140,9 @@ func (c *Connector) Destroy() error {
 	if nil != c.connManager {
 		c.connManager.Destroy()
 	}
+	if nil != c.creds {
+		network.CloseTransportCredentials(c.creds)
+	}
 	return nil
 }
 
@@ -141,8 +176,8 @@ func (c *Connector) GetConfigFile(configFile *configconnector.ConfigFile) (*conf
 	grpcClientStart := clock.GetClock().Now()
 	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
 	reqID := connector.NextRegisterInstanceReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
-		connector.AppendHeaderWithReqId(reqID))
+	ctx, cancel := connector.CreateHeadersContextWithParent(configFile.GetContext(), 0,
+		connector.AppendAuthHeader(c.token), connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
 	}
@@ -0,0 +1,240 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaris
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/polarismesh/specification/source/go/api/v1/config_manage"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
+)
+
+// DeleteConfigFile 删除配置文件.
+func (c *Connector) DeleteConfigFile(configFile *configconnector.ConfigFile,
+	operator string) (*configconnector.ConfigFileResponse, error) {
+	query := url.Values{}
+	query.Set("namespace", configFile.GetNamespace())
+	query.Set("group", configFile.GetFileGroup())
+	query.Set("name", configFile.GetFileName())
+	if len(operator) > 0 {
+		query.Set("deleteBy", operator)
+	}
+	response := &config_manage.ConfigResponse{}
+	if err := c.invokeOpenAPI(connector.OpKeyDeleteConfigFile, http.MethodDelete, openAPIConfigFilePath, query,
+		nil, connector.NextDeleteConfigFileReqID(), response); err != nil {
+		return nil, err
+	}
+	return &configconnector.ConfigFileResponse{
+		Code:       response.GetCode().GetValue(),
+		Message:    response.GetInfo().GetValue(),
+		ConfigFile: configFile,
+	}, nil
+}
+
+// ListConfigFiles 查询分组下的配置文件及其元数据.
+func (c *Connector) ListConfigFiles(
+	req *configconnector.ConfigFileListRequest) (*configconnector.ConfigFileRecordsResponse, error) {
+	query := url.Values{}
+	query.Set("namespace", req.Namespace)
+	query.Set("group", req.FileGroup)
+	if len(req.FileName) > 0 {
+		query.Set("name", req.FileName)
+	}
+	query.Set("offset", strconv.FormatUint(uint64(req.Offset), 10))
+	query.Set("limit", strconv.FormatUint(uint64(req.Limit), 10))
+	response := &config_manage.ConfigBatchQueryResponse{}
+	if err := c.invokeOpenAPI(connector.OpKeyListConfigFiles, http.MethodGet, openAPISearchConfigFilesPath, query,
+		nil, connector.NextListConfigFilesReqID(), response); err != nil {
+		return nil, err
+	}
+	records := make([]*configconnector.ConfigFileRecord, 0, len(response.GetConfigFiles()))
+	for _, item := range response.GetConfigFiles() {
+		records = append(records, transferFromSpecConfigFile(item))
+	}
+	return &configconnector.ConfigFileRecordsResponse{
+		Code:    response.GetCode().GetValue(),
+		Message: response.GetInfo().GetValue(),
+		Total:   response.GetTotal().GetValue(),
+		Records: records,
+	}, nil
+}
+
+// GetConfigFileReleaseHistory 查询配置文件的发布历史，加密配置返回密文，由 configFilter 链负责解密.
+func (c *Connector) GetConfigFileReleaseHistory(
+	req *configconnector.ConfigFileReleaseHistoryRequest) (*configconnector.ConfigFileRecordsResponse, error) {
+	query := url.Values{}
+	query.Set("namespace", req.Namespace)
+	query.Set("group", req.FileGroup)
+	query.Set("name", req.FileName)
+	query.Set("offset", strconv.FormatUint(uint64(req.Offset), 10))
+	query.Set("limit", strconv.FormatUint(uint64(req.Limit), 10))
+	response := &config_manage.ConfigBatchQueryResponse{}
+	if err := c.invokeOpenAPI(connector.OpKeyGetConfigFileReleaseHistory, http.MethodGet, openAPIReleaseHistoryPath,
+		query, nil, connector.NextGetConfigFileReleaseHistoryReqID(), response); err != nil {
+		return nil, err
+	}
+	records := make([]*configconnector.ConfigFileRecord, 0, len(response.GetConfigFileReleaseHistories()))
+	for _, item := range response.GetConfigFileReleaseHistories() {
+		records = append(records, transferFromSpecReleaseHistory(item))
+	}
+	return &configconnector.ConfigFileRecordsResponse{
+		Code:    response.GetCode().GetValue(),
+		Message: response.GetInfo().GetValue(),
+		Total:   response.GetTotal().GetValue(),
+		Records: records,
+	}, nil
+}
+
+// RollbackConfigFile 将配置文件回滚到 configFile.VersionName 对应的发布版本.
+func (c *Connector) RollbackConfigFile(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	release := transferToConfigFileRelease(configFile)
+	release.Name = wrapperspb.String(configFile.GetVersionName())
+	item, err := (&jsonpb.Marshaler{OrigName: true}).MarshalToString(release)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "fail to marshal rollback request")
+	}
+	response := &config_manage.ConfigBatchWriteResponse{}
+	if err := c.invokeOpenAPI(connector.OpKeyRollbackConfigFile, http.MethodPut, openAPIRollbackReleasesPath, nil,
+		[]byte("["+item+"]"), connector.NextRollbackConfigFileReqID(), response); err != nil {
+		return nil, err
+	}
+	code, info := response.GetCode().GetValue(), response.GetInfo().GetValue()
+	// 批量接口整体成功时，仍需以单个文件的处理结果为准
+	for _, itemResp := range response.GetResponses() {
+		if itemResp.GetCode().GetValue() != uint32(apimodel.Code_ExecuteSuccess) {
+			code, info = itemResp.GetCode().GetValue(), itemResp.GetInfo().GetValue()
+			break
+		}
+	}
+	return &configconnector.ConfigFileResponse{
+		Code:       code,
+		Message:    info,
+		ConfigFile: configFile,
+	}, nil
+}
+
+// invokeOpenAPI 调用服务端 OpenAPI，并打印请求与应答报文.
+func (c *Connector) invokeOpenAPI(opKey, method, path string, query url.Values, body []byte, reqID string,
+	response proto.Message) error {
+	if c.logCtx.GetBaseLogger().IsLevelEnabled(log.DebugLog) {
+		c.logCtx.GetBaseLogger().Debugf("request to send is %s %s?%s, body %s, opKey %s, reqID %s",
+			method, path, query.Encode(), body, opKey, reqID)
+	}
+	if err := c.openAPI.invoke(method, path, query, body, reqID, response); err != nil {
+		if err == errOpenAPIAddressEmpty {
+			return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to %s", opKey)
+		}
+		return model.NewSDKError(model.ErrCodeNetworkError, err, "fail to %s, reqID %s", opKey, reqID)
+	}
+	if c.logCtx.GetBaseLogger().IsLevelEnabled(log.DebugLog) {
+		respJson, _ := (&jsonpb.Marshaler{}).MarshalToString(response)
+		c.logCtx.GetBaseLogger().Debugf("response recv is %s, opKey %s, reqID %s", respJson, opKey, reqID)
+	}
+	return nil
+}
+
+func transferFromSpecTags(specTags []*config_manage.ConfigFileTag) []*configconnector.ConfigFileTag {
+	var tags []*configconnector.ConfigFileTag
+	for _, tag := range specTags {
+		tags = append(tags, &configconnector.ConfigFileTag{
+			Key:   tag.GetKey().GetValue(),
+			Value: tag.GetValue().GetValue(),
+		})
+	}
+	return tags
+}
+
+// isEncryptedByTags 发布历史没有加密标识字段，根据服务端写入的内部 tag 判断是否为加密配置
+func isEncryptedByTags(tags []*configconnector.ConfigFileTag) bool {
+	for _, tag := range tags {
+		switch tag.Key {
+		case configconnector.ConfigFileTagKeyUseEncrypted:
+			if encrypted, _ := strconv.ParseBool(tag.Value); encrypted {
+				return true
+			}
+		case configconnector.ConfigFileTagKeyDataKey:
+			if len(tag.Value) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func transferFromSpecConfigFile(item *config_manage.ConfigFile) *configconnector.ConfigFileRecord {
+	tags := transferFromSpecTags(item.GetTags())
+	return &configconnector.ConfigFileRecord{
+		ConfigFile: &configconnector.ConfigFile{
+			Namespace: item.GetNamespace().GetValue(),
+			FileGroup: item.GetGroup().GetValue(),
+			FileName:  item.GetName().GetValue(),
+			Encrypted: item.GetEncrypted().GetValue() || isEncryptedByTags(tags),
+			Tags:      tags,
+		},
+		ID:          item.GetId().GetValue(),
+		Format:      item.GetFormat().GetValue(),
+		Comment:     item.GetComment().GetValue(),
+		Status:      item.GetStatus().GetValue(),
+		EncryptAlgo: item.GetEncryptAlgo().GetValue(),
+		CreateTime:  item.GetCreateTime().GetValue(),
+		CreateBy:    item.GetCreateBy().GetValue(),
+		ModifyTime:  item.GetModifyTime().GetValue(),
+		ModifyBy:    item.GetModifyBy().GetValue(),
+		ReleaseTime: item.GetReleaseTime().GetValue(),
+		ReleaseBy:   item.GetReleaseBy().GetValue(),
+	}
+}
+
+func transferFromSpecReleaseHistory(item *config_manage.ConfigFileReleaseHistory) *configconnector.ConfigFileRecord {
+	tags := transferFromSpecTags(item.GetTags())
+	configFile := &configconnector.ConfigFile{
+		Namespace:     item.GetNamespace().GetValue(),
+		FileGroup:     item.GetGroup().GetValue(),
+		FileName:      item.GetFileName().GetValue(),
+		SourceContent: item.GetContent().GetValue(),
+		VersionName:   item.GetName().GetValue(),
+		Md5:           item.GetMd5().GetValue(),
+		Encrypted:     isEncryptedByTags(tags),
+		Tags:          tags,
+	}
+	return &configconnector.ConfigFileRecord{
+		ConfigFile:         configFile,
+		ID:                 item.GetId().GetValue(),
+		Format:             item.GetFormat().GetValue(),
+		Comment:            item.GetComment().GetValue(),
+		Type:               item.GetType().GetValue(),
+		Status:             item.GetStatus().GetValue(),
+		Reason:             item.GetReason().GetValue(),
+		ReleaseDescription: item.GetReleaseDescription().GetValue(),
+		EncryptAlgo:        configFile.GetEncryptAlgo(),
+		CreateTime:         item.GetCreateTime().GetValue(),
+		CreateBy:           item.GetCreateBy().GetValue(),
+		ModifyTime:         item.GetModifyTime().GetValue(),
+		ModifyBy:           item.GetModifyBy().GetValue(),
+	}
+}
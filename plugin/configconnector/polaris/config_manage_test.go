@@ -0,0 +1,266 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaris
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/network"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+
+	// 匿名引入 zaplog 插件，初始化全局 baseLogger
+	_ "github.com/polarismesh/polaris-go/plugin/logger/zaplog"
+)
+
+func newTestOpenAPIConnector(addresses ...string) *Connector {
+	logCtx := &log.ContextLogger{}
+	logCtx.Init()
+	return &Connector{
+		logCtx:  logCtx,
+		openAPI: newOpenAPIClient(addresses, "test-token", time.Second, nil),
+	}
+}
+
+// TestDeleteConfigFile 测试删除配置文件
+// 测试场景：通过 OpenAPI 删除配置文件
+// 前置条件：模拟服务端返回执行成功
+// 预期结果：请求使用 DELETE 方法并携带文件标识、操作人与鉴权 token，应答码透传
+func TestDeleteConfigFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, openAPIConfigFilePath, r.URL.Path)
+		assert.Equal(t, "ns", r.URL.Query().Get("namespace"))
+		assert.Equal(t, "group", r.URL.Query().Get("group"))
+		assert.Equal(t, "app.yaml", r.URL.Query().Get("name"))
+		assert.Equal(t, "ops", r.URL.Query().Get("deleteBy"))
+		assert.Equal(t, "test-token", r.Header.Get(openAPIHeaderToken))
+		_, _ = w.Write([]byte(`{"code":200000,"info":"execute success"}`))
+	}))
+	defer server.Close()
+
+	c := newTestOpenAPIConnector(server.URL)
+	resp, err := c.DeleteConfigFile(&configconnector.ConfigFile{
+		Namespace: "ns", FileGroup: "group", FileName: "app.yaml"}, "ops")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), resp.GetCode())
+}
+
+// TestListConfigFiles 测试查询配置文件列表
+// 测试场景：查询分组下的配置文件元数据
+// 前置条件：模拟服务端返回两个文件，其中一个为加密文件
+// 预期结果：分页参数正确传递，元数据与加密标识被正确解析
+func TestListConfigFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, openAPISearchConfigFilesPath, r.URL.Path)
+		assert.Equal(t, "10", r.URL.Query().Get("offset"))
+		assert.Equal(t, "20", r.URL.Query().Get("limit"))
+		_, _ = w.Write([]byte(`{"code":200000,"total":12,"configFiles":[
+			{"id":1,"name":"a.yaml","namespace":"ns","group":"group","format":"yaml","comment":"c",
+			 "tags":[{"key":"env","value":"prod"}],"create_time":"2023-01-02 03:04:05","release_by":"ops",
+			 "unknownField":"ignored"},
+			{"id":2,"name":"b.json","namespace":"ns","group":"group","encrypted":true,"encrypt_algo":"AES"}]}`))
+	}))
+	defer server.Close()
+
+	c := newTestOpenAPIConnector(server.URL)
+	resp, err := c.ListConfigFiles(&configconnector.ConfigFileListRequest{
+		Namespace: "ns", FileGroup: "group", Offset: 10, Limit: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(12), resp.Total)
+	assert.Len(t, resp.Records, 2)
+	assert.Equal(t, "a.yaml", resp.Records[0].GetFileName())
+	assert.Equal(t, "yaml", resp.Records[0].Format)
+	assert.Equal(t, "prod", resp.Records[0].GetLabels()["env"])
+	assert.Equal(t, "2023-01-02 03:04:05", resp.Records[0].CreateTime)
+	assert.Equal(t, "ops", resp.Records[0].ReleaseBy)
+	assert.False(t, resp.Records[0].GetEncrypted())
+	assert.True(t, resp.Records[1].GetEncrypted())
+	assert.Equal(t, "AES", resp.Records[1].EncryptAlgo)
+}
+
+// TestGetConfigFileReleaseHistory 测试查询发布历史
+// 测试场景：发布历史中包含加密配置
+// 前置条件：模拟服务端返回带数据密钥 tag 的发布记录
+// 预期结果：记录保留密文与数据密钥，并标记为加密配置，交由 configFilter 链解密
+func TestGetConfigFileReleaseHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, openAPIReleaseHistoryPath, r.URL.Path)
+		assert.Equal(t, "app.yaml", r.URL.Query().Get("name"))
+		_, _ = w.Write([]byte(`{"code":200000,"total":1,"configFileReleaseHistories":[
+			{"id":7,"name":"release-7","namespace":"ns","group":"group","file_name":"app.yaml","content":"cipher",
+			 "md5":"m","type":"normal","status":"success","tags":[{"key":"internal-datakey","value":"key"},
+			 {"key":"internal-encryptalgo","value":"AES"}]}]}`))
+	}))
+	defer server.Close()
+
+	c := newTestOpenAPIConnector(server.URL)
+	resp, err := c.GetConfigFileReleaseHistory(&configconnector.ConfigFileReleaseHistoryRequest{
+		Namespace: "ns", FileGroup: "group", FileName: "app.yaml", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Records, 1)
+	record := resp.Records[0]
+	assert.Equal(t, uint64(7), record.ID)
+	assert.Equal(t, "release-7", record.GetVersionName())
+	assert.Equal(t, "cipher", record.GetSourceContent())
+	assert.Equal(t, "key", record.GetDataKey())
+	assert.Equal(t, "AES", record.EncryptAlgo)
+	assert.True(t, record.GetEncrypted())
+}
+
+// TestRollbackConfigFile 测试回滚配置文件
+// 测试场景：批量回滚接口整体成功但单个文件失败
+// 前置条件：模拟服务端在 responses 中返回文件级失败码
+// 预期结果：请求体为包含发布名称的数组，返回文件级的失败码与原因
+func TestRollbackConfigFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, openAPIRollbackReleasesPath, r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		var releases []map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body, &releases))
+		assert.Len(t, releases, 1)
+		assert.Equal(t, "release-7", releases[0]["name"])
+		assert.Equal(t, "app.yaml", releases[0]["file_name"])
+		_, _ = w.Write([]byte(`{"code":200000,"responses":[{"code":400202,"info":"not found release"}]}`))
+	}))
+	defer server.Close()
+
+	c := newTestOpenAPIConnector(server.URL)
+	resp, err := c.RollbackConfigFile(&configconnector.ConfigFile{
+		Namespace: "ns", FileGroup: "group", FileName: "app.yaml", VersionName: "release-7"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(400202), resp.GetCode())
+	assert.Equal(t, "not found release", resp.GetMessage())
+}
+
+// TestOpenAPIAddressFailover 测试 OpenAPI 地址切换
+// 测试场景：部分地址不可达
+// 前置条件：配置一个已关闭的地址与一个正常地址（不带协议前缀）
+// 预期结果：网络失败时切换到下一个地址，请求最终成功
+func TestOpenAPIAddressFailover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":200000}`))
+	}))
+	defer server.Close()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	deadAddress := listener.Addr().String()
+	_ = listener.Close()
+
+	c := newTestOpenAPIConnector(deadAddress, server.Listener.Addr().String())
+	for i := 0; i < 2; i++ {
+		resp, err := c.DeleteConfigFile(&configconnector.ConfigFile{
+			Namespace: "ns", FileGroup: "group", FileName: "app.yaml"}, "")
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), resp.GetCode())
+	}
+}
+
+// TestOpenAPIErrors 测试 OpenAPI 异常场景
+// 测试场景：未配置地址、服务端返回非 JSON 应答
+// 前置条件：分别构造无地址的连接器与返回 HTML 的服务端
+// 预期结果：分别返回配置错误与网络错误
+func TestOpenAPIErrors(t *testing.T) {
+	configFile := &configconnector.ConfigFile{Namespace: "ns", FileGroup: "group", FileName: "app.yaml"}
+	_, err := newTestOpenAPIConnector().DeleteConfigFile(configFile, "")
+	assert.Error(t, err)
+	assert.Equal(t, model.ErrCodeAPIInvalidConfig, err.(model.SDKError).ErrorCode())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`<html>404</html>`))
+	}))
+	defer server.Close()
+	_, err = newTestOpenAPIConnector(server.URL).DeleteConfigFile(configFile, "")
+	assert.Error(t, err)
+	assert.Equal(t, model.ErrCodeNetworkError, err.(model.SDKError).ErrorCode())
+	assert.Contains(t, err.Error(), "http status 404")
+}
+
+// TestOpenAPITLS 测试 OpenAPI 客户端使用配置中心的TLS配置
+// 测试场景：服务端仅提供 HTTPS，OpenAPI 地址未指定协议
+// 前置条件：TLS配置的 caFile 为服务端证书
+// 预期结果：开启TLS后默认使用 https 并通过证书校验；未开启TLS时无法访问 HTTPS 服务端
+func TestOpenAPITLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":200000,"info":"execute success"}`))
+	}))
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	enable := true
+	tlsConfig, err := network.NewTLSConfig(&config.TLSConfigImpl{Enable: &enable, CAFile: caFile})
+	assert.NoError(t, err)
+
+	address := strings.TrimPrefix(server.URL, "https://")
+	c := newTestOpenAPIConnector()
+	c.openAPI = newOpenAPIClient([]string{address}, "test-token", time.Second, tlsConfig)
+	resp, err := c.DeleteConfigFile(&configconnector.ConfigFile{
+		Namespace: "ns", FileGroup: "group", FileName: "app.yaml"}, "ops")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), resp.GetCode())
+
+	c.openAPI = newOpenAPIClient([]string{address}, "test-token", time.Second, nil)
+	_, err = c.DeleteConfigFile(&configconnector.ConfigFile{
+		Namespace: "ns", FileGroup: "group", FileName: "app.yaml"}, "ops")
+	assert.Error(t, err)
+}
+
+// TestGetTLSConfig 测试配置中心连接的TLS配置选择
+// 测试场景：全局 serverConnector 开启TLS，配置中心未配置、显式关闭、显式开启 tls.enable
+// 前置条件：全局TLS配置了 caFile
+// 预期结果：未配置时沿用全局配置；显式关闭时不使用TLS；显式开启时使用配置中心自身的配置
+func TestGetTLSConfig(t *testing.T) {
+	cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	globalTLS := cfg.GetGlobal().GetServerConnector().GetTLS()
+	globalTLS.SetEnable(true)
+	globalTLS.SetCAFile("global-ca.pem")
+
+	tlsCfg := getTLSConfig(cfg)
+	assert.True(t, tlsCfg.IsEnable())
+	assert.Equal(t, "global-ca.pem", tlsCfg.GetCAFile())
+
+	connectorTLS := cfg.GetConfigFile().GetConfigConnectorConfig().GetTLS()
+	connectorTLS.SetEnable(false)
+	tlsCfg = getTLSConfig(cfg)
+	assert.False(t, tlsCfg.IsEnable())
+	assert.Empty(t, tlsCfg.GetCAFile())
+
+	connectorTLS.SetEnable(true)
+	connectorTLS.SetCAFile("config-ca.pem")
+	tlsCfg = getTLSConfig(cfg)
+	assert.True(t, tlsCfg.IsEnable())
+	assert.Equal(t, "config-ca.pem", tlsCfg.GetCAFile())
+}
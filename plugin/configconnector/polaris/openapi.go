@@ -0,0 +1,150 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaris
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	openAPIConfigFilePath         = "/config/v1/configfiles"
+	openAPISearchConfigFilesPath  = "/config/v1/configfiles/search"
+	openAPIReleaseHistoryPath     = "/config/v1/configfiles/releasehistory"
+	openAPIRollbackReleasesPath   = "/config/v1/configfiles/releases/rollback"
+	openAPIHeaderToken            = "X-Polaris-Token"
+	openAPIHeaderRequestID        = "Request-Id"
+	openAPIMaxErrorBodyLength     = 512
+	openAPIDefaultScheme          = "http://"
+	openAPIDefaultTLSScheme       = "https://"
+	openAPIContentTypeApplication = "application/json"
+)
+
+// errOpenAPIAddressEmpty 未配置 OpenAPI 地址
+var errOpenAPIAddressEmpty = errors.New("config.configConnector.plugin.polaris.openAPIAddresses is empty")
+
+// openAPIClient 北极星服务端 HTTP OpenAPI 客户端
+// 配置中心的 gRPC 协议没有提供删除、发布历史与回滚接口，这些管理操作统一走服务端的 HTTP OpenAPI
+type openAPIClient struct {
+	addresses []string
+	token     string
+	client    *http.Client
+	// 轮询下标，单个地址网络失败时切换到下一个地址
+	index uint32
+}
+
+// newOpenAPIClient 创建 OpenAPI 客户端，tlsConfig 不为 nil 时使用该配置发起 HTTPS 请求
+func newOpenAPIClient(addresses []string, token string, timeout time.Duration, tlsConfig *tls.Config) *openAPIClient {
+	baseURLs := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		baseURLs = append(baseURLs, openAPIBaseURL(address, tlsConfig != nil))
+	}
+	client := &http.Client{Timeout: timeout}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.Transport = transport
+	}
+	return &openAPIClient{
+		addresses: baseURLs,
+		token:     token,
+		client:    client,
+	}
+}
+
+// openAPIBaseURL 未指定协议的地址默认使用 http，开启TLS时默认使用 https
+func openAPIBaseURL(address string, enableTLS bool) string {
+	address = strings.TrimRight(strings.TrimSpace(address), "/")
+	if !strings.Contains(address, "://") {
+		if enableTLS {
+			return openAPIDefaultTLSScheme + address
+		}
+		address = openAPIDefaultScheme + address
+	}
+	return address
+}
+
+// invoke 调用 OpenAPI，并将应答报文解析到 resp 中，业务错误码由调用方根据 resp 判断
+func (o *openAPIClient) invoke(method, path string, query url.Values, body []byte, reqID string,
+	resp proto.Message) error {
+	if o == nil || len(o.addresses) == 0 {
+		return errOpenAPIAddressEmpty
+	}
+	start := int(atomic.AddUint32(&o.index, 1))
+	var lastErr error
+	for i := 0; i < len(o.addresses); i++ {
+		address := o.addresses[(start+i)%len(o.addresses)]
+		retryable, err := o.doInvoke(address, method, path, query, body, reqID, resp)
+		if err == nil {
+			return nil
+		}
+		lastErr = fmt.Errorf("address %s: %w", address, err)
+		if !retryable {
+			break
+		}
+	}
+	return lastErr
+}
+
+// doInvoke 向单个地址发起请求，返回的 bool 表示失败是否可以切换地址重试
+func (o *openAPIClient) doInvoke(address, method, path string, query url.Values, body []byte, reqID string,
+	resp proto.Message) (bool, error) {
+	target := address + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", openAPIContentTypeApplication)
+	req.Header.Set(openAPIHeaderRequestID, reqID)
+	if len(o.token) > 0 {
+		req.Header.Set(openAPIHeaderToken, o.token)
+	}
+	httpResp, err := o.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer httpResp.Body.Close()
+	data, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return true, err
+	}
+	// 服务端在业务失败时同样会返回带 code 的应答报文，因此不论 HTTP 状态码都先尝试解析
+	unmarshaler := &jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err := unmarshaler.Unmarshal(bytes.NewReader(data), resp); err != nil {
+		if len(data) > openAPIMaxErrorBodyLength {
+			data = data[:openAPIMaxErrorBodyLength]
+		}
+		return httpResp.StatusCode >= http.StatusInternalServerError,
+			fmt.Errorf("http status %d, fail to decode response: %v, body: %s", httpResp.StatusCode, err, data)
+	}
+	return false, nil
+}
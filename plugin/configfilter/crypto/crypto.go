@@ -160,6 +160,19 @@ func (c *CryptoFilter) DoFilter(configFile *configconnector.ConfigFile, next con
 	}
 }
 
+// Decrypt 使用 base64 编码的明文数据密钥与 encryptAlgo 指定的算法直接解密配置内容，不经过 RSA 交换数据密钥
+func (c *CryptoFilter) Decrypt(cipherContent, dataKey, encryptAlgo string) (string, error) {
+	crypto, err := c.GetCrypto(encryptAlgo)
+	if err != nil {
+		return "", err
+	}
+	key, err := base64.StdEncoding.DecodeString(dataKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid data key: %w", err)
+	}
+	return crypto.Decrypt(cipherContent, key)
+}
+
 // GetCrypto get crypto by algorithm
 func (c *CryptoFilter) GetCrypto(algo string) (Crypto, error) {
 	crypto, ok := c.cryptos[algo]
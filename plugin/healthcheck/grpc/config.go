@@ -0,0 +1,45 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"fmt"
+	"time"
+)
+
+// Config 健康探测的配置
+type Config struct {
+	// Service 调用 grpc.health.v1.Health/Check 时携带的服务名，为空表示探测整个服务端
+	Service string `yaml:"service" json:"service"`
+	// Timeout 单次探测超时时间，未配置时使用 consumer.healthCheck.timeout；探测规则中配置的超时优先
+	Timeout *time.Duration `yaml:"timeout" json:"timeout"`
+	// Metadata 探测请求附带的 gRPC metadata
+	Metadata map[string]string `yaml:"metadata" json:"metadata"`
+}
+
+// SetDefault 设置默认值
+func (r *Config) SetDefault() {
+}
+
+// Verify 检验健康探测配置
+func (r *Config) Verify() error {
+	if r.Timeout != nil && *r.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout: %v, it must greater than 0", *r.Timeout)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/healthcheck"
+)
+
+// Detector gRPC协议的实例健康探测器，调用 grpc.health.v1.Health/Check 判定实例健康
+type Detector struct {
+	*plugin.PluginBase
+	cfg     *Config
+	timeout time.Duration
+	// 上下文日志
+	logCtx *log.ContextLogger
+	// lastErr 记录每个探测地址的上一次探测错误信息，err 内容不变时静默不重复打印。
+	lastErr map[string]string
+	// lastErrMu 保护 lastErr 的并发访问。
+	lastErrMu sync.Mutex
+}
+
+// Type 插件类型
+func (g *Detector) Type() common.Type {
+	return common.TypeHealthCheck
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *Detector) Name() string {
+	return config.DefaultGRPCHealthCheck
+}
+
+// Init 初始化插件
+func (g *Detector) Init(ctx *plugin.InitContext) (err error) {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	cfgValue := ctx.Config.GetConsumer().GetHealthCheck().GetPluginConfig(g.Name())
+	if cfgValue != nil {
+		g.cfg = cfgValue.(*Config)
+	}
+	if g.cfg == nil {
+		g.cfg = &Config{}
+	}
+	g.timeout = ctx.Config.GetConsumer().GetHealthCheck().GetTimeout()
+	if g.cfg.Timeout != nil {
+		g.timeout = *g.cfg.Timeout
+	}
+	g.logCtx = ctx.ValueCtx.GetContextLogger()
+	g.lastErr = make(map[string]string, 16)
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *Detector) Destroy() error {
+	return nil
+}
+
+// DetectInstance 探测服务实例健康
+func (g *Detector) DetectInstance(ins model.Instance, rule *fault_tolerance.FaultDetectRule) (result healthcheck.DetectResult, err error) {
+	start := time.Now()
+	address := fmt.Sprintf("%s:%d", ins.GetHost(), ins.GetPort())
+	if rule != nil && rule.GetPort() > 0 {
+		address = fmt.Sprintf("%s:%d", ins.GetHost(), rule.GetPort())
+	}
+	timeout := g.timeout
+	if rule != nil && healthcheck.RuleProtocol(rule) == healthcheck.CheckerProtocol(g) && rule.GetTimeout() > 0 {
+		timeout = time.Duration(rule.GetTimeout()) * time.Millisecond
+	}
+	code, success := g.doGRPCDetect(address, timeout)
+	result = &healthcheck.DetectResultImp{
+		Success:        success,
+		DetectTime:     start,
+		DetectInstance: ins,
+		Code:           code,
+	}
+	return result, nil
+}
+
+// doGRPCDetect 执行一次探测逻辑，返回探测码与是否健康
+// 探测码为 HealthCheckResponse 的 ServingStatus，RPC 失败时为 gRPC 状态码，连接失败时为空
+func (g *Detector) doGRPCDetect(address string, timeout time.Duration) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if len(g.cfg.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(g.cfg.Metadata))
+	}
+	// 阻塞建连以便把连接失败与 Health/Check 失败区分开，连接被拒绝等非临时错误立即返回
+	conn, err := grpc.DialContext(ctx, address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+		grpc.FailOnNonTempDialError(true))
+	if err != nil {
+		g.logDetectErr(address, err)
+		return "", false
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: g.cfg.Service})
+	if err != nil {
+		g.logDetectErr(address, err)
+		return status.Code(err).String(), false
+	}
+	g.clearDetectErr(address)
+	servingStatus := resp.GetStatus()
+	success := servingStatus == healthpb.HealthCheckResponse_SERVING
+	// 探测完成，每个探测周期都会产生，使用 Debug 级别记录，便于确认探测真实发起。
+	if l := g.getDetectLog(); l != nil {
+		l.Debugf("[HealthCheck][grpc] detect done, address=%s, service=%s, status=%s, success=%t",
+			address, g.cfg.Service, servingStatus, success)
+	}
+	return servingStatus.String(), success
+}
+
+// logDetectErr 探测异常收敛：err 内容不变时仅首次打印，避免连接超时/拒绝等重复刷屏。
+func (g *Detector) logDetectErr(address string, err error) {
+	errMsg := err.Error()
+	g.lastErrMu.Lock()
+	if g.lastErr == nil {
+		g.lastErr = make(map[string]string, 8)
+	}
+	if lastErr, ok := g.lastErr[address]; ok && lastErr == errMsg {
+		g.lastErrMu.Unlock()
+		return
+	}
+	g.lastErr[address] = errMsg
+	g.lastErrMu.Unlock()
+	if l := g.getDetectLog(); l != nil {
+		l.Errorf("[HealthCheck][grpc] fail to check %s, err is %v", address, err)
+	}
+}
+
+// clearDetectErr err 恢复后清除记录，确保下次异常能重新打印。
+func (g *Detector) clearDetectErr(address string) {
+	g.lastErrMu.Lock()
+	defer g.lastErrMu.Unlock()
+	if g.lastErr != nil {
+		delete(g.lastErr, address)
+	}
+}
+
+// getDetectLog 获取探测日志记录器，logCtx 未初始化时返回 nil
+func (g *Detector) getDetectLog() log.Logger {
+	if g.logCtx == nil {
+		return nil
+	}
+	return g.logCtx.GetDetectLogger()
+}
+
+// Protocol FaultDetectRule_Protocol 未定义 gRPC，返回 UNKNOWN，由探测规则 metadata 中的 protocol=grpc 选取
+func (g *Detector) Protocol() fault_tolerance.FaultDetectRule_Protocol {
+	return fault_tolerance.FaultDetectRule_UNKNOWN
+}
+
+// IsEnable enable
+func (g *Detector) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// init 注册插件信息
+func init() {
+	plugin.RegisterConfigurablePlugin(&Detector{}, &Config{})
+}
@@ -0,0 +1,119 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	"github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/healthcheck"
+)
+
+// startHealthServer 启动一个仅注册 grpc.health.v1.Health 的服务端，返回端口
+func startHealthServer(t *testing.T, opts ...grpc.ServerOption) (*health.Server, uint32) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	srv := grpc.NewServer(opts...)
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+	return hs, uint32(ln.Addr().(*net.TCPAddr).Port)
+}
+
+func newTestIns(port uint32) *pb.InstanceInProto {
+	return &pb.InstanceInProto{
+		Instance: &service_manage.Instance{
+			Host: wrapperspb.String("127.0.0.1"),
+			Port: wrapperspb.UInt32(port),
+		},
+	}
+}
+
+func TestDetector_DetectInstance(t *testing.T) {
+	var gotMD metadata.MD
+	hs, port := startHealthServer(t, grpc.UnaryInterceptor(func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		gotMD, _ = metadata.FromIncomingContext(ctx)
+		return handler(ctx, req)
+	}))
+	hs.SetServingStatus("echo", healthpb.HealthCheckResponse_SERVING)
+
+	detector := &Detector{
+		cfg:     &Config{Service: "echo", Metadata: map[string]string{"x-detect": "polaris"}},
+		timeout: time.Second,
+	}
+
+	t.Run("serving", func(t *testing.T) {
+		ret, err := detector.DetectInstance(newTestIns(port), nil)
+		assert.NoError(t, err)
+		assert.True(t, ret.IsSuccess())
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING.String(), ret.GetCode())
+		assert.Equal(t, []string{"polaris"}, gotMD.Get("x-detect"))
+	})
+
+	t.Run("not_serving", func(t *testing.T) {
+		hs.SetServingStatus("echo", healthpb.HealthCheckResponse_NOT_SERVING)
+		defer hs.SetServingStatus("echo", healthpb.HealthCheckResponse_SERVING)
+		ret, err := detector.DetectInstance(newTestIns(port), nil)
+		assert.NoError(t, err)
+		assert.False(t, ret.IsSuccess())
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING.String(), ret.GetCode())
+	})
+
+	t.Run("unknown_service", func(t *testing.T) {
+		unknown := &Detector{cfg: &Config{Service: "missing"}, timeout: time.Second}
+		ret, err := unknown.DetectInstance(newTestIns(port), nil)
+		assert.NoError(t, err)
+		assert.False(t, ret.IsSuccess())
+		assert.Equal(t, "NotFound", ret.GetCode())
+	})
+
+	t.Run("rule_port", func(t *testing.T) {
+		rule := &fault_tolerance.FaultDetectRule{Port: port, Timeout: 500, Protocol: fault_tolerance.FaultDetectRule_TCP,
+			Metadata: map[string]string{healthcheck.MetadataKeyProtocol: "grpc"}}
+		ret, err := detector.DetectInstance(newTestIns(1), rule)
+		assert.NoError(t, err)
+		assert.True(t, ret.IsSuccess())
+	})
+
+	t.Run("connect_refused", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		closedPort := uint32(ln.Addr().(*net.TCPAddr).Port)
+		_ = ln.Close()
+		ret, err := detector.DetectInstance(newTestIns(closedPort), nil)
+		assert.NoError(t, err)
+		assert.False(t, ret.IsSuccess())
+		assert.Equal(t, "", ret.GetCode())
+	})
+}
@@ -0,0 +1,213 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package leastrequest
+
+import (
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	lbcommon "github.com/polarismesh/polaris-go/plugin/loadbalancer/common"
+)
+
+// 选取第二个候选实例时，与第一个候选重复的最大重试次数
+const maxPickRetry = 3
+
+var (
+	_ loadbalancer.CallResultListener = (*LoadBalancer)(nil)
+	_ loadbalancer.InflightCounter    = (*LoadBalancer)(nil)
+)
+
+// LoadBalancer 最少在途请求负载均衡插件
+// 每次从可用实例中按权重随机选出两个候选（P2C），选择 在途请求数/权重 较小的实例，
+// 选中时在途请求数加一，用户通过 UpdateServiceCallResult 上报调用结果时减一；
+// 超时未上报调用结果以及实例已下线的在途请求由负载均衡代理通过 ReleaseInflight 归还
+type LoadBalancer struct {
+	*plugin.PluginBase
+	scalableRand *rand.ScalableRand
+	log          *log.ContextLogger
+	mutex        sync.Mutex
+	// 实例ID到在途请求的映射，在途请求归零后移除
+	inflights map[string]int64
+}
+
+// Type 插件类型
+func (l *LoadBalancer) Type() common.Type {
+	return common.TypeLoadBalancer
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (l *LoadBalancer) Name() string {
+	return config.DefaultLoadBalancerLeastRequest
+}
+
+// Init 初始化插件
+func (l *LoadBalancer) Init(ctx *plugin.InitContext) error {
+	l.log = ctx.ValueCtx.GetContextLogger()
+	l.PluginBase = plugin.NewPluginBase(ctx)
+	l.scalableRand = rand.NewScalableRand()
+	l.inflights = make(map[string]int64)
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (l *LoadBalancer) Destroy() error {
+	return nil
+}
+
+// ChooseInstance 获取单个服务实例
+func (l *LoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
+	inputInstances model.ServiceInstances) (model.Instance, error) {
+	targetInstances, err := lbcommon.SelectAvailableInstanceSetFromCriteria(criteria, inputInstances)
+	if err != nil {
+		return nil, err
+	}
+	svcInstances := inputInstances.GetServiceClusters().GetServiceInstances().GetInstances()
+	first := l.pick(targetInstances, svcInstances)
+	instance := first
+	if targetInstances.Count() > 1 {
+		second := l.pickOther(targetInstances, svcInstances, first)
+		instance = l.lessLoaded(criteria, first, second)
+	}
+	count := l.acquire(instance)
+	l.log.GetBaseLogger().Debugf("[LeastRequestLoadBalancer] ChooseInstance selected instance %s (host=%s, "+
+		"port=%d), inflight=%d", instance.GetId(), instance.GetHost(), instance.GetPort(), count)
+	return instance, nil
+}
+
+// OnServiceCallResult 调用结束，释放实例的在途请求
+func (l *LoadBalancer) OnServiceCallResult(result *model.ServiceCallResult) {
+	if result == nil || result.GetCalledInstance() == nil {
+		return
+	}
+	l.release(result.GetCalledInstance(), 1)
+}
+
+// ReleaseInflight 归还实例的 count 个在途请求
+func (l *LoadBalancer) ReleaseInflight(instance model.Instance, count int64) {
+	if instance == nil || count <= 0 {
+		return
+	}
+	l.release(instance, count)
+}
+
+// GetInflight 获取实例当前的在途请求数
+func (l *LoadBalancer) GetInflight(instance model.Instance) int64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.inflights[instance.GetId()]
+}
+
+// pick 按权重随机选择一个实例
+func (l *LoadBalancer) pick(targetInstances *model.InstanceSet, svcInstances []model.Instance) model.Instance {
+	index := rand.SelectWeightedRandItem(l.scalableRand, targetInstances)
+	if index < 0 {
+		// 一般不会走到这一步，除非有并发修改，使用真随机选择
+		index = l.scalableRand.Intn(targetInstances.Count())
+	}
+	instanceIndex := targetInstances.GetInstances()[index]
+	return svcInstances[instanceIndex.Index]
+}
+
+// pickOther 按权重随机选择与 first 不同的实例，多次随机仍重复时顺序选择下一个权重非零的实例
+func (l *LoadBalancer) pickOther(targetInstances *model.InstanceSet, svcInstances []model.Instance,
+	first model.Instance) model.Instance {
+	for i := 0; i < maxPickRetry; i++ {
+		if second := l.pick(targetInstances, svcInstances); second.GetId() != first.GetId() {
+			return second
+		}
+	}
+	for _, instanceIndex := range targetInstances.GetInstances() {
+		second := svcInstances[instanceIndex.Index]
+		if second.GetId() != first.GetId() && second.GetWeight() > 0 {
+			return second
+		}
+	}
+	return first
+}
+
+// lessLoaded 选择两个候选中负载较低的实例，负载为 (在途请求数+1)/权重
+// 配置了 IgnoreHalfOpen 时，优先选择非半开的实例
+func (l *LoadBalancer) lessLoaded(criteria *loadbalancer.Criteria, first, second model.Instance) model.Instance {
+	if criteria.IgnoreHalfOpen {
+		firstHalfOpen, secondHalfOpen := isHalfOpen(first), isHalfOpen(second)
+		if firstHalfOpen != secondHalfOpen {
+			if firstHalfOpen {
+				return second
+			}
+			return first
+		}
+	}
+	firstWeight := getWeight(criteria.DynamicWeight, first)
+	secondWeight := getWeight(criteria.DynamicWeight, second)
+	l.mutex.Lock()
+	firstLoad := (l.inflights[first.GetId()] + 1) * secondWeight
+	secondLoad := (l.inflights[second.GetId()] + 1) * firstWeight
+	l.mutex.Unlock()
+	if secondLoad < firstLoad {
+		return second
+	}
+	return first
+}
+
+// acquire 实例在途请求数加一，返回加一后的值
+func (l *LoadBalancer) acquire(instance model.Instance) int64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.inflights[instance.GetId()]++
+	return l.inflights[instance.GetId()]
+}
+
+// release 实例在途请求数减 count，归零后移除
+func (l *LoadBalancer) release(instance model.Instance, count int64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	inflight, ok := l.inflights[instance.GetId()]
+	if !ok {
+		// 非本插件选出的实例，或调用结果重复上报
+		return
+	}
+	if inflight <= count {
+		delete(l.inflights, instance.GetId())
+		return
+	}
+	l.inflights[instance.GetId()] = inflight - count
+}
+
+// getWeight 获取实例权重，存在动态权重时优先使用动态权重
+func getWeight(dynamicWeights map[string]*model.InstanceWeight, instance model.Instance) int64 {
+	if w, ok := dynamicWeights[instance.GetId()]; ok {
+		return int64(w.DynamicWeight)
+	}
+	return int64(instance.GetWeight())
+}
+
+func isHalfOpen(instance model.Instance) bool {
+	status := instance.GetCircuitBreakerStatus()
+	return status != nil && status.GetStatus() == model.HalfOpen
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterPlugin(&LoadBalancer{})
+}
@@ -0,0 +1,260 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package leastrequest
+
+import (
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	"github.com/polarismesh/polaris-go/pkg/sdk"
+)
+
+// noopLogger 空日志实现，用于测试
+type noopLogger struct{}
+
+func (n *noopLogger) Tracef(format string, args ...interface{}) {}
+func (n *noopLogger) Debugf(format string, args ...interface{}) {}
+func (n *noopLogger) Infof(format string, args ...interface{})  {}
+func (n *noopLogger) Warnf(format string, args ...interface{})  {}
+func (n *noopLogger) Errorf(format string, args ...interface{}) {}
+func (n *noopLogger) Fatalf(format string, args ...interface{}) {}
+func (n *noopLogger) IsLevelEnabled(l int) bool                 { return true }
+func (n *noopLogger) SetLogLevel(l int) error                   { return nil }
+
+func newTestLoadBalancer() *LoadBalancer {
+	ctxLogger := &log.ContextLogger{}
+	log.SetBaseLogger(&noopLogger{})
+	ctxLogger.Init()
+	return &LoadBalancer{
+		scalableRand: rand.NewScalableRand(),
+		log:          ctxLogger,
+		inflights:    make(map[string]int64),
+	}
+}
+
+// fakeEngine 仅实现负载均衡代理用到的方法，其余 sdk.Engine 方法继承嵌入的 nil 接口
+type fakeEngine struct {
+	sdk.Engine
+	ctx     sdk.ValueContext
+	reports []model.InstanceGauge
+}
+
+func (f *fakeEngine) GetContext() sdk.ValueContext { return f.ctx }
+
+// SyncReportStat 记录上报的统计数据
+func (f *fakeEngine) SyncReportStat(_ model.MetricType, stat model.InstanceGauge) error {
+	f.reports = append(f.reports, stat)
+	return nil
+}
+
+func newTestInstance(id string, weight uint32, port uint32, status model.CircuitBreakerStatus) model.Instance {
+	inst := &apiservice.Instance{
+		Id:      wrapperspb.String(id),
+		Host:    wrapperspb.String("127.0.0.1"),
+		Port:    wrapperspb.UInt32(port),
+		Weight:  wrapperspb.UInt32(weight),
+		Healthy: wrapperspb.Bool(true),
+		Isolate: wrapperspb.Bool(false),
+	}
+	localValue := local.NewInstanceLocalValue()
+	if status != nil {
+		localValue.(*local.DefaultInstanceLocalValue).SetCircuitBreakerStatus(status)
+	}
+	svcKey := &model.ServiceKey{Namespace: "test-ns", Service: "test-svc"}
+	return pb.NewInstanceInProto(inst, svcKey, localValue)
+}
+
+func newCriteria(instances ...model.Instance) (*loadbalancer.Criteria, model.ServiceInstances) {
+	svcInstances := model.NewDefaultServiceInstances(model.ServiceInfo{
+		Service:   "test-svc",
+		Namespace: "test-ns",
+	}, instances)
+	cluster := model.NewCluster(svcInstances.GetServiceClusters(), nil)
+	cluster.SetReuse(false)
+	cluster.IncludeHalfOpen = true
+	return &loadbalancer.Criteria{Cluster: cluster}, svcInstances
+}
+
+func choose(t *testing.T, lb *LoadBalancer, instances ...model.Instance) model.Instance {
+	criteria, svcInstances := newCriteria(instances...)
+	instance, err := lb.ChooseInstance(criteria, svcInstances)
+	if err != nil {
+		t.Fatalf("ChooseInstance 返回错误: %v", err)
+	}
+	return instance
+}
+
+func TestChooseInstance_选择在途请求较少的实例(t *testing.T) {
+	lb := newTestLoadBalancer()
+	busy := newTestInstance("busy", 100, 8081, nil)
+	idle := newTestInstance("idle", 100, 8082, nil)
+	lb.inflights[busy.GetId()] = 10
+
+	// 两个实例时P2C总是同时比较二者，空闲实例会被连续选中直到负载持平
+	for i := 0; i < 10; i++ {
+		if selected := choose(t, lb, busy, idle); selected.GetId() != idle.GetId() {
+			t.Fatalf("第 %d 次选择了 %s，期望 idle", i, selected.GetId())
+		}
+	}
+	if got := lb.GetInflight(idle); got != 10 {
+		t.Fatalf("idle 在途请求数 %d，期望 10", got)
+	}
+}
+
+func TestChooseInstance_按权重比较负载(t *testing.T) {
+	lb := newTestLoadBalancer()
+	small := newTestInstance("small", 100, 8081, nil)
+	large := newTestInstance("large", 300, 8082, nil)
+
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		counts[choose(t, lb, small, large).GetId()]++
+	}
+	// 请求只选出不结束时，在途请求数按权重 1:3 分布，负载相同时随机选择，允许相差一次
+	if counts["small"] < 99 || counts["small"] > 101 {
+		t.Fatalf("选择次数 %v，期望 small≈100 large≈300", counts)
+	}
+}
+
+func TestChooseInstance_忽略半开实例(t *testing.T) {
+	lb := newTestLoadBalancer()
+	halfOpen := newTestInstance("half-open", 100, 8081, model.NewHalfOpenStatus("test", time.Now(), 10))
+	closed := newTestInstance("closed", 100, 8082, nil)
+	lb.inflights[closed.GetId()] = 100
+
+	criteria, svcInstances := newCriteria(halfOpen, closed)
+	criteria.IgnoreHalfOpen = true
+	instance, err := lb.ChooseInstance(criteria, svcInstances)
+	if err != nil {
+		t.Fatalf("ChooseInstance 返回错误: %v", err)
+	}
+	if instance.GetId() != closed.GetId() {
+		t.Fatalf("IgnoreHalfOpen 时选择了 %s，期望 closed", instance.GetId())
+	}
+
+	// 未设置 IgnoreHalfOpen 时，半开实例按负载参与选择
+	if selected := choose(t, lb, halfOpen, closed); selected.GetId() != halfOpen.GetId() {
+		t.Fatalf("选择了 %s，期望 half-open", selected.GetId())
+	}
+}
+
+func TestChooseInstance_单实例(t *testing.T) {
+	lb := newTestLoadBalancer()
+	only := newTestInstance("only", 100, 8081, nil)
+	for i := 0; i < 3; i++ {
+		if selected := choose(t, lb, only); selected.GetId() != only.GetId() {
+			t.Fatalf("选择了 %s，期望 only", selected.GetId())
+		}
+	}
+	if got := lb.GetInflight(only); got != 3 {
+		t.Fatalf("在途请求数 %d，期望 3", got)
+	}
+}
+
+func TestOnServiceCallResult_释放在途请求(t *testing.T) {
+	lb := newTestLoadBalancer()
+	inst := newTestInstance("inst", 100, 8081, nil)
+	choose(t, lb, inst)
+	choose(t, lb, inst)
+
+	result := &model.ServiceCallResult{CalledInstance: inst, RetStatus: model.RetSuccess}
+	lb.OnServiceCallResult(result)
+	if got := lb.GetInflight(inst); got != 1 {
+		t.Fatalf("在途请求数 %d，期望 1", got)
+	}
+	lb.OnServiceCallResult(result)
+	// 重复上报不会使在途请求数变为负数
+	lb.OnServiceCallResult(result)
+	if got := lb.GetInflight(inst); got != 0 {
+		t.Fatalf("在途请求数 %d，期望 0", got)
+	}
+	if len(lb.inflights) != 0 {
+		t.Fatalf("在途请求归零后应移除记录，实际 %v", lb.inflights)
+	}
+	lb.OnServiceCallResult(&model.ServiceCallResult{})
+}
+
+// TestProxy_只回调选出实例的插件 验证调用结果只分发给选出该实例的负载均衡插件
+// 测试场景：两个 leastRequest 代理，实例由代理 a 选出，调用结果同时分发给两个代理
+// 前置条件：代理 b 的插件中该实例已有在途请求（模拟其他服务通过 b 选出的请求）
+// 预期结果：代理 a 的在途请求数释放；代理 b 忽略该结果，在途请求数不变
+func TestProxy_只回调选出实例的插件(t *testing.T) {
+	inst := newTestInstance("inst", 100, 8081, nil)
+	engineA := &fakeEngine{ctx: sdk.NewValueContext()}
+	engineB := &fakeEngine{ctx: sdk.NewValueContext()}
+	lbA, lbB := newTestLoadBalancer(), newTestLoadBalancer()
+	lbB.inflights[inst.GetId()] = 1
+	proxyA, proxyB := &loadbalancer.Proxy{}, &loadbalancer.Proxy{}
+	proxyA.SetRealPlugin(lbA, engineA)
+	proxyB.SetRealPlugin(lbB, engineB)
+
+	criteria, svcInstances := newCriteria(inst)
+	if _, err := proxyA.ChooseInstance(criteria, svcInstances); err != nil {
+		t.Fatalf("ChooseInstance 返回错误: %v", err)
+	}
+	if got := lbA.GetInflight(inst); got != 1 {
+		t.Fatalf("代理 a 在途请求数 %d，期望 1", got)
+	}
+
+	result := &model.ServiceCallResult{CalledInstance: inst, RetStatus: model.RetSuccess}
+	for _, listener := range []loadbalancer.CallResultListener{proxyA, proxyB} {
+		listener.OnServiceCallResult(result)
+	}
+	if got := lbA.GetInflight(inst); got != 0 {
+		t.Fatalf("代理 a 在途请求数 %d，期望 0", got)
+	}
+	if got := lbB.GetInflight(inst); got != 1 {
+		t.Fatalf("代理 b 在途请求数 %d，期望 1", got)
+	}
+	// 重复上报同一结果不会再次回调代理 a
+	lbA.inflights[inst.GetId()] = 1
+	proxyA.OnServiceCallResult(result)
+	if got := lbA.GetInflight(inst); got != 1 {
+		t.Fatalf("重复上报不应回调代理 a，在途请求数 %d，期望 1", got)
+	}
+	if len(engineA.reports)+len(engineB.reports) != 0 {
+		t.Fatalf("在途请求数按周期上报，选出实例与调用结束时不应上报")
+	}
+}
+
+// TestReleaseInflight 验证代理回收请求时归还在途请求
+// 测试场景：实例有 3 个在途请求，分两次归还 2 个
+// 预期结果：第一次归还后剩 1 个，超出在途请求数的归还会移除记录而不会变为负数
+func TestReleaseInflight(t *testing.T) {
+	lb := newTestLoadBalancer()
+	inst := newTestInstance("inst", 100, 8081, nil)
+	lb.inflights[inst.GetId()] = 3
+	lb.ReleaseInflight(inst, 2)
+	if got := lb.GetInflight(inst); got != 1 {
+		t.Fatalf("在途请求数 %d，期望 1", got)
+	}
+	lb.ReleaseInflight(inst, 2)
+	if _, ok := lb.inflights[inst.GetId()]; ok {
+		t.Fatalf("在途请求归零后应移除记录，实际 %v", lb.inflights)
+	}
+	lb.ReleaseInflight(nil, 1)
+}
@@ -0,0 +1,74 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package peakewma
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	defaultDecayTime   = 10 * time.Second
+	defaultRTT         = 30 * time.Millisecond
+	defaultChoiceCount = 2
+)
+
+// Config 时延感知负载均衡配置
+type Config struct {
+	// DecayTime 时延EWMA的衰减时间常数，越大历史时延的权重越高；长时间未更新的时延会按该常数回落到 DefaultRTT
+	DecayTime *time.Duration `yaml:"decayTime" json:"decayTime"`
+	// DefaultRTT 尚未统计到时延的实例使用的预估时延
+	DefaultRTT *time.Duration `yaml:"defaultRtt" json:"defaultRtt"`
+	// ChoiceCount 每次随机选取的候选实例数
+	ChoiceCount int `yaml:"choiceCount" json:"choiceCount"`
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if nil == c.DecayTime {
+		c.DecayTime = model.ToDurationPtr(defaultDecayTime)
+	}
+	if nil == c.DefaultRTT {
+		c.DefaultRTT = model.ToDurationPtr(defaultRTT)
+	}
+	if c.ChoiceCount == 0 {
+		c.ChoiceCount = defaultChoiceCount
+	}
+}
+
+// Verify 校验配置值
+func (c *Config) Verify() error {
+	if nil == c.DecayTime {
+		return fmt.Errorf("decayTime not configured")
+	}
+	if *c.DecayTime <= 0 {
+		return fmt.Errorf("invalid decayTime: %v, it must greater than 0", *c.DecayTime)
+	}
+	if nil == c.DefaultRTT {
+		return fmt.Errorf("defaultRtt not configured")
+	}
+	if *c.DefaultRTT <= 0 {
+		return fmt.Errorf("invalid defaultRtt: %v, it must greater than 0", *c.DefaultRTT)
+	}
+	if c.ChoiceCount < 2 {
+		return fmt.Errorf("invalid choiceCount: %d, it must greater than or equal to 2", c.ChoiceCount)
+	}
+	return nil
+}
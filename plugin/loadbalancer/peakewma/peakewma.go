@@ -0,0 +1,291 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package peakewma
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	lbcommon "github.com/polarismesh/polaris-go/plugin/loadbalancer/common"
+)
+
+const (
+	// 选取候选实例时，每个候选的最大随机次数
+	maxPickRetry = 3
+	// 空闲且时延已衰减超过该倍数的衰减时间常数的实例统计会被清理
+	expireDecayTimes = 5
+)
+
+var (
+	_ loadbalancer.CallResultListener = (*LoadBalancer)(nil)
+	_ loadbalancer.InflightCounter    = (*LoadBalancer)(nil)
+)
+
+// LoadBalancer 时延感知负载均衡插件
+// 为每个实例维护调用时延的峰值EWMA：时延高于当前值时直接取峰值，否则按时间衰减平滑；
+// 每次按权重随机选出若干候选，选择 时延EWMA×(在途请求数+1)/权重 最小的实例。
+// 长时间没有新时延的实例，其时延估计会按衰减时间常数回落到 DefaultRTT，使慢实例恢复后能重新获得流量
+type LoadBalancer struct {
+	*plugin.PluginBase
+	cfg          *Config
+	scalableRand *rand.ScalableRand
+	log          *log.ContextLogger
+	now          func() time.Time
+	mutex        sync.Mutex
+	// 实例ID到统计数据的映射
+	stats     map[string]*instanceStat
+	lastSweep time.Time
+}
+
+// instanceStat 单个实例的时延及在途请求统计
+type instanceStat struct {
+	// 时延峰值EWMA，单位纳秒
+	ewma float64
+	// 时延最后一次更新的时间
+	stamp    time.Time
+	inflight int64
+}
+
+// Type 插件类型
+func (l *LoadBalancer) Type() common.Type {
+	return common.TypeLoadBalancer
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (l *LoadBalancer) Name() string {
+	return config.DefaultLoadBalancerPeakEWMA
+}
+
+// Init 初始化插件
+func (l *LoadBalancer) Init(ctx *plugin.InitContext) error {
+	l.log = ctx.ValueCtx.GetContextLogger()
+	l.PluginBase = plugin.NewPluginBase(ctx)
+	l.cfg = ctx.Config.GetConsumer().GetLoadbalancer().GetPluginConfig(l.Name()).(*Config)
+	l.scalableRand = rand.NewScalableRand()
+	l.now = ctx.ValueCtx.Now
+	l.stats = make(map[string]*instanceStat)
+	l.lastSweep = l.now()
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (l *LoadBalancer) Destroy() error {
+	return nil
+}
+
+// ChooseInstance 获取单个服务实例
+func (l *LoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
+	inputInstances model.ServiceInstances) (model.Instance, error) {
+	targetInstances, err := lbcommon.SelectAvailableInstanceSetFromCriteria(criteria, inputInstances)
+	if err != nil {
+		return nil, err
+	}
+	candidates := l.pickCandidates(targetInstances, inputInstances.GetServiceClusters().GetServiceInstances())
+	if len(candidates) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"instances of %s all weight 0 in load balance", inputInstances.GetServiceClusters().GetServiceKey())
+	}
+	now := l.now()
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	var (
+		instance model.Instance
+		minCost  = math.Inf(1)
+	)
+	for _, candidate := range candidates {
+		cost := l.costLocked(criteria, candidate, now)
+		if instance == nil || cost < minCost {
+			instance = candidate
+			minCost = cost
+		}
+	}
+	l.statLocked(instance.GetId()).inflight++
+	l.log.GetBaseLogger().Debugf("[PeakEWMALoadBalancer] ChooseInstance selected instance %s (host=%s, "+
+		"port=%d) from %d candidates, cost=%f", instance.GetId(), instance.GetHost(), instance.GetPort(),
+		len(candidates), minCost)
+	return instance, nil
+}
+
+// OnServiceCallResult 调用结束，释放在途请求并更新实例的时延EWMA
+func (l *LoadBalancer) OnServiceCallResult(result *model.ServiceCallResult) {
+	if result == nil || result.GetCalledInstance() == nil {
+		return
+	}
+	now := l.now()
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	stat := l.statLocked(result.GetCalledInstance().GetId())
+	if stat.inflight > 0 {
+		stat.inflight--
+	}
+	if delay := result.GetDelay(); delay != nil {
+		l.observeLocked(stat, float64(*delay), now)
+	}
+	l.sweepLocked(now)
+}
+
+// ReleaseInflight 归还实例的 count 个在途请求，不更新时延
+func (l *LoadBalancer) ReleaseInflight(instance model.Instance, count int64) {
+	if instance == nil || count <= 0 {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	stat, ok := l.stats[instance.GetId()]
+	if !ok {
+		return
+	}
+	stat.inflight -= count
+	if stat.inflight < 0 {
+		stat.inflight = 0
+	}
+}
+
+// GetInflight 获取实例当前的在途请求数
+func (l *LoadBalancer) GetInflight(instance model.Instance) int64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if stat, ok := l.stats[instance.GetId()]; ok {
+		return stat.inflight
+	}
+	return 0
+}
+
+// pickCandidates 按权重随机选出不重复的候选实例，可用实例不多于候选数时全部作为候选
+func (l *LoadBalancer) pickCandidates(targetInstances *model.InstanceSet,
+	svcInstances model.ServiceInstances) []model.Instance {
+	instances := svcInstances.GetInstances()
+	if targetInstances.Count() <= l.cfg.ChoiceCount {
+		candidates := make([]model.Instance, 0, targetInstances.Count())
+		for _, instanceIndex := range targetInstances.GetInstances() {
+			if instance := instances[instanceIndex.Index]; instance.GetWeight() > 0 {
+				candidates = append(candidates, instance)
+			}
+		}
+		return candidates
+	}
+	candidates := make([]model.Instance, 0, l.cfg.ChoiceCount)
+	picked := make(map[int]struct{}, l.cfg.ChoiceCount)
+	for i := 0; i < l.cfg.ChoiceCount*maxPickRetry && len(candidates) < l.cfg.ChoiceCount; i++ {
+		index := rand.SelectWeightedRandItem(l.scalableRand, targetInstances)
+		if index < 0 {
+			// 一般不会走到这一步，除非有并发修改，使用真随机选择
+			index = l.scalableRand.Intn(targetInstances.Count())
+		}
+		if _, ok := picked[index]; ok {
+			continue
+		}
+		picked[index] = struct{}{}
+		candidates = append(candidates, instances[targetInstances.GetInstances()[index].Index])
+	}
+	return candidates
+}
+
+// costLocked 计算实例的负载代价：时延EWMA×(在途请求数+1)/权重
+func (l *LoadBalancer) costLocked(criteria *loadbalancer.Criteria, instance model.Instance, now time.Time) float64 {
+	weight := getWeight(criteria.DynamicWeight, instance)
+	if weight <= 0 {
+		return math.Inf(1)
+	}
+	ewma := float64(*l.cfg.DefaultRTT)
+	var inflight int64
+	if stat, ok := l.stats[instance.GetId()]; ok {
+		ewma = l.decayedLocked(stat, now)
+		inflight = stat.inflight
+	}
+	return ewma * float64(inflight+1) / float64(weight)
+}
+
+// decayedLocked 按距上次更新的时间将时延EWMA向 DefaultRTT 衰减
+func (l *LoadBalancer) decayedLocked(stat *instanceStat, now time.Time) float64 {
+	defaultRTT := float64(*l.cfg.DefaultRTT)
+	if stat.stamp.IsZero() {
+		return defaultRTT
+	}
+	elapsed := now.Sub(stat.stamp)
+	if elapsed <= 0 {
+		return stat.ewma
+	}
+	w := math.Exp(-float64(elapsed) / float64(*l.cfg.DecayTime))
+	return defaultRTT + (stat.ewma-defaultRTT)*w
+}
+
+// observeLocked 记录一次调用时延，高于当前EWMA时直接取峰值
+func (l *LoadBalancer) observeLocked(stat *instanceStat, rtt float64, now time.Time) {
+	if stat.stamp.IsZero() {
+		stat.ewma = rtt
+		stat.stamp = now
+		return
+	}
+	prev := stat.ewma
+	elapsed := now.Sub(stat.stamp)
+	if elapsed > 0 {
+		prev = l.decayedLocked(stat, now)
+	}
+	if rtt > prev {
+		stat.ewma = rtt
+	} else {
+		w := math.Exp(-float64(elapsed) / float64(*l.cfg.DecayTime))
+		stat.ewma = prev*w + rtt*(1-w)
+	}
+	stat.stamp = now
+}
+
+// sweepLocked 每个衰减时间常数清理一次空闲且长时间未更新的实例统计，避免已下线实例的数据常驻
+func (l *LoadBalancer) sweepLocked(now time.Time) {
+	decayTime := *l.cfg.DecayTime
+	if now.Sub(l.lastSweep) < decayTime {
+		return
+	}
+	l.lastSweep = now
+	for id, stat := range l.stats {
+		if stat.inflight == 0 && now.Sub(stat.stamp) > expireDecayTimes*decayTime {
+			delete(l.stats, id)
+		}
+	}
+}
+
+func (l *LoadBalancer) statLocked(id string) *instanceStat {
+	stat, ok := l.stats[id]
+	if !ok {
+		stat = &instanceStat{}
+		l.stats[id] = stat
+	}
+	return stat
+}
+
+// getWeight 获取实例权重，存在动态权重时优先使用动态权重
+func getWeight(dynamicWeights map[string]*model.InstanceWeight, instance model.Instance) int64 {
+	if w, ok := dynamicWeights[instance.GetId()]; ok {
+		return int64(w.DynamicWeight)
+	}
+	return int64(instance.GetWeight())
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&LoadBalancer{}, &Config{})
+}
@@ -0,0 +1,228 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package peakewma
+
+import (
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+)
+
+// noopLogger 空日志实现，用于测试
+type noopLogger struct{}
+
+func (n *noopLogger) Tracef(format string, args ...interface{}) {}
+func (n *noopLogger) Debugf(format string, args ...interface{}) {}
+func (n *noopLogger) Infof(format string, args ...interface{})  {}
+func (n *noopLogger) Warnf(format string, args ...interface{})  {}
+func (n *noopLogger) Errorf(format string, args ...interface{}) {}
+func (n *noopLogger) Fatalf(format string, args ...interface{}) {}
+func (n *noopLogger) IsLevelEnabled(l int) bool                 { return true }
+func (n *noopLogger) SetLogLevel(l int) error                   { return nil }
+
+// fakeClock 可手动推进的时钟
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestLoadBalancer(clock *fakeClock) *LoadBalancer {
+	ctxLogger := &log.ContextLogger{}
+	log.SetBaseLogger(&noopLogger{})
+	ctxLogger.Init()
+	cfg := &Config{}
+	cfg.SetDefault()
+	return &LoadBalancer{
+		cfg:          cfg,
+		scalableRand: rand.NewScalableRand(),
+		log:          ctxLogger,
+		now:          clock.Now,
+		stats:        make(map[string]*instanceStat),
+		lastSweep:    clock.Now(),
+	}
+}
+
+func newTestInstance(id string, weight uint32, port uint32) model.Instance {
+	inst := &apiservice.Instance{
+		Id:      wrapperspb.String(id),
+		Host:    wrapperspb.String("127.0.0.1"),
+		Port:    wrapperspb.UInt32(port),
+		Weight:  wrapperspb.UInt32(weight),
+		Healthy: wrapperspb.Bool(true),
+		Isolate: wrapperspb.Bool(false),
+	}
+	svcKey := &model.ServiceKey{Namespace: "test-ns", Service: "test-svc"}
+	return pb.NewInstanceInProto(inst, svcKey, local.NewInstanceLocalValue())
+}
+
+func choose(t *testing.T, lb *LoadBalancer, instances ...model.Instance) model.Instance {
+	svcInstances := model.NewDefaultServiceInstances(model.ServiceInfo{
+		Service:   "test-svc",
+		Namespace: "test-ns",
+	}, instances)
+	cluster := model.NewCluster(svcInstances.GetServiceClusters(), nil)
+	cluster.SetReuse(false)
+	instance, err := lb.ChooseInstance(&loadbalancer.Criteria{Cluster: cluster}, svcInstances)
+	if err != nil {
+		t.Fatalf("ChooseInstance 返回错误: %v", err)
+	}
+	return instance
+}
+
+func complete(lb *LoadBalancer, instance model.Instance, delay time.Duration) {
+	lb.OnServiceCallResult(&model.ServiceCallResult{
+		CalledInstance: instance,
+		RetStatus:      model.RetSuccess,
+		Delay:          &delay,
+	})
+}
+
+func TestChooseInstance_选择时延较低的实例(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	lb := newTestLoadBalancer(clock)
+	slow := newTestInstance("slow", 100, 8081)
+	fast := newTestInstance("fast", 100, 8082)
+	complete(lb, slow, 500*time.Millisecond)
+	complete(lb, fast, 10*time.Millisecond)
+
+	for i := 0; i < 20; i++ {
+		selected := choose(t, lb, slow, fast)
+		if selected.GetId() != fast.GetId() {
+			t.Fatalf("第 %d 次选择了 %s，期望 fast", i, selected.GetId())
+		}
+		complete(lb, selected, 10*time.Millisecond)
+	}
+	if got := lb.GetInflight(fast); got != 0 {
+		t.Fatalf("fast 在途请求数 %d，期望 0", got)
+	}
+}
+
+func TestChooseInstance_在途请求与权重(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	lb := newTestLoadBalancer(clock)
+	small := newTestInstance("small", 100, 8081)
+	large := newTestInstance("large", 300, 8082)
+
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		counts[choose(t, lb, small, large).GetId()]++
+	}
+	// 时延相同且请求只选出不结束时，在途请求数按权重 1:3 分布，负载相同时随机选择，允许相差一次
+	if counts["small"] < 99 || counts["small"] > 101 {
+		t.Fatalf("选择次数 %v，期望 small≈100 large≈300", counts)
+	}
+}
+
+func TestObserve_峰值与衰减(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	lb := newTestLoadBalancer(clock)
+	inst := newTestInstance("inst", 100, 8081)
+	decayTime := *lb.cfg.DecayTime
+	defaultRTT := float64(*lb.cfg.DefaultRTT)
+
+	complete(lb, inst, 100*time.Millisecond)
+	stat := lb.stats[inst.GetId()]
+	if stat.ewma != float64(100*time.Millisecond) {
+		t.Fatalf("首次时延 %v，期望 100ms", time.Duration(stat.ewma))
+	}
+	// 时延升高时直接取峰值
+	clock.now = clock.now.Add(time.Millisecond)
+	complete(lb, inst, 300*time.Millisecond)
+	if stat.ewma != float64(300*time.Millisecond) {
+		t.Fatalf("峰值时延 %v，期望 300ms", time.Duration(stat.ewma))
+	}
+	// 时延降低时按时间平滑
+	clock.now = clock.now.Add(decayTime)
+	complete(lb, inst, 100*time.Millisecond)
+	if stat.ewma <= float64(100*time.Millisecond) || stat.ewma >= float64(300*time.Millisecond) {
+		t.Fatalf("平滑后时延 %v，期望介于 100ms 与 300ms 之间", time.Duration(stat.ewma))
+	}
+	// 长时间未更新时回落到默认时延
+	clock.now = clock.now.Add(20 * decayTime)
+	if got := lb.decayedLocked(stat, clock.now); got-defaultRTT > float64(time.Millisecond) {
+		t.Fatalf("衰减后时延 %v，期望接近 %v", time.Duration(got), *lb.cfg.DefaultRTT)
+	}
+}
+
+func TestChooseInstance_慢实例恢复后重新获得流量(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	lb := newTestLoadBalancer(clock)
+	slow := newTestInstance("slow", 100, 8081)
+	fast := newTestInstance("fast", 100, 8082)
+	complete(lb, slow, 2*time.Second)
+	complete(lb, fast, 20*time.Millisecond)
+	if selected := choose(t, lb, slow, fast); selected.GetId() != fast.GetId() {
+		t.Fatalf("选择了 %s，期望 fast", selected.GetId())
+	}
+	complete(lb, fast, 40*time.Millisecond)
+
+	// slow 的时延长时间未更新，回落到默认时延后低于 fast 的时延
+	clock.now = clock.now.Add(10 * *lb.cfg.DecayTime)
+	complete(lb, fast, 40*time.Millisecond)
+	if selected := choose(t, lb, slow, fast); selected.GetId() != slow.GetId() {
+		t.Fatalf("选择了 %s，期望 slow", selected.GetId())
+	}
+}
+
+func TestSweep_清理过期统计(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	lb := newTestLoadBalancer(clock)
+	idle := newTestInstance("idle", 100, 8081)
+	busy := newTestInstance("busy", 100, 8082)
+	complete(lb, idle, 10*time.Millisecond)
+	choose(t, lb, busy)
+
+	clock.now = clock.now.Add(10 * *lb.cfg.DecayTime)
+	other := newTestInstance("other", 100, 8083)
+	complete(lb, other, 10*time.Millisecond)
+	if _, ok := lb.stats[idle.GetId()]; ok {
+		t.Fatal("空闲实例的过期统计应被清理")
+	}
+	if got := lb.GetInflight(busy); got != 1 {
+		t.Fatalf("存在在途请求的实例统计不应被清理，在途请求数 %d", got)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	cfg := &Config{}
+	cfg.SetDefault()
+	if err := cfg.Verify(); err != nil {
+		t.Fatalf("默认配置校验失败: %v", err)
+	}
+	cfg.ChoiceCount = 1
+	if err := cfg.Verify(); err == nil {
+		t.Fatal("choiceCount 小于 2 时应校验失败")
+	}
+	cfg.ChoiceCount = 2
+	cfg.DecayTime = model.ToDurationPtr(0)
+	if err := cfg.Verify(); err == nil {
+		t.Fatal("decayTime 为 0 时应校验失败")
+	}
+}
@@ -18,7 +18,9 @@
 package common
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -145,6 +147,22 @@ func (cph *CachePersistHandler) LoadMessageFromFile(relativeFile string, message
 // 从绝对文件中加载缓存
 func (cph *CachePersistHandler) loadMessageFromAbsoluteFile(cacheFile string, message proto.Message,
 	maxRetry int) error {
+	return cph.loadFromAbsoluteFile(cacheFile, maxRetry, func(reader io.Reader) error {
+		return jsonpb.Unmarshal(reader, message)
+	})
+}
+
+// LoadDataFromFile 从相对文件中读取 JSON 数据并解码到 value，解码失败时按读重试次数重试
+func (cph *CachePersistHandler) LoadDataFromFile(relativeFile string, value interface{}) error {
+	absFile := filepath.Join(cph.persistDir, relativeFile)
+	return cph.loadFromAbsoluteFile(absFile, cph.maxReadRetry, func(reader io.Reader) error {
+		return json.NewDecoder(reader).Decode(value)
+	})
+}
+
+// 从绝对文件中加载数据，decode 负责具体的解码
+func (cph *CachePersistHandler) loadFromAbsoluteFile(cacheFile string, maxRetry int,
+	decode func(io.Reader) error) error {
 	cph.logCtx.GetBaseLogger().Infof("Start to load cache from %s", cacheFile)
 	var lastErr error
 	var retryTimes int
@@ -155,7 +173,7 @@ func (cph *CachePersistHandler) loadMessageFromAbsoluteFile(cacheFile string, me
 			// 文件打开失败的话，重试没有意义，直接失败
 			break
 		}
-		err = jsonpb.Unmarshal(cacheJson, message)
+		err = decode(cacheJson)
 		_ = cacheJson.Close()
 		if err != nil {
 			lastErr = multierror.Prefix(err, "Fail to unmarshal file cache: ")
@@ -218,14 +236,20 @@ func (cph *CachePersistHandler) DeleteCacheFromFile(fileName string) {
 // SaveMessageToFile 按服务来进行缓存存储
 func (cph *CachePersistHandler) SaveMessageToFile(fileName string, svcResp proto.Message) {
 	fileToAdd := filepath.Join(cph.persistDir, fileName)
-	cph.logCtx.GetBaseLogger().Infof("Start to save cache to file %s", fileToAdd)
 	msg, err := cph.marshaler.MarshalToString(svcResp)
 	if err != nil {
 		cph.logCtx.GetBaseLogger().Warnf("Fail to marshal the service response for %s", fileToAdd)
 		return
 	}
+	cph.SaveDataToFile(fileName, []byte(msg))
+}
+
+// SaveDataToFile 将已编码的数据写入持久化目录下的相对文件，写入失败时按写重试次数重试
+func (cph *CachePersistHandler) SaveDataToFile(fileName string, data []byte) {
+	fileToAdd := filepath.Join(cph.persistDir, fileName)
+	cph.logCtx.GetBaseLogger().Infof("Start to save cache to file %s", fileToAdd)
 	for retryTimes := 0; retryTimes <= cph.maxWriteRetry; retryTimes++ {
-		err = cph.doWriteFile(fileToAdd, []byte(msg))
+		err := cph.doWriteFile(fileToAdd, data)
 		if err != nil {
 			if retryTimes > 0 {
 				cph.logCtx.GetBaseLogger().Warnf("Fail to write cache file %s, error: %s,"+
@@ -239,6 +263,16 @@ func (cph *CachePersistHandler) SaveMessageToFile(fileName string, svcResp proto
 	}
 }
 
+// ListFiles 列出持久化目录下匹配 pattern 的文件名（不含目录）
+func (cph *CachePersistHandler) ListFiles(pattern string) []string {
+	files, _ := filepath.Glob(filepath.Join(cph.persistDir, pattern))
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, filepath.Base(file))
+	}
+	return names
+}
+
 // 实际写文件
 func (cph *CachePersistHandler) doWriteFile(cacheFile string, msg []byte) error {
 	tempFileName := cacheFile + ".tmp"
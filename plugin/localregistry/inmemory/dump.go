@@ -0,0 +1,137 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/modern-go/reflect2"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	lrplug "github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+var _ lrplug.CacheDumper = (*LocalCache)(nil)
+
+// DumpCache 按过滤条件导出缓存条目，结果按命名空间、服务名、类型排序
+func (g *LocalCache) DumpCache(filter lrplug.CacheDumpFilter) []*lrplug.CacheEntry {
+	entries := make([]*lrplug.CacheEntry, 0, 8)
+	now := clock.GetClock().Now()
+	g.serviceMap.Range(func(k, v interface{}) bool {
+		svcKey := k.(model.ServiceEventKey)
+		if !matchDumpFilter(svcKey, filter) {
+			return true
+		}
+		entries = append(entries, v.(*CacheObject).dump(now))
+		return true
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Namespace != entries[j].Namespace {
+			return entries[i].Namespace < entries[j].Namespace
+		}
+		if entries[i].Service != entries[j].Service {
+			return entries[i].Service < entries[j].Service
+		}
+		return entries[i].Type < entries[j].Type
+	})
+	return entries
+}
+
+func matchDumpFilter(svcKey model.ServiceEventKey, filter lrplug.CacheDumpFilter) bool {
+	if filter.Namespace != "" && filter.Namespace != svcKey.Namespace {
+		return false
+	}
+	if filter.Service != "" && filter.Service != svcKey.Service {
+		return false
+	}
+	if filter.Type != "" && !strings.EqualFold(filter.Type, svcKey.Type.String()) {
+		return false
+	}
+	return true
+}
+
+// dump 生成缓存对象的快照
+func (s *CacheObject) dump(now time.Time) *lrplug.CacheEntry {
+	entry := &lrplug.CacheEntry{
+		Namespace:     s.serviceValueKey.Namespace,
+		Service:       s.serviceValueKey.Service,
+		Type:          s.serviceValueKey.Type.String(),
+		RemoteUpdated: atomic.LoadUint32(&s.hasRemoteUpdated) > 0,
+		Invalid:       s.IsInValid(),
+		CreateTime:    s.createTime,
+		LastVisitTime: time.Unix(0, atomic.LoadInt64(&s.lastVisitTime)),
+	}
+	if updateTime := atomic.LoadInt64(&s.updateTime); updateTime > 0 {
+		entry.UpdateTime = time.Unix(0, updateTime)
+		entry.CacheAgeSeconds = now.Sub(entry.UpdateTime).Seconds()
+	}
+	value := s.LoadValue(false)
+	if reflect2.IsNil(value) {
+		return entry
+	}
+	regValue := value.(model.RegistryValue)
+	entry.Revision = regValue.GetRevision()
+	entry.Initialized = regValue.IsInitialized()
+	entry.NotExists = regValue.IsNotExists()
+	switch v := value.(type) {
+	case *pb.ServiceInstancesInProto:
+		entry.CacheLoaded = v.IsCacheLoaded()
+		entry.Instances = dumpInstances(v.GetInstances())
+	case *pb.ServicesProto:
+		entry.CacheLoaded = atomic.LoadInt32(&v.CacheLoaded) > 0
+		entry.Services = v.GetValue()
+	case *pb.ServiceRuleInProto:
+		entry.CacheLoaded = v.IsCacheLoaded()
+		if err := v.GetValidateError(); err != nil {
+			entry.ValidateError = err.Error()
+		}
+		if msg, ok := v.GetValue().(proto.Message); ok && !reflect2.IsNil(msg) {
+			buf := &bytes.Buffer{}
+			if err := (&jsonpb.Marshaler{}).Marshal(buf, msg); err == nil {
+				entry.Rule = buf.Bytes()
+			}
+		}
+	}
+	return entry
+}
+
+func dumpInstances(instances []model.Instance) []lrplug.CacheInstance {
+	ret := make([]lrplug.CacheInstance, 0, len(instances))
+	for _, inst := range instances {
+		ret = append(ret, lrplug.CacheInstance{
+			ID:       inst.GetId(),
+			Host:     inst.GetHost(),
+			Port:     inst.GetPort(),
+			Protocol: inst.GetProtocol(),
+			Version:  inst.GetVersion(),
+			Weight:   inst.GetWeight(),
+			Healthy:  inst.IsHealthy(),
+			Isolated: inst.IsIsolated(),
+			Metadata: inst.GetMetadata(),
+		})
+	}
+	return ret
+}
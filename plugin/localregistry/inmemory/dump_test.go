@@ -0,0 +1,139 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	lrplug "github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+// newDumpTestCache 构造仅包含 serviceMap 的本地缓存，写入一个服务的实例与路由规则
+func newDumpTestCache(t *testing.T) *LocalCache {
+	if log.GetBaseLogger() == nil {
+		log.SetBaseLogger(&recordingLogger{})
+	}
+	logCtx := &log.ContextLogger{}
+	logCtx.Init()
+	cache := &LocalCache{serviceMap: &sync.Map{}, logCtx: logCtx}
+	svc := &apiservice.Service{
+		Namespace: wrapperspb.String("default"),
+		Name:      wrapperspb.String("svc-a"),
+		Revision:  wrapperspb.String("inst-rev"),
+	}
+
+	instKey := &model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: "default", Service: "svc-a"},
+		Type:       model.EventInstances,
+	}
+	instObj := NewCacheObject(CacheHandlers{}, cache, instKey)
+	instances := pb.NewServiceInstancesInProto(&apiservice.DiscoverResponse{
+		Type:    apiservice.DiscoverResponse_INSTANCE,
+		Service: svc,
+		Instances: []*apiservice.Instance{{
+			Id:      wrapperspb.String("ins-1"),
+			Host:    wrapperspb.String("127.0.0.1"),
+			Port:    wrapperspb.UInt32(8080),
+			Weight:  wrapperspb.UInt32(100),
+			Healthy: wrapperspb.Bool(true),
+		}},
+	}, func(string) local.InstanceLocalValue { return local.NewInstanceLocalValue() },
+		nil, instObj.svcLocalValue, log.GetBaseLogger())
+	atomic.StoreInt32(&instances.CacheLoaded, 1)
+	instObj.SetValue(instances)
+	cache.serviceMap.Store(*instKey, instObj)
+
+	ruleKey := &model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: "default", Service: "svc-a"},
+		Type:       model.EventRouting,
+	}
+	ruleObj := NewCacheObject(CacheHandlers{}, cache, ruleKey)
+	ruleObj.SetValue(pb.NewServiceRuleInProto(&apiservice.DiscoverResponse{
+		Type:    apiservice.DiscoverResponse_ROUTING,
+		Service: svc,
+		Routing: &apitraffic.Routing{
+			Namespace: wrapperspb.String("default"),
+			Service:   wrapperspb.String("svc-a"),
+			Revision:  wrapperspb.String("route-rev"),
+		},
+	}, log.GetBaseLogger()))
+	cache.serviceMap.Store(*ruleKey, ruleObj)
+
+	otherKey := &model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: "test", Service: "svc-b"},
+		Type:       model.EventRouting,
+	}
+	cache.serviceMap.Store(*otherKey, NewCacheObject(CacheHandlers{}, cache, otherKey))
+	return cache
+}
+
+// TestLocalCache_DumpCache 验证本地缓存导出的内容与过滤
+// 测试场景：缓存中存在 default/svc-a 的实例、路由规则，以及一个尚未加载值的 test/svc-b 路由缓存
+// 前置条件：实例缓存标记为从持久化文件加载
+// 预期结果：无过滤时按命名空间、服务、类型排序返回全部条目；实例条目包含实例信息与 cacheLoaded 标记；
+// 规则条目包含规则 JSON；按命名空间、服务、类型过滤生效
+func TestLocalCache_DumpCache(t *testing.T) {
+	cache := newDumpTestCache(t)
+
+	entries := cache.DumpCache(lrplug.CacheDumpFilter{})
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "instance", entries[0].Type)
+	assert.Equal(t, "routing", entries[1].Type)
+	assert.Equal(t, "test", entries[2].Namespace)
+
+	inst := entries[0]
+	assert.Equal(t, "inst-rev", inst.Revision)
+	assert.True(t, inst.Initialized)
+	assert.True(t, inst.CacheLoaded)
+	assert.False(t, inst.UpdateTime.IsZero())
+	assert.Len(t, inst.Instances, 1)
+	assert.Equal(t, "ins-1", inst.Instances[0].ID)
+	assert.Equal(t, uint32(8080), inst.Instances[0].Port)
+	assert.True(t, inst.Instances[0].Healthy)
+
+	rule := entries[1]
+	assert.Equal(t, "route-rev", rule.Revision)
+	assert.False(t, rule.CacheLoaded)
+	var ruleValue map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rule.Rule, &ruleValue))
+	assert.Equal(t, "svc-a", ruleValue["service"])
+
+	empty := entries[2]
+	assert.False(t, empty.Initialized)
+	assert.True(t, empty.UpdateTime.IsZero())
+	assert.Nil(t, empty.Rule)
+
+	assert.Len(t, cache.DumpCache(lrplug.CacheDumpFilter{Namespace: "default"}), 2)
+	assert.Len(t, cache.DumpCache(lrplug.CacheDumpFilter{Service: "svc-b"}), 1)
+	filtered := cache.DumpCache(lrplug.CacheDumpFilter{Namespace: "default", Service: "svc-a", Type: "ROUTING"})
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "routing", filtered[0].Type)
+	assert.Empty(t, cache.DumpCache(lrplug.CacheDumpFilter{Namespace: "missing"}))
+}
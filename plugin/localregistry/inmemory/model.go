@@ -117,7 +117,9 @@ type CacheHandlers struct {
 // CacheObject 缓存值的管理基类
 type CacheObject struct {
 	// 最后一次访问的时间，初始化时为加入轮询队列的时间
-	lastVisitTime   int64
+	lastVisitTime int64
+	// 最后一次设置缓存值的时间
+	updateTime      int64
 	value           atomic.Value
 	serviceValueKey *model.ServiceEventKey
 	Handler         CacheHandlers
@@ -313,6 +315,7 @@ func (s *CacheObject) GetRevision() string {
 // SetValue 设置缓存对象
 func (s *CacheObject) SetValue(cacheValue model.RegistryValue) {
 	s.value.Store(cacheValue)
+	atomic.StoreInt64(&s.updateTime, clock.GetClock().Now().UnixNano())
 	s.registry.logCtx.GetBaseLogger().Infof(
 		"CacheObject: value for %s is updated, revision %s", *s.serviceValueKey, cacheValue.GetRevision())
 }
@@ -174,37 +174,44 @@ const (
 	RevisionMaxScope = 2
 )
 
-func PutDataFromContainerInOrder(metricVecCaches map[string]*prometheus.GaugeVec, collector StatCollector,
-	currentRevision int64) {
+// MetricValueVisitor 遍历容器内指标时的回调，expired 为 true 表示该指标已过期并已从容器移除，导出方应删除对应序列
+type MetricValueVisitor func(metric StatMetric, value float64, expired bool)
+
+// VisitDataFromContainer 按统一的版本过期规则遍历容器内的指标，供各导出方（Prometheus、OTel 等）复用：
+// 连续 RevisionMaxScope 个版本没有数据的指标从容器移除，老版本的指标按 0 导出。
+// currentRevision==0 表示永不过期（熔断 gauge 等状态指标），跳过版本过期清零逻辑
+func VisitDataFromContainer(collector StatCollector, currentRevision int64, visitor MetricValueVisitor) {
 	values := collector.CollectValues()
 	for i := range values {
 		metricValue := values[i]
-		gauge, ok := metricVecCaches[metricValue.MetricName()]
-		if !ok {
-			continue
-		}
-		canReport := true
-		switch rs := metricValue.(type) {
-		case *StatRevisionMetric:
-			// currentRevision==0 表示永不过期（熔断 gauge 等状态指标），跳过版本过期清零逻辑
-			if currentRevision > 0 && rs.GetRevision() < currentRevision-RevisionMaxScope {
+		if rs, ok := metricValue.(*StatRevisionMetric); ok && currentRevision > 0 {
+			if rs.GetRevision() < currentRevision-RevisionMaxScope {
 				// 如果连续两个版本还没有数据，就清除该数据
-				gauge.Delete(rs.GetLabels())
 				collector.RemoveStatMetric(rs.GetSignature())
-				canReport = false
+				visitor(metricValue, 0, true)
 				continue
 			}
-			// currentRevision==0 表示永不过期（熔断 gauge 等状态指标），跳过版本过期清零逻辑
-			if currentRevision > 0 && rs.GetRevision() < currentRevision {
+			if rs.GetRevision() < currentRevision {
 				// 如果版本为老版本，则清零数据
-				gauge.Delete(rs.GetLabels())
-				gauge.With(rs.GetLabels()).Set(0)
-				canReport = false
+				visitor(metricValue, 0, false)
 				continue
 			}
 		}
-		if canReport {
-			gauge.With(metricValue.GetLabels()).Set(float64(metricValue.GetValue()))
-		}
+		visitor(metricValue, metricValue.GetValue(), false)
 	}
 }
+
+func PutDataFromContainerInOrder(metricVecCaches map[string]*prometheus.GaugeVec, collector StatCollector,
+	currentRevision int64) {
+	VisitDataFromContainer(collector, currentRevision, func(metric StatMetric, value float64, expired bool) {
+		gauge, ok := metricVecCaches[metric.MetricName()]
+		if !ok {
+			return
+		}
+		if expired {
+			gauge.Delete(metric.GetLabels())
+			return
+		}
+		gauge.With(metric.GetLabels()).Set(value)
+	})
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/modern-go/reflect2"
 
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/pkg/stat/loadbalance"
 )
 
 // MetricsType 指标类型，对应 Prometheus 提供的 Collector 类型.
@@ -61,6 +63,12 @@ const (
 	CallerLabels    = "caller_labels"
 	MetricNameLabel = "metric_name"
 	RuleName        = "rule_name"
+	RuleID          = "rule_id"
+	APIName         = "api_name"
+	RoutePlugin     = "route_plugin"
+	RouteRuleType   = "route_rule_type"
+	RouteStatus     = "route_status"
+	LoadBalanceType = "lb_type"
 
 	// MetricsNameUpstreamRequestTotal 与路由、请求相关的指标信息.
 	MetricsNameUpstreamRequestTotal      = "upstream_rq_total"
@@ -73,11 +81,24 @@ const (
 	MetricsNameRateLimitRequestTotal = "ratelimit_rq_total"
 	MetricsNameRateLimitRequestPass  = "ratelimit_rq_pass"
 	MetricsNameRateLimitRequestLimit = "ratelimit_rq_limit"
+	// MetricsNameRateLimitRequestDryRunLimit 演练模式下本应被限流的请求数
+	MetricsNameRateLimitRequestDryRunLimit = "ratelimit_rq_dryrun_limit"
 
 	// 熔断相关指标信息.
 	MetricsNameCircuitBreakerOpen     = "circuitbreaker_open"
 	MetricsNameCircuitBreakerHalfOpen = "circuitbreaker_halfopen"
 
+	// SDK API 调用相关指标信息.
+	MetricsNameSDKAPIRequestTotal   = "sdk_api_rq_total"
+	MetricsNameSDKAPIRequestSuccess = "sdk_api_rq_success"
+	MetricsNameSDKAPIRequestDelay   = "sdk_api_rq_delay"
+
+	// 路由相关指标信息.
+	MetricsNameRouteRequestTotal = "route_rq_total"
+
+	// 负载均衡相关指标信息.
+	MetricsNameLoadBalanceMaxInflight = "loadbalance_max_inflight"
+
 	// SystemMetricValue.
 	NilValue = "__NULL__"
 )
@@ -191,6 +212,13 @@ var (
 			}
 			return NilValue
 		},
+		RuleID: func(args interface{}) string {
+			val := args.(*model.RateLimitGauge)
+			if val.RuleID != "" {
+				return val.RuleID
+			}
+			return NilValue
+		},
 	}
 
 	CircuitBreakerGaugeLabelOrder map[string]LabelValueSupplier = map[string]LabelValueSupplier{
@@ -242,6 +270,96 @@ var (
 			return val.RuleName
 		},
 	}
+	APICallGaugeLabelOrder map[string]LabelValueSupplier = map[string]LabelValueSupplier{
+		APIName: func(args interface{}) string {
+			val := args.(*model.APICallResult)
+			return val.GetAPI().String()
+		},
+		CalleeRetCode: func(args interface{}) string {
+			val := args.(*model.APICallResult)
+			return fmt.Sprintf("%d", val.GetRetCodeValue())
+		},
+		CalleeResult: func(args interface{}) string {
+			val := args.(*model.APICallResult)
+			retStatus := string(val.GetRetStatus())
+			if retStatus != "" {
+				return retStatus
+			}
+			return NilValue
+		},
+	}
+
+	RouteGaugeLabelOrder map[string]LabelValueSupplier = map[string]LabelValueSupplier{
+		CalleeNamespace: func(args interface{}) string {
+			val := args.(*servicerouter.RouteGauge)
+			if reflect2.IsNil(val.ServiceInstances) {
+				return NilValue
+			}
+			return val.ServiceInstances.GetNamespace()
+		},
+		CalleeService: func(args interface{}) string {
+			val := args.(*servicerouter.RouteGauge)
+			if reflect2.IsNil(val.ServiceInstances) {
+				return NilValue
+			}
+			return val.ServiceInstances.GetService()
+		},
+		CalleeRetCode: func(args interface{}) string {
+			val := args.(*servicerouter.RouteGauge)
+			return fmt.Sprintf("%d", val.RetCode)
+		},
+		CallerNamespace: func(args interface{}) string {
+			val := args.(*servicerouter.RouteGauge)
+			if val.SrcService.Namespace != "" {
+				return val.SrcService.Namespace
+			}
+			return NilValue
+		},
+		CallerService: func(args interface{}) string {
+			val := args.(*servicerouter.RouteGauge)
+			if val.SrcService.Service != "" {
+				return val.SrcService.Service
+			}
+			return NilValue
+		},
+		RouteRuleType: func(args interface{}) string {
+			val := args.(*servicerouter.RouteGauge)
+			switch val.RouteRuleType {
+			case servicerouter.DestRule:
+				return "dest"
+			case servicerouter.SrcRule:
+				return "src"
+			default:
+				return NilValue
+			}
+		},
+		RouteStatus: func(args interface{}) string {
+			val := args.(*servicerouter.RouteGauge)
+			return val.Status.String()
+		},
+	}
+
+	LoadBalanceGaugeLabelOrder map[string]LabelValueSupplier = map[string]LabelValueSupplier{
+		CalleeNamespace: func(args interface{}) string {
+			val := args.(*loadbalance.LoadBalanceGauge)
+			return val.GetNamespace()
+		},
+		CalleeService: func(args interface{}) string {
+			val := args.(*loadbalance.LoadBalanceGauge)
+			return val.GetService()
+		},
+		CalleeInstance: func(args interface{}) string {
+			val := args.(*loadbalance.LoadBalanceGauge)
+			return fmt.Sprintf("%s:%d", val.GetHost(), val.GetPort())
+		},
+		LoadBalanceType: func(args interface{}) string {
+			val := args.(*loadbalance.LoadBalanceGauge)
+			if val.LbType != "" {
+				return val.LbType
+			}
+			return NilValue
+		},
+	}
 )
 
 func formatLabelsToStr(arguments []model.Argument) string {
@@ -283,3 +401,32 @@ func ConvertCircuitBreakGaugeToLabels(val *model.CircuitBreakGauge, bindIP strin
 	labels[CallerIP] = bindIP
 	return labels
 }
+
+func ConvertAPICallGaugeToLabels(val *model.APICallResult, bindIP string) map[string]string {
+	labels := make(map[string]string)
+	for label, supplier := range APICallGaugeLabelOrder {
+		labels[label] = supplier(val)
+	}
+	labels[CallerIP] = bindIP
+	return labels
+}
+
+// ConvertRouteGaugeToLabels pluginName 为执行本次路由的插件名，由上报方根据 RouteGauge.PluginID 解析
+func ConvertRouteGaugeToLabels(val *servicerouter.RouteGauge, pluginName string, bindIP string) map[string]string {
+	labels := make(map[string]string)
+	for label, supplier := range RouteGaugeLabelOrder {
+		labels[label] = supplier(val)
+	}
+	labels[RoutePlugin] = pluginName
+	labels[CallerIP] = bindIP
+	return labels
+}
+
+func ConvertLoadBalanceGaugeToLabels(val *loadbalance.LoadBalanceGauge, bindIP string) map[string]string {
+	labels := make(map[string]string)
+	for label, supplier := range LoadBalanceGaugeLabelOrder {
+		labels[label] = supplier(val)
+	}
+	labels[CallerIP] = bindIP
+	return labels
+}
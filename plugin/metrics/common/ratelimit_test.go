@@ -0,0 +1,63 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestRateLimitStrategy_DryRun 验证演练模式记录只计入演练限流指标，不影响请求总数、通过数与限流数。
+func TestRateLimitStrategy_DryRun(t *testing.T) {
+	passGauge := &model.RateLimitGauge{Result: model.QuotaResultOk}
+	limitGauge := &model.RateLimitGauge{Result: model.QuotaResultLimited, RuleName: "rule", RuleID: "rule-id"}
+	dryRunGauge := &model.RateLimitGauge{
+		Result: model.QuotaResultLimited, RuleName: "rule", RuleID: "rule-id", DryRun: true}
+
+	expects := map[string][3]float64{
+		MetricsNameRateLimitRequestTotal:       {1, 1, 0},
+		MetricsNameRateLimitRequestPass:        {1, 0, 0},
+		MetricsNameRateLimitRequestLimit:       {0, 1, 0},
+		MetricsNameRateLimitRequestDryRunLimit: {0, 0, 1},
+	}
+	assert.Len(t, RateLimitStrategy, len(expects))
+	for _, strategy := range RateLimitStrategy {
+		expect, ok := expects[strategy.GetStrategyName()]
+		assert.True(t, ok, strategy.GetStrategyName())
+		for i, gauge := range []*model.RateLimitGauge{passGauge, limitGauge, dryRunGauge} {
+			assert.Equal(t, expect[i], strategy.InitMetricValue(gauge), "%s gauge %d", strategy.GetStrategyName(), i)
+			metric := NewStatStatefulMetric(strategy.GetStrategyName(), map[string]string{}, 0)
+			strategy.UpdateMetricValue(metric, gauge)
+			assert.Equal(t, expect[i], metric.GetValue(), "%s gauge %d", strategy.GetStrategyName(), i)
+		}
+	}
+}
+
+// TestConvertRateLimitGaugeToLabels_RuleID 验证限流指标携带 rule_id 标签，未命中规则时取空值占位。
+func TestConvertRateLimitGaugeToLabels_RuleID(t *testing.T) {
+	labels := ConvertRateLimitGaugeToLabels(&model.RateLimitGauge{
+		Namespace: "ns", Service: "svc", Result: model.QuotaResultLimited, RuleName: "rule", RuleID: "rule-id", DryRun: true})
+	assert.Equal(t, "rule", labels[RuleName])
+	assert.Equal(t, "rule-id", labels[RuleID])
+
+	labels = ConvertRateLimitGaugeToLabels(&model.RateLimitGauge{Namespace: "ns", Service: "svc"})
+	assert.Equal(t, NilValue, labels[RuleID])
+}
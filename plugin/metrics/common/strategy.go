@@ -18,6 +18,7 @@ package common
 
 import (
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/stat/loadbalance"
 )
 
 var (
@@ -47,6 +48,7 @@ var (
 		&RateLimitRequestTotalStrategy{},
 		&RateLimitRequestPassStrategy{},
 		&RateLimitRequestLimitStrategy{},
+		&RateLimitRequestDryRunLimitStrategy{},
 	}
 	RateLimitLabelOrder = []string{
 		CalleeNamespace,
@@ -54,6 +56,7 @@ var (
 		CalleeMethod,
 		CallerLabels,
 		RuleName,
+		RuleID,
 		MetricNameLabel,
 	}
 
@@ -73,6 +76,47 @@ var (
 		MetricNameLabel,
 		RuleName,
 	}
+
+	SDKAPIStrategy = []MetricValueAggregationStrategy{
+		&SDKAPIRequestTotalStrategy{},
+		&SDKAPIRequestSuccessStrategy{},
+		&SDKAPIRequestDelayStrategy{},
+	}
+	SDKAPILabelOrder = []string{
+		APIName,
+		CalleeRetCode,
+		CalleeResult,
+		CallerIP,
+		MetricNameLabel,
+	}
+
+	RouteStrategy = []MetricValueAggregationStrategy{
+		&RouteRequestTotalStrategy{},
+	}
+	RouteLabelOrder = []string{
+		CalleeNamespace,
+		CalleeService,
+		CalleeRetCode,
+		CallerNamespace,
+		CallerService,
+		CallerIP,
+		RoutePlugin,
+		RouteRuleType,
+		RouteStatus,
+		MetricNameLabel,
+	}
+
+	LoadBalanceStrategy = []MetricValueAggregationStrategy{
+		&LoadBalanceMaxInflightStrategy{},
+	}
+	LoadBalanceLabelOrder = []string{
+		CalleeNamespace,
+		CalleeService,
+		CalleeInstance,
+		CallerIP,
+		LoadBalanceType,
+		MetricNameLabel,
+	}
 )
 
 type MetricValueAggregationStrategy interface {
@@ -360,14 +404,26 @@ func (us *RateLimitRequestTotalStrategy) GetStrategyName() string {
 
 // 根据数据源的内容获取第一次创建metric的时候的初始值
 func (us *RateLimitRequestTotalStrategy) InitMetricValue(dataSource interface{}) float64 {
+	if isDryRunRateLimitGauge(dataSource) {
+		return 0
+	}
 	return 1.0
 }
 
 // 根据metric自身的value值和聚合数据源T的值来更新metric的value
 func (us *RateLimitRequestTotalStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	if isDryRunRateLimitGauge(dataSource) {
+		return
+	}
 	targetValue.Inc()
 }
 
+// isDryRunRateLimitGauge 是否演练模式下本应限流的记录，这类记录只计入 ratelimit_rq_dryrun_limit
+func isDryRunRateLimitGauge(dataSource interface{}) bool {
+	gauge, ok := dataSource.(*model.RateLimitGauge)
+	return ok && gauge.DryRun
+}
+
 type RateLimitRequestPassStrategy struct {
 }
 
@@ -384,7 +440,7 @@ func (us *RateLimitRequestPassStrategy) GetStrategyName() string {
 // 根据数据源的内容获取第一次创建metric的时候的初始值
 func (us *RateLimitRequestPassStrategy) InitMetricValue(dataSource interface{}) float64 {
 	gauge, ok := dataSource.(*model.RateLimitGauge)
-	if !ok {
+	if !ok || gauge.DryRun {
 		return 0
 	}
 	if gauge.Result == model.QuotaResultOk {
@@ -396,7 +452,7 @@ func (us *RateLimitRequestPassStrategy) InitMetricValue(dataSource interface{})
 // 根据metric自身的value值和聚合数据源T的值来更新metric的value
 func (us *RateLimitRequestPassStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
 	gauge, ok := dataSource.(*model.RateLimitGauge)
-	if !ok {
+	if !ok || gauge.DryRun {
 		return
 	}
 	if gauge.Result == model.QuotaResultOk {
@@ -420,7 +476,7 @@ func (us *RateLimitRequestLimitStrategy) GetStrategyName() string {
 // 根据数据源的内容获取第一次创建metric的时候的初始值
 func (us *RateLimitRequestLimitStrategy) InitMetricValue(dataSource interface{}) float64 {
 	gauge, ok := dataSource.(*model.RateLimitGauge)
-	if !ok {
+	if !ok || gauge.DryRun {
 		return 0
 	}
 	if gauge.Result == model.QuotaResultLimited {
@@ -432,10 +488,192 @@ func (us *RateLimitRequestLimitStrategy) InitMetricValue(dataSource interface{})
 // 根据metric自身的value值和聚合数据源T的值来更新metric的value
 func (us *RateLimitRequestLimitStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
 	gauge, ok := dataSource.(*model.RateLimitGauge)
-	if !ok {
+	if !ok || gauge.DryRun {
 		return
 	}
 	if gauge.Result == model.QuotaResultLimited {
 		targetValue.Inc()
 	}
 }
+
+type RateLimitRequestDryRunLimitStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *RateLimitRequestDryRunLimitStrategy) GetStrategyDescription() string {
+	return "total of request would have been limited by dry-run rules per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *RateLimitRequestDryRunLimitStrategy) GetStrategyName() string {
+	return MetricsNameRateLimitRequestDryRunLimit
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *RateLimitRequestDryRunLimitStrategy) InitMetricValue(dataSource interface{}) float64 {
+	if isDryRunRateLimitGauge(dataSource) {
+		return 1.0
+	}
+	return 0
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *RateLimitRequestDryRunLimitStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	if isDryRunRateLimitGauge(dataSource) {
+		targetValue.Inc()
+	}
+}
+
+type SDKAPIRequestTotalStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *SDKAPIRequestTotalStrategy) GetStrategyDescription() string {
+	return "total of sdk api request per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *SDKAPIRequestTotalStrategy) GetStrategyName() string {
+	return MetricsNameSDKAPIRequestTotal
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *SDKAPIRequestTotalStrategy) InitMetricValue(dataSource interface{}) float64 {
+	return 1.0
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *SDKAPIRequestTotalStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	targetValue.Inc()
+}
+
+type SDKAPIRequestSuccessStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *SDKAPIRequestSuccessStrategy) GetStrategyDescription() string {
+	return "total of success sdk api request per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *SDKAPIRequestSuccessStrategy) GetStrategyName() string {
+	return MetricsNameSDKAPIRequestSuccess
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *SDKAPIRequestSuccessStrategy) InitMetricValue(dataSource interface{}) float64 {
+	gauge, ok := dataSource.(*model.APICallResult)
+	if !ok {
+		return 0
+	}
+	if gauge.RetStatus == model.RetSuccess {
+		return 1
+	}
+	return 0
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *SDKAPIRequestSuccessStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	if gauge, ok := dataSource.(*model.APICallResult); ok {
+		if gauge.RetStatus == model.RetSuccess {
+			targetValue.Inc()
+		}
+	}
+}
+
+type SDKAPIRequestDelayStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *SDKAPIRequestDelayStrategy) GetStrategyDescription() string {
+	return "average sdk api request delay per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *SDKAPIRequestDelayStrategy) GetStrategyName() string {
+	return MetricsNameSDKAPIRequestDelay
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *SDKAPIRequestDelayStrategy) InitMetricValue(dataSource interface{}) float64 {
+	gauge, ok := dataSource.(*model.APICallResult)
+	if !ok {
+		return 0
+	}
+	return float64(gauge.GetDelay().Milliseconds())
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *SDKAPIRequestDelayStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	gauge, ok := dataSource.(*model.APICallResult)
+	if !ok {
+		return
+	}
+	targetValue.Add(gauge.GetDelay().Milliseconds())
+}
+
+func (us *SDKAPIRequestDelayStrategy) NeedAvg() bool {
+	return true
+}
+
+type RouteRequestTotalStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *RouteRequestTotalStrategy) GetStrategyDescription() string {
+	return "total of route request per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *RouteRequestTotalStrategy) GetStrategyName() string {
+	return MetricsNameRouteRequestTotal
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *RouteRequestTotalStrategy) InitMetricValue(dataSource interface{}) float64 {
+	return 1.0
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *RouteRequestTotalStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	targetValue.Inc()
+}
+
+type LoadBalanceMaxInflightStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *LoadBalanceMaxInflightStrategy) GetStrategyDescription() string {
+	return "maximum in-flight request of instance per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *LoadBalanceMaxInflightStrategy) GetStrategyName() string {
+	return MetricsNameLoadBalanceMaxInflight
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *LoadBalanceMaxInflightStrategy) InitMetricValue(dataSource interface{}) float64 {
+	gauge, ok := dataSource.(*loadbalance.LoadBalanceGauge)
+	if !ok {
+		return 0
+	}
+	return float64(gauge.Inflight)
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *LoadBalanceMaxInflightStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	gauge, ok := dataSource.(*loadbalance.LoadBalanceGauge)
+	if !ok {
+		return
+	}
+	for {
+		oldValue := targetValue.GetValue()
+		if float64(gauge.Inflight) <= oldValue {
+			return
+		}
+		if targetValue.CompareAndSwap(int64(oldValue), gauge.Inflight) {
+			return
+		}
+	}
+}
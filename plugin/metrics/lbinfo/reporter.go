@@ -0,0 +1,197 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package lbinfo 负载均衡统计插件，汇总有状态负载均衡插件（如 leastRequest）上报的实例在途请求数，
+// 并通过 admin 服务的 /lbinfo 路径以 JSON 格式对外暴露。
+package lbinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/admin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
+	"github.com/polarismesh/polaris-go/pkg/stat/loadbalance"
+)
+
+const (
+	// PluginName 插件名，对应 polaris.yaml 中 statReporter.chain 的配置项
+	PluginName = config.DefaultLoadBalanceReporter
+	// AdminPath 在 admin 服务上暴露的查询路径
+	AdminPath = "/lbinfo"
+)
+
+var _ statreporter.StatReporter = (*Reporter)(nil)
+
+// init 注册插件实现
+func init() {
+	plugin.RegisterPlugin(&Reporter{})
+}
+
+// InstanceInflight 实例的在途请求数
+type InstanceInflight struct {
+	Namespace  string `json:"namespace"`
+	Service    string `json:"service"`
+	InstanceID string `json:"instanceId"`
+	Host       string `json:"host"`
+	Port       int    `json:"port"`
+	Inflight   int64  `json:"inflight"`
+}
+
+// Reporter 负载均衡统计插件
+type Reporter struct {
+	*plugin.PluginBase
+	pluginCtx *plugin.InitContext
+	logCtx    *log.ContextLogger
+	mutex     sync.RWMutex
+	// 负载均衡插件名 -> 实例ID -> 在途请求，在途请求归零后移除
+	inflights map[string]map[string]*InstanceInflight
+}
+
+// Type 插件类型
+func (r *Reporter) Type() common.Type {
+	return common.TypeStatReporter
+}
+
+// Name 插件名
+func (r *Reporter) Name() string {
+	return PluginName
+}
+
+// IsEnable 仅当 global.statReporter 启用且其 chain 中显式包含本插件时启用
+func (r *Reporter) IsEnable(cfg config.Configuration) bool {
+	statReporter := cfg.GetGlobal().GetStatReporter()
+	if !statReporter.IsEnable() {
+		return false
+	}
+	for _, name := range statReporter.GetChain() {
+		if name == PluginName {
+			return true
+		}
+	}
+	return false
+}
+
+// Init 初始化插件，注册 admin 查询路径
+func (r *Reporter) Init(ctx *plugin.InitContext) error {
+	r.PluginBase = plugin.NewPluginBase(ctx)
+	r.pluginCtx = ctx
+	r.logCtx = ctx.ValueCtx.GetContextLogger()
+	ctx.Config.GetGlobal().GetAdmin().RegisterPath(model.AdminHandler{
+		Path:        AdminPath,
+		HandlerFunc: r.ServeHTTP,
+	})
+	return nil
+}
+
+// Start 启动 admin 服务
+func (r *Reporter) Start() error {
+	adminType := r.pluginCtx.Config.GetGlobal().GetAdmin().GetType()
+	targetPlugin, err := r.pluginCtx.Plugins.GetPlugin(common.TypeAdmin, adminType)
+	if err != nil {
+		r.logCtx.GetBaseLogger().Errorf("[lbInfo] get admin plugin fail: %v", err)
+		return nil
+	}
+	targetPlugin.(admin.Admin).Run()
+	return nil
+}
+
+// ReportStat 记录负载均衡插件上报的实例在途请求数
+func (r *Reporter) ReportStat(metricsType model.MetricType, metricsVal model.InstanceGauge) error {
+	if metricsType != model.LoadBalanceStat {
+		return nil
+	}
+	gauge, ok := metricsVal.(*loadbalance.LoadBalanceGauge)
+	if !ok || gauge == nil || gauge.Inst == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.inflights == nil {
+		r.inflights = make(map[string]map[string]*InstanceInflight)
+	}
+	instances, ok := r.inflights[gauge.LbType]
+	if !ok {
+		instances = make(map[string]*InstanceInflight)
+		r.inflights[gauge.LbType] = instances
+	}
+	if gauge.Inflight <= 0 {
+		delete(instances, gauge.Inst.GetId())
+		return nil
+	}
+	instances[gauge.Inst.GetId()] = &InstanceInflight{
+		Namespace:  gauge.Inst.GetNamespace(),
+		Service:    gauge.Inst.GetService(),
+		InstanceID: gauge.Inst.GetId(),
+		Host:       gauge.Inst.GetHost(),
+		Port:       int(gauge.Inst.GetPort()),
+		Inflight:   gauge.Inflight,
+	}
+	return nil
+}
+
+// Snapshot 获取各负载均衡插件下存在在途请求的实例，按命名空间、服务、实例ID排序
+func (r *Reporter) Snapshot() map[string][]InstanceInflight {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	snapshot := make(map[string][]InstanceInflight, len(r.inflights))
+	for lbType, instances := range r.inflights {
+		values := make([]InstanceInflight, 0, len(instances))
+		for _, value := range instances {
+			values = append(values, *value)
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if values[i].Namespace != values[j].Namespace {
+				return values[i].Namespace < values[j].Namespace
+			}
+			if values[i].Service != values[j].Service {
+				return values[i].Service < values[j].Service
+			}
+			return values[i].InstanceID < values[j].InstanceID
+		})
+		snapshot[lbType] = values
+	}
+	return snapshot
+}
+
+// ServeHTTP 以 JSON 格式返回 Snapshot
+func (r *Reporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(r.Snapshot()); err != nil {
+		r.logCtx.GetBaseLogger().Errorf("[lbInfo] encode snapshot fail: %v", err)
+	}
+}
+
+// Info 插件元信息，本插件不对服务端暴露监控端口
+func (r *Reporter) Info() model.StatInfo {
+	return model.StatInfo{}
+}
+
+// Destroy 销毁插件
+func (r *Reporter) Destroy() error {
+	if r.PluginBase != nil {
+		return r.PluginBase.Destroy()
+	}
+	return nil
+}
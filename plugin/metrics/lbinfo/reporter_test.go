@@ -0,0 +1,183 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package lbinfo_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
+	"github.com/polarismesh/polaris-go/pkg/stat/loadbalance"
+	"github.com/polarismesh/polaris-go/plugin/metrics/lbinfo"
+	"github.com/polarismesh/polaris-go/polaristest"
+)
+
+func TestMain(m *testing.M) {
+	logDir, err := ioutil.TempDir("", "lbinfo-log-")
+	if err != nil {
+		panic(err)
+	}
+	if err = api.ConfigLoggers(logDir, api.NoneLog); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	_ = os.RemoveAll(logDir)
+	os.Exit(code)
+}
+
+func newTestInstance(id string, port uint32) model.Instance {
+	return pb.NewInstanceInProto(&apiservice.Instance{
+		Id:   wrapperspb.String(id),
+		Host: wrapperspb.String("127.0.0.1"),
+		Port: wrapperspb.UInt32(port),
+	}, &model.ServiceKey{Namespace: "test-ns", Service: "test-svc"}, local.NewInstanceLocalValue())
+}
+
+func report(t *testing.T, reporter *lbinfo.Reporter, inst model.Instance, inflight int64) {
+	gauge := &loadbalance.LoadBalanceGauge{Inst: inst, LbType: "leastRequest", Inflight: inflight}
+	if err := reporter.ReportStat(model.LoadBalanceStat, gauge); err != nil {
+		t.Fatalf("ReportStat 返回错误: %v", err)
+	}
+}
+
+func TestReporter_Snapshot(t *testing.T) {
+	reporter := &lbinfo.Reporter{}
+	inst1 := newTestInstance("inst-1", 8081)
+	inst2 := newTestInstance("inst-2", 8082)
+	report(t, reporter, inst2, 3)
+	report(t, reporter, inst1, 1)
+	report(t, reporter, inst1, 2)
+	// 非负载均衡统计数据会被忽略
+	if err := reporter.ReportStat(model.ServiceStat, &model.ServiceCallResult{CalledInstance: inst1}); err != nil {
+		t.Fatalf("ReportStat 返回错误: %v", err)
+	}
+
+	snapshot := reporter.Snapshot()["leastRequest"]
+	if len(snapshot) != 2 || snapshot[0].InstanceID != "inst-1" || snapshot[0].Inflight != 2 ||
+		snapshot[1].InstanceID != "inst-2" || snapshot[1].Inflight != 3 {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+
+	// 在途请求归零后移除
+	report(t, reporter, inst2, 0)
+	recorder := httptest.NewRecorder()
+	reporter.ServeHTTP(recorder, httptest.NewRequest("GET", lbinfo.AdminPath, nil))
+	result := map[string][]lbinfo.InstanceInflight{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode response %q: %v", recorder.Body.String(), err)
+	}
+	values := result["leastRequest"]
+	if len(values) != 1 || values[0].InstanceID != "inst-1" || values[0].Port != 8081 ||
+		values[0].Namespace != "test-ns" || values[0].Service != "test-svc" {
+		t.Fatalf("unexpected response %s", recorder.Body.String())
+	}
+}
+
+func freePort(t *testing.T) int {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestReporter_LeastRequest(t *testing.T) {
+	srv := polaristest.Start(t)
+	srv.AddInstance("test-ns", "test-svc", "127.0.0.1", 8081, nil)
+	srv.AddInstance("test-ns", "test-svc", "127.0.0.1", 8082, nil)
+
+	cfg := srv.Config()
+	cfg.GetGlobal().GetStatReporter().SetEnable(true)
+	cfg.GetGlobal().GetStatReporter().SetChain([]string{lbinfo.PluginName})
+	cfg.GetGlobal().GetAdmin().SetHost("127.0.0.1")
+	cfg.GetGlobal().GetAdmin().SetPort(freePort(t))
+	// 在途请求数按周期上报
+	cfg.GetConsumer().GetLoadbalancer().SetInflightReportInterval(100 * time.Millisecond)
+	sdkCtx, err := api.InitContextByConfig(cfg)
+	if err != nil {
+		t.Fatalf("init sdk context: %v", err)
+	}
+	defer sdkCtx.Destroy()
+	consumer := api.NewConsumerAPIByContext(sdkCtx)
+
+	plug, err := sdkCtx.GetPlugins().GetPlugin(common.TypeStatReporter, lbinfo.PluginName)
+	if err != nil {
+		t.Fatalf("get lbInfo plugin: %v", err)
+	}
+	reporter := plug.(*statreporter.Proxy).StatReporter.(*lbinfo.Reporter)
+
+	req := &api.GetOneInstanceRequest{}
+	req.Namespace = "test-ns"
+	req.Service = "test-svc"
+	req.LbPolicy = api.LBPolicyLeastRequest
+	selected := make([]model.Instance, 0, 2)
+	for i := 0; i < 2; i++ {
+		resp, err := consumer.GetOneInstance(req)
+		if err != nil {
+			t.Fatalf("GetOneInstance: %v", err)
+		}
+		selected = append(selected, resp.GetInstance())
+	}
+	// 两个实例时总会选择在途请求较少的实例
+	if selected[0].GetId() == selected[1].GetId() {
+		t.Fatalf("expect different instances, got %s twice", selected[0].GetId())
+	}
+	if !waitInflights(reporter, 2) {
+		t.Fatalf("expect 2 inflight instances, got %+v", reporter.Snapshot()[api.LBPolicyLeastRequest])
+	}
+
+	for _, inst := range selected {
+		result := &api.ServiceCallResult{}
+		result.SetCalledInstance(inst)
+		result.SetRetStatus(model.RetSuccess)
+		result.SetRetCode(0)
+		result.SetDelay(time.Millisecond)
+		if err := consumer.UpdateServiceCallResult(result); err != nil {
+			t.Fatalf("UpdateServiceCallResult: %v", err)
+		}
+	}
+	if !waitInflights(reporter, 0) {
+		t.Fatalf("expect no inflight instances, got %+v", reporter.Snapshot()[api.LBPolicyLeastRequest])
+	}
+}
+
+// waitInflights 等待 leastRequest 有在途请求的实例数达到 expect
+func waitInflights(reporter *lbinfo.Reporter, expect int) bool {
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(reporter.Snapshot()[api.LBPolicyLeastRequest]) == expect {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return false
+}
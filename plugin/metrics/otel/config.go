@@ -0,0 +1,133 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package otel
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// ProtocolGRPC 通过 OTLP/gRPC 导出
+	ProtocolGRPC = "grpc"
+	// ProtocolHTTP 通过 OTLP/HTTP（protobuf 编码）导出
+	ProtocolHTTP = "http"
+
+	defaultGRPCEndpoint = "127.0.0.1:4317"
+	defaultHTTPEndpoint = "http://127.0.0.1:4318/v1/metrics"
+	defaultInterval     = 15 * time.Second
+	defaultTimeout      = 5 * time.Second
+	// defaultServiceName 未配置 service.name 资源属性时使用的默认值，与 Prometheus pushgateway 的 job 名保持一致
+	defaultServiceName = "polaris-client"
+)
+
+// Config OpenTelemetry 指标导出配置
+type Config struct {
+	// Protocol 导出协议，grpc 或 http
+	Protocol string `yaml:"protocol" json:"protocol"`
+	// Endpoint 导出地址，grpc 协议为 host:port，http 协议为完整 URL
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+	// Interval 导出周期，同时也是指标的聚合周期
+	Interval *time.Duration `yaml:"interval" json:"interval"`
+	// Timeout 单次导出超时时间
+	Timeout *time.Duration `yaml:"timeout" json:"timeout"`
+	// Headers 导出请求附带的 header（gRPC 为 metadata），常用于携带鉴权信息
+	Headers map[string]string `yaml:"headers" json:"headers"`
+	// TLS 导出连接的TLS配置；grpc 协议需启用 tls.enable，http 协议使用 https 地址时生效
+	TLS *config.TLSConfigImpl `yaml:"tls" json:"tls"`
+	// AllowInsecureHeaders 是否允许通过明文连接发送 Headers，默认不允许，避免鉴权信息泄露
+	AllowInsecureHeaders bool `yaml:"allowInsecureHeaders" json:"allowInsecureHeaders"`
+	// ResourceAttributes OTel Resource 属性，如 service.name
+	ResourceAttributes map[string]string `yaml:"resourceAttributes" json:"resourceAttributes"`
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.Protocol == "" {
+		c.Protocol = ProtocolGRPC
+	}
+	if c.Endpoint == "" {
+		if c.Protocol == ProtocolHTTP {
+			c.Endpoint = defaultHTTPEndpoint
+		} else {
+			c.Endpoint = defaultGRPCEndpoint
+		}
+	}
+	if nil == c.Interval {
+		c.Interval = model.ToDurationPtr(defaultInterval)
+	}
+	if nil == c.Timeout {
+		c.Timeout = model.ToDurationPtr(defaultTimeout)
+	}
+	if nil == c.TLS {
+		c.TLS = &config.TLSConfigImpl{}
+	}
+	c.TLS.SetDefault()
+}
+
+// Verify 校验配置值
+func (c *Config) Verify() error {
+	if c.Protocol != ProtocolGRPC && c.Protocol != ProtocolHTTP {
+		return fmt.Errorf("invalid protocol: %s, it must be %s or %s", c.Protocol, ProtocolGRPC, ProtocolHTTP)
+	}
+	if c.Endpoint == "" {
+		return fmt.Errorf("endpoint not configured")
+	}
+	if nil == c.Interval {
+		return fmt.Errorf("interval not configured")
+	}
+	if *c.Interval <= 0 {
+		return fmt.Errorf("invalid interval: %v, it must greater than 0", *c.Interval)
+	}
+	if nil == c.Timeout {
+		return fmt.Errorf("timeout not configured")
+	}
+	if *c.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout: %v, it must greater than 0", *c.Timeout)
+	}
+	if c.TLS.IsEnable() {
+		if err := c.TLS.Verify(); err != nil {
+			return err
+		}
+	}
+	if c.Protocol == ProtocolHTTP && c.TLS.IsEnable() && !c.isHTTPS() {
+		return fmt.Errorf("tls is enabled, but endpoint %s is not https", c.Endpoint)
+	}
+	if len(c.Headers) > 0 && !c.isSecure() && !c.AllowInsecureHeaders {
+		return fmt.Errorf("headers can not be sent over insecure endpoint %s, "+
+			"enable tls or set allowInsecureHeaders explicitly", c.Endpoint)
+	}
+	return nil
+}
+
+// isSecure 导出连接是否经过TLS加密
+func (c *Config) isSecure() bool {
+	if c.Protocol == ProtocolHTTP {
+		return c.isHTTPS()
+	}
+	return c.TLS.IsEnable()
+}
+
+// isHTTPS http 协议的导出地址是否为 https
+func (c *Config) isHTTPS() bool {
+	return strings.HasPrefix(strings.ToLower(c.Endpoint), "https://")
+}
@@ -0,0 +1,163 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package otel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/polarismesh/polaris-go/pkg/network"
+)
+
+// exporter 将 ExportMetricsServiceRequest 发送给 OTLP 接收端
+type exporter interface {
+	io.Closer
+	// export 发送一次导出请求
+	export(ctx context.Context, req *colmetricpb.ExportMetricsServiceRequest) error
+}
+
+// newExporter 根据配置创建导出器，TLS 证书加载失败时返回错误
+func newExporter(cfg *Config) (exporter, error) {
+	if cfg.Protocol == ProtocolHTTP {
+		tlsConfig, err := network.NewTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if tlsConfig != nil {
+			transport.TLSClientConfig = tlsConfig
+		}
+		return &httpExporter{
+			endpoint: cfg.Endpoint,
+			headers:  cfg.Headers,
+			client:   &http.Client{Timeout: *cfg.Timeout, Transport: transport},
+		}, nil
+	}
+	creds, err := network.NewTransportCredentials(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcExporter{
+		endpoint: cfg.Endpoint,
+		headers:  cfg.Headers,
+		creds:    creds,
+	}, nil
+}
+
+// grpcExporter OTLP/gRPC 导出，连接在首次导出时建立并复用
+type grpcExporter struct {
+	endpoint string
+	headers  map[string]string
+	// 连接使用的传输凭证，未启用TLS时为明文
+	creds  credentials.TransportCredentials
+	mutex  sync.Mutex
+	conn   *grpc.ClientConn
+	client colmetricpb.MetricsServiceClient
+}
+
+func (e *grpcExporter) getClient() (colmetricpb.MetricsServiceClient, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.client != nil {
+		return e.client, nil
+	}
+	conn, err := grpc.Dial(e.endpoint, grpc.WithTransportCredentials(e.creds))
+	if err != nil {
+		return nil, err
+	}
+	e.conn = conn
+	e.client = colmetricpb.NewMetricsServiceClient(conn)
+	return e.client, nil
+}
+
+func (e *grpcExporter) export(ctx context.Context, req *colmetricpb.ExportMetricsServiceRequest) error {
+	client, err := e.getClient()
+	if err != nil {
+		return err
+	}
+	if len(e.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(e.headers))
+	}
+	_, err = client.Export(ctx, req)
+	return err
+}
+
+// Close 关闭连接
+func (e *grpcExporter) Close() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.Close()
+	e.conn = nil
+	e.client = nil
+	return err
+}
+
+// httpExporter OTLP/HTTP 导出，使用 protobuf 编码
+type httpExporter struct {
+	endpoint string
+	headers  map[string]string
+	client   *http.Client
+}
+
+func (e *httpExporter) export(ctx context.Context, exportReq *colmetricpb.ExportMetricsServiceRequest) error {
+	body, err := proto.Marshal(exportReq)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("otlp http export fail, status: %d, body: %s", resp.StatusCode, string(msg))
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+// Close 释放空闲连接
+func (e *httpExporter) Close() error {
+	e.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,107 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package otel
+
+import (
+	"sort"
+	"time"
+
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// dataPoint 单个指标序列在本周期的取值
+type dataPoint struct {
+	attributes map[string]string
+	value      float64
+}
+
+// metricData 一个 OTel 指标及其全部数据点
+type metricData struct {
+	name        string
+	description string
+	unit        string
+	// sum 是否为计数类指标，计数类指标以单调递增的 Sum 导出，其余以 Gauge 导出
+	sum    bool
+	points []dataPoint
+}
+
+// buildExportRequest 将指标转换为 OTLP 的 ExportMetricsServiceRequest
+// 取值与 Prometheus 插件暴露的周期聚合值一致：计数类指标为 [start, now) 周期内的增量，以 DELTA 时间性的 Sum 导出；
+// 最大值、状态类指标以 Gauge 导出
+func buildExportRequest(resource map[string]string, scopeName, scopeVersion string,
+	metrics []*metricData, start, now time.Time) *colmetricpb.ExportMetricsServiceRequest {
+	scopeMetrics := &metricpb.ScopeMetrics{
+		Scope:   &commonpb.InstrumentationScope{Name: scopeName, Version: scopeVersion},
+		Metrics: make([]*metricpb.Metric, 0, len(metrics)),
+	}
+	for _, m := range metrics {
+		scopeMetrics.Metrics = append(scopeMetrics.Metrics, buildMetric(m, start, now))
+	}
+	return &colmetricpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricpb.ResourceMetrics{{
+			Resource:     &resourcepb.Resource{Attributes: buildAttributes(resource)},
+			ScopeMetrics: []*metricpb.ScopeMetrics{scopeMetrics},
+		}},
+	}
+}
+
+func buildMetric(m *metricData, start, now time.Time) *metricpb.Metric {
+	points := make([]*metricpb.NumberDataPoint, 0, len(m.points))
+	for _, p := range m.points {
+		point := &metricpb.NumberDataPoint{
+			Attributes:   buildAttributes(p.attributes),
+			TimeUnixNano: uint64(now.UnixNano()),
+			Value:        &metricpb.NumberDataPoint_AsDouble{AsDouble: p.value},
+		}
+		if m.sum {
+			point.StartTimeUnixNano = uint64(start.UnixNano())
+		}
+		points = append(points, point)
+	}
+	metric := &metricpb.Metric{Name: m.name, Description: m.description, Unit: m.unit}
+	if !m.sum {
+		metric.Data = &metricpb.Metric_Gauge{Gauge: &metricpb.Gauge{DataPoints: points}}
+		return metric
+	}
+	metric.Data = &metricpb.Metric_Sum{Sum: &metricpb.Sum{
+		DataPoints:             points,
+		AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+		IsMonotonic:            true,
+	}}
+	return metric
+}
+
+// buildAttributes 按 key 排序转换 KeyValue 列表，保证相同属性集合的导出结果稳定
+func buildAttributes(attributes map[string]string) []*commonpb.KeyValue {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make([]*commonpb.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, &commonpb.KeyValue{
+			Key:   k,
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: attributes[k]}},
+		})
+	}
+	return kvs
+}
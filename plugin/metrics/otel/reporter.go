@@ -0,0 +1,384 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package otel
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/modern-go/reflect2"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/pkg/stat/loadbalance"
+	"github.com/polarismesh/polaris-go/pkg/version"
+	statcommon "github.com/polarismesh/polaris-go/plugin/metrics/common"
+)
+
+const (
+	// PluginName is the name of the plugin.
+	PluginName = "otel"
+	// scopeName OTel InstrumentationScope 名称
+	scopeName = "github.com/polarismesh/polaris-go"
+	// metricUnitMillis 时延类指标的单位
+	metricUnitMillis = "ms"
+)
+
+var _ statreporter.StatReporter = (*Reporter)(nil)
+
+// init 注册插件.
+func init() {
+	plugin.RegisterConfigurablePlugin(&Reporter{}, &Config{})
+}
+
+// collectorGroup 一类 MetricType 对应的聚合器及其策略
+type collectorGroup struct {
+	collector  statcommon.StatCollector
+	revision   *statcommon.StatInfoRevisionCollector
+	strategies []statcommon.MetricValueAggregationStrategy
+	order      []string
+}
+
+// currentRevision 状态类聚合器返回 0，表示永不过期
+func (g *collectorGroup) currentRevision() int64 {
+	if g.revision == nil {
+		return 0
+	}
+	return g.revision.GetCurrentRevision()
+}
+
+// Reporter 通过 OTLP 导出 SDK 指标的 StatReporter
+// 指标的聚合与 Prometheus 插件共用 plugin/metrics/common 的策略和聚合器，
+// 指标名与属性名与 Prometheus 的 metric/label 保持一致。
+type Reporter struct {
+	*plugin.PluginBase
+	cfg     *Config
+	plugins plugin.Supplier
+	bindIP  string
+	// resource OTel Resource 属性
+	resource map[string]string
+
+	insCollector            *statcommon.StatInfoRevisionCollector
+	rateLimitCollector      *statcommon.StatInfoRevisionCollector
+	apiCollector            *statcommon.StatInfoRevisionCollector
+	routeCollector          *statcommon.StatInfoRevisionCollector
+	loadBalanceCollector    *statcommon.StatInfoRevisionCollector
+	circuitBreakerCollector *statcommon.StatInfoStatefulCollector
+	groups                  []*collectorGroup
+	// descriptions 指标名到描述的映射
+	descriptions map[string]string
+
+	exporter exporter
+	// lastCollect 上一次收集聚合值的时间，即本周期计数类指标的起始时间
+	lastCollect time.Time
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	// lastExportErr 上一次导出失败的错误信息，错误不变时不重复打印
+	lastExportErr string
+	// 上下文日志
+	logCtx *log.ContextLogger
+}
+
+// Type 插件类型.
+func (r *Reporter) Type() common.Type {
+	return common.TypeStatReporter
+}
+
+// Name 插件名，一个类型下插件名唯一.
+func (r *Reporter) Name() string {
+	return PluginName
+}
+
+// IsEnable 仅当 global.statReporter 启用且其 chain 中显式包含本插件时启用
+func (r *Reporter) IsEnable(cfg config.Configuration) bool {
+	statReporter := cfg.GetGlobal().GetStatReporter()
+	if !statReporter.IsEnable() {
+		return false
+	}
+	for _, name := range statReporter.GetChain() {
+		if name == PluginName {
+			return true
+		}
+	}
+	return false
+}
+
+// Init 初始化插件.
+func (r *Reporter) Init(ctx *plugin.InitContext) error {
+	r.PluginBase = plugin.NewPluginBase(ctx)
+	r.plugins = ctx.Plugins
+	r.logCtx = ctx.ValueCtx.GetContextLogger()
+	r.cfg = &Config{}
+	r.cfg.SetDefault()
+	cfgValue := ctx.Config.GetGlobal().GetStatReporter().GetPluginConfig(PluginName)
+	if cfgValue != nil {
+		r.cfg = cfgValue.(*Config)
+	}
+	r.init(ctx.Config.GetGlobal().GetAPI().GetBindIP())
+	exporter, err := newExporter(r.cfg)
+	if err != nil {
+		return err
+	}
+	r.exporter = exporter
+	// headers 中通常携带鉴权信息，不打印到日志中
+	log.GetBaseLogger().Infof("[metrics][otel] init, protocol:%s, endpoint:%s, interval:%v, tls:%v",
+		r.cfg.Protocol, r.cfg.Endpoint, *r.cfg.Interval, r.cfg.TLS.IsEnable())
+	return nil
+}
+
+// init 初始化聚合器与资源属性
+func (r *Reporter) init(bindIP string) {
+	r.bindIP = bindIP
+	r.resource = map[string]string{}
+	for k, v := range r.cfg.ResourceAttributes {
+		r.resource[k] = v
+	}
+	if _, ok := r.resource["service.name"]; !ok {
+		r.resource["service.name"] = defaultServiceName
+	}
+	r.insCollector = statcommon.NewStatInfoRevisionCollector()
+	r.rateLimitCollector = statcommon.NewStatInfoRevisionCollector()
+	r.apiCollector = statcommon.NewStatInfoRevisionCollector()
+	r.routeCollector = statcommon.NewStatInfoRevisionCollector()
+	r.loadBalanceCollector = statcommon.NewStatInfoRevisionCollector()
+	r.circuitBreakerCollector = statcommon.NewStatInfoStatefulCollector()
+	r.groups = []*collectorGroup{
+		{collector: r.insCollector, revision: r.insCollector,
+			strategies: statcommon.ServiceCallStrategy, order: statcommon.ServiceCallLabelOrder},
+		{collector: r.rateLimitCollector, revision: r.rateLimitCollector,
+			strategies: statcommon.RateLimitStrategy, order: statcommon.RateLimitLabelOrder},
+		{collector: r.apiCollector, revision: r.apiCollector,
+			strategies: statcommon.SDKAPIStrategy, order: statcommon.SDKAPILabelOrder},
+		{collector: r.routeCollector, revision: r.routeCollector,
+			strategies: statcommon.RouteStrategy, order: statcommon.RouteLabelOrder},
+		{collector: r.loadBalanceCollector, revision: r.loadBalanceCollector,
+			strategies: statcommon.LoadBalanceStrategy, order: statcommon.LoadBalanceLabelOrder},
+		{collector: r.circuitBreakerCollector,
+			strategies: statcommon.CircuitBreakerStrategy, order: statcommon.CircuitBreakerLabelOrder},
+	}
+	r.descriptions = map[string]string{}
+	for _, g := range r.groups {
+		for _, strategy := range g.strategies {
+			r.descriptions[strategy.GetStrategyName()] = strategy.GetStrategyDescription()
+		}
+	}
+}
+
+// Start 启动周期导出任务
+func (r *Reporter) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.lastCollect = time.Now()
+	r.wg.Add(1)
+	go r.run(ctx)
+	return nil
+}
+
+func (r *Reporter) run(ctx context.Context) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(*r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.exportOnce(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// exportOnce 收集本周期的聚合值并导出，导出失败的数据不重试
+func (r *Reporter) exportOnce(ctx context.Context) {
+	defer func() {
+		if err := recover(); err != nil {
+			r.logCtx.GetStatReportLogger().Errorf("[metrics][otel] export panic: %v", err)
+		}
+	}()
+	start, now := r.lastCollect, time.Now()
+	metrics := r.collect()
+	r.lastCollect = now
+	if len(metrics) == 0 {
+		return
+	}
+	req := buildExportRequest(r.resource, scopeName, version.Version, metrics, start, now)
+	exportCtx, cancel := context.WithTimeout(ctx, *r.cfg.Timeout)
+	defer cancel()
+	if err := r.exporter.export(exportCtx, req); err != nil {
+		if errMsg := err.Error(); errMsg != r.lastExportErr {
+			r.lastExportErr = errMsg
+			r.logCtx.GetStatReportLogger().Errorf("[metrics][otel] export to %s fail: %v", r.cfg.Endpoint, err)
+		}
+		return
+	}
+	r.lastExportErr = ""
+	r.logCtx.GetStatReportLogger().Debugf("[metrics][otel] export %d metrics to %s", len(metrics), r.cfg.Endpoint)
+}
+
+// collect 按与 Prometheus 插件相同的版本过期规则取出各聚合器本周期的值，并推进聚合版本
+func (r *Reporter) collect() []*metricData {
+	metrics := map[string]*metricData{}
+	for _, g := range r.groups {
+		statcommon.VisitDataFromContainer(g.collector, g.currentRevision(),
+			func(metric statcommon.StatMetric, value float64, expired bool) {
+				if expired {
+					return
+				}
+				name := metric.MetricName()
+				data, ok := metrics[name]
+				if !ok {
+					data = &metricData{name: name, description: r.descriptions[name], unit: metricUnit(name),
+						sum: isSumMetric(name)}
+					metrics[name] = data
+				}
+				data.points = append(data.points, dataPoint{
+					attributes: toAttributes(metric.GetLabels()),
+					value:      value,
+				})
+			})
+		if g.revision != nil {
+			g.revision.IncRevision()
+		}
+	}
+	ret := make([]*metricData, 0, len(metrics))
+	for _, data := range metrics {
+		ret = append(ret, data)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].name < ret[j].name
+	})
+	return ret
+}
+
+// toAttributes 指标名已作为 OTel 指标名导出，属性中不再重复携带 metric_name
+func toAttributes(labels map[string]string) map[string]string {
+	attributes := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if k == statcommon.MetricNameLabel {
+			continue
+		}
+		attributes[k] = v
+	}
+	return attributes
+}
+
+// isSumMetric 是否为计数类指标：请求数、成功数、限流数与时延总和在每个周期内累加，周期结束后清零
+func isSumMetric(name string) bool {
+	switch name {
+	case statcommon.MetricsNameUpstreamRequestTotal, statcommon.MetricsNameUpstreamRequestSuccess,
+		statcommon.MetricsNameUpstreamRequestTimeout,
+		statcommon.MetricsNameRateLimitRequestTotal, statcommon.MetricsNameRateLimitRequestPass,
+		statcommon.MetricsNameRateLimitRequestLimit, statcommon.MetricsNameRateLimitRequestDryRunLimit,
+		statcommon.MetricsNameSDKAPIRequestTotal, statcommon.MetricsNameSDKAPIRequestSuccess,
+		statcommon.MetricsNameSDKAPIRequestDelay, statcommon.MetricsNameRouteRequestTotal:
+		return true
+	default:
+		return false
+	}
+}
+
+func metricUnit(name string) string {
+	switch name {
+	case statcommon.MetricsNameUpstreamRequestTimeout, statcommon.MetricsNameUpstreamRequestMaxTimeout,
+		statcommon.MetricsNameSDKAPIRequestDelay:
+		return metricUnitMillis
+	default:
+		return ""
+	}
+}
+
+// ReportStat 报告统计数据.
+func (r *Reporter) ReportStat(metricsType model.MetricType, metricsVal model.InstanceGauge) error {
+	if reflect2.IsNil(metricsVal) {
+		return nil
+	}
+	switch metricsType {
+	case model.SDKAPIStat:
+		if val, ok := metricsVal.(*model.APICallResult); ok {
+			r.apiCollector.CollectStatInfo(val, statcommon.ConvertAPICallGaugeToLabels(val, r.bindIP),
+				statcommon.SDKAPIStrategy, statcommon.SDKAPILabelOrder)
+		}
+	case model.ServiceStat, model.InstanceStat:
+		if val, ok := metricsVal.(*model.ServiceCallResult); ok {
+			r.insCollector.CollectStatInfo(val, statcommon.ConvertInsGaugeToLabels(val, r.bindIP),
+				statcommon.ServiceCallStrategy, statcommon.ServiceCallLabelOrder)
+		}
+	case model.CircuitBreakStat:
+		if val, ok := metricsVal.(*model.CircuitBreakGauge); ok {
+			r.circuitBreakerCollector.CollectStatInfo(val, statcommon.ConvertCircuitBreakGaugeToLabels(val, r.bindIP),
+				statcommon.CircuitBreakerStrategy, statcommon.CircuitBreakerLabelOrder)
+		}
+	case model.RateLimitStat:
+		if val, ok := metricsVal.(*model.RateLimitGauge); ok {
+			r.rateLimitCollector.CollectStatInfo(val, statcommon.ConvertRateLimitGaugeToLabels(val),
+				statcommon.RateLimitStrategy, statcommon.RateLimitLabelOrder)
+		}
+	case model.RouteStat:
+		if val, ok := metricsVal.(*servicerouter.RouteGauge); ok {
+			labels := statcommon.ConvertRouteGaugeToLabels(val, r.routePluginName(val.PluginID), r.bindIP)
+			r.routeCollector.CollectStatInfo(val, labels, statcommon.RouteStrategy, statcommon.RouteLabelOrder)
+		}
+	case model.LoadBalanceStat:
+		if val, ok := metricsVal.(*loadbalance.LoadBalanceGauge); ok && !reflect2.IsNil(val.Inst) {
+			r.loadBalanceCollector.CollectStatInfo(val, statcommon.ConvertLoadBalanceGaugeToLabels(val, r.bindIP),
+				statcommon.LoadBalanceStrategy, statcommon.LoadBalanceLabelOrder)
+		}
+	}
+	return nil
+}
+
+// routePluginName 根据插件 ID 解析路由插件名
+func (r *Reporter) routePluginName(id int32) string {
+	if r.plugins == nil {
+		return statcommon.NilValue
+	}
+	p, err := r.plugins.GetPluginById(id)
+	if err != nil {
+		return statcommon.NilValue
+	}
+	return p.Name()
+}
+
+// Info 插件信息，推送模式无需对外暴露拉取地址.
+func (r *Reporter) Info() model.StatInfo {
+	return model.StatInfo{}
+}
+
+// Destroy 销毁插件.
+func (r *Reporter) Destroy() error {
+	if r.PluginBase != nil {
+		if err := r.PluginBase.Destroy(); err != nil {
+			return err
+		}
+	}
+	if r.cancel != nil {
+		r.cancel()
+		r.wg.Wait()
+	}
+	if r.exporter != nil {
+		return r.exporter.Close()
+	}
+	return nil
+}
@@ -0,0 +1,393 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package otel
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/pkg/stat/loadbalance"
+	statcommon "github.com/polarismesh/polaris-go/plugin/metrics/common"
+)
+
+// stringAttributes 将 KeyValue 列表转换为 map，便于断言
+func stringAttributes(kvs []*commonpb.KeyValue) map[string]string {
+	ret := map[string]string{}
+	for _, kv := range kvs {
+		ret[kv.GetKey()] = kv.GetValue().GetStringValue()
+	}
+	return ret
+}
+
+func TestBuildExportRequest(t *testing.T) {
+	start := time.Unix(1699999985, 0)
+	now := time.Unix(1700000000, 123)
+	req := buildExportRequest(map[string]string{"service.name": "demo"}, scopeName, "v1.0.0",
+		[]*metricData{{
+			name:        "upstream_rq_total",
+			description: "total of request per period",
+			sum:         true,
+			points: []dataPoint{
+				{attributes: map[string]string{"callee_service": "svc", "callee_subset": ""}, value: 3},
+			},
+		}, {
+			name:   "upstream_rq_max_timeout",
+			unit:   metricUnitMillis,
+			points: []dataPoint{{value: 20}},
+		}}, start, now)
+
+	// 经过 protobuf 编解码后内容保持一致
+	data, err := proto.Marshal(req)
+	assert.Nil(t, err)
+	decoded := &colmetricpb.ExportMetricsServiceRequest{}
+	assert.Nil(t, proto.Unmarshal(data, decoded))
+	assert.True(t, proto.Equal(req, decoded))
+
+	assert.Len(t, decoded.GetResourceMetrics(), 1)
+	resourceMetrics := decoded.GetResourceMetrics()[0]
+	assert.Equal(t, map[string]string{"service.name": "demo"},
+		stringAttributes(resourceMetrics.GetResource().GetAttributes()))
+	assert.Len(t, resourceMetrics.GetScopeMetrics(), 1)
+	scopeMetrics := resourceMetrics.GetScopeMetrics()[0]
+	assert.Equal(t, scopeName, scopeMetrics.GetScope().GetName())
+	assert.Equal(t, "v1.0.0", scopeMetrics.GetScope().GetVersion())
+
+	assert.Len(t, scopeMetrics.GetMetrics(), 2)
+	metric := scopeMetrics.GetMetrics()[0]
+	assert.Equal(t, "upstream_rq_total", metric.GetName())
+	assert.Equal(t, "total of request per period", metric.GetDescription())
+	assert.Empty(t, metric.GetUnit())
+
+	// 计数类指标以 DELTA 时间性、单调递增的 Sum 导出，数据点携带周期起始时间
+	assert.Nil(t, metric.GetGauge())
+	sum := metric.GetSum()
+	assert.Equal(t, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, sum.GetAggregationTemporality())
+	assert.True(t, sum.GetIsMonotonic())
+	assert.Len(t, sum.GetDataPoints(), 1)
+	point := sum.GetDataPoints()[0]
+	assert.Equal(t, uint64(start.UnixNano()), point.GetStartTimeUnixNano())
+	assert.Equal(t, uint64(now.UnixNano()), point.GetTimeUnixNano())
+	assert.Equal(t, float64(3), point.GetAsDouble())
+	// 空字符串属性值同样需要保留
+	assert.Equal(t, map[string]string{"callee_service": "svc", "callee_subset": ""},
+		stringAttributes(point.GetAttributes()))
+
+	// 最大值类指标以 Gauge 导出，数据点不携带起始时间
+	metric = scopeMetrics.GetMetrics()[1]
+	assert.Equal(t, metricUnitMillis, metric.GetUnit())
+	assert.Nil(t, metric.GetSum())
+	point = metric.GetGauge().GetDataPoints()[0]
+	assert.Zero(t, point.GetStartTimeUnixNano())
+	assert.Equal(t, float64(20), point.GetAsDouble())
+}
+
+// newTestExportRequest 构造只包含一个指标的导出请求
+func newTestExportRequest() *colmetricpb.ExportMetricsServiceRequest {
+	return buildExportRequest(map[string]string{"service.name": "demo"}, scopeName, "v1.0.0",
+		[]*metricData{{name: "upstream_rq_total", sum: true, points: []dataPoint{{value: 1}}}},
+		time.Unix(1699999985, 0), time.Unix(1700000000, 0))
+}
+
+// fakeMetricsService 记录收到的导出请求与 metadata 的 OTLP 接收端
+type fakeMetricsService struct {
+	colmetricpb.UnimplementedMetricsServiceServer
+	received chan *colmetricpb.ExportMetricsServiceRequest
+	tokens   chan []string
+}
+
+func (f *fakeMetricsService) Export(ctx context.Context,
+	req *colmetricpb.ExportMetricsServiceRequest) (*colmetricpb.ExportMetricsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	f.tokens <- md.Get("x-token")
+	f.received <- req
+	return &colmetricpb.ExportMetricsServiceResponse{}, nil
+}
+
+func newTestInstance() model.Instance {
+	return pb.NewInstanceInProto(&apiservice.Instance{
+		Id:   wrapperspb.String("inst-1"),
+		Host: wrapperspb.String("127.0.0.1"),
+		Port: wrapperspb.UInt32(8080),
+	}, &model.ServiceKey{Namespace: "test-ns", Service: "test-svc"}, local.NewInstanceLocalValue())
+}
+
+func newTestReporter() *Reporter {
+	cfg := &Config{}
+	cfg.SetDefault()
+	r := &Reporter{cfg: cfg}
+	r.init("10.0.0.1")
+	return r
+}
+
+func findMetric(metrics []*metricData, name string) *metricData {
+	for _, m := range metrics {
+		if m.name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestReporter_Collect(t *testing.T) {
+	r := newTestReporter()
+	inst := newTestInstance()
+
+	apiResult := &model.APICallResult{APICallKey: model.APICallKey{APIName: model.ApiGetOneInstance}}
+	apiResult.SetSuccess(10 * time.Millisecond)
+	assert.Nil(t, r.ReportStat(model.SDKAPIStat, apiResult))
+
+	callResult := &model.ServiceCallResult{}
+	callResult.SetCalledInstance(inst)
+	callResult.SetRetStatus(model.RetSuccess)
+	callResult.SetDelay(20 * time.Millisecond)
+	assert.Nil(t, r.ReportStat(model.ServiceStat, callResult))
+
+	assert.Nil(t, r.ReportStat(model.RateLimitStat, &model.RateLimitGauge{
+		Namespace: "test-ns", Service: "test-svc", Result: model.QuotaResultOk,
+	}))
+	assert.Nil(t, r.ReportStat(model.CircuitBreakStat, &model.CircuitBreakGauge{
+		Level:         "SERVICE",
+		RuleName:      "cb-rule",
+		CalleeService: &model.ServiceKey{Namespace: "test-ns", Service: "test-svc"},
+		CBStatus:      model.NewCircuitBreakerStatus("cb-rule", model.Open, time.Now()),
+	}))
+	assert.Nil(t, r.ReportStat(model.RouteStat, &servicerouter.RouteGauge{
+		SrcService: model.ServiceKey{Namespace: "caller-ns", Service: "caller-svc"},
+		Status:     servicerouter.Normal,
+	}))
+	assert.Nil(t, r.ReportStat(model.LoadBalanceStat, &loadbalance.LoadBalanceGauge{
+		Inst: inst, LbType: "leastRequest", Inflight: 5,
+	}))
+	// 没有实例的负载均衡数据会被忽略
+	assert.Nil(t, r.ReportStat(model.LoadBalanceStat, &loadbalance.LoadBalanceGauge{LbType: "leastRequest"}))
+
+	metrics := r.collect()
+	for _, name := range []string{
+		statcommon.MetricsNameSDKAPIRequestTotal,
+		statcommon.MetricsNameSDKAPIRequestDelay,
+		statcommon.MetricsNameUpstreamRequestTotal,
+		statcommon.MetricsNameRateLimitRequestTotal,
+		statcommon.MetricsNameCircuitBreakerOpen,
+		statcommon.MetricsNameRouteRequestTotal,
+		statcommon.MetricsNameLoadBalanceMaxInflight,
+	} {
+		assert.NotNil(t, findMetric(metrics, name), "metric %s not collected", name)
+	}
+	for i := 1; i < len(metrics); i++ {
+		assert.True(t, metrics[i-1].name < metrics[i].name, "metrics must be sorted by name")
+	}
+
+	delay := findMetric(metrics, statcommon.MetricsNameSDKAPIRequestDelay)
+	assert.Equal(t, metricUnitMillis, delay.unit)
+	assert.NotEmpty(t, delay.description)
+
+	assert.True(t, findMetric(metrics, statcommon.MetricsNameUpstreamRequestTotal).sum)
+	assert.True(t, findMetric(metrics, statcommon.MetricsNameRateLimitRequestTotal).sum)
+	assert.False(t, findMetric(metrics, statcommon.MetricsNameCircuitBreakerOpen).sum)
+
+	lb := findMetric(metrics, statcommon.MetricsNameLoadBalanceMaxInflight)
+	assert.False(t, lb.sum)
+	assert.Len(t, lb.points, 1)
+	assert.Equal(t, float64(5), lb.points[0].value)
+	attributes := lb.points[0].attributes
+	assert.Equal(t, "leastRequest", attributes[statcommon.LoadBalanceType])
+	assert.Equal(t, "127.0.0.1:8080", attributes[statcommon.CalleeInstance])
+	assert.Equal(t, "10.0.0.1", attributes[statcommon.CallerIP])
+	_, ok := attributes[statcommon.MetricNameLabel]
+	assert.False(t, ok, "metric_name must not be exported as attribute")
+
+	route := findMetric(metrics, statcommon.MetricsNameRouteRequestTotal)
+	assert.Equal(t, statcommon.NilValue, route.points[0].attributes[statcommon.RoutePlugin])
+
+	// 下一个周期没有新数据时，周期类指标导出 0，熔断状态保持
+	metrics = r.collect()
+	total := findMetric(metrics, statcommon.MetricsNameUpstreamRequestTotal)
+	assert.Equal(t, float64(0), total.points[0].value)
+	open := findMetric(metrics, statcommon.MetricsNameCircuitBreakerOpen)
+	assert.Equal(t, float64(1), open.points[0].value)
+
+	// 连续多个周期无数据后，周期类指标被淘汰
+	r.collect()
+	metrics = r.collect()
+	assert.Nil(t, findMetric(metrics, statcommon.MetricsNameUpstreamRequestTotal))
+	assert.NotNil(t, findMetric(metrics, statcommon.MetricsNameCircuitBreakerOpen))
+}
+
+func TestHTTPExporter(t *testing.T) {
+	var (
+		gotBody        []byte
+		gotContentType string
+		gotToken       string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotBody, _ = ioutil.ReadAll(req.Body)
+		gotContentType = req.Header.Get("Content-Type")
+		gotToken = req.Header.Get("x-token")
+		if req.URL.Path != "/v1/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+		}
+	}))
+	defer server.Close()
+
+	cfg := &Config{Protocol: ProtocolHTTP, Endpoint: server.URL + "/v1/metrics", Headers: map[string]string{"x-token": "abc"},
+		AllowInsecureHeaders: true}
+	cfg.SetDefault()
+	assert.Nil(t, cfg.Verify())
+	exp, err := newExporter(cfg)
+	assert.Nil(t, err)
+	defer exp.Close()
+	exportReq := newTestExportRequest()
+	assert.Nil(t, exp.export(context.Background(), exportReq))
+	gotReq := &colmetricpb.ExportMetricsServiceRequest{}
+	assert.Nil(t, proto.Unmarshal(gotBody, gotReq))
+	assert.True(t, proto.Equal(exportReq, gotReq))
+	assert.Equal(t, "application/x-protobuf", gotContentType)
+	assert.Equal(t, "abc", gotToken)
+
+	cfg.Endpoint = server.URL + "/unknown"
+	exp, err = newExporter(cfg)
+	assert.Nil(t, err)
+	defer exp.Close()
+	err = exp.export(context.Background(), exportReq)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestGRPCExporter(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	service := &fakeMetricsService{
+		received: make(chan *colmetricpb.ExportMetricsServiceRequest, 1),
+		tokens:   make(chan []string, 1),
+	}
+	server := grpc.NewServer()
+	colmetricpb.RegisterMetricsServiceServer(server, service)
+	go func() {
+		_ = server.Serve(ln)
+	}()
+	defer server.Stop()
+
+	cfg := &Config{Protocol: ProtocolGRPC, Endpoint: ln.Addr().String(), Headers: map[string]string{"x-token": "abc"},
+		AllowInsecureHeaders: true}
+	cfg.SetDefault()
+	assert.Nil(t, cfg.Verify())
+	exp, err := newExporter(cfg)
+	assert.Nil(t, err)
+	defer exp.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	exportReq := newTestExportRequest()
+	assert.Nil(t, exp.export(ctx, exportReq))
+	assert.True(t, proto.Equal(exportReq, <-service.received))
+	assert.Equal(t, []string{"abc"}, <-service.tokens)
+}
+
+// TestHTTPSExporter 测试通过 https 导出时使用 TLS 配置中的 CA 校验服务端证书
+func TestHTTPSExporter(t *testing.T) {
+	var gotToken string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotToken = req.Header.Get("x-token")
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "otel")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	caFile := filepath.Join(dir, "ca.pem")
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.Nil(t, ioutil.WriteFile(caFile, caData, 0600))
+
+	cfg := &Config{Protocol: ProtocolHTTP, Endpoint: server.URL + "/v1/metrics", Headers: map[string]string{"x-token": "abc"}}
+	cfg.SetDefault()
+	// 未配置 CA 时无法校验自签名证书
+	exp, err := newExporter(cfg)
+	assert.Nil(t, err)
+	assert.NotNil(t, exp.export(context.Background(), newTestExportRequest()))
+	_ = exp.Close()
+
+	cfg.TLS.SetEnable(true)
+	cfg.TLS.SetCAFile(caFile)
+	assert.Nil(t, cfg.Verify())
+	exp, err = newExporter(cfg)
+	assert.Nil(t, err)
+	defer exp.Close()
+	assert.Nil(t, exp.export(context.Background(), newTestExportRequest()))
+	assert.Equal(t, "abc", gotToken)
+}
+
+// TestConfig_VerifyHeaders 测试 headers 只能通过加密连接发送，除非显式允许明文发送
+func TestConfig_VerifyHeaders(t *testing.T) {
+	headers := map[string]string{"Authorization": "Bearer token"}
+	cases := []struct {
+		name  string
+		cfg   *Config
+		valid bool
+	}{
+		{name: "grpc without headers", cfg: &Config{Protocol: ProtocolGRPC}, valid: true},
+		{name: "grpc insecure with headers", cfg: &Config{Protocol: ProtocolGRPC, Headers: headers}},
+		{name: "grpc insecure with headers allowed",
+			cfg: &Config{Protocol: ProtocolGRPC, Headers: headers, AllowInsecureHeaders: true}, valid: true},
+		{name: "grpc tls with headers", cfg: &Config{Protocol: ProtocolGRPC, Headers: headers,
+			TLS: &config.TLSConfigImpl{Enable: boolPtr(true)}}, valid: true},
+		{name: "http with headers",
+			cfg: &Config{Protocol: ProtocolHTTP, Endpoint: "http://127.0.0.1:4318/v1/metrics", Headers: headers}},
+		{name: "https with headers",
+			cfg:   &Config{Protocol: ProtocolHTTP, Endpoint: "https://127.0.0.1:4318/v1/metrics", Headers: headers},
+			valid: true},
+		{name: "http with tls enabled", cfg: &Config{Protocol: ProtocolHTTP,
+			Endpoint: "http://127.0.0.1:4318/v1/metrics", TLS: &config.TLSConfigImpl{Enable: boolPtr(true)}}},
+	}
+	for _, c := range cases {
+		c.cfg.SetDefault()
+		err := c.cfg.Verify()
+		if c.valid {
+			assert.Nil(t, err, c.name)
+		} else {
+			assert.NotNil(t, err, c.name)
+		}
+	}
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
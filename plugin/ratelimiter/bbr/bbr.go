@@ -0,0 +1,100 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package bbr 提供基于系统负载的自适应限流插件。
+// 限流规则的 Action 为 bbr 时由框架选用本插件，根据 CPU 使用率、在途请求数与响应时间自适应估算并发容量，
+// 无需手工配置阈值；只依赖本地信号，无远程同步依赖。
+package bbr
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// RateLimiterBBR 基于系统负载的自适应限流控制器
+type RateLimiterBBR struct {
+	*plugin.PluginBase
+	cfg    *Config
+	cpu    *cpuMonitor
+	logCtx *log.ContextLogger
+}
+
+// Type 插件类型
+func (g *RateLimiterBBR) Type() common.Type {
+	return common.TypeRateLimiter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *RateLimiterBBR) Name() string {
+	return config.DefaultBBRRateLimiter
+}
+
+// Init 初始化插件
+func (g *RateLimiterBBR) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.logCtx = ctx.ValueCtx.GetContextLogger()
+	g.cfg = &Config{}
+	cfgValue := ctx.Config.GetProvider().GetRateLimit().GetPluginConfig(g.Name())
+	if cfgValue != nil {
+		g.cfg = cfgValue.(*Config)
+	}
+	g.cfg.SetDefault()
+	sampler, err := newCPUSampler()
+	if err != nil {
+		// 无法采样时 CPU 使用率恒为 0，自适应限流不会触发
+		g.logCtx.GetRateLimitLogger().Warnf("%s cpu usage is unavailable, bbr limiter will never trigger, err: %v",
+			logTag, err)
+	}
+	g.cpu = newCPUMonitor(sampler)
+	return nil
+}
+
+// Start 启动 CPU 使用率采样
+func (g *RateLimiterBBR) Start() error {
+	if g.cpu.sampler != nil {
+		go g.cpu.run(*g.cfg.SampleInterval, g.logCtx.GetRateLimitLogger())
+	}
+	return nil
+}
+
+// Destroy 销毁插件，停止 CPU 使用率采样
+func (g *RateLimiterBBR) Destroy() error {
+	if g.cpu != nil {
+		g.cpu.stop()
+	}
+	return nil
+}
+
+// IsEnable enable ?
+func (g *RateLimiterBBR) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// InitQuota 初始化并创建自适应配额池
+// 主流程会在首次调用，以及规则对象变更的时候，调用该方法
+func (g *RateLimiterBBR) InitQuota(criteria *ratelimiter.InitCriteria) ratelimiter.QuotaBucket {
+	return NewBBRBucket(criteria, g.cfg, g.cpu.Usage, g.logCtx)
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&RateLimiterBBR{}, &Config{})
+}
@@ -0,0 +1,336 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package bbr
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+	"github.com/polarismesh/polaris-go/plugin/ratelimiter/common"
+)
+
+// logTag bbr 插件限流日志统一前缀；与 reject/unirate/warmup 形成对照.
+const logTag = "[RateLimit][BBR]"
+
+// BBRBucket 基于系统负载的自适应配额池，参考 BBR 拥塞控制的思路：
+// 滑动窗口内单桶最大通过数 maxPass 与最小平均响应时间 minRT 的乘积即为系统在最优状态下能够承载的并发数，
+// CPU 使用率超过阈值时，在途请求数超过该估算值的请求被拒绝；CPU 回落后在冷却时间内继续按估算值限流，
+// 避免 CPU 抖动导致瞬间放通过量请求。
+// 请求通过后需由调用方在请求结束时调用 QuotaFuture.Release，用于统计在途请求数、通过数与响应时间；
+// 超过 releaseTimeout 仍未归还的请求会被自动回收，窗口内没有完成样本时无法估算容量，不会限流。
+type BBRBucket struct {
+	rule      *apitraffic.Rule
+	windowKey string
+	// 获取平滑后的 CPU 使用率
+	cpuUsage      func() float64
+	cpuThreshold  float64
+	coolDownMilli int64
+	windowMilli   int64
+	slotMilli     int64
+	// 在途请求未归还的最长时间，超过后自动回收
+	releaseTimeout time.Duration
+	// 当前时间，便于测试替换
+	now func() time.Time
+	// 在途请求数
+	inFlight int64
+	// 最近一次进入限流状态的时间，为 0 表示未处于限流或冷却状态
+	prevDropMilli int64
+	// 是否已提示过存在未归还的请求，只提示一次
+	expireWarned int32
+	// 保护滑窗、在途请求队列与估算缓存
+	mutex sync.Mutex
+	slots []bbrSlot
+	// 尚未归还的在途请求，按通过时间先后排列
+	permits []*bbrPermit
+	// 估算的并发容量按桶缓存，同一个桶内不重复计算
+	cachedSlotStart   int64
+	cachedMaxInFlight int64
+	cachedSampled     bool
+	logCtx            *log.ContextLogger
+}
+
+// bbrPermit 一个通过的在途请求
+type bbrPermit struct {
+	start time.Time
+	// 是否已归还，由 Release 回调或超时回收置为 1，保证只归还一次
+	done int32
+}
+
+// bbrSlot 滑动窗口中的单个桶
+type bbrSlot struct {
+	// 桶的起始时间，毫秒
+	startMilli int64
+	// 桶内完成的请求数
+	pass int64
+	// 桶内被拒绝的请求数
+	limited int64
+	// 桶内完成请求的响应时间总和，微秒
+	rtSumMicro int64
+}
+
+// NewBBRBucket 创建自适应配额池，cpuUsage 返回平滑后的 CPU 使用率（百分比）
+func NewBBRBucket(criteria *ratelimiter.InitCriteria, cfg *Config, cpuUsage func() float64,
+	logCtx *log.ContextLogger) *BBRBucket {
+	rule := criteria.DstRule
+	windowMilli := model.ToMilliSeconds(*cfg.Window)
+	bucket := &BBRBucket{
+		rule:           rule,
+		windowKey:      criteria.WindowKey,
+		cpuUsage:       cpuUsage,
+		cpuThreshold:   cfg.CPUThreshold,
+		coolDownMilli:  model.ToMilliSeconds(*cfg.CoolDown),
+		windowMilli:    windowMilli,
+		slotMilli:      windowMilli / int64(cfg.BucketCount),
+		releaseTimeout: *cfg.ReleaseTimeout,
+		now:            time.Now,
+		slots:          make([]bbrSlot, cfg.BucketCount),
+		logCtx:         logCtx,
+	}
+	logCtx.GetRateLimitLogger().Infof(
+		"%s created bucket windowKey=%q rule[%s] method=%s window=%s bucketCount=%d cpuThreshold=%v coolDown=%s %s",
+		logTag, criteria.WindowKey, common.RuleID(rule),
+		rule.GetMethod().GetValue().GetValue(),
+		*cfg.Window, cfg.BucketCount, cfg.CPUThreshold, *cfg.CoolDown,
+		common.FormatRuleSummary(rule),
+	)
+	return bucket
+}
+
+// GetQuota 判断系统是否过载并分配配额；token 不参与计算，每次调用按一个在途请求统计
+func (b *BBRBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
+	start := b.now()
+	nowMilli := toMilli(start)
+	logger := b.logCtx.GetRateLimitLogger()
+	b.reclaim(start)
+	if drop, maxInFlight := b.shouldDrop(nowMilli); drop {
+		b.mutex.Lock()
+		b.slotAt(nowMilli).limited++
+		b.mutex.Unlock()
+		if logger.IsLevelEnabled(log.DebugLog) {
+			logger.Debugf("%s limited rule[%s] windowKey=%s cpu=%.1f inFlight=%d maxInFlight=%d",
+				logTag, common.RuleID(b.rule), b.windowKey, b.cpuUsage(), atomic.LoadInt64(&b.inFlight), maxInFlight)
+		}
+		return &model.QuotaResponse{
+			Code: model.QuotaResultLimited,
+			// info 格式 "<resource>:<估算的并发容量>"，与并发数限流保持一致
+			Info: fmt.Sprintf("%s:%d", b.rule.GetResource().String(), maxInFlight),
+		}
+	}
+	permit := &bbrPermit{start: start}
+	atomic.AddInt64(&b.inFlight, 1)
+	b.mutex.Lock()
+	b.permits = append(b.permits, permit)
+	b.mutex.Unlock()
+	resp := &model.QuotaResponse{
+		Code: model.QuotaResultOk,
+	}
+	resp.AddRelease(func() {
+		b.onDone(permit)
+	})
+	return resp
+}
+
+// onDone 请求结束，减少在途请求数并记录通过数与响应时间；已被超时回收的请求不再重复统计
+func (b *BBRBucket) onDone(permit *bbrPermit) {
+	if !atomic.CompareAndSwapInt32(&permit.done, 0, 1) {
+		return
+	}
+	atomic.AddInt64(&b.inFlight, -1)
+	end := b.now()
+	rt := end.Sub(permit.start)
+	if rt < 0 {
+		rt = 0
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	slot := b.slotAt(toMilli(end))
+	slot.pass++
+	slot.rtSumMicro += rt.Microseconds()
+}
+
+// reclaim 移除队首已归还的请求，并回收超过 releaseTimeout 仍未归还的请求。
+// 回收只归还在途计数，不计入完成数与响应时间，避免调用方从不调用 Release 时在途请求数只增不减
+func (b *BBRBucket) reclaim(now time.Time) {
+	var expired int
+	b.mutex.Lock()
+	i := 0
+	for ; i < len(b.permits); i++ {
+		permit := b.permits[i]
+		if atomic.LoadInt32(&permit.done) == 0 {
+			if now.Sub(permit.start) < b.releaseTimeout {
+				break
+			}
+			if atomic.CompareAndSwapInt32(&permit.done, 0, 1) {
+				atomic.AddInt64(&b.inFlight, -1)
+				expired++
+			}
+		}
+		b.permits[i] = nil
+	}
+	b.permits = b.permits[i:]
+	b.mutex.Unlock()
+	if expired > 0 && atomic.CompareAndSwapInt32(&b.expireWarned, 0, 1) {
+		b.logCtx.GetRateLimitLogger().Warnf(
+			"%s %d permits of rule[%s] windowKey=%s were not released within %s, "+
+				"QuotaFuture.Release must be called when request finished, otherwise bbr limiter will not take effect",
+			logTag, expired, common.RuleID(b.rule), b.windowKey, b.releaseTimeout)
+	}
+}
+
+// shouldDrop 判断本次请求是否需要拒绝，同时返回当前估算的并发容量
+func (b *BBRBucket) shouldDrop(nowMilli int64) (bool, int64) {
+	maxInFlight, sampled := b.maxInFlight(nowMilli)
+	if !sampled {
+		// 窗口内没有已完成请求的样本，无法估算容量
+		return false, maxInFlight
+	}
+	inFlight := atomic.LoadInt64(&b.inFlight)
+	overCapacity := inFlight > 1 && inFlight > maxInFlight
+	prevDropMilli := atomic.LoadInt64(&b.prevDropMilli)
+	cpu := b.cpuUsage()
+	if cpu < b.cpuThreshold {
+		if prevDropMilli == 0 {
+			return false, maxInFlight
+		}
+		if nowMilli-prevDropMilli <= b.coolDownMilli {
+			return overCapacity, maxInFlight
+		}
+		if atomic.CompareAndSwapInt64(&b.prevDropMilli, prevDropMilli, 0) {
+			b.logCtx.GetRateLimitLogger().Infof("%s leave overload rule[%s] windowKey=%s cpu=%.1f",
+				logTag, common.RuleID(b.rule), b.windowKey, cpu)
+		}
+		return false, maxInFlight
+	}
+	if overCapacity && prevDropMilli == 0 && atomic.CompareAndSwapInt64(&b.prevDropMilli, 0, nowMilli) {
+		b.logCtx.GetRateLimitLogger().Infof(
+			"%s enter overload rule[%s] windowKey=%s cpu=%.1f inFlight=%d maxInFlight=%d",
+			logTag, common.RuleID(b.rule), b.windowKey, cpu, inFlight, maxInFlight)
+	}
+	return overCapacity, maxInFlight
+}
+
+// maxInFlight 根据滑动窗口中已结束的桶估算并发容量：maxPass * minRT / 单桶时长，至少为 1；
+// 同时返回窗口内是否存在已完成请求的样本
+func (b *BBRBucket) maxInFlight(nowMilli int64) (int64, bool) {
+	curStart := nowMilli - nowMilli%b.slotMilli
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.cachedSlotStart == curStart && b.cachedMaxInFlight > 0 {
+		return b.cachedMaxInFlight, b.cachedSampled
+	}
+	var maxPass int64
+	minRTMilli := math.MaxFloat64
+	for i := range b.slots {
+		slot := &b.slots[i]
+		if slot.startMilli == curStart || !b.inWindow(slot, curStart) || slot.pass == 0 {
+			continue
+		}
+		if slot.pass > maxPass {
+			maxPass = slot.pass
+		}
+		if rt := float64(slot.rtSumMicro) / float64(slot.pass) / 1e3; rt < minRTMilli {
+			minRTMilli = rt
+		}
+	}
+	sampled := maxPass > 0
+	if !sampled {
+		maxPass = 1
+	}
+	if minRTMilli == math.MaxFloat64 {
+		minRTMilli = 1
+	}
+	maxInFlight := int64(math.Ceil(float64(maxPass) * minRTMilli / float64(b.slotMilli)))
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+	b.cachedSlotStart = curStart
+	b.cachedMaxInFlight = maxInFlight
+	b.cachedSampled = sampled
+	return maxInFlight, sampled
+}
+
+// slotAt 获取时间点所在的桶，桶已过期时重置，调用方需持有锁
+func (b *BBRBucket) slotAt(nowMilli int64) *bbrSlot {
+	start := nowMilli - nowMilli%b.slotMilli
+	slot := &b.slots[(start/b.slotMilli)%int64(len(b.slots))]
+	if slot.startMilli != start {
+		*slot = bbrSlot{startMilli: start}
+	}
+	return slot
+}
+
+// inWindow 桶是否仍在以 curStart 所在桶结尾的滑动窗口内
+func (b *BBRBucket) inWindow(slot *bbrSlot, curStart int64) bool {
+	return slot.startMilli <= curStart && curStart-slot.startMilli < b.windowMilli
+}
+
+// Release 释放配额；在途请求数由 GetQuota 注入的回调归还，此处无需处理
+func (b *BBRBucket) Release() {
+}
+
+// OnRemoteUpdate 远程配额更新（自适应限流只依赖本地信号，框架强制使用本地模式，无需处理）
+func (b *BBRBucket) OnRemoteUpdate(remoteQuota ratelimiter.RemoteQuotaResult) {
+}
+
+// GetQuotaUsed 获取滑动窗口内的通过数与拒绝数，以窗口长度为统计周期
+func (b *BBRBucket) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageInfo {
+	nowMilli := toMilli(b.now())
+	curStart := nowMilli - nowMilli%b.slotMilli
+	var passed, limited int64
+	b.mutex.Lock()
+	for i := range b.slots {
+		if slot := &b.slots[i]; b.inWindow(slot, curStart) {
+			passed += slot.pass
+			limited += slot.limited
+		}
+	}
+	b.mutex.Unlock()
+	return ratelimiter.UsageInfo{
+		CurTimeMilli: curTimeMilli,
+		Passed:       map[int64]uint32{b.windowMilli: uint32(passed)},
+		Limited:      map[int64]uint32{b.windowMilli: uint32(limited)},
+	}
+}
+
+// GetAmountInfos 获取当前估算的并发容量
+func (b *BBRBucket) GetAmountInfos() []ratelimiter.AmountInfo {
+	maxInFlight, _ := b.maxInFlight(toMilli(b.now()))
+	return []ratelimiter.AmountInfo{
+		{
+			ValidDuration: 0, // 与并发数限流一致，估算的是并发容量，无时间窗口概念
+			MaxAmount:     uint32(maxInFlight),
+		},
+	}
+}
+
+// InFlight 获取当前在途请求数
+func (b *BBRBucket) InFlight() int64 {
+	return atomic.LoadInt64(&b.inFlight)
+}
+
+func toMilli(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
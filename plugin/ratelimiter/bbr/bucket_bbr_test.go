@@ -0,0 +1,259 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package bbr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// silentLogger 一个完全静默的 log.Logger 实现，用于只关心限流行为的用例.
+type silentLogger struct{}
+
+func (silentLogger) Tracef(string, ...interface{}) {}
+func (silentLogger) Debugf(string, ...interface{}) {}
+func (silentLogger) Infof(string, ...interface{})  {}
+func (silentLogger) Warnf(string, ...interface{})  {}
+func (silentLogger) Errorf(string, ...interface{}) {}
+func (silentLogger) Fatalf(string, ...interface{}) {}
+func (silentLogger) IsLevelEnabled(int) bool       { return false }
+func (silentLogger) SetLogLevel(int) error         { return nil }
+
+// noopCtx 返回一个挂载了静默 logger 的 ContextLogger.
+func noopCtx() *log.ContextLogger {
+	orig := log.GetRateLimitLogger()
+	log.SetRateLimitLogger(silentLogger{})
+	ctx := &log.ContextLogger{}
+	ctx.Init()
+	log.SetRateLimitLogger(orig)
+	return ctx
+}
+
+// testBucket 带可控时钟与 CPU 使用率的自适应配额池
+type testBucket struct {
+	*BBRBucket
+	cur time.Time
+	cpu float64
+}
+
+// newTestBucket 创建窗口 1s、分桶 10 个（单桶 100ms）、CPU 阈值 80%、冷却 1s 的配额池
+func newTestBucket() *testBucket {
+	cfg := &Config{Window: model.ToDurationPtr(time.Second), BucketCount: 10}
+	cfg.SetDefault()
+	rule := &apitraffic.Rule{
+		Id:       wrapperspb.String("bbr-rule"),
+		Resource: apitraffic.Rule_QPS,
+		Type:     apitraffic.Rule_LOCAL,
+		Action:   wrapperspb.String("bbr"),
+	}
+	tb := &testBucket{cur: time.Unix(1000, 0)}
+	tb.BBRBucket = NewBBRBucket(&ratelimiter.InitCriteria{DstRule: rule, WindowKey: "test-svc#default"}, cfg,
+		func() float64 { return tb.cpu }, noopCtx())
+	tb.now = func() time.Time { return tb.cur }
+	return tb
+}
+
+// acquire 连续获取 n 次配额，返回通过请求的 future
+func (tb *testBucket) acquire(n int) ([]*model.QuotaFutureImpl, int) {
+	var futures []*model.QuotaFutureImpl
+	limited := 0
+	for i := 0; i < n; i++ {
+		resp := tb.GetQuota(toMilli(tb.cur), 1)
+		if resp.Code == model.QuotaResultLimited {
+			limited++
+			continue
+		}
+		futures = append(futures, model.QuotaFutureCompleted(resp))
+	}
+	return futures, limited
+}
+
+// warmUp 在当前桶内完成 50 个响应时间为 20ms 的请求，使估算容量为 50 * 20ms / 100ms = 10
+func (tb *testBucket) warmUp(t *testing.T) {
+	futures, limited := tb.acquire(50)
+	assert.Equal(t, 0, limited)
+	tb.cur = tb.cur.Add(20 * time.Millisecond)
+	for _, future := range futures {
+		future.Release()
+	}
+	assert.Equal(t, int64(0), tb.InFlight())
+	tb.cur = tb.cur.Add(100 * time.Millisecond)
+}
+
+// TestBBRBucket_NotOverloaded 验证 CPU 未过载时不限流。
+// 前置条件：CPU 使用率 10%，低于阈值 80%，且从未进入过载状态。
+// 预期结果：在途请求数远超估算容量时依然全部放通。
+func TestBBRBucket_NotOverloaded(t *testing.T) {
+	tb := newTestBucket()
+	tb.cpu = 10
+	futures, limited := tb.acquire(100)
+	assert.Equal(t, 0, limited)
+	assert.Equal(t, int64(100), tb.InFlight())
+	for _, future := range futures {
+		future.Release()
+	}
+	assert.Equal(t, int64(0), tb.InFlight(), "Release 后在途请求数应归零")
+}
+
+// TestBBRBucket_Overload 验证过载时按 maxPass * minRT 估算的并发容量限流，并上报估算容量与使用量。
+// 前置条件：上一个桶完成 50 个 RT 20ms 的请求，估算容量为 10；CPU 使用率 90%。
+// 预期结果：在途请求数达到 11 之后的请求被拒绝；GetAmountInfos 上报容量 10，GetQuotaUsed 上报通过 50、拒绝 5。
+func TestBBRBucket_Overload(t *testing.T) {
+	tb := newTestBucket()
+	tb.warmUp(t)
+	tb.cpu = 90
+	futures, limited := tb.acquire(16)
+	assert.Equal(t, 11, len(futures))
+	assert.Equal(t, 5, limited)
+
+	amounts := tb.GetAmountInfos()
+	assert.Equal(t, []ratelimiter.AmountInfo{{ValidDuration: 0, MaxAmount: 10}}, amounts)
+	usage := tb.GetQuotaUsed(toMilli(tb.cur))
+	assert.Equal(t, uint32(50), usage.Passed[1000])
+	assert.Equal(t, uint32(5), usage.Limited[1000])
+
+	for _, future := range futures {
+		future.Release()
+	}
+	_, limited = tb.acquire(11)
+	assert.Equal(t, 0, limited, "在途请求释放后应恢复放通")
+}
+
+// TestBBRBucket_CoolDown 验证 CPU 回落后的冷却行为。
+// 前置条件：过载触发过限流后 CPU 回落到 10%。
+// 预期结果：冷却时间（1s）内仍按估算容量限流；冷却结束后不再限流。
+func TestBBRBucket_CoolDown(t *testing.T) {
+	tb := newTestBucket()
+	tb.warmUp(t)
+	tb.cpu = 90
+	held, limited := tb.acquire(12)
+	assert.Equal(t, 1, limited)
+
+	tb.cpu = 10
+	tb.cur = tb.cur.Add(500 * time.Millisecond)
+	_, limited = tb.acquire(1)
+	assert.Equal(t, 1, limited, "冷却时间内超出估算容量仍应限流")
+
+	tb.cur = tb.cur.Add(600 * time.Millisecond)
+	more, limited := tb.acquire(5)
+	assert.Equal(t, 0, limited, "冷却结束后应放通")
+	for _, future := range append(held, more...) {
+		future.Release()
+	}
+}
+
+// TestBBRBucket_ReleaseTimeout 验证调用方从不调用 Release 时的回退行为。
+// 前置条件：CPU 使用率 90%，通过的请求都不调用 Release，releaseTimeout 为默认的 5s。
+// 预期结果：窗口内没有完成样本时不限流；超过 releaseTimeout 后在途请求被回收，之后迟到的 Release 不会重复归还。
+func TestBBRBucket_ReleaseTimeout(t *testing.T) {
+	tb := newTestBucket()
+	tb.cpu = 90
+	futures, limited := tb.acquire(20)
+	assert.Equal(t, 0, limited, "没有完成样本时无法估算容量，不应限流")
+	assert.Equal(t, int64(20), tb.InFlight())
+
+	tb.cur = tb.cur.Add(defaultReleaseTimeout)
+	_, limited = tb.acquire(1)
+	assert.Equal(t, 0, limited)
+	assert.Equal(t, int64(1), tb.InFlight(), "超时未归还的在途请求应被回收")
+
+	for _, future := range futures {
+		future.Release()
+	}
+	assert.Equal(t, int64(1), tb.InFlight(), "已回收的请求再调用 Release 不应重复归还")
+	usage := tb.GetQuotaUsed(toMilli(tb.cur))
+	assert.Equal(t, uint32(0), usage.Passed[1000], "回收的请求不计入完成数")
+}
+
+// TestConfig_Verify 验证配置默认值与校验。
+// 前置条件：分别构造默认配置与非法的 cpuThreshold、bucketCount。
+// 预期结果：默认配置校验通过，非法配置返回错误。
+func TestConfig_Verify(t *testing.T) {
+	cfg := &Config{}
+	cfg.SetDefault()
+	assert.NoError(t, cfg.Verify())
+	assert.Equal(t, defaultWindow, *cfg.Window)
+	assert.Equal(t, defaultCPUThreshold, cfg.CPUThreshold)
+
+	cfg.CPUThreshold = 120
+	assert.Error(t, cfg.Verify())
+	cfg.CPUThreshold = defaultCPUThreshold
+	cfg.BucketCount = -1
+	assert.Error(t, cfg.Verify())
+}
+
+// TestCPUSampler 验证基于 /proc/stat 与 cgroup v2 的 CPU 使用率计算，以及指数移动平均。
+// 前置条件：构造临时的 stat 文件，两次采样之间 CPU 时间与空闲时间各增加 100 与 25。
+// 预期结果：/proc/stat 采样为 75%；cgroup 配额为 2 核时累计时间增长按配额折算；平滑值按 0.05 权重合入。
+func TestCPUSampler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bbr")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	statPath := filepath.Join(dir, "stat")
+	assert.NoError(t, ioutil.WriteFile(statPath, []byte("cpu  100 0 100 200 0 0 0 0 0 0\ncpu0 1 1 1 1\n"), 0644))
+	sampler, err := newProcStatSampler(statPath)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(statPath, []byte("cpu  150 0 125 225 0 0 0 0 0 0\n"), 0644))
+	usage, err := sampler.sample()
+	assert.NoError(t, err)
+	assert.InDelta(t, 75.0, usage, 0.001)
+
+	maxPath := filepath.Join(dir, "cpu.max")
+	cgroupStatPath := filepath.Join(dir, "cpu.stat")
+	assert.NoError(t, ioutil.WriteFile(maxPath, []byte("max 100000\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(cgroupStatPath, []byte("usage_usec 1000\nuser_usec 800\n"), 0644))
+	_, err = newCgroupSampler(maxPath, cgroupStatPath)
+	assert.Error(t, err, "未限制 CPU 配额时不应使用 cgroup 采样")
+	assert.NoError(t, ioutil.WriteFile(maxPath, []byte("200000 100000\n"), 0644))
+	cgroup, err := newCgroupSampler(maxPath, cgroupStatPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, cgroup.quotaCores)
+	cgroup.lastTime = cgroup.lastTime.Add(-time.Second)
+	assert.NoError(t, ioutil.WriteFile(cgroupStatPath, []byte("usage_usec 1001000\n"), 0644))
+	usage, err = cgroup.sample()
+	assert.NoError(t, err)
+	assert.InDelta(t, 50.0, usage, 1.0)
+
+	monitor := newCPUMonitor(sampler)
+	monitor.update(100)
+	assert.InDelta(t, 5.0, monitor.Usage(), 0.001)
+}
+
+// TestNewCPUSampler_Unavailable 验证 cgroup 与 /proc/stat 均不可用时的采样器创建。
+// 前置条件：cpu.max、cpu.stat 与 /proc/stat 对应的文件都不存在。
+// 预期结果：返回错误，且采样器为 nil 接口，插件不会启动采样协程。
+func TestNewCPUSampler_Unavailable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bbr")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	sampler, err := newCPUSamplerFrom(filepath.Join(dir, "cpu.max"), filepath.Join(dir, "cpu.stat"),
+		filepath.Join(dir, "stat"))
+	assert.Error(t, err)
+	assert.True(t, sampler == nil, "采样器应为 nil 接口，而不是包装了 nil 指针的接口")
+}
@@ -0,0 +1,98 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package bbr
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	defaultWindow         = 10 * time.Second
+	defaultBucketCount    = 100
+	defaultCPUThreshold   = 80.0
+	defaultCoolDown       = time.Second
+	defaultSampleInterval = 500 * time.Millisecond
+	defaultReleaseTimeout = 5 * time.Second
+)
+
+// Config 自适应限流器配置
+type Config struct {
+	// Window 统计通过数与响应时间的滑动窗口长度
+	Window *time.Duration `yaml:"window" json:"window"`
+	// BucketCount 滑动窗口的分桶数，单桶时长为 Window/BucketCount
+	BucketCount int `yaml:"bucketCount" json:"bucketCount"`
+	// CPUThreshold 触发限流的 CPU 使用率阈值，单位为百分比
+	CPUThreshold float64 `yaml:"cpuThreshold" json:"cpuThreshold"`
+	// CoolDown CPU 回落到阈值以下后，继续按估算容量限流的冷却时间，避免 CPU 抖动导致放通过量请求
+	CoolDown *time.Duration `yaml:"coolDown" json:"coolDown"`
+	// SampleInterval CPU 使用率的采样间隔
+	SampleInterval *time.Duration `yaml:"sampleInterval" json:"sampleInterval"`
+	// ReleaseTimeout 通过的请求超过该时间仍未调用 QuotaFuture.Release 时，视为调用方不会释放，自动归还在途计数
+	ReleaseTimeout *time.Duration `yaml:"releaseTimeout" json:"releaseTimeout"`
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if nil == c.Window {
+		c.Window = model.ToDurationPtr(defaultWindow)
+	}
+	if c.BucketCount == 0 {
+		c.BucketCount = defaultBucketCount
+	}
+	if c.CPUThreshold == 0 {
+		c.CPUThreshold = defaultCPUThreshold
+	}
+	if nil == c.CoolDown {
+		c.CoolDown = model.ToDurationPtr(defaultCoolDown)
+	}
+	if nil == c.SampleInterval {
+		c.SampleInterval = model.ToDurationPtr(defaultSampleInterval)
+	}
+	if nil == c.ReleaseTimeout {
+		c.ReleaseTimeout = model.ToDurationPtr(defaultReleaseTimeout)
+	}
+}
+
+// Verify 校验配置值
+func (c *Config) Verify() error {
+	if nil == c.Window || nil == c.CoolDown || nil == c.SampleInterval || nil == c.ReleaseTimeout {
+		return fmt.Errorf("window, coolDown, sampleInterval and releaseTimeout must be configured")
+	}
+	if c.BucketCount <= 0 {
+		return fmt.Errorf("invalid bucketCount: %d, it must greater than 0", c.BucketCount)
+	}
+	if *c.Window < time.Duration(c.BucketCount)*time.Millisecond {
+		return fmt.Errorf("invalid window: %v, it must greater than or equal to bucketCount milliseconds", *c.Window)
+	}
+	if c.CPUThreshold <= 0 || c.CPUThreshold > 100 {
+		return fmt.Errorf("invalid cpuThreshold: %v, it must in (0, 100]", c.CPUThreshold)
+	}
+	if *c.CoolDown < 0 {
+		return fmt.Errorf("invalid coolDown: %v, it must greater than or equal to 0", *c.CoolDown)
+	}
+	if *c.SampleInterval <= 0 {
+		return fmt.Errorf("invalid sampleInterval: %v, it must greater than 0", *c.SampleInterval)
+	}
+	if *c.ReleaseTimeout <= 0 {
+		return fmt.Errorf("invalid releaseTimeout: %v, it must greater than 0", *c.ReleaseTimeout)
+	}
+	return nil
+}
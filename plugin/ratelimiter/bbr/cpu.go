@@ -0,0 +1,251 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package bbr
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+const (
+	// cpuDecay CPU 使用率指数移动平均的衰减系数，越大越平滑
+	cpuDecay = 0.95
+
+	procStatPath   = "/proc/stat"
+	cgroupCPUMax   = "/sys/fs/cgroup/cpu.max"
+	cgroupCPUStat  = "/sys/fs/cgroup/cpu.stat"
+	cgroupNoLimit  = "max"
+	cgroupUsageKey = "usage_usec"
+)
+
+// cpuSampler 采样自上一次采样以来的 CPU 使用率，单位为百分比
+type cpuSampler interface {
+	sample() (float64, error)
+}
+
+// newCPUSampler 容器配置了 cgroup v2 CPU 配额时按配额计算使用率，否则使用 /proc/stat 中的整机使用率；
+// 两者都不可用（如非 Linux 平台）时返回错误
+func newCPUSampler() (cpuSampler, error) {
+	return newCPUSamplerFrom(cgroupCPUMax, cgroupCPUStat, procStatPath)
+}
+
+// newCPUSamplerFrom 按指定的 cgroup 与 /proc/stat 文件路径创建采样器，便于测试
+func newCPUSamplerFrom(maxPath, statPath, procPath string) (cpuSampler, error) {
+	if sampler, err := newCgroupSampler(maxPath, statPath); err == nil {
+		return sampler, nil
+	}
+	sampler, err := newProcStatSampler(procPath)
+	if err != nil {
+		// 不能直接返回 *procStatSampler 类型的 nil，否则调用方拿到的是非 nil 的接口值
+		return nil, err
+	}
+	return sampler, nil
+}
+
+// cgroupSampler 基于 cgroup v2 cpu.stat 的累计 CPU 时间与 cpu.max 的配额计算使用率
+type cgroupSampler struct {
+	statPath      string
+	quotaCores    float64
+	lastUsageUsec uint64
+	lastTime      time.Time
+}
+
+func newCgroupSampler(maxPath, statPath string) (*cgroupSampler, error) {
+	content, err := ioutil.ReadFile(maxPath)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) != 2 || fields[0] == cgroupNoLimit {
+		return nil, fmt.Errorf("cpu quota of cgroup is not limited: %q", strings.TrimSpace(string(content)))
+	}
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nil, err
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || period <= 0 {
+		return nil, fmt.Errorf("invalid cpu period of cgroup: %q", fields[1])
+	}
+	sampler := &cgroupSampler{statPath: statPath, quotaCores: quota / period}
+	if sampler.lastUsageUsec, err = readCgroupUsage(statPath); err != nil {
+		return nil, err
+	}
+	sampler.lastTime = time.Now()
+	return sampler, nil
+}
+
+// sample 实现 cpuSampler
+func (c *cgroupSampler) sample() (float64, error) {
+	usageUsec, err := readCgroupUsage(c.statPath)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	elapsedUsec := float64(now.Sub(c.lastTime).Microseconds())
+	usedUsec := float64(usageUsec - c.lastUsageUsec)
+	c.lastUsageUsec, c.lastTime = usageUsec, now
+	if elapsedUsec <= 0 {
+		return 0, nil
+	}
+	return math.Min(100, usedUsec/(elapsedUsec*c.quotaCores)*100), nil
+}
+
+// readCgroupUsage 读取 cgroup 累计使用的 CPU 时间，单位微秒
+func readCgroupUsage(statPath string) (uint64, error) {
+	file, err := os.Open(statPath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == cgroupUsageKey {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("%s not found in %s", cgroupUsageKey, statPath)
+}
+
+// procStatSampler 基于 /proc/stat 中整机 CPU 时间计算使用率
+type procStatSampler struct {
+	path      string
+	lastTotal uint64
+	lastIdle  uint64
+}
+
+func newProcStatSampler(path string) (*procStatSampler, error) {
+	total, idle, err := readProcStat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &procStatSampler{path: path, lastTotal: total, lastIdle: idle}, nil
+}
+
+// sample 实现 cpuSampler
+func (p *procStatSampler) sample() (float64, error) {
+	total, idle, err := readProcStat(p.path)
+	if err != nil {
+		return 0, err
+	}
+	deltaTotal := total - p.lastTotal
+	deltaIdle := idle - p.lastIdle
+	p.lastTotal, p.lastIdle = total, idle
+	if deltaTotal == 0 {
+		return 0, nil
+	}
+	return float64(deltaTotal-deltaIdle) / float64(deltaTotal) * 100, nil
+}
+
+// readProcStat 读取整机累计的 CPU 时间与空闲时间（含 iowait），单位为时钟周期
+func readProcStat(path string) (uint64, uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return 0, 0, err
+		}
+		return 0, 0, errors.New("empty cpu stat")
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("invalid cpu stat line: %q", scanner.Text())
+	}
+	var total, idle uint64
+	for i, field := range fields[1:] {
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		total += value
+		// 第 4、5 列分别为 idle 与 iowait
+		if i == 3 || i == 4 {
+			idle += value
+		}
+	}
+	return total, idle, nil
+}
+
+// cpuMonitor 周期采样 CPU 使用率并以指数移动平均平滑，供所有自适应限流配额池共享
+type cpuMonitor struct {
+	sampler cpuSampler
+	// 平滑后的 CPU 使用率，math.Float64bits 编码
+	usage    uint64
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+func newCPUMonitor(sampler cpuSampler) *cpuMonitor {
+	return &cpuMonitor{sampler: sampler, stopCh: make(chan struct{})}
+}
+
+// Usage 获取平滑后的 CPU 使用率，单位为百分比
+func (m *cpuMonitor) Usage() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&m.usage))
+}
+
+// update 以指数移动平均合入一次采样值
+func (m *cpuMonitor) update(cur float64) {
+	next := m.Usage()*cpuDecay + cur*(1-cpuDecay)
+	atomic.StoreUint64(&m.usage, math.Float64bits(next))
+}
+
+// run 按 interval 采样，直到 stop 被调用
+func (m *cpuMonitor) run(interval time.Duration, logger log.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			cur, err := m.sampler.sample()
+			if err != nil {
+				logger.Warnf("%s fail to sample cpu usage, err: %v", logTag, err)
+				continue
+			}
+			m.update(cur)
+		}
+	}
+}
+
+// stop 停止采样
+func (m *cpuMonitor) stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+}
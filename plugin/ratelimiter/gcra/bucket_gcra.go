@@ -0,0 +1,394 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package gcra
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+	"github.com/polarismesh/polaris-go/plugin/ratelimiter/common"
+)
+
+// logTag gcra 插件限流日志统一前缀；与 reject/unirate/warmup 形成对照.
+const logTag = "[RateLimit][GCRA]"
+
+// MetadataBurst 规则 metadata 中覆盖插件 burst 配置的键
+const MetadataBurst = "burst"
+
+// 多久没同步远程配额，则退化为本地限流，与 reject 插件保持一致
+const remoteExpireMilli = 1000
+
+// GCRABucket GCRA 配额池
+// 每个 amount 对应一个 gcraLimiter，按 emissionInterval = 周期/阈值 计算每个请求的理论到达时间 TAT：
+// 请求到达时间不早于 TAT - burst*emissionInterval 才准入，准入后 TAT 推进 token 个 emissionInterval。
+// burst 为 0 时相邻请求至少间隔一个 emissionInterval，不存在滑窗分片边界上的突发；
+// 一次获取的 token 数大于 burst+1 时，需等待限流器空闲（TAT 不晚于当前时间）才准入。
+// 对于远程（GLOBAL）规则，除本地 GCRA 外，还需满足限流服务端下发的剩余配额；全局总量规则按客户端数均分本地速率。
+type GCRABucket struct {
+	rule      *apitraffic.Rule
+	windowKey string
+	// 所有 limiter 共用一把锁，保证多 amount 场景下判断与扣减的原子性
+	mutex sync.Mutex
+	// GCRA 限流器数组，时间从大到小排列
+	limiters []*gcraLimiter
+	// 按时间窗索引 GCRA 限流器，用于远程配额更新
+	limiterMap map[int64]*gcraLimiter
+	// 是否本地配额
+	local bool
+	// 是否单机均摊
+	shareEqual bool
+	// 远程失效是否放通
+	passOnRemoteFail bool
+	logCtx           *log.ContextLogger
+}
+
+// NewGCRABucket 创建 GCRA 配额池
+func NewGCRABucket(criteria *ratelimiter.InitCriteria, cfg *Config, logCtx *log.ContextLogger) *GCRABucket {
+	rule := criteria.DstRule
+	bucket := &GCRABucket{
+		rule:             rule,
+		windowKey:        criteria.WindowKey,
+		local:            rule.GetType() == apitraffic.Rule_LOCAL,
+		shareEqual:       rule.GetAmountMode() == apitraffic.Rule_SHARE_EQUALLY,
+		passOnRemoteFail: rule.GetFailover() == apitraffic.Rule_FAILOVER_PASS,
+		logCtx:           logCtx,
+	}
+	burst := ruleBurst(rule, cfg.Burst, criteria.WindowKey, logCtx)
+	amounts := rule.GetAmounts()
+	bucket.limiters = make([]*gcraLimiter, 0, len(amounts))
+	bucket.limiterMap = make(map[int64]*gcraLimiter, len(amounts))
+	for _, amount := range amounts {
+		duration, _ := pb.ConvertDuration(amount.GetValidDuration())
+		limiter := &gcraLimiter{
+			validDurationMilli: model.ToMilliSeconds(duration),
+			ruleAmount:         amount.GetMaxAmount().GetValue(),
+			burst:              float64(burst),
+			instanceCount:      1,
+		}
+		limiter.sliceWindow = common.NewSlidingWindow(1, int(limiter.validDurationMilli))
+		bucket.limiters = append(bucket.limiters, limiter)
+		bucket.limiterMap[limiter.validDurationMilli] = limiter
+	}
+	sort.Slice(bucket.limiters, func(i, j int) bool {
+		return bucket.limiters[i].validDurationMilli > bucket.limiters[j].validDurationMilli
+	})
+	for _, limiter := range bucket.limiters {
+		limiter.setRate(bucket.amountPerInstance(limiter))
+	}
+	logCtx.GetRateLimitLogger().Infof(
+		"%s created bucket windowKey=%q rule[%s] method=%s type=%s amounts=%s burst=%d %s",
+		logTag, criteria.WindowKey, common.RuleID(rule),
+		rule.GetMethod().GetValue().GetValue(),
+		rule.GetType().String(),
+		formatAmounts(bucket.limiters), burst,
+		common.FormatRuleSummary(rule),
+	)
+	return bucket
+}
+
+// ruleBurst 获取规则的突发容忍度，规则 metadata 中的 burst 优先于插件配置，非法取值时使用插件配置
+func ruleBurst(rule *apitraffic.Rule, defaultBurst int, windowKey string, logCtx *log.ContextLogger) int {
+	value, ok := rule.GetMetadata()[MetadataBurst]
+	if !ok {
+		return defaultBurst
+	}
+	burst, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || burst < 0 {
+		logCtx.GetRateLimitLogger().Warnf("%s invalid metadata burst=%q for windowKey=%q rule[%s], fallback to %d",
+			logTag, value, windowKey, common.RuleID(rule), defaultBurst)
+		return defaultBurst
+	}
+	return burst
+}
+
+// formatAmounts 把限流器序列格式化为 "[N1/D1ms,N2/D2ms]" 形式，便于 info 日志一行展示规则阈值.
+func formatAmounts(limiters []*gcraLimiter) string {
+	parts := make([]string, 0, len(limiters))
+	for _, l := range limiters {
+		parts = append(parts, fmt.Sprintf("%d/%dms", l.ruleAmount, l.validDurationMilli))
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+// amountPerInstance 计算本实例应承担的阈值：本地规则与单机均摊规则直接使用规则阈值，
+// 全局总量规则按远程下发的客户端数均分
+func (g *GCRABucket) amountPerInstance(limiter *gcraLimiter) float64 {
+	if g.local || g.shareEqual || limiter.instanceCount <= 1 {
+		return float64(limiter.ruleAmount)
+	}
+	return math.Ceil(float64(limiter.ruleAmount) / float64(limiter.instanceCount))
+}
+
+// ruleTotal 获取远程限流的配额总量
+func (g *GCRABucket) ruleTotal(limiter *gcraLimiter) int64 {
+	if g.shareEqual && !g.local {
+		return int64(limiter.ruleAmount) * int64(limiter.instanceCount)
+	}
+	return int64(limiter.ruleAmount)
+}
+
+// remoteMode 判断限流器当前的配额来源：useRemote 表示需要同时满足远程下发的剩余配额，
+// passAll 表示远程配额已过期且规则配置了失败放通
+func (g *GCRABucket) remoteMode(limiter *gcraLimiter, curTimeMs int64) (useRemote bool, passAll bool) {
+	if g.local {
+		return false, false
+	}
+	if !limiter.remoteExpired(curTimeMs) {
+		return true, false
+	}
+	return false, g.passOnRemoteFail
+}
+
+// GetQuota 在 GCRA 中进行 token 个配额的划扣，并返回本次分配的结果
+func (g *GCRABucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
+	if len(g.limiters) == 0 {
+		return &model.QuotaResponse{
+			Code: model.QuotaResultOk,
+			Info: "rule has no amount config",
+		}
+	}
+	if token == 0 {
+		token = 1
+	}
+	logger := g.logCtx.GetRateLimitLogger()
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	now := float64(curTimeMs)
+	permits := float64(token)
+	for _, limiter := range g.limiters {
+		useRemote, passAll := g.remoteMode(limiter, curTimeMs)
+		if passAll {
+			continue
+		}
+		remoteLimited := useRemote && limiter.remoteLeft < int64(token)
+		allowAt, ok := limiter.allowAt(now, permits)
+		if !remoteLimited && ok && allowAt <= now {
+			continue
+		}
+		if !g.local {
+			limiter.sliceWindow.AddAndGetCurrentLimited(curTimeMs, token)
+		}
+		if logger.IsLevelEnabled(log.DebugLog) {
+			logger.Debugf("%s limited rule[%s] windowKey=%s remote=%v duration=%dms remoteLeft=%d waitMs=%.1f",
+				logTag, common.RuleID(g.rule), g.windowKey, useRemote, limiter.validDurationMilli,
+				limiter.remoteLeft, allowAt-now)
+		}
+		windowDur := time.Duration(limiter.validDurationMilli) * time.Millisecond
+		return &model.QuotaResponse{
+			Code: model.QuotaResultLimited,
+			// info 协议格式 "<resource>:<amount>/<duration>"，与 reject/unirate 保持一致
+			Info: fmt.Sprintf("%s:%d/%s", g.rule.GetResource().String(), limiter.ruleAmount, windowDur),
+		}
+	}
+	for _, limiter := range g.limiters {
+		useRemote, passAll := g.remoteMode(limiter, curTimeMs)
+		if !g.local {
+			limiter.sliceWindow.AddAndGetCurrentPassed(curTimeMs, token)
+		}
+		if passAll {
+			continue
+		}
+		limiter.reserve(now, permits)
+		if useRemote {
+			limiter.remoteLeft -= int64(token)
+		}
+	}
+	if logger.IsLevelEnabled(log.DebugLog) {
+		logger.Debugf("%s passed rule[%s] windowKey=%s", logTag, common.RuleID(g.rule), g.windowKey)
+	}
+	return &model.QuotaResponse{
+		Code: model.QuotaResultOk,
+	}
+}
+
+// EarliestPermitTime 计算获取 token 个配额最早可成功的时间点，不划扣配额
+func (g *GCRABucket) EarliestPermitTime(curTimeMs int64, token uint32) (int64, bool) {
+	if len(g.limiters) == 0 {
+		return curTimeMs, true
+	}
+	if token == 0 {
+		token = 1
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	now := float64(curTimeMs)
+	earliest := curTimeMs
+	for _, limiter := range g.limiters {
+		useRemote, passAll := g.remoteMode(limiter, curTimeMs)
+		if passAll {
+			continue
+		}
+		allowAt, ok := limiter.allowAt(now, float64(token))
+		if !ok {
+			return 0, false
+		}
+		permitTime := int64(math.Ceil(allowAt))
+		if useRemote && limiter.remoteLeft < int64(token) {
+			if g.ruleTotal(limiter) < int64(token) {
+				return 0, false
+			}
+			// 远程剩余配额不足，需等待下一个周期服务端重置配额
+			nextStart := common.CalculateStartTimeMilli(curTimeMs, limiter.validDurationMilli) +
+				limiter.validDurationMilli
+			if nextStart > permitTime {
+				permitTime = nextStart
+			}
+		}
+		if permitTime > earliest {
+			earliest = permitTime
+		}
+	}
+	return earliest, true
+}
+
+// Release 释放配额（仅对于并发数限流有用）
+func (g *GCRABucket) Release() {
+	// 对于QPS限流，无需进行释放
+}
+
+// OnRemoteUpdate 远程配额更新，客户端数变化时按均分后的阈值调整本地速率
+func (g *GCRABucket) OnRemoteUpdate(remoteQuota ratelimiter.RemoteQuotaResult) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	limiter := g.limiterMap[remoteQuota.DurationMill]
+	if nil == limiter {
+		return
+	}
+	clientCount := remoteQuota.ClientCount
+	if clientCount == 0 {
+		clientCount = 1
+	}
+	if limiter.instanceCount != clientCount {
+		g.logCtx.GetRateLimitLogger().Infof("%s clientCount change from %d to %d, windowKey %s",
+			logTag, limiter.instanceCount, clientCount, g.windowKey)
+		limiter.instanceCount = clientCount
+		limiter.setRate(g.amountPerInstance(limiter))
+	}
+	durationMilli := remoteQuota.DurationMill
+	curStartTimeMilli := common.CalculateStartTimeMilli(remoteQuota.ClientTimeMilli, durationMilli)
+	remoteStartTimeMilli := common.CalculateStartTimeMilli(remoteQuota.ServerTimeMilli, durationMilli)
+	if curStartTimeMilli != remoteStartTimeMilli {
+		if remoteStartTimeMilli+durationMilli != curStartTimeMilli {
+			// 不在一个时间段内，丢弃
+			g.logCtx.GetRateLimitLogger().Warnf("%s drop remote quota, windowKey %s, clientTime %d(startMilli %d), "+
+				"remoteTime %d(startMilli %d), interval %d", logTag, g.windowKey, remoteQuota.ClientTimeMilli,
+				curStartTimeMilli, remoteQuota.ServerTimeMilli, remoteStartTimeMilli, durationMilli)
+			return
+		}
+		// 仅仅相差一个周期，可以认为是周期间切换导致，这时候可以直接更新配额为全量配额
+		remoteQuota.ServerTimeMilli = curStartTimeMilli
+		remoteQuota.Left = g.ruleTotal(limiter)
+	}
+	// 需要减去在上报期间使用的配额数
+	used, _ := limiter.sliceWindow.TouchCurrentPassed(remoteQuota.ServerTimeMilli)
+	limiter.remoteLeft = remoteQuota.Left - int64(used)
+	limiter.lastRemoteUpdateMilli = remoteQuota.ServerTimeMilli
+}
+
+// GetQuotaUsed 拉取本地使用配额情况以供上报
+func (g *GCRABucket) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageInfo {
+	result := ratelimiter.UsageInfo{
+		Passed:       make(map[int64]uint32, len(g.limiters)),
+		Limited:      make(map[int64]uint32, len(g.limiters)),
+		CurTimeMilli: curTimeMilli,
+	}
+	for _, limiter := range g.limiters {
+		passed, limited, _ := limiter.sliceWindow.AcquireCurrentValues(curTimeMilli)
+		result.Passed[limiter.validDurationMilli] = passed
+		result.Limited[limiter.validDurationMilli] = limited
+	}
+	return result
+}
+
+// GetAmountInfos 获取规则的限流阈值信息
+func (g *GCRABucket) GetAmountInfos() []ratelimiter.AmountInfo {
+	amounts := make([]ratelimiter.AmountInfo, 0, len(g.limiters))
+	for _, limiter := range g.limiters {
+		amounts = append(amounts, ratelimiter.AmountInfo{
+			ValidDuration: uint32(limiter.validDurationMilli / 1e3),
+			MaxAmount:     limiter.ruleAmount,
+		})
+	}
+	return amounts
+}
+
+// gcraLimiter 单个 amount 的 GCRA 状态，时间单位均为毫秒，并发由 GCRABucket 的锁保护
+type gcraLimiter struct {
+	// 限流区间 单位毫秒
+	validDurationMilli int64
+	// 规则中定义的阈值
+	ruleAmount uint32
+	// 突发容忍度，允许额外放通的请求数
+	burst float64
+	// 相邻请求的理论间隔，为 0 表示全部拒绝
+	emissionIntervalMilli float64
+	// 理论到达时间，即下一个请求在不占用突发容忍度时最早可准入的时间
+	tat float64
+	// 客户端数，通过远程更新
+	instanceCount uint32
+	// 远程下发的剩余配额
+	remoteLeft int64
+	// 最近一次远程更新时间点
+	lastRemoteUpdateMilli int64
+	// 统计滑窗，用于远程上报
+	sliceWindow *common.SlidingWindow
+}
+
+// setRate 设置放通阈值；速率变化不重置理论到达时间，已准入请求占用的时间按新速率自然消化
+func (l *gcraLimiter) setRate(amount float64) {
+	if amount <= 0 {
+		l.emissionIntervalMilli = 0
+		return
+	}
+	l.emissionIntervalMilli = float64(l.validDurationMilli) / amount
+}
+
+// remoteExpired 远程配额过期
+func (l *gcraLimiter) remoteExpired(nowMilli int64) bool {
+	return nowMilli-l.lastRemoteUpdateMilli > remoteExpireMilli
+}
+
+// allowAt 计算 permits 个配额最早可准入的时间点，返回 false 表示永远无法准入（阈值为 0 或超过单周期阈值）。
+// 一次获取多个配额时容忍度至少容纳本次请求：限流器空闲时可一次准入，之后按 permits 个间隔匀速消化
+func (l *gcraLimiter) allowAt(nowMilli float64, permits float64) (float64, bool) {
+	if l.emissionIntervalMilli == 0 {
+		return 0, false
+	}
+	increment := permits * l.emissionIntervalMilli
+	if increment > float64(l.validDurationMilli) {
+		return 0, false
+	}
+	tolerance := math.Max(l.burst+1, permits) * l.emissionIntervalMilli
+	return math.Max(l.tat, nowMilli) + increment - tolerance, true
+}
+
+// reserve 准入 permits 个配额，推进理论到达时间
+func (l *gcraLimiter) reserve(nowMilli float64, permits float64) {
+	l.tat = math.Max(l.tat, nowMilli) + permits*l.emissionIntervalMilli
+}
@@ -0,0 +1,223 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package gcra
+
+import (
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// silentLogger 一个完全静默的 log.Logger 实现，用于只关心限流行为的用例.
+type silentLogger struct{}
+
+func (silentLogger) Tracef(string, ...interface{}) {}
+func (silentLogger) Debugf(string, ...interface{}) {}
+func (silentLogger) Infof(string, ...interface{})  {}
+func (silentLogger) Warnf(string, ...interface{})  {}
+func (silentLogger) Errorf(string, ...interface{}) {}
+func (silentLogger) Fatalf(string, ...interface{}) {}
+func (silentLogger) IsLevelEnabled(int) bool       { return false }
+func (silentLogger) SetLogLevel(int) error         { return nil }
+
+// noopCtx 返回一个挂载了静默 logger 的 ContextLogger.
+func noopCtx() *log.ContextLogger {
+	orig := log.GetRateLimitLogger()
+	log.SetRateLimitLogger(silentLogger{})
+	ctx := &log.ContextLogger{}
+	ctx.Init()
+	log.SetRateLimitLogger(orig)
+	return ctx
+}
+
+// buildQpsRule 构造 QPS 限流规则
+func buildQpsRule(ruleType apitraffic.Rule_Type, maxAmount uint32, validDuration time.Duration) *apitraffic.Rule {
+	return &apitraffic.Rule{
+		Id:       wrapperspb.String("gcra-rule"),
+		Resource: apitraffic.Rule_QPS,
+		Type:     ruleType,
+		Action:   wrapperspb.String("gcra"),
+		Amounts: []*apitraffic.Amount{
+			{
+				MaxAmount:     wrapperspb.UInt32(maxAmount),
+				ValidDuration: durationpb.New(validDuration),
+			},
+		},
+	}
+}
+
+func newTestBucket(rule *apitraffic.Rule, burst int) *GCRABucket {
+	cfg := &Config{Burst: burst}
+	cfg.SetDefault()
+	return NewGCRABucket(&ratelimiter.InitCriteria{DstRule: rule, WindowKey: "test-svc#default"}, cfg, noopCtx())
+}
+
+// passedTimes 在 [startMs, endMs) 内每毫秒请求一次，返回放通请求的时间点
+func passedTimes(bucket *GCRABucket, startMs, endMs int64) []int64 {
+	var times []int64
+	for now := startMs; now < endMs; now++ {
+		if bucket.GetQuota(now, 1).Code == model.QuotaResultOk {
+			times = append(times, now)
+		}
+	}
+	return times
+}
+
+func TestGCRABucket_ExactSpacing(t *testing.T) {
+	// 测试场景：10/1s，burst 为 0，跨越滑窗分片边界持续请求
+	// 相邻放通请求的间隔严格为 100ms，不会在周期边界产生突发
+	const start = int64(1_000_950)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_LOCAL, 10, time.Second), 0)
+	times := passedTimes(bucket, start, start+1000)
+	assert.Equal(t, 10, len(times))
+	for i := 1; i < len(times); i++ {
+		assert.Equal(t, int64(100), times[i]-times[i-1])
+	}
+}
+
+func TestGCRABucket_Burst(t *testing.T) {
+	// 测试场景：10/1s，burst 为 3，空闲后可一次性放通 burst+1 个请求，之后恢复匀速
+	const start = int64(1_000_000)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_LOCAL, 10, time.Second), 3)
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start, 1).Code)
+	}
+	assert.Equal(t, model.QuotaResultLimited, bucket.GetQuota(start, 1).Code)
+	assert.Equal(t, []int64{start + 100, start + 200}, passedTimes(bucket, start+1, start+250))
+	// 空闲足够长时间后突发容忍度恢复
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start+2000, 1).Code)
+	}
+	assert.Equal(t, model.QuotaResultLimited, bucket.GetQuota(start+2000, 1).Code)
+}
+
+func TestGCRABucket_MetadataBurst(t *testing.T) {
+	// 测试场景：规则 metadata 中的 burst 覆盖插件配置，非法取值退化为插件配置
+	rule := buildQpsRule(apitraffic.Rule_LOCAL, 10, time.Second)
+	rule.Metadata = map[string]string{MetadataBurst: "5"}
+	assert.Equal(t, 5.0, newTestBucket(rule, 1).limiters[0].burst)
+	rule.Metadata[MetadataBurst] = "-1"
+	assert.Equal(t, 1.0, newTestBucket(rule, 1).limiters[0].burst)
+}
+
+func TestGCRABucket_MultiToken(t *testing.T) {
+	// 测试场景：一次获取多个配额按 token 个间隔推进理论到达时间；超过规则阈值的 token 永远无法获取
+	// burst 为 1 时容忍度为 2 个间隔，一次获取 2 个配额后，下一个配额需等待 1 个间隔
+	const start = int64(1_000_000)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_LOCAL, 10, time.Second), 1)
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start, 2).Code)
+	permitTime, ok := bucket.EarliestPermitTime(start, 1)
+	assert.True(t, ok)
+	assert.Equal(t, start+100, permitTime)
+	assert.Equal(t, model.QuotaResultLimited, bucket.GetQuota(start+99, 1).Code)
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start+100, 1).Code)
+
+	// token 数超过 burst+1 时需等待限流器空闲（理论到达时间为 start+300）后才可一次获取
+	permitTime, ok = bucket.EarliestPermitTime(start, 3)
+	assert.True(t, ok)
+	assert.Equal(t, start+300, permitTime)
+	// token 数超过规则阈值时永远无法获取
+	_, ok = bucket.EarliestPermitTime(start, 11)
+	assert.False(t, ok)
+}
+
+func TestGCRABucket_MultiTokenDefaultBurst(t *testing.T) {
+	// 测试场景：默认 burst 为 0 时一次获取多个配额
+	// 预期结果：限流器空闲时可一次获取，之后按 token 个间隔匀速消化，不会被判定为永远无法获取
+	const start = int64(1_000_000)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_LOCAL, 10, time.Second), 0)
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start, 5).Code)
+	assert.Equal(t, model.QuotaResultLimited, bucket.GetQuota(start+100, 1).Code)
+	permitTime, ok := bucket.EarliestPermitTime(start, 5)
+	assert.True(t, ok)
+	assert.Equal(t, start+500, permitTime)
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start+500, 5).Code)
+	// 阈值内的 token 数均可获取
+	permitTime, ok = bucket.EarliestPermitTime(start+500, 10)
+	assert.True(t, ok)
+	assert.Equal(t, start+1000, permitTime)
+}
+
+func TestGCRABucket_ZeroAmountRejectAll(t *testing.T) {
+	const start = int64(1_000_000)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_LOCAL, 0, time.Second), 0)
+	resp := bucket.GetQuota(start, 1)
+	assert.Equal(t, model.QuotaResultLimited, resp.Code)
+	assert.Equal(t, "QPS:0/1s", resp.Info)
+}
+
+func TestGCRABucket_RemoteQuota(t *testing.T) {
+	// 测试场景：GLOBAL 规则在远程配额有效期内同时受远程剩余配额约束，并上报使用量
+	const start = int64(1_000_000)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_GLOBAL, 10, time.Second), 0)
+	bucket.OnRemoteUpdate(ratelimiter.RemoteQuotaResult{
+		Left:            2,
+		ClientCount:     1,
+		ServerTimeMilli: start,
+		DurationMill:    1000,
+		ClientTimeMilli: start,
+	})
+
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start, 1).Code)
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start+100, 1).Code)
+	assert.Equal(t, model.QuotaResultLimited, bucket.GetQuota(start+200, 1).Code)
+	permitTime, ok := bucket.EarliestPermitTime(start+200, 1)
+	assert.True(t, ok)
+	assert.Equal(t, start+1000, permitTime, "远程剩余配额不足时需等待下一个周期")
+
+	usage := bucket.GetQuotaUsed(start + 200)
+	assert.Equal(t, uint32(2), usage.Passed[1000])
+	assert.Equal(t, uint32(1), usage.Limited[1000])
+
+	// 远程配额过期后退化为本地 GCRA 限流
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start+2000, 1).Code)
+
+	assert.Equal(t, []ratelimiter.AmountInfo{{ValidDuration: 1, MaxAmount: 10}}, bucket.GetAmountInfos())
+}
+
+func TestGCRABucket_GlobalTotalSharedByClients(t *testing.T) {
+	// 测试场景：全局总量规则按远程下发的客户端数均分本地速率
+	const start = int64(1_000_000)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_GLOBAL, 100, time.Second), 0)
+	assert.InDelta(t, 10.0, bucket.limiters[0].emissionIntervalMilli, 0.001)
+	bucket.OnRemoteUpdate(ratelimiter.RemoteQuotaResult{
+		Left:            100,
+		ClientCount:     4,
+		ServerTimeMilli: start,
+		DurationMill:    1000,
+		ClientTimeMilli: start,
+	})
+	assert.InDelta(t, 40.0, bucket.limiters[0].emissionIntervalMilli, 0.001)
+}
+
+func TestConfig_Verify(t *testing.T) {
+	cfg := &Config{}
+	cfg.SetDefault()
+	assert.NoError(t, cfg.Verify())
+
+	cfg.Burst = -1
+	assert.Error(t, cfg.Verify())
+}
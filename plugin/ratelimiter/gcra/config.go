@@ -0,0 +1,41 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package gcra
+
+import (
+	"fmt"
+)
+
+// Config GCRA 限流器配置
+type Config struct {
+	// Burst 突发容忍度：在严格匀速间隔之外允许一次性额外放通的请求数，为 0 时严格按匀速间隔放通；
+	// 单条规则可通过 metadata burst 覆盖。一次获取多个配额时容忍度至少为本次的 token 数，token 数不超过规则阈值即可获取
+	Burst int `yaml:"burst" json:"burst"`
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+}
+
+// Verify 校验配置值
+func (c *Config) Verify() error {
+	if c.Burst < 0 {
+		return fmt.Errorf("invalid burst: %d, it must greater than or equal to 0", c.Burst)
+	}
+	return nil
+}
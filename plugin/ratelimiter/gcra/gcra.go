@@ -0,0 +1,81 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package gcra 提供基于 GCRA（Generic Cell Rate Algorithm）的精确限流插件。
+// 限流规则的 Action 为 gcra 时由框架选用本插件：每个请求按理论到达时间逐个准入，保证请求间的精确间隔，
+// 不会像滑窗分片那样在分片边界产生突发；每个限流窗口只需维护一个时间戳，内存开销为 O(1)。
+package gcra
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// RateLimiterGCRA 基于 GCRA 的精确限流控制器
+type RateLimiterGCRA struct {
+	*plugin.PluginBase
+	cfg    *Config
+	logCtx *log.ContextLogger
+}
+
+// Type 插件类型
+func (g *RateLimiterGCRA) Type() common.Type {
+	return common.TypeRateLimiter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *RateLimiterGCRA) Name() string {
+	return config.DefaultGCRARateLimiter
+}
+
+// Init 初始化插件
+func (g *RateLimiterGCRA) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.logCtx = ctx.ValueCtx.GetContextLogger()
+	g.cfg = &Config{}
+	cfgValue := ctx.Config.GetProvider().GetRateLimit().GetPluginConfig(g.Name())
+	if cfgValue != nil {
+		g.cfg = cfgValue.(*Config)
+	}
+	g.cfg.SetDefault()
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *RateLimiterGCRA) Destroy() error {
+	return nil
+}
+
+// IsEnable enable ?
+func (g *RateLimiterGCRA) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// InitQuota 初始化并创建限流窗口
+// 主流程会在首次调用，以及规则对象变更的时候，调用该方法
+func (g *RateLimiterGCRA) InitQuota(criteria *ratelimiter.InitCriteria) ratelimiter.QuotaBucket {
+	return NewGCRABucket(criteria, g.cfg, g.logCtx)
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&RateLimiterGCRA{}, &Config{})
+}
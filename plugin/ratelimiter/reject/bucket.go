@@ -31,6 +31,11 @@ func (q *QuotaBucketReject) GetQuota(curTimeMs int64, token uint32) *model.Quota
 	return q.bucket.Allocate(curTimeMs, token)
 }
 
+// EarliestPermitTime 计算获取 token 个配额最早可成功的时间点，不划扣配额
+func (q *QuotaBucketReject) EarliestPermitTime(curTimeMs int64, token uint32) (int64, bool) {
+	return q.bucket.EarliestPermitTime(curTimeMs, token)
+}
+
 // Release 释放配额（仅对于并发数限流有用）
 func (q *QuotaBucketReject) Release() {
 	q.bucket.Release()
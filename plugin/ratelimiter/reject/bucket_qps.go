@@ -99,7 +99,7 @@ type RemoteAwareQpsBucket struct {
 }
 
 const (
-	// 单次分配的token数量
+	// 未指定时单次分配的token数量
 	tokenPerAlloc = 1
 )
 
@@ -147,6 +147,9 @@ func (r *RemoteAwareQpsBucket) Allocate(curTimeMs int64, token uint32) *model.Qu
 			Info: "rule has no amount config",
 		}
 	}
+	if token == 0 {
+		token = tokenPerAlloc
+	}
 	var stopIndex = -1
 	var mode = Unknown
 	identifiers := r.poolGetIdentifier()
@@ -154,7 +157,7 @@ func (r *RemoteAwareQpsBucket) Allocate(curTimeMs int64, token uint32) *model.Qu
 	// 先尝试扣除
 	var left int64
 	for i, tokenBucket := range r.tokenBuckets {
-		left, mode = tokenBucket.TryAllocateToken(tokenPerAlloc, curTimeMs, &identifiers[i], mode)
+		left, mode = tokenBucket.TryAllocateToken(token, curTimeMs, &identifiers[i], mode)
 		if left < 0 {
 			stopIndex = i
 			break
@@ -170,10 +173,10 @@ func (r *RemoteAwareQpsBucket) Allocate(curTimeMs int64, token uint32) *model.Qu
 			// 远程才记录滑窗, 滑窗用于上报
 			tokenBucket.ConfirmLimited(token, curTimeMs)
 		}
-		// 归还配额
-		for i := 0; i < stopIndex; i++ {
+		// 归还配额，包括扣减失败的令牌桶，避免一次获取多个配额失败时吞掉剩余配额
+		for i := 0; i <= stopIndex; i++ {
 			tokenBucket := r.tokenBuckets[i]
-			tokenBucket.GiveBackToken(&identifiers[i], tokenPerAlloc, mode)
+			tokenBucket.GiveBackToken(&identifiers[i], int64(token), mode)
 		}
 		// 限流也是热路径（高 QPS 场景下被拒请求可能数倍于通过），先做 IsLevelEnabled 闸门
 		if logger.IsLevelEnabled(log.DebugLog) {
@@ -205,6 +208,25 @@ func (r *RemoteAwareQpsBucket) Allocate(curTimeMs int64, token uint32) *model.Qu
 	}
 }
 
+// EarliestPermitTime 计算获取 token 个配额最早可成功的时间点，不划扣配额
+// 各令牌桶的配额均在周期起始时刷新，因此配额不足时以最晚的下一个周期起点作为预估结果
+func (r *RemoteAwareQpsBucket) EarliestPermitTime(curTimeMs int64, token uint32) (int64, bool) {
+	if token == 0 {
+		token = tokenPerAlloc
+	}
+	permitTime := curTimeMs
+	for _, tokenBucket := range r.tokenBuckets {
+		bucketPermitTime, ok := tokenBucket.EarliestPermitTime(curTimeMs, token)
+		if !ok {
+			return 0, false
+		}
+		if bucketPermitTime > permitTime {
+			permitTime = bucketPermitTime
+		}
+	}
+	return permitTime, true
+}
+
 // Release 执行配额回收操作
 func (r *RemoteAwareQpsBucket) Release() {
 	// 对于QPS限流，无需进行释放
@@ -550,6 +572,45 @@ func (t *TokenBucket) TryAllocateToken(
 	return t.tryAllocateRemote(token, nowMilli, identifier)
 }
 
+// EarliestPermitTime 按当前分配模式预估获取 token 个配额最早可成功的时间点，不划扣配额
+func (t *TokenBucket) EarliestPermitTime(nowMilli int64, token uint32) (int64, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	nowStageMilli := t.calculateStageStart(nowMilli)
+	nextStageMilli := nowStageMilli + t.validDurationMilli
+	var total, left int64
+	switch {
+	case t.shareInfo.local:
+		total = int64(t.ruleTokenAmount)
+		left = atomic.LoadInt64(&t.tokenLeft)
+		if atomic.LoadInt64(&t.stageStartMilli) != nowStageMilli {
+			// 进入新周期后首次分配会重置配额
+			left = total
+		}
+	case !t.remoteExpired(nowMilli):
+		total = t.GetRuleTotal()
+		left = atomic.LoadInt64(&t.tokenLeft)
+	case t.shareInfo.passOnRemoteFail:
+		return nowMilli, true
+	default:
+		total = int64(math.Ceil(float64(t.GetRuleTotal()) / float64(atomic.LoadUint32(&t.instanceCount))))
+		if total == 0 {
+			total = 1
+		}
+		left = atomic.LoadInt64(&t.remoteToLocalTokenLeft)
+		if atomic.LoadInt64(&t.stageStartMilli) != nowStageMilli {
+			left = total
+		}
+	}
+	if int64(token) > total {
+		return 0, false
+	}
+	if left >= int64(token) {
+		return nowMilli, true
+	}
+	return nextStageMilli, true
+}
+
 // ConfirmPassed 记录真实分配配额
 func (t *TokenBucket) ConfirmPassed(passed uint32, nowMilli int64) {
 	t.sliceWindow.AddAndGetCurrentPassed(nowMilli, passed)
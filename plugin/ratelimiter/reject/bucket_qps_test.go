@@ -0,0 +1,95 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package reject
+
+import (
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+	_ "github.com/polarismesh/polaris-go/plugin/logger/zaplog"
+)
+
+// newLocalQpsBucket 构造单机 QPS 限流配额池
+func newLocalQpsBucket(maxAmount uint32, validDuration time.Duration) *RemoteAwareQpsBucket {
+	rule := &apitraffic.Rule{
+		Id:       wrapperspb.String("reject-rule"),
+		Resource: apitraffic.Rule_QPS,
+		Type:     apitraffic.Rule_LOCAL,
+		Amounts: []*apitraffic.Amount{
+			{
+				MaxAmount:     wrapperspb.UInt32(maxAmount),
+				ValidDuration: durationpb.New(validDuration),
+			},
+		},
+	}
+	logCtx := &log.ContextLogger{}
+	logCtx.Init()
+	return NewRemoteAwareQpsBucket(&ratelimiter.InitCriteria{DstRule: rule, WindowKey: "test-svc#default"}, logCtx)
+}
+
+// TestRemoteAwareQpsBucket_AllocateTokens 测试按 token 数划扣配额
+// 测试场景：单机限流 5/1s，先获取 3 个配额，再获取 3 个配额
+// 预期结果：第一次通过，第二次因剩余配额不足被限流，且被限流时不扣减剩余配额
+func TestRemoteAwareQpsBucket_AllocateTokens(t *testing.T) {
+	bucket := newLocalQpsBucket(5, time.Second)
+	startMs := int64(10000)
+
+	assert.Equal(t, model.QuotaResultOk, bucket.Allocate(startMs, 3).Code)
+	assert.Equal(t, model.QuotaResultLimited, bucket.Allocate(startMs+1, 3).Code)
+	assert.Equal(t, model.QuotaResultOk, bucket.Allocate(startMs+2, 2).Code)
+	assert.Equal(t, model.QuotaResultLimited, bucket.Allocate(startMs+3, 1).Code)
+}
+
+// TestRemoteAwareQpsBucket_EarliestPermitTime 测试预估最早可分配时间
+// 测试场景：单机限流 2/1s，分别在配额充足、配额耗尽、token 超过单周期总量时预估
+// 预期结果：充足时返回当前时间，耗尽时返回下一周期起点，超过总量时返回不可分配；预估不扣减配额
+func TestRemoteAwareQpsBucket_EarliestPermitTime(t *testing.T) {
+	bucket := newLocalQpsBucket(2, time.Second)
+	nowMs := int64(10200)
+
+	permitTime, ok := bucket.EarliestPermitTime(nowMs, 2)
+	assert.True(t, ok)
+	assert.Equal(t, nowMs, permitTime)
+	permitTime, ok = bucket.EarliestPermitTime(nowMs, 2)
+	assert.True(t, ok)
+	assert.Equal(t, nowMs, permitTime)
+
+	assert.Equal(t, model.QuotaResultOk, bucket.Allocate(nowMs, 1).Code)
+	permitTime, ok = bucket.EarliestPermitTime(nowMs, 1)
+	assert.True(t, ok)
+	assert.Equal(t, nowMs, permitTime)
+	permitTime, ok = bucket.EarliestPermitTime(nowMs, 2)
+	assert.True(t, ok)
+	assert.Equal(t, int64(11000), permitTime)
+
+	// 进入下一个周期后配额恢复
+	permitTime, ok = bucket.EarliestPermitTime(11000, 2)
+	assert.True(t, ok)
+	assert.Equal(t, int64(11000), permitTime)
+
+	_, ok = bucket.EarliestPermitTime(nowMs, 3)
+	assert.False(t, ok)
+}
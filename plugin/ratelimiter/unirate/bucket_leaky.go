@@ -128,7 +128,7 @@ func createLeakyBucket(criteria *ratelimiter.InitCriteria, cfg *Config, logCtx *
 	return bucket
 }
 
-func (l *LeakyBucket) allocateQuota() *model.QuotaResponse {
+func (l *LeakyBucket) allocateQuota(token uint32) *model.QuotaResponse {
 	logger := l.logCtx.GetRateLimitLogger()
 	if l.rejectAll {
 		if logger.IsLevelEnabled(log.DebugLog) {
@@ -142,8 +142,11 @@ func (l *LeakyBucket) allocateQuota() *model.QuotaResponse {
 			Info: fmt.Sprintf("%s:0/%s", l.rule.GetResource().String(), l.effectiveDuration),
 		}
 	}
+	if token == 0 {
+		token = 1
+	}
 	// 需要多久产生这么请求的配额
-	costDuration := atomic.LoadInt64(&l.effectiveRate)
+	costDuration := atomic.LoadInt64(&l.effectiveRate) * int64(token)
 
 	var waitDuration int64
 	for {
@@ -199,7 +202,24 @@ func (l *LeakyBucket) allocateQuota() *model.QuotaResponse {
 
 // GetQuota 在令牌桶/漏桶中进行单个配额的划扣，并返回本次分配的结果
 func (l *LeakyBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
-	return l.allocateQuota()
+	return l.allocateQuota(token)
+}
+
+// EarliestPermitTime 计算获取 token 个配额无需排队即可通过的最早时间点，不占用排队位置
+func (l *LeakyBucket) EarliestPermitTime(curTimeMs int64, token uint32) (int64, bool) {
+	if l.rejectAll {
+		return 0, false
+	}
+	if token == 0 {
+		token = 1
+	}
+	// 漏桶基于本地时钟计算，这里换算为与 curTimeMs 相同时钟的时间点
+	costDuration := atomic.LoadInt64(&l.effectiveRate) * int64(token)
+	waitDuration := atomic.LoadInt64(&l.lastGrantTime) + costDuration - model.CurrentMillisecond()
+	if waitDuration < 0 {
+		waitDuration = 0
+	}
+	return curTimeMs + waitDuration, true
 }
 
 // Release 释放配额（仅对于并发数限流有用）
@@ -0,0 +1,88 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package unirate
+
+import (
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+	_ "github.com/polarismesh/polaris-go/plugin/logger/zaplog"
+)
+
+// newTestLeakyBucket 构造匀速排队配额池
+func newTestLeakyBucket(maxAmount uint32, validDuration time.Duration) *LeakyBucket {
+	rule := &apitraffic.Rule{
+		Id:       wrapperspb.String("unirate-rule"),
+		Resource: apitraffic.Rule_QPS,
+		Type:     apitraffic.Rule_LOCAL,
+		Amounts: []*apitraffic.Amount{
+			{
+				MaxAmount:     wrapperspb.UInt32(maxAmount),
+				ValidDuration: durationpb.New(validDuration),
+			},
+		},
+	}
+	cfg := &Config{}
+	cfg.SetDefault()
+	logCtx := &log.ContextLogger{}
+	logCtx.Init()
+	return createLeakyBucket(&ratelimiter.InitCriteria{DstRule: rule, WindowKey: "test-svc#default"}, cfg, logCtx)
+}
+
+// TestLeakyBucket_EarliestPermitTime 测试匀速排队的最早可分配时间预估
+// 测试场景：10/1s（每 100ms 一个配额），分配一次后分别预估获取 1 个、2 个配额无需排队的时间
+// 预期结果：首次预估为当前时间；分配后分别约为 100ms、200ms 之后，且预估不占用排队位置
+func TestLeakyBucket_EarliestPermitTime(t *testing.T) {
+	bucket := newTestLeakyBucket(10, time.Second)
+	curTimeMs := int64(50000)
+
+	permitTime, ok := bucket.EarliestPermitTime(curTimeMs, 1)
+	assert.True(t, ok)
+	assert.Equal(t, curTimeMs, permitTime)
+
+	resp := bucket.GetQuota(curTimeMs, 2)
+	assert.Equal(t, model.QuotaResultOk, resp.Code)
+	assert.Equal(t, int64(0), resp.WaitMs)
+
+	permitTime, ok = bucket.EarliestPermitTime(curTimeMs, 1)
+	assert.True(t, ok)
+	assert.InDelta(t, curTimeMs+100, permitTime, 20)
+	permitTime, ok = bucket.EarliestPermitTime(curTimeMs, 2)
+	assert.True(t, ok)
+	assert.InDelta(t, curTimeMs+200, permitTime, 20)
+	permitTime, ok = bucket.EarliestPermitTime(curTimeMs, 1)
+	assert.True(t, ok)
+	assert.InDelta(t, curTimeMs+100, permitTime, 20)
+}
+
+// TestLeakyBucket_EarliestPermitTimeRejectAll 测试阈值为0时的预估
+// 测试场景：规则阈值为0
+// 预期结果：返回不可分配
+func TestLeakyBucket_EarliestPermitTimeRejectAll(t *testing.T) {
+	bucket := newTestLeakyBucket(0, time.Second)
+	_, ok := bucket.EarliestPermitTime(model.CurrentMillisecond(), 1)
+	assert.False(t, ok)
+}
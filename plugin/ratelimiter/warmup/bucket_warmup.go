@@ -0,0 +1,387 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package warmup
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+	"github.com/polarismesh/polaris-go/plugin/ratelimiter/common"
+)
+
+// logTag warmup 插件限流日志统一前缀；与 reject/unirate 形成对照.
+const logTag = "[RateLimit][WarmUp]"
+
+// 多久没同步远程配额，则退化为本地限流，与 reject 插件保持一致
+const remoteExpireMilli = 1000
+
+// WarmUpBucket 预热令牌桶配额池
+// 每个 amount 对应一个 warmUpLimiter，参考 Guava SmoothWarmingUp 实现：
+// 启动或空闲一段时间后令牌桶处于"冷"状态，放通速率为阈值的 1/coldFactor，
+// 随着请求持续消耗存量令牌，放通速率在 warmUpPeriod 内线性爬升到规则阈值。
+// 对于远程（GLOBAL）规则，除本地预热速率外，还需满足限流服务端下发的剩余配额。
+type WarmUpBucket struct {
+	rule      *apitraffic.Rule
+	windowKey string
+	// 所有 limiter 共用一把锁，保证多 amount 场景下判断与扣减的原子性
+	mutex sync.Mutex
+	// 预热限流器数组，时间从大到小排列
+	limiters []*warmUpLimiter
+	// 按时间窗索引预热限流器，用于远程配额更新
+	limiterMap map[int64]*warmUpLimiter
+	// 是否本地配额
+	local bool
+	// 是否单机均摊
+	shareEqual bool
+	// 远程失效是否放通
+	passOnRemoteFail bool
+	logCtx           *log.ContextLogger
+}
+
+// NewWarmUpBucket 创建预热令牌桶配额池，curTimeMs 为冷启动的起始时间
+func NewWarmUpBucket(criteria *ratelimiter.InitCriteria, cfg *Config, curTimeMs int64,
+	logCtx *log.ContextLogger) *WarmUpBucket {
+	rule := criteria.DstRule
+	bucket := &WarmUpBucket{
+		rule:             rule,
+		windowKey:        criteria.WindowKey,
+		local:            rule.GetType() == apitraffic.Rule_LOCAL,
+		shareEqual:       rule.GetAmountMode() == apitraffic.Rule_SHARE_EQUALLY,
+		passOnRemoteFail: rule.GetFailover() == apitraffic.Rule_FAILOVER_PASS,
+		logCtx:           logCtx,
+	}
+	amounts := rule.GetAmounts()
+	bucket.limiters = make([]*warmUpLimiter, 0, len(amounts))
+	bucket.limiterMap = make(map[int64]*warmUpLimiter, len(amounts))
+	for _, amount := range amounts {
+		duration, _ := pb.ConvertDuration(amount.GetValidDuration())
+		limiter := newWarmUpLimiter(duration, amount.GetMaxAmount().GetValue(), cfg, curTimeMs)
+		bucket.limiters = append(bucket.limiters, limiter)
+		bucket.limiterMap[limiter.validDurationMilli] = limiter
+	}
+	sort.Slice(bucket.limiters, func(i, j int) bool {
+		return bucket.limiters[i].validDurationMilli > bucket.limiters[j].validDurationMilli
+	})
+	for _, limiter := range bucket.limiters {
+		limiter.setRate(bucket.amountPerInstance(limiter))
+	}
+	logCtx.GetRateLimitLogger().Infof(
+		"%s created bucket windowKey=%q rule[%s] method=%s type=%s amounts=%s warmUpPeriod=%s coldFactor=%v %s",
+		logTag, criteria.WindowKey, common.RuleID(rule),
+		rule.GetMethod().GetValue().GetValue(),
+		rule.GetType().String(),
+		formatAmounts(bucket.limiters), *cfg.WarmUpPeriod, cfg.ColdFactor,
+		common.FormatRuleSummary(rule),
+	)
+	return bucket
+}
+
+// formatAmounts 把限流器序列格式化为 "[N1/D1ms,N2/D2ms]" 形式，便于 info 日志一行展示规则阈值.
+func formatAmounts(limiters []*warmUpLimiter) string {
+	parts := make([]string, 0, len(limiters))
+	for _, l := range limiters {
+		parts = append(parts, fmt.Sprintf("%d/%dms", l.ruleAmount, l.validDurationMilli))
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+// amountPerInstance 计算本实例应承担的阈值：本地规则与单机均摊规则直接使用规则阈值，
+// 全局总量规则按远程下发的客户端数均分
+func (w *WarmUpBucket) amountPerInstance(limiter *warmUpLimiter) float64 {
+	if w.local || w.shareEqual || limiter.instanceCount <= 1 {
+		return float64(limiter.ruleAmount)
+	}
+	return math.Ceil(float64(limiter.ruleAmount) / float64(limiter.instanceCount))
+}
+
+// ruleTotal 获取远程限流的配额总量
+func (w *WarmUpBucket) ruleTotal(limiter *warmUpLimiter) int64 {
+	if w.shareEqual && !w.local {
+		return int64(limiter.ruleAmount) * int64(limiter.instanceCount)
+	}
+	return int64(limiter.ruleAmount)
+}
+
+// remoteMode 判断限流器当前的配额来源：useRemote 表示需要同时满足远程下发的剩余配额，
+// passAll 表示远程配额已过期且规则配置了失败放通
+func (w *WarmUpBucket) remoteMode(limiter *warmUpLimiter, curTimeMs int64) (useRemote bool, passAll bool) {
+	if w.local {
+		return false, false
+	}
+	if !limiter.remoteExpired(curTimeMs) {
+		return true, false
+	}
+	return false, w.passOnRemoteFail
+}
+
+// GetQuota 在令牌桶/漏桶中进行单个配额的划扣，并返回本次分配的结果
+func (w *WarmUpBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
+	if len(w.limiters) == 0 {
+		return &model.QuotaResponse{
+			Code: model.QuotaResultOk,
+			Info: "rule has no amount config",
+		}
+	}
+	if token == 0 {
+		token = 1
+	}
+	logger := w.logCtx.GetRateLimitLogger()
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	now := float64(curTimeMs)
+	for _, limiter := range w.limiters {
+		useRemote, passAll := w.remoteMode(limiter, curTimeMs)
+		if passAll {
+			continue
+		}
+		remoteLimited := useRemote && limiter.remoteLeft < int64(token)
+		if !remoteLimited && limiter.canAcquire(now) {
+			continue
+		}
+		if !w.local {
+			limiter.sliceWindow.AddAndGetCurrentLimited(curTimeMs, token)
+		}
+		if logger.IsLevelEnabled(log.DebugLog) {
+			logger.Debugf("%s limited rule[%s] windowKey=%s remote=%v duration=%dms remoteLeft=%d waitMs=%.1f",
+				logTag, common.RuleID(w.rule), w.windowKey, useRemote, limiter.validDurationMilli,
+				limiter.remoteLeft, limiter.nextFreeMilli-now)
+		}
+		windowDur := time.Duration(limiter.validDurationMilli) * time.Millisecond
+		return &model.QuotaResponse{
+			Code: model.QuotaResultLimited,
+			// info 协议格式 "<resource>:<amount>/<duration>"，与 reject/unirate 保持一致
+			Info: fmt.Sprintf("%s:%d/%s", w.rule.GetResource().String(), limiter.ruleAmount, windowDur),
+		}
+	}
+	for _, limiter := range w.limiters {
+		useRemote, passAll := w.remoteMode(limiter, curTimeMs)
+		if !w.local {
+			limiter.sliceWindow.AddAndGetCurrentPassed(curTimeMs, token)
+		}
+		if passAll {
+			continue
+		}
+		limiter.reserve(float64(token), now)
+		if useRemote {
+			limiter.remoteLeft -= int64(token)
+		}
+	}
+	if logger.IsLevelEnabled(log.DebugLog) {
+		logger.Debugf("%s passed rule[%s] windowKey=%s", logTag, common.RuleID(w.rule), w.windowKey)
+	}
+	return &model.QuotaResponse{
+		Code: model.QuotaResultOk,
+	}
+}
+
+// Release 释放配额（仅对于并发数限流有用）
+func (w *WarmUpBucket) Release() {
+	// 对于QPS限流，无需进行释放
+}
+
+// OnRemoteUpdate 远程配额更新
+func (w *WarmUpBucket) OnRemoteUpdate(remoteQuota ratelimiter.RemoteQuotaResult) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	limiter := w.limiterMap[remoteQuota.DurationMill]
+	if nil == limiter {
+		return
+	}
+	clientCount := remoteQuota.ClientCount
+	if clientCount == 0 {
+		clientCount = 1
+	}
+	if limiter.instanceCount != clientCount {
+		w.logCtx.GetRateLimitLogger().Infof("%s clientCount change from %d to %d, windowKey %s",
+			logTag, limiter.instanceCount, clientCount, w.windowKey)
+		limiter.instanceCount = clientCount
+		limiter.setRate(w.amountPerInstance(limiter))
+	}
+	durationMilli := remoteQuota.DurationMill
+	curStartTimeMilli := common.CalculateStartTimeMilli(remoteQuota.ClientTimeMilli, durationMilli)
+	remoteStartTimeMilli := common.CalculateStartTimeMilli(remoteQuota.ServerTimeMilli, durationMilli)
+	if curStartTimeMilli != remoteStartTimeMilli {
+		if remoteStartTimeMilli+durationMilli != curStartTimeMilli {
+			// 不在一个时间段内，丢弃
+			w.logCtx.GetRateLimitLogger().Warnf("%s drop remote quota, windowKey %s, clientTime %d(startMilli %d), "+
+				"remoteTime %d(startMilli %d), interval %d", logTag, w.windowKey, remoteQuota.ClientTimeMilli,
+				curStartTimeMilli, remoteQuota.ServerTimeMilli, remoteStartTimeMilli, durationMilli)
+			return
+		}
+		// 仅仅相差一个周期，可以认为是周期间切换导致，这时候可以直接更新配额为全量配额
+		remoteQuota.ServerTimeMilli = curStartTimeMilli
+		remoteQuota.Left = w.ruleTotal(limiter)
+	}
+	// 需要减去在上报期间使用的配额数
+	used, _ := limiter.sliceWindow.TouchCurrentPassed(remoteQuota.ServerTimeMilli)
+	limiter.remoteLeft = remoteQuota.Left - int64(used)
+	limiter.lastRemoteUpdateMilli = remoteQuota.ServerTimeMilli
+}
+
+// GetQuotaUsed 拉取本地使用配额情况以供上报
+func (w *WarmUpBucket) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageInfo {
+	result := ratelimiter.UsageInfo{
+		Passed:       make(map[int64]uint32, len(w.limiters)),
+		Limited:      make(map[int64]uint32, len(w.limiters)),
+		CurTimeMilli: curTimeMilli,
+	}
+	for _, limiter := range w.limiters {
+		passed, limited, _ := limiter.sliceWindow.AcquireCurrentValues(curTimeMilli)
+		result.Passed[limiter.validDurationMilli] = passed
+		result.Limited[limiter.validDurationMilli] = limited
+	}
+	return result
+}
+
+// GetAmountInfos 获取规则的限流阈值信息
+func (w *WarmUpBucket) GetAmountInfos() []ratelimiter.AmountInfo {
+	amounts := make([]ratelimiter.AmountInfo, 0, len(w.limiters))
+	for _, limiter := range w.limiters {
+		amounts = append(amounts, ratelimiter.AmountInfo{
+			ValidDuration: uint32(limiter.validDurationMilli / 1e3),
+			MaxAmount:     limiter.ruleAmount,
+		})
+	}
+	return amounts
+}
+
+// warmUpLimiter 单个 amount 的预热令牌桶，时间单位均为毫秒，并发由 WarmUpBucket 的锁保护
+//
+// 存量令牌 storedPermits 在空闲时按 warmUpPeriod/maxPermits 的间隔回填，最多回填到 maxPermits；
+// 存量令牌高于 thresholdPermits 时，每个令牌的发放间隔从 coldInterval 线性下降到 stableInterval，
+// 低于 thresholdPermits 时按 stableInterval 发放。因此从满存量消耗到阈值恰好需要 warmUpPeriod。
+type warmUpLimiter struct {
+	// 限流区间 单位毫秒
+	validDurationMilli int64
+	// 规则中定义的阈值
+	ruleAmount uint32
+	// 预热时长
+	warmUpPeriodMilli float64
+	// 冷启动系数
+	coldFactor float64
+	// 稳定状态下每个令牌的发放间隔，为 0 表示全部拒绝
+	stableIntervalMilli float64
+	// 预热区间的斜率
+	slope float64
+	// 开始预热的存量令牌阈值
+	thresholdPermits float64
+	// 最大存量令牌数
+	maxPermits float64
+	// 当前存量令牌数
+	storedPermits float64
+	// 下一个令牌可发放的时间
+	nextFreeMilli float64
+	// 客户端数，通过远程更新
+	instanceCount uint32
+	// 远程下发的剩余配额
+	remoteLeft int64
+	// 最近一次远程更新时间点
+	lastRemoteUpdateMilli int64
+	// 统计滑窗，用于远程上报
+	sliceWindow *common.SlidingWindow
+}
+
+func newWarmUpLimiter(validDuration time.Duration, amount uint32, cfg *Config, curTimeMs int64) *warmUpLimiter {
+	validDurationMilli := model.ToMilliSeconds(validDuration)
+	return &warmUpLimiter{
+		validDurationMilli: validDurationMilli,
+		ruleAmount:         amount,
+		warmUpPeriodMilli:  float64(model.ToMilliSeconds(*cfg.WarmUpPeriod)),
+		coldFactor:         cfg.ColdFactor,
+		nextFreeMilli:      float64(curTimeMs),
+		instanceCount:      1,
+		sliceWindow:        common.NewSlidingWindow(1, int(validDurationMilli)),
+	}
+}
+
+// setRate 设置稳定状态下的放通阈值，存量令牌按最大存量的变化等比缩放，首次设置时处于冷启动状态
+func (l *warmUpLimiter) setRate(amount float64) {
+	if amount <= 0 {
+		l.stableIntervalMilli = 0
+		return
+	}
+	oldMaxPermits := l.maxPermits
+	l.stableIntervalMilli = float64(l.validDurationMilli) / amount
+	coldIntervalMilli := l.stableIntervalMilli * l.coldFactor
+	l.thresholdPermits = 0.5 * l.warmUpPeriodMilli / l.stableIntervalMilli
+	l.maxPermits = l.thresholdPermits + 2.0*l.warmUpPeriodMilli/(l.stableIntervalMilli+coldIntervalMilli)
+	l.slope = (coldIntervalMilli - l.stableIntervalMilli) / (l.maxPermits - l.thresholdPermits)
+	if oldMaxPermits == 0 {
+		l.storedPermits = l.maxPermits
+	} else {
+		l.storedPermits = l.storedPermits * l.maxPermits / oldMaxPermits
+	}
+}
+
+// remoteExpired 远程配额过期
+func (l *warmUpLimiter) remoteExpired(nowMilli int64) bool {
+	return nowMilli-l.lastRemoteUpdateMilli > remoteExpireMilli
+}
+
+// canAcquire 当前时间是否可以发放令牌
+func (l *warmUpLimiter) canAcquire(nowMilli float64) bool {
+	return l.stableIntervalMilli > 0 && l.nextFreeMilli <= nowMilli
+}
+
+// reserve 发放令牌，并根据消耗的存量令牌与新令牌推进下一次可发放时间
+func (l *warmUpLimiter) reserve(permits float64, nowMilli float64) {
+	l.resync(nowMilli)
+	storedToSpend := math.Min(permits, l.storedPermits)
+	freshPermits := permits - storedToSpend
+	l.nextFreeMilli += l.storedPermitsToWaitTime(storedToSpend) + freshPermits*l.stableIntervalMilli
+	l.storedPermits -= storedToSpend
+}
+
+// resync 根据空闲时长回填存量令牌
+func (l *warmUpLimiter) resync(nowMilli float64) {
+	if nowMilli <= l.nextFreeMilli {
+		return
+	}
+	coolDownIntervalMilli := l.warmUpPeriodMilli / l.maxPermits
+	l.storedPermits = math.Min(l.maxPermits, l.storedPermits+(nowMilli-l.nextFreeMilli)/coolDownIntervalMilli)
+	l.nextFreeMilli = nowMilli
+}
+
+// storedPermitsToWaitTime 计算消耗存量令牌所需的时间，即发放间隔曲线在对应区间上的积分
+func (l *warmUpLimiter) storedPermitsToWaitTime(permitsToTake float64) float64 {
+	availableAboveThreshold := l.storedPermits - l.thresholdPermits
+	waitMilli := 0.0
+	if availableAboveThreshold > 0 {
+		aboveThresholdToTake := math.Min(availableAboveThreshold, permitsToTake)
+		length := l.permitsToTime(availableAboveThreshold) +
+			l.permitsToTime(availableAboveThreshold-aboveThresholdToTake)
+		waitMilli = aboveThresholdToTake * length / 2.0
+		permitsToTake -= aboveThresholdToTake
+	}
+	return waitMilli + l.stableIntervalMilli*permitsToTake
+}
+
+// permitsToTime 存量令牌高于阈值 permits 个时的发放间隔
+func (l *warmUpLimiter) permitsToTime(permits float64) float64 {
+	return l.stableIntervalMilli + permits*l.slope
+}
@@ -0,0 +1,197 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package warmup
+
+import (
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// silentLogger 一个完全静默的 log.Logger 实现，用于只关心限流行为的用例.
+type silentLogger struct{}
+
+func (silentLogger) Tracef(string, ...interface{}) {}
+func (silentLogger) Debugf(string, ...interface{}) {}
+func (silentLogger) Infof(string, ...interface{})  {}
+func (silentLogger) Warnf(string, ...interface{})  {}
+func (silentLogger) Errorf(string, ...interface{}) {}
+func (silentLogger) Fatalf(string, ...interface{}) {}
+func (silentLogger) IsLevelEnabled(int) bool       { return false }
+func (silentLogger) SetLogLevel(int) error         { return nil }
+
+// noopCtx 返回一个挂载了静默 logger 的 ContextLogger.
+func noopCtx() *log.ContextLogger {
+	orig := log.GetRateLimitLogger()
+	log.SetRateLimitLogger(silentLogger{})
+	ctx := &log.ContextLogger{}
+	ctx.Init()
+	log.SetRateLimitLogger(orig)
+	return ctx
+}
+
+// buildQpsRule 构造 QPS 限流规则
+func buildQpsRule(ruleType apitraffic.Rule_Type, maxAmount uint32, validDuration time.Duration) *apitraffic.Rule {
+	return &apitraffic.Rule{
+		Id:       wrapperspb.String("warmup-rule"),
+		Resource: apitraffic.Rule_QPS,
+		Type:     ruleType,
+		Action:   wrapperspb.String("warmup"),
+		Amounts: []*apitraffic.Amount{
+			{
+				MaxAmount:     wrapperspb.UInt32(maxAmount),
+				ValidDuration: durationpb.New(validDuration),
+			},
+		},
+	}
+}
+
+func newTestBucket(rule *apitraffic.Rule, warmUpPeriod time.Duration, startMs int64) *WarmUpBucket {
+	cfg := &Config{WarmUpPeriod: model.ToDurationPtr(warmUpPeriod)}
+	cfg.SetDefault()
+	return NewWarmUpBucket(&ratelimiter.InitCriteria{DstRule: rule, WindowKey: "test-svc#default"}, cfg,
+		startMs, noopCtx())
+}
+
+// countPassed 在 [startMs, endMs) 内每毫秒请求一次，返回放通的请求数
+func countPassed(bucket *WarmUpBucket, startMs, endMs int64) int {
+	passed := 0
+	for now := startMs; now < endMs; now++ {
+		if bucket.GetQuota(now, 1).Code == model.QuotaResultOk {
+			passed++
+		}
+	}
+	return passed
+}
+
+func TestWarmUpBucket_RampUpFromColdRate(t *testing.T) {
+	// 测试场景：100/1s，预热 1s，冷启动系数 3
+	// 冷启动时放通速率约为 1/3 阈值，1s 预热期内消耗掉阈值以上的存量令牌（约 50 个），之后稳定在阈值
+	const start = int64(1_000_000)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_LOCAL, 100, time.Second), time.Second, start)
+
+	coldPassed := countPassed(bucket, start, start+100)
+	assert.LessOrEqual(t, coldPassed, 5, "cold rate should be about 1/3 of the rule amount")
+
+	warmingPassed := coldPassed + countPassed(bucket, start+100, start+1000)
+	assert.InDelta(t, 50, warmingPassed, 5)
+
+	_ = countPassed(bucket, start+1000, start+2000)
+	stablePassed := countPassed(bucket, start+2000, start+3000)
+	assert.InDelta(t, 100, stablePassed, 2)
+}
+
+func TestWarmUpBucket_ColdAgainAfterIdle(t *testing.T) {
+	// 测试场景：预热完成后空闲超过预热时长，存量令牌回填，放通速率回到冷启动速率
+	const start = int64(1_000_000)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_LOCAL, 100, time.Second), time.Second, start)
+	_ = countPassed(bucket, start, start+3000)
+	assert.Greater(t, countPassed(bucket, start+3000, start+3100), 8)
+
+	idleEnd := start + 3100 + 2000
+	assert.LessOrEqual(t, countPassed(bucket, idleEnd, idleEnd+100), 5)
+}
+
+func TestWarmUpBucket_ZeroAmountRejectAll(t *testing.T) {
+	const start = int64(1_000_000)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_LOCAL, 0, time.Second), time.Second, start)
+	resp := bucket.GetQuota(start, 1)
+	assert.Equal(t, model.QuotaResultLimited, resp.Code)
+	assert.Equal(t, "QPS:0/1s", resp.Info)
+}
+
+func TestWarmUpBucket_RemoteQuota(t *testing.T) {
+	// 测试场景：GLOBAL 规则在远程配额有效期内同时受远程剩余配额约束，并上报使用量
+	const start = int64(1_000_000)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_GLOBAL, 10, time.Second), time.Second, start)
+	bucket.OnRemoteUpdate(ratelimiter.RemoteQuotaResult{
+		Left:            2,
+		ClientCount:     1,
+		ServerTimeMilli: start,
+		DurationMill:    1000,
+		ClientTimeMilli: start,
+	})
+
+	// 冷启动发放间隔约 300ms，间隔 400ms 请求保证不被本地预热速率限制
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start, 1).Code)
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start+400, 1).Code)
+	assert.Equal(t, model.QuotaResultLimited, bucket.GetQuota(start+800, 1).Code)
+
+	usage := bucket.GetQuotaUsed(start + 800)
+	assert.Equal(t, uint32(2), usage.Passed[1000])
+	assert.Equal(t, uint32(1), usage.Limited[1000])
+
+	// 远程配额过期后退化为本地预热限流
+	assert.Equal(t, model.QuotaResultOk, bucket.GetQuota(start+2000, 1).Code)
+
+	assert.Equal(t, []ratelimiter.AmountInfo{{ValidDuration: 1, MaxAmount: 10}}, bucket.GetAmountInfos())
+}
+
+func TestWarmUpBucket_GlobalTotalSharedByClients(t *testing.T) {
+	// 测试场景：全局总量规则按客户端数均分本地预热速率
+	const start = int64(1_000_000)
+	bucket := newTestBucket(buildQpsRule(apitraffic.Rule_GLOBAL, 100, time.Second), time.Second, start)
+	bucket.OnRemoteUpdate(ratelimiter.RemoteQuotaResult{
+		Left:            100,
+		ClientCount:     4,
+		ServerTimeMilli: start,
+		DurationMill:    1000,
+		ClientTimeMilli: start,
+	})
+	assert.InDelta(t, 40.0, bucket.limiters[0].stableIntervalMilli, 0.001)
+}
+
+func TestConfig_Verify(t *testing.T) {
+	cfg := &Config{}
+	cfg.SetDefault()
+	assert.NoError(t, cfg.Verify())
+	assert.Equal(t, defaultWarmUpPeriod, *cfg.WarmUpPeriod)
+
+	cfg.ColdFactor = 0.5
+	assert.Error(t, cfg.Verify())
+}
+
+// TestRuleAction_CaseInsensitive 验证规则 Action 按忽略大小写的方式匹配预热限流插件
+// 测试场景：规则 Action 分别为 warmUp、warmup、WARMUP 以及未注册的插件名
+// 预期结果：前三者均归一为插件名 warmUp 且插件已注册；未注册的插件名沿用小写形式
+func TestRuleAction_CaseInsensitive(t *testing.T) {
+	assert.Equal(t, "warmUp", config.DefaultWarmUpRateLimiter)
+	for _, action := range []string{"warmUp", "warmup", "WARMUP", "Unknown"} {
+		rule := buildQpsRule(apitraffic.Rule_LOCAL, 10, time.Second)
+		rule.Action = wrapperspb.String(action)
+		(&pb.RateLimitingAssistant{}).SetDefault(&apitraffic.RateLimit{Rules: []*apitraffic.Rule{rule}})
+		if action == "Unknown" {
+			assert.Equal(t, "unknown", rule.GetAction().GetValue())
+			continue
+		}
+		assert.Equal(t, config.DefaultWarmUpRateLimiter, rule.GetAction().GetValue(), action)
+		assert.True(t, plugin.IsPluginRegistered(common.TypeRateLimiter, rule.GetAction().GetValue()))
+	}
+}
@@ -0,0 +1,62 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package warmup
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	defaultWarmUpPeriod = 10 * time.Second
+	defaultColdFactor   = 3.0
+)
+
+// Config 预热限流器配置
+type Config struct {
+	// WarmUpPeriod 从冷启动速率爬升到规则阈值所需的时间
+	WarmUpPeriod *time.Duration `yaml:"warmUpPeriod" json:"warmUpPeriod"`
+	// ColdFactor 冷启动系数，冷启动时的放通速率为规则阈值的 1/ColdFactor
+	ColdFactor float64 `yaml:"coldFactor" json:"coldFactor"`
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if nil == c.WarmUpPeriod {
+		c.WarmUpPeriod = model.ToDurationPtr(defaultWarmUpPeriod)
+	}
+	if c.ColdFactor == 0 {
+		c.ColdFactor = defaultColdFactor
+	}
+}
+
+// Verify 校验配置值
+func (c *Config) Verify() error {
+	if nil == c.WarmUpPeriod {
+		return fmt.Errorf("warmUpPeriod not configured")
+	}
+	if *c.WarmUpPeriod <= 0 {
+		return fmt.Errorf("invalid warmUpPeriod: %v, it must greater than 0", *c.WarmUpPeriod)
+	}
+	if c.ColdFactor < 1 {
+		return fmt.Errorf("invalid coldFactor: %v, it must greater than or equal to 1", c.ColdFactor)
+	}
+	return nil
+}
@@ -52,25 +52,33 @@ const (
 	reqIDPrefixCreateConfigFile
 	reqIDPrefixUpdateConfigFile
 	reqIDPrefixPublishConfigFile
+	reqIDPrefixDeleteConfigFile
+	reqIDPrefixListConfigFiles
+	reqIDPrefixGetConfigFileReleaseHistory
+	reqIDPrefixRollbackConfigFile
 )
 
 const (
-	OpKeyRegisterInstance           = "RegisterInstance"
-	OpKeyDeregisterInstance         = "DeregisterInstance"
-	OpKeyInstanceHeartbeat          = "InstanceHeartbeat"
-	OpKeyDiscover                   = "Discover"
-	OpKeyReportClient               = "ReportClient"
-	OpKeyRateLimitInit              = "RateLimitInit"
-	OpKeyRateLimitAcquire           = "RateLimitAcquire"
-	OpKeyRateLimitMetricInit        = "RateLimitMetricInit"
-	OpKeyRateLimitMetricReport      = "RateLimitMetricReport"
-	OpKeyGetConfigFile              = "GetConfigFile"
-	OpKeyWatchConfigFiles           = "WatchConfigFiles"
-	OpKeyCreateConfigFile           = "CreateConfigFile"
-	OpKeyUpdateConfigFile           = "UpdateConfigFile"
-	OpKeyPublishConfigFile          = "PublishConfigFile"
-	OpKeyGetConfigGroup             = "GetConfigGroup"
-	OpkeyUpsertAndPublishConfigFile = "UpsertAndPublishConfigFile"
+	OpKeyRegisterInstance            = "RegisterInstance"
+	OpKeyDeregisterInstance          = "DeregisterInstance"
+	OpKeyInstanceHeartbeat           = "InstanceHeartbeat"
+	OpKeyDiscover                    = "Discover"
+	OpKeyReportClient                = "ReportClient"
+	OpKeyRateLimitInit               = "RateLimitInit"
+	OpKeyRateLimitAcquire            = "RateLimitAcquire"
+	OpKeyRateLimitMetricInit         = "RateLimitMetricInit"
+	OpKeyRateLimitMetricReport       = "RateLimitMetricReport"
+	OpKeyGetConfigFile               = "GetConfigFile"
+	OpKeyWatchConfigFiles            = "WatchConfigFiles"
+	OpKeyCreateConfigFile            = "CreateConfigFile"
+	OpKeyUpdateConfigFile            = "UpdateConfigFile"
+	OpKeyPublishConfigFile           = "PublishConfigFile"
+	OpKeyGetConfigGroup              = "GetConfigGroup"
+	OpkeyUpsertAndPublishConfigFile  = "UpsertAndPublishConfigFile"
+	OpKeyDeleteConfigFile            = "DeleteConfigFile"
+	OpKeyListConfigFiles             = "ListConfigFiles"
+	OpKeyGetConfigFileReleaseHistory = "GetConfigFileReleaseHistory"
+	OpKeyRollbackConfigFile          = "RollbackConfigFile"
 )
 
 // NextDiscoverReqID 生成GetInstances调用的请求Id
@@ -133,6 +141,26 @@ func NextPublishConfigFileReqID() string {
 	return fmt.Sprintf("%d%d", reqIDPrefixPublishConfigFile, uuid.New().ID())
 }
 
+// NextDeleteConfigFileReqID 生成DeleteConfigFile调用的请求Id
+func NextDeleteConfigFileReqID() string {
+	return fmt.Sprintf("%d%d", reqIDPrefixDeleteConfigFile, uuid.New().ID())
+}
+
+// NextListConfigFilesReqID 生成ListConfigFiles调用的请求Id
+func NextListConfigFilesReqID() string {
+	return fmt.Sprintf("%d%d", reqIDPrefixListConfigFiles, uuid.New().ID())
+}
+
+// NextGetConfigFileReleaseHistoryReqID 生成GetConfigFileReleaseHistory调用的请求Id
+func NextGetConfigFileReleaseHistoryReqID() string {
+	return fmt.Sprintf("%d%d", reqIDPrefixGetConfigFileReleaseHistory, uuid.New().ID())
+}
+
+// NextRollbackConfigFileReqID 生成RollbackConfigFile调用的请求Id
+func NextRollbackConfigFileReqID() string {
+	return fmt.Sprintf("%d%d", reqIDPrefixRollbackConfigFile, uuid.New().ID())
+}
+
 // GetConnErrorCode 获取连接错误码
 func GetConnErrorCode(err error) int32 {
 	code, ok := status.FromError(err)
@@ -176,18 +204,33 @@ func GetUpdateTaskRequestTime(updateTask *serviceUpdateTask) time.Duration {
 // }
 
 func CreateHeadersContext(timeout time.Duration, options ...func(map[string]string)) (context.Context, context.CancelFunc) {
+	return CreateHeadersContextWithParent(context.Background(), timeout, options...)
+}
+
+// CreateHeadersContextWithParent 基于调用方的请求上下文创建传输grpc头的context，
+// 调用方的 deadline、取消信号以及已有的 outgoing metadata（如 trace 头）会一并透传到服务端
+func CreateHeadersContextWithParent(parent context.Context, timeout time.Duration,
+	options ...func(map[string]string)) (context.Context, context.CancelFunc) {
 	headers := map[string]string{}
 	for _, option := range options {
 		option(headers)
 	}
 
-	md := metadata.New(headers)
+	md, ok := metadata.FromOutgoingContext(parent)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	for k, v := range headers {
+		md.Set(k, v)
+	}
 	var ctx context.Context
 	var cancel context.CancelFunc
 	if timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		ctx, cancel = context.WithTimeout(parent, timeout)
 	} else {
-		ctx = context.Background()
+		ctx = parent
 		cancel = nil
 	}
 	return metadata.NewOutgoingContext(ctx, md), cancel
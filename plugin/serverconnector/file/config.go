@@ -0,0 +1,71 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// DefaultPath 默认的本地注册数据目录
+	DefaultPath = "./polaris/registry"
+	// DefaultRefreshInterval 默认的文件变更检查周期
+	DefaultRefreshInterval = 2 * time.Second
+	// minRefreshInterval 文件变更检查周期的下限
+	minRefreshInterval = 100 * time.Millisecond
+)
+
+// Config 本地文件连接器插件级配置
+type Config struct {
+	// Path 存放服务数据文件的目录，目录结构为 <path>/<namespace>/<service>.(yaml|yml|json)
+	Path string `yaml:"path" json:"path"`
+	// RefreshInterval 检查文件变更的周期
+	RefreshInterval *time.Duration `yaml:"refreshInterval" json:"refreshInterval"`
+}
+
+// Verify 校验配置值
+func (c *Config) Verify() error {
+	if nil == c {
+		return errors.New("file serverConnector config is nil")
+	}
+	var errs error
+	if len(c.Path) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("file.path is empty"))
+	}
+	if c.RefreshInterval == nil || *c.RefreshInterval < minRefreshInterval {
+		errs = multierror.Append(errs, fmt.Errorf("file.refreshInterval must be greater than or equal to %v",
+			minRefreshInterval))
+	}
+	return errs
+}
+
+// SetDefault 设置配置默认值
+func (c *Config) SetDefault() {
+	if len(c.Path) == 0 {
+		c.Path = DefaultPath
+	}
+	if c.RefreshInterval == nil {
+		c.RefreshInterval = model.ToDurationPtr(DefaultRefreshInterval)
+	}
+}
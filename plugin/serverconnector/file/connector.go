@@ -0,0 +1,226 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package file 基于本地文件的服务端连接器，适用于没有北极星服务端的离线或静态注册场景
+//
+// 服务数据存放在 <path>/<namespace>/<service>.(yaml|yml|json) 文件中，插件定期检查文件变更，
+// 通过与grpc连接器相同的 ServiceEventHandler 回调将新版本推送给本地缓存，WatchService 等监听因此同样生效。
+package file
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+// watcher 已注册的监听及最近一次推送的资源版本
+type watcher struct {
+	handler *serverconnector.ServiceEventHandler
+	pushed  bool
+	// 最近一次推送的应答码及版本号
+	lastCode     uint32
+	lastRevision string
+}
+
+// Connector 基于本地文件的服务端连接器
+type Connector struct {
+	*plugin.PluginBase
+	*common.RunContext
+	cfg      *Config
+	enable   bool
+	registry *registry
+	// 保护监听列表
+	mutex    sync.Mutex
+	watchers map[model.ServiceEventKey]*watcher
+	// 保证推送按顺序进行，回调中可能反注册监听，因此与监听列表使用不同的锁
+	pushMutex sync.Mutex
+	logCtx    *log.ContextLogger
+}
+
+// Type 插件类型
+func (c *Connector) Type() common.Type {
+	return common.TypeServerConnector
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (c *Connector) Name() string {
+	return config.ServerConnectorFile
+}
+
+// Init 初始化插件
+func (c *Connector) Init(ctx *plugin.InitContext) error {
+	c.PluginBase = plugin.NewPluginBase(ctx)
+	c.RunContext = common.NewRunContext()
+	c.logCtx = ctx.ValueCtx.GetContextLogger()
+	c.cfg = &Config{}
+	if cfgValue := ctx.Config.GetGlobal().GetServerConnector().GetPluginConfig(c.Name()); cfgValue != nil {
+		c.cfg = cfgValue.(*Config)
+	}
+	c.cfg.SetDefault()
+	c.enable = ctx.Config.GetGlobal().GetServerConnector().GetProtocol() == c.Name()
+	c.registry = newRegistry(c.cfg.Path)
+	c.watchers = map[model.ServiceEventKey]*watcher{}
+	if !c.enable {
+		return nil
+	}
+	if _, err := c.registry.reload(); err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to load registry files from %s",
+			c.cfg.Path)
+	}
+	return nil
+}
+
+// Start 启动插件，仅在当前协议为 file 时检查文件变更
+func (c *Connector) Start() error {
+	if c.enable {
+		go c.refreshRoutine()
+	}
+	return nil
+}
+
+// Destroy 销毁插件
+func (c *Connector) Destroy() error {
+	return c.RunContext.Destroy()
+}
+
+// IsEnable 插件开关
+func (c *Connector) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// RegisterServiceHandler 注册服务监听器，注册后异步推送当前文件中的数据
+func (c *Connector) RegisterServiceHandler(svcEventHandler *serverconnector.ServiceEventHandler) error {
+	if c.IsDestroyed() {
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil, "file connector has been destroyed")
+	}
+	key := *svcEventHandler.ServiceEventKey
+	w := &watcher{handler: svcEventHandler}
+	c.mutex.Lock()
+	c.watchers[key] = w
+	c.mutex.Unlock()
+	go c.push(key, w)
+	return nil
+}
+
+// DeRegisterServiceHandler 反注册事件监听器
+func (c *Connector) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.watchers, *key)
+	return nil
+}
+
+// RegisterInstance 本地文件模式不支持注册实例
+func (c *Connector) RegisterInstance(req *model.InstanceRegisterRequest,
+	header map[string]string) (*model.InstanceRegisterResponse, error) {
+	return nil, c.notSupported("RegisterInstance")
+}
+
+// DeregisterInstance 本地文件模式不支持反注册实例
+func (c *Connector) DeregisterInstance(instance *model.InstanceDeRegisterRequest) error {
+	return c.notSupported("DeregisterInstance")
+}
+
+// Heartbeat 本地文件模式不支持心跳上报
+func (c *Connector) Heartbeat(instance *model.InstanceHeartbeatRequest) error {
+	return c.notSupported("Heartbeat")
+}
+
+// ReportClient 本地文件模式没有服务端，返回空的地域信息
+func (c *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportClientResponse, error) {
+	return &model.ReportClientResponse{}, nil
+}
+
+// UpdateServers 本地文件模式没有服务端地址，无需更新
+func (c *Connector) UpdateServers(key *model.ServiceEventKey) error {
+	return nil
+}
+
+func (c *Connector) notSupported(op string) error {
+	return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+		"%s is not supported by %s serverConnector", op, c.Name())
+}
+
+// refreshRoutine 定期检查文件变更，数据变化后推送给全部监听
+func (c *Connector) refreshRoutine() {
+	ticker := time.NewTicker(*c.cfg.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Done():
+			c.logCtx.GetBaseLogger().Infof("refreshRoutine of file connector has been terminated")
+			return
+		case <-ticker.C:
+			c.refresh()
+		}
+	}
+}
+
+func (c *Connector) refresh() {
+	changed, err := c.registry.reload()
+	if err != nil {
+		c.logCtx.GetBaseLogger().Errorf("file connector: %v", err)
+	}
+	if !changed {
+		return
+	}
+	c.mutex.Lock()
+	watchers := make(map[model.ServiceEventKey]*watcher, len(c.watchers))
+	for key, w := range c.watchers {
+		watchers[key] = w
+	}
+	c.mutex.Unlock()
+	for key, w := range watchers {
+		c.push(key, w)
+	}
+}
+
+// push 构造应答并在版本号变化时回调监听
+func (c *Connector) push(key model.ServiceEventKey, w *watcher) {
+	c.pushMutex.Lock()
+	defer c.pushMutex.Unlock()
+	c.mutex.Lock()
+	current := c.watchers[key]
+	c.mutex.Unlock()
+	if current != w {
+		// 已经反注册或被重新注册
+		return
+	}
+	resp := c.registry.buildResponse(&key)
+	code := resp.GetCode().GetValue()
+	revision := resp.GetService().GetRevision().GetValue()
+	if w.pushed && w.lastCode == code && w.lastRevision == revision {
+		return
+	}
+	w.pushed, w.lastCode, w.lastRevision = true, code, revision
+	c.logCtx.GetBaseLogger().Debugf("file connector: push %s, code %d, revision %s", key, code, revision)
+	w.handler.Handler.OnServiceUpdate(&serverconnector.ServiceEvent{
+		ServiceEventKey: key,
+		Value:           resp,
+	})
+}
+
+// init 注册插件信息
+func init() {
+	plugin.RegisterConfigurablePlugin(&Connector{}, &Config{})
+}
@@ -0,0 +1,344 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+const echoYAML = `
+metadata:
+  owner: polaris
+instances:
+  - host: 127.0.0.1
+    port: 8080
+  - id: ins-2
+    host: 127.0.0.2
+    port: 8080
+    weight: 50
+    healthy: false
+    metadata:
+      env: prod
+routing:
+  inbounds:
+    - sources:
+        - service: "*"
+          namespace: "*"
+      destinations:
+        - metadata:
+            env:
+              value: prod
+rateLimit:
+  rules:
+    - name: qps
+      resource: QPS
+      type: LOCAL
+      amounts:
+        - maxAmount: 10
+          validDuration: 1s
+lanes:
+  - name: group
+    rules:
+      - name: gray
+`
+
+const helloJSON = `{"instances": [{"host": "10.0.0.1", "port": 80}]}`
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// 保证同一测试中的连续写入能被修改时间区分
+	later := time.Now().Add(time.Duration(len(content)) * time.Millisecond)
+	_ = os.Chtimes(path, later, later)
+}
+
+func eventKey(namespace, service string, eventType model.EventType) *model.ServiceEventKey {
+	return &model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: namespace, Service: service},
+		Type:       eventType,
+	}
+}
+
+func TestRegistryLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "default", "echo.yaml"), echoYAML)
+	writeFile(t, filepath.Join(dir, "Test", "hello.json"), helloJSON)
+	writeFile(t, filepath.Join(dir, "default", "README.md"), "ignored")
+	r := newRegistry(dir)
+	changed, err := r.reload()
+	if err != nil || !changed {
+		t.Fatalf("reload: changed %v, err %v", changed, err)
+	}
+
+	resp := r.buildResponse(eventKey("default", "echo", model.EventInstances))
+	if resp.GetCode().GetValue() != uint32(apimodel.Code_ExecuteSuccess) || resp.GetType() != apiservice.DiscoverResponse_INSTANCE {
+		t.Fatalf("unexpected response code %d, type %v", resp.GetCode().GetValue(), resp.GetType())
+	}
+	if resp.GetService().GetMetadata()["owner"] != "polaris" || len(resp.GetService().GetRevision().GetValue()) == 0 {
+		t.Fatalf("unexpected service %v", resp.GetService())
+	}
+	if len(resp.GetInstances()) != 2 {
+		t.Fatalf("expect 2 instances, got %d", len(resp.GetInstances()))
+	}
+	first, second := resp.GetInstances()[0], resp.GetInstances()[1]
+	if first.GetId().GetValue() != "127.0.0.1:8080" || !first.GetHealthy().GetValue() ||
+		first.GetWeight().GetValue() != defaultInstanceWeight || first.GetService().GetValue() != "echo" {
+		t.Fatalf("instance defaults not filled: %v", first)
+	}
+	if second.GetId().GetValue() != "ins-2" || second.GetHealthy().GetValue() || second.GetWeight().GetValue() != 50 {
+		t.Fatalf("instance values overridden: %v", second)
+	}
+
+	// 规则自身的版本号需与服务版本号一致，否则本地缓存会认为每次推送都有变更
+	routing := r.buildResponse(eventKey("default", "echo", model.EventRouting))
+	if routing.GetRouting().GetRevision().GetValue() != routing.GetService().GetRevision().GetValue() ||
+		routing.GetRouting().GetService().GetValue() != "echo" || len(routing.GetRouting().GetInbounds()) != 1 {
+		t.Fatalf("unexpected routing %v", routing)
+	}
+	rateLimit := r.buildResponse(eventKey("default", "echo", model.EventRateLimiting))
+	rule := rateLimit.GetRateLimit().GetRules()[0]
+	if rule.GetId().GetValue() != "qps" || rule.GetNamespace().GetValue() != "default" ||
+		len(rule.GetRevision().GetValue()) == 0 ||
+		rateLimit.GetRateLimit().GetRevision().GetValue() != rateLimit.GetService().GetRevision().GetValue() {
+		t.Fatalf("unexpected rate limit %v", rateLimit)
+	}
+	lanes := r.buildResponse(eventKey("default", "echo", model.EventLane))
+	if len(lanes.GetLanes()) != 1 || len(lanes.GetService().GetRevision().GetValue()) == 0 {
+		t.Fatalf("unexpected lanes %v", lanes)
+	}
+
+	// 未配置的规则返回空内容和空版本号
+	cb := r.buildResponse(eventKey("default", "echo", model.EventCircuitBreaker))
+	if cb.GetCode().GetValue() != uint32(apimodel.Code_ExecuteSuccess) || cb.GetCircuitBreaker() != nil ||
+		cb.GetService().GetRevision().GetValue() != "" {
+		t.Fatalf("unexpected circuit breaker %v", cb)
+	}
+	missing := r.buildResponse(eventKey("default", "missing", model.EventInstances))
+	if missing.GetCode().GetValue() != uint32(apimodel.Code_NotFoundResource) {
+		t.Fatalf("expect not found, got %d", missing.GetCode().GetValue())
+	}
+
+	services := r.buildResponse(eventKey("", "", model.EventServices))
+	if len(services.GetServices()) != 2 || services.GetServices()[0].GetNamespace().GetValue() != "Test" {
+		t.Fatalf("unexpected services %v", services.GetServices())
+	}
+	services = r.buildResponse(eventKey("default", "", model.EventServices))
+	if len(services.GetServices()) != 1 || services.GetServices()[0].GetName().GetValue() != "echo" {
+		t.Fatalf("unexpected services %v", services.GetServices())
+	}
+}
+
+func TestRegistryReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "default", "echo.yaml")
+	writeFile(t, path, echoYAML)
+	r := newRegistry(dir)
+	if _, err := r.reload(); err != nil {
+		t.Fatal(err)
+	}
+	key := eventKey("default", "echo", model.EventInstances)
+	oldRevision := r.buildResponse(key).GetService().GetRevision().GetValue()
+	oldRouting := r.buildResponse(eventKey("default", "echo", model.EventRouting)).GetService().GetRevision().GetValue()
+
+	changed, err := r.reload()
+	if err != nil || changed {
+		t.Fatalf("expect no change, changed %v, err %v", changed, err)
+	}
+
+	writeFile(t, path, echoYAML+"\n# comment only\n")
+	changed, err = r.reload()
+	if err != nil || !changed {
+		t.Fatalf("expect change, changed %v, err %v", changed, err)
+	}
+	if r.buildResponse(key).GetService().GetRevision().GetValue() != oldRevision {
+		t.Fatal("revision must depend on content only")
+	}
+
+	writeFile(t, path, "instances:\n  - host: 127.0.0.9\n    port: 9\n")
+	if _, err = r.reload(); err != nil {
+		t.Fatal(err)
+	}
+	resp := r.buildResponse(key)
+	if resp.GetService().GetRevision().GetValue() == oldRevision || len(resp.GetInstances()) != 1 {
+		t.Fatalf("expect new instances, got %v", resp)
+	}
+	routing := r.buildResponse(eventKey("default", "echo", model.EventRouting))
+	if routing.GetRouting() != nil || routing.GetService().GetRevision().GetValue() == oldRouting {
+		t.Fatalf("expect routing removed, got %v", routing)
+	}
+
+	// 解析失败时保留上一次加载成功的内容
+	writeFile(t, path, "instances: [{host: 127.0.0.1, port: not-a-port}]")
+	if _, err = r.reload(); err == nil {
+		t.Fatal("expect parse error")
+	}
+	if len(r.buildResponse(key).GetInstances()) != 1 {
+		t.Fatal("previous data must be kept on parse error")
+	}
+
+	if err = os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	changed, err = r.reload()
+	if err != nil || !changed {
+		t.Fatalf("expect change, changed %v, err %v", changed, err)
+	}
+	if r.buildResponse(key).GetCode().GetValue() != uint32(apimodel.Code_NotFoundResource) {
+		t.Fatal("expect deleted service not found")
+	}
+}
+
+// recordHandler 记录收到的推送
+type recordHandler struct {
+	mutex  sync.Mutex
+	events []*serverconnector.ServiceEvent
+	ch     chan struct{}
+}
+
+func (h *recordHandler) OnServiceUpdate(event *serverconnector.ServiceEvent) {
+	h.mutex.Lock()
+	h.events = append(h.events, event)
+	h.mutex.Unlock()
+	h.ch <- struct{}{}
+}
+
+func (h *recordHandler) GetRevision() string { return "" }
+
+func (h *recordHandler) GetBusiness() string { return "" }
+
+func (h *recordHandler) wait(t *testing.T) *serverconnector.ServiceEvent {
+	t.Helper()
+	select {
+	case <-h.ch:
+	case <-time.After(3 * time.Second):
+		t.Fatal("wait push timeout")
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.events[len(h.events)-1]
+}
+
+// silentLogger 丢弃全部日志
+type silentLogger struct{}
+
+func (silentLogger) Tracef(string, ...interface{}) {}
+func (silentLogger) Debugf(string, ...interface{}) {}
+func (silentLogger) Infof(string, ...interface{})  {}
+func (silentLogger) Warnf(string, ...interface{})  {}
+func (silentLogger) Errorf(string, ...interface{}) {}
+func (silentLogger) Fatalf(string, ...interface{}) {}
+func (silentLogger) IsLevelEnabled(int) bool       { return false }
+func (silentLogger) SetLogLevel(int) error         { return nil }
+
+func newTestConnector(dir string) *Connector {
+	orig := log.GetBaseLogger()
+	log.SetBaseLogger(silentLogger{})
+	logCtx := &log.ContextLogger{}
+	logCtx.Init()
+	log.SetBaseLogger(orig)
+	cfg := &Config{Path: dir}
+	cfg.SetDefault()
+	return &Connector{
+		RunContext: common.NewRunContext(),
+		cfg:        cfg,
+		enable:     true,
+		registry:   newRegistry(dir),
+		watchers:   map[model.ServiceEventKey]*watcher{},
+		logCtx:     logCtx,
+	}
+}
+
+func TestConnectorPush(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "default", "echo.yaml")
+	writeFile(t, path, echoYAML)
+	c := newTestConnector(dir)
+	defer c.Destroy()
+	c.refresh()
+
+	handler := &recordHandler{ch: make(chan struct{}, 10)}
+	key := eventKey("default", "echo", model.EventInstances)
+	if err := c.RegisterServiceHandler(&serverconnector.ServiceEventHandler{ServiceEventKey: key, Handler: handler}); err != nil {
+		t.Fatal(err)
+	}
+	event := handler.wait(t)
+	if event.ServiceEventKey != *key || len(event.Value.(*apiservice.DiscoverResponse).GetInstances()) != 2 {
+		t.Fatalf("unexpected first push %v", event)
+	}
+
+	// 与当前资源无关的变更不推送
+	writeFile(t, path, echoYAML+"circuitBreaker: {}\n")
+	c.refresh()
+	select {
+	case <-handler.ch:
+		t.Fatal("unexpected push without instance change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	writeFile(t, path, "instances: [{host: 127.0.0.3, port: 9090}]")
+	c.refresh()
+	event = handler.wait(t)
+	instances := event.Value.(*apiservice.DiscoverResponse).GetInstances()
+	if len(instances) != 1 || instances[0].GetHost().GetValue() != "127.0.0.3" {
+		t.Fatalf("unexpected push %v", instances)
+	}
+
+	if err := c.DeRegisterServiceHandler(key); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Remove(path)
+	c.refresh()
+	select {
+	case <-handler.ch:
+		t.Fatal("unexpected push after deregister")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestConfig(t *testing.T) {
+	cfg := &Config{}
+	cfg.SetDefault()
+	if cfg.Path != DefaultPath || *cfg.RefreshInterval != DefaultRefreshInterval {
+		t.Fatalf("unexpected default %+v", cfg)
+	}
+	if err := cfg.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.RefreshInterval = model.ToDurationPtr(time.Millisecond)
+	if err := cfg.Verify(); err == nil {
+		t.Fatal("expect refreshInterval error")
+	}
+}
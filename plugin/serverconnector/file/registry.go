@@ -0,0 +1,556 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+const (
+	// 实例未配置权重时使用的默认权重
+	defaultInstanceWeight = 100
+)
+
+// serviceFile 单个服务数据文件的原始内容，各字段沿用北极星 OpenAPI 的 JSON 格式
+type serviceFile struct {
+	Metadata         map[string]string `json:"metadata"`
+	Instances        []json.RawMessage `json:"instances"`
+	Routing          json.RawMessage   `json:"routing"`
+	RateLimit        json.RawMessage   `json:"rateLimit"`
+	CircuitBreaker   json.RawMessage   `json:"circuitBreaker"`
+	FaultDetector    json.RawMessage   `json:"faultDetector"`
+	NearbyRouteRules []json.RawMessage `json:"nearbyRouteRules"`
+	Lossless         []json.RawMessage `json:"lossless"`
+	Lanes            []json.RawMessage `json:"lanes"`
+	BlockAllowRules  []json.RawMessage `json:"blockAllowRules"`
+}
+
+// serviceData 解析后的服务数据
+type serviceData struct {
+	key              model.ServiceKey
+	metadata         map[string]string
+	instances        []*apiservice.Instance
+	routing          *apitraffic.Routing
+	rateLimit        *apitraffic.RateLimit
+	circuitBreaker   *apifault.CircuitBreaker
+	faultDetector    *apifault.FaultDetector
+	nearbyRouteRules []*apitraffic.RouteRule
+	lossless         []*apitraffic.LosslessRule
+	lanes            []*apitraffic.LaneGroup
+	blockAllowRules  []*apisecurity.BlockAllowListRule
+	// 各类资源的版本号，由资源内容计算得出，资源未配置时为空
+	revisions map[model.EventType]string
+}
+
+// fileEntry 已加载的数据文件
+type fileEntry struct {
+	modTime time.Time
+	size    int64
+	data    *serviceData
+}
+
+// registry 本地文件中的服务数据
+type registry struct {
+	dir      string
+	mutex    sync.RWMutex
+	files    map[string]*fileEntry
+	services map[model.ServiceKey]*serviceData
+}
+
+func newRegistry(dir string) *registry {
+	return &registry{
+		dir:      dir,
+		files:    map[string]*fileEntry{},
+		services: map[model.ServiceKey]*serviceData{},
+	}
+}
+
+// reload 扫描目录并重新加载发生变化的文件，返回数据是否发生变化
+// 解析失败的文件保留上一次成功加载的内容，错误会合并返回
+func (r *registry) reload() (bool, error) {
+	found, err := r.scan()
+	if err != nil {
+		return false, err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var (
+		changed bool
+		errs    []string
+	)
+	for path, info := range found {
+		entry, ok := r.files[path]
+		if ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+			continue
+		}
+		data, err := loadServiceFile(r.dir, path)
+		if err != nil {
+			errs = append(errs, err.Error())
+			if !ok {
+				continue
+			}
+			// 记录文件状态，避免对同一个错误文件反复解析
+			entry.modTime, entry.size = info.ModTime(), info.Size()
+			continue
+		}
+		r.files[path] = &fileEntry{modTime: info.ModTime(), size: info.Size(), data: data}
+		changed = true
+	}
+	for path := range r.files {
+		if _, ok := found[path]; !ok {
+			delete(r.files, path)
+			changed = true
+		}
+	}
+	if changed {
+		services := make(map[model.ServiceKey]*serviceData, len(r.files))
+		for _, entry := range r.files {
+			services[entry.data.key] = entry.data
+		}
+		r.services = services
+	}
+	if len(errs) > 0 {
+		return changed, fmt.Errorf("fail to load service files: %s", strings.Join(errs, "; "))
+	}
+	return changed, nil
+}
+
+// scan 列出 <dir>/<namespace>/<service>.(yaml|yml|json) 形式的全部数据文件
+func (r *registry) scan() (map[string]os.FileInfo, error) {
+	namespaces, err := ioutil.ReadDir(r.dir)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read registry dir %s: %v", r.dir, err)
+	}
+	found := map[string]os.FileInfo{}
+	for _, ns := range namespaces {
+		if !ns.IsDir() {
+			continue
+		}
+		nsDir := filepath.Join(r.dir, ns.Name())
+		files, err := ioutil.ReadDir(nsDir)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read namespace dir %s: %v", nsDir, err)
+		}
+		for _, file := range files {
+			if file.IsDir() || !isServiceFile(file.Name()) {
+				continue
+			}
+			found[filepath.Join(nsDir, file.Name())] = file
+		}
+	}
+	return found, nil
+}
+
+func isServiceFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return !strings.HasPrefix(name, ".")
+	default:
+		return false
+	}
+}
+
+// buildResponse 按照服务端的应答格式构造指定资源的应答
+func (r *registry) buildResponse(key *model.ServiceEventKey) *apiservice.DiscoverResponse {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	resp := &apiservice.DiscoverResponse{
+		Code: wrapperspb.UInt32(uint32(apimodel.Code_ExecuteSuccess)),
+		Type: eventTypeToResponseType[key.Type],
+		Service: &apiservice.Service{
+			Namespace: wrapperspb.String(key.Namespace),
+			Name:      wrapperspb.String(key.Service),
+		},
+	}
+	if key.Type == model.EventServices {
+		r.fillServices(resp, key.Namespace)
+		return resp
+	}
+	svc, ok := r.services[key.ServiceKey]
+	if !ok {
+		resp.Code = wrapperspb.UInt32(uint32(apimodel.Code_NotFoundResource))
+		resp.Info = wrapperspb.String(fmt.Sprintf("service %s not found in %s", key.ServiceKey, r.dir))
+		return resp
+	}
+	resp.Service.Metadata = svc.metadata
+	resp.Service.Revision = wrapperspb.String(svc.revisions[key.Type])
+	switch key.Type {
+	case model.EventInstances:
+		for _, instance := range svc.instances {
+			resp.Instances = append(resp.Instances, proto.Clone(instance).(*apiservice.Instance))
+		}
+	case model.EventRouting:
+		if svc.routing != nil {
+			resp.Routing = proto.Clone(svc.routing).(*apitraffic.Routing)
+		}
+	case model.EventRateLimiting:
+		if svc.rateLimit != nil {
+			resp.RateLimit = proto.Clone(svc.rateLimit).(*apitraffic.RateLimit)
+		}
+	case model.EventCircuitBreaker:
+		if svc.circuitBreaker != nil {
+			resp.CircuitBreaker = proto.Clone(svc.circuitBreaker).(*apifault.CircuitBreaker)
+		}
+	case model.EventFaultDetect:
+		if svc.faultDetector != nil {
+			resp.FaultDetector = proto.Clone(svc.faultDetector).(*apifault.FaultDetector)
+		}
+	case model.EventNearbyRouteRule:
+		for _, rule := range svc.nearbyRouteRules {
+			resp.NearbyRouteRules = append(resp.NearbyRouteRules, proto.Clone(rule).(*apitraffic.RouteRule))
+		}
+	case model.EventLossless:
+		for _, rule := range svc.lossless {
+			resp.LosslessRuleList = append(resp.LosslessRuleList, proto.Clone(rule).(*apitraffic.LosslessRule))
+		}
+	case model.EventLane:
+		for _, group := range svc.lanes {
+			resp.Lanes = append(resp.Lanes, proto.Clone(group).(*apitraffic.LaneGroup))
+		}
+	case model.EventBlockAllowRule:
+		for _, rule := range svc.blockAllowRules {
+			resp.BlockAllowListRule = append(resp.BlockAllowListRule,
+				proto.Clone(rule).(*apisecurity.BlockAllowListRule))
+		}
+	}
+	return resp
+}
+
+// fillServices 填充命名空间下的服务列表，命名空间为空时返回全部服务
+func (r *registry) fillServices(resp *apiservice.DiscoverResponse, namespace string) {
+	keys := make([]model.ServiceKey, 0, len(r.services))
+	for key := range r.services {
+		if len(namespace) > 0 && key.Namespace != namespace {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Service < keys[j].Service
+	})
+	for _, key := range keys {
+		resp.Services = append(resp.Services, &apiservice.Service{
+			Namespace: wrapperspb.String(key.Namespace),
+			Name:      wrapperspb.String(key.Service),
+			Metadata:  r.services[key].metadata,
+		})
+	}
+	resp.Service.Revision = wrapperspb.String(pb.GenServicesRevision(resp.Services))
+}
+
+// loadServiceFile 解析单个服务数据文件，命名空间和服务名由文件路径决定
+func loadServiceFile(dir string, path string) (*serviceData, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read %s: %v", path, err)
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(rel)
+	key := model.ServiceKey{
+		Namespace: filepath.Dir(rel),
+		Service:   strings.TrimSuffix(name, filepath.Ext(name)),
+	}
+	if strings.ToLower(filepath.Ext(name)) != ".json" {
+		if content, err = yamlToJSON(content); err != nil {
+			return nil, fmt.Errorf("fail to parse %s: %v", path, err)
+		}
+	}
+	raw := &serviceFile{}
+	if err = json.Unmarshal(content, raw); err != nil {
+		return nil, fmt.Errorf("fail to parse %s: %v", path, err)
+	}
+	data, err := parseServiceFile(key, raw)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse %s: %v", path, err)
+	}
+	return data, nil
+}
+
+// parseServiceFile 将原始内容解析为北极星的资源对象，并补齐服务端通常会填充的字段
+func parseServiceFile(key model.ServiceKey, raw *serviceFile) (*serviceData, error) {
+	data := &serviceData{
+		key:       key,
+		metadata:  raw.Metadata,
+		revisions: map[model.EventType]string{},
+	}
+	for i, value := range raw.Instances {
+		instance := &apiservice.Instance{}
+		if err := unmarshal(value, instance); err != nil {
+			return nil, fmt.Errorf("instances[%d]: %v", i, err)
+		}
+		fillInstance(key, instance)
+		data.instances = append(data.instances, instance)
+	}
+	if len(raw.Routing) > 0 {
+		data.routing = &apitraffic.Routing{}
+		if err := unmarshal(raw.Routing, data.routing); err != nil {
+			return nil, fmt.Errorf("routing: %v", err)
+		}
+	}
+	if len(raw.RateLimit) > 0 {
+		data.rateLimit = &apitraffic.RateLimit{}
+		if err := unmarshal(raw.RateLimit, data.rateLimit); err != nil {
+			return nil, fmt.Errorf("rateLimit: %v", err)
+		}
+		fillRateLimit(key, data.rateLimit)
+	}
+	if len(raw.CircuitBreaker) > 0 {
+		data.circuitBreaker = &apifault.CircuitBreaker{}
+		if err := unmarshal(raw.CircuitBreaker, data.circuitBreaker); err != nil {
+			return nil, fmt.Errorf("circuitBreaker: %v", err)
+		}
+	}
+	if len(raw.FaultDetector) > 0 {
+		data.faultDetector = &apifault.FaultDetector{}
+		if err := unmarshal(raw.FaultDetector, data.faultDetector); err != nil {
+			return nil, fmt.Errorf("faultDetector: %v", err)
+		}
+	}
+	for i, value := range raw.NearbyRouteRules {
+		rule := &apitraffic.RouteRule{}
+		if err := unmarshal(value, rule); err != nil {
+			return nil, fmt.Errorf("nearbyRouteRules[%d]: %v", i, err)
+		}
+		data.nearbyRouteRules = append(data.nearbyRouteRules, rule)
+	}
+	for i, value := range raw.Lossless {
+		rule := &apitraffic.LosslessRule{}
+		if err := unmarshal(value, rule); err != nil {
+			return nil, fmt.Errorf("lossless[%d]: %v", i, err)
+		}
+		data.lossless = append(data.lossless, rule)
+	}
+	for i, value := range raw.Lanes {
+		group := &apitraffic.LaneGroup{}
+		if err := unmarshal(value, group); err != nil {
+			return nil, fmt.Errorf("lanes[%d]: %v", i, err)
+		}
+		data.lanes = append(data.lanes, group)
+	}
+	for i, value := range raw.BlockAllowRules {
+		rule := &apisecurity.BlockAllowListRule{}
+		if err := unmarshal(value, rule); err != nil {
+			return nil, fmt.Errorf("blockAllowRules[%d]: %v", i, err)
+		}
+		data.blockAllowRules = append(data.blockAllowRules, rule)
+	}
+	data.fillRevisions()
+	return data, nil
+}
+
+// fillRevisions 根据资源内容计算版本号，并回填到资源自身的版本号字段
+// 本地缓存对路由、限流、熔断和探测规则使用规则自身的版本号，其余资源使用服务的版本号，两者需保持一致
+func (s *serviceData) fillRevisions() {
+	instanceMessages := []proto.Message{&apiservice.Service{Metadata: s.metadata}}
+	for _, instance := range s.instances {
+		instanceMessages = append(instanceMessages, instance)
+	}
+	s.revisions[model.EventInstances] = revisionOf(instanceMessages...)
+	if s.routing != nil {
+		s.routing.Revision = nil
+		revision := revisionOf(s.routing)
+		s.routing.Namespace = wrapperspb.String(s.key.Namespace)
+		s.routing.Service = wrapperspb.String(s.key.Service)
+		s.routing.Revision = wrapperspb.String(revision)
+		s.revisions[model.EventRouting] = revision
+	}
+	if s.rateLimit != nil {
+		s.rateLimit.Revision = nil
+		revision := revisionOf(s.rateLimit)
+		s.rateLimit.Revision = wrapperspb.String(revision)
+		s.revisions[model.EventRateLimiting] = revision
+	}
+	if s.circuitBreaker != nil {
+		s.circuitBreaker.Revision = nil
+		revision := revisionOf(s.circuitBreaker)
+		s.circuitBreaker.Revision = wrapperspb.String(revision)
+		s.revisions[model.EventCircuitBreaker] = revision
+	}
+	if s.faultDetector != nil {
+		s.faultDetector.Revision = ""
+		revision := revisionOf(s.faultDetector)
+		s.faultDetector.Revision = revision
+		s.revisions[model.EventFaultDetect] = revision
+	}
+	var nearbyRules, losslessRules, lanes, blockAllowRules []proto.Message
+	for _, rule := range s.nearbyRouteRules {
+		nearbyRules = append(nearbyRules, rule)
+	}
+	for _, rule := range s.lossless {
+		losslessRules = append(losslessRules, rule)
+	}
+	for _, group := range s.lanes {
+		lanes = append(lanes, group)
+	}
+	for _, rule := range s.blockAllowRules {
+		blockAllowRules = append(blockAllowRules, rule)
+	}
+	s.revisions[model.EventNearbyRouteRule] = revisionOfList(nearbyRules)
+	s.revisions[model.EventLossless] = revisionOfList(losslessRules)
+	s.revisions[model.EventLane] = revisionOfList(lanes)
+	s.revisions[model.EventBlockAllowRule] = revisionOfList(blockAllowRules)
+}
+
+// fillInstance 补齐实例的服务信息及缺省字段，未配置时实例默认健康、不隔离、权重为100
+func fillInstance(key model.ServiceKey, instance *apiservice.Instance) {
+	instance.Namespace = wrapperspb.String(key.Namespace)
+	instance.Service = wrapperspb.String(key.Service)
+	if len(instance.GetId().GetValue()) == 0 {
+		instance.Id = wrapperspb.String(fmt.Sprintf("%s:%d", instance.GetHost().GetValue(),
+			instance.GetPort().GetValue()))
+	}
+	if instance.Healthy == nil {
+		instance.Healthy = wrapperspb.Bool(true)
+	}
+	if instance.Isolate == nil {
+		instance.Isolate = wrapperspb.Bool(false)
+	}
+	if instance.Weight == nil {
+		instance.Weight = wrapperspb.UInt32(defaultInstanceWeight)
+	}
+	instance.Revision = nil
+	instance.Revision = wrapperspb.String(revisionOf(instance))
+}
+
+// fillRateLimit 补齐限流规则的服务信息、ID及版本号，限流窗口依赖规则ID和版本号区分规则
+func fillRateLimit(key model.ServiceKey, rateLimit *apitraffic.RateLimit) {
+	for i, rule := range rateLimit.GetRules() {
+		rule.Namespace = wrapperspb.String(key.Namespace)
+		rule.Service = wrapperspb.String(key.Service)
+		if len(rule.GetId().GetValue()) == 0 {
+			id := rule.GetName().GetValue()
+			if len(id) == 0 {
+				id = fmt.Sprintf("%s/%s/%d", key.Namespace, key.Service, i)
+			}
+			rule.Id = wrapperspb.String(id)
+		}
+		if len(rule.GetRevision().GetValue()) == 0 {
+			rule.Revision = wrapperspb.String(revisionOf(rule))
+		}
+	}
+}
+
+func unmarshal(value json.RawMessage, message proto.Message) error {
+	return jsonpb.Unmarshal(bytes.NewReader(value), message)
+}
+
+// revisionOf 计算资源内容的摘要作为版本号
+func revisionOf(messages ...proto.Message) string {
+	h := sha1.New()
+	opts := protov2.MarshalOptions{Deterministic: true}
+	for _, message := range messages {
+		content, _ := opts.Marshal(proto.MessageV2(message))
+		_, _ = h.Write(content)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// revisionOfList 计算资源列表的版本号，列表为空时版本号为空
+func revisionOfList(messages []proto.Message) string {
+	if len(messages) == 0 {
+		return ""
+	}
+	return revisionOf(messages...)
+}
+
+// yamlToJSON 将YAML内容转换为JSON，以便复用北极星资源的JSON解析逻辑
+func yamlToJSON(content []byte) ([]byte, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(content, &value); err != nil {
+		return nil, err
+	}
+	value, err := convertYAMLValue(value)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		value = map[string]interface{}{}
+	}
+	return json.Marshal(value)
+}
+
+func convertYAMLValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted, err := convertYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			ret[fmt.Sprint(key)] = converted
+		}
+		return ret, nil
+	case []interface{}:
+		ret := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			converted, err := convertYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, converted)
+		}
+		return ret, nil
+	default:
+		return value, nil
+	}
+}
+
+var eventTypeToResponseType = map[model.EventType]apiservice.DiscoverResponse_DiscoverResponseType{
+	model.EventInstances:       apiservice.DiscoverResponse_INSTANCE,
+	model.EventRouting:         apiservice.DiscoverResponse_ROUTING,
+	model.EventRateLimiting:    apiservice.DiscoverResponse_RATE_LIMIT,
+	model.EventServices:        apiservice.DiscoverResponse_SERVICES,
+	model.EventCircuitBreaker:  apiservice.DiscoverResponse_CIRCUIT_BREAKER,
+	model.EventFaultDetect:     apiservice.DiscoverResponse_FAULT_DETECTOR,
+	model.EventNearbyRouteRule: apiservice.DiscoverResponse_NEARBY_ROUTE_RULE,
+	model.EventLossless:        apiservice.DiscoverResponse_LOSSLESS,
+	model.EventBlockAllowRule:  apiservice.DiscoverResponse_BLOCK_ALLOW_RULE,
+	model.EventLane:            apiservice.DiscoverResponse_LANE,
+}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -31,6 +32,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
 	"github.com/polarismesh/polaris-go/pkg/sdk"
+	"github.com/polarismesh/polaris-go/pkg/tracing"
 	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
 )
 
@@ -54,7 +56,11 @@ type Connector struct {
 	// 有没有打印过connManager ready的信息，用于避免重复打印
 	hasPrintedReady uint32
 	token           string
-	logCtx          *log.ContextLogger
+	// 与server通信的传输凭证，开启TLS时支持证书热加载
+	creds  credentials.TransportCredentials
+	logCtx *log.ContextLogger
+	// 链路追踪，同步请求记录为客户端 span
+	tracer *tracing.Tracer
 }
 
 // Type 插件类型
@@ -77,6 +83,12 @@ func (g *Connector) Init(ctx *plugin.InitContext) error {
 		g.cfg = cfgValue.(*networkConfig)
 	}
 	g.token = ctx.Config.GetGlobal().GetServerConnector().GetToken()
+	g.tracer = tracing.NewTracer(ctx.Config.GetGlobal().GetTracing())
+	creds, err := network.NewTransportCredentials(ctx.Config.GetGlobal().GetServerConnector().GetTLS())
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to create server connector tls credentials")
+	}
+	g.creds = creds
 	g.connManager = ctx.ConnManager
 	g.connectionIdleTimeout = ctx.Config.GetGlobal().GetServerConnector().GetConnectionIdleTimeout()
 	g.valueCtx = ctx.ValueCtx
@@ -119,6 +131,7 @@ func (g *Connector) Destroy() error {
 	_ = g.RunContext.Destroy()
 	_ = g.discoverConnector.Destroy()
 	g.connManager.Destroy()
+	network.CloseTransportCredentials(g.creds)
 	return nil
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/golang/protobuf/jsonpb"
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/polarismesh/polaris-go/pkg/clock"
 	"github.com/polarismesh/polaris-go/pkg/config"
@@ -33,11 +34,21 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/model/pb"
 	"github.com/polarismesh/polaris-go/pkg/network"
+	"github.com/polarismesh/polaris-go/pkg/tracing"
 	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
 )
 
 // RegisterInstance 同步注册服务
 func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header map[string]string) (*model.InstanceRegisterResponse, error) {
+	ctx, span := g.tracer.StartRPC(req.GetContext(), "RegisterInstance",
+		tracing.ServiceAttributes(&model.ServiceKey{Namespace: req.Namespace, Service: req.Service})...)
+	resp, err := g.registerInstance(ctx, req, span)
+	tracing.End(span, err)
+	return resp, err
+}
+
+func (g *Connector) registerInstance(parent context.Context, req *model.InstanceRegisterRequest,
+	span trace.Span) (*model.InstanceRegisterResponse, error) {
 	if err := g.waitDiscoverReady(); err != nil {
 		return nil, err
 	}
@@ -53,10 +64,11 @@ func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header
 	}
 	// 释放server连接
 	defer conn.Release(opKey)
+	span.SetAttributes(tracing.AttrNetPeerName.String(conn.ConnID.Address))
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextRegisterInstanceReqID()
-		ctx, cancel  = connector.CreateHeadersContext(*req.Timeout,
+		ctx, cancel  = connector.CreateHeadersContextWithParent(parent, *req.Timeout,
 			connector.AppendAuthHeader(g.token),
 			connector.AppendHeaderWithReqId(reqID))
 	)
@@ -105,6 +117,15 @@ func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header
 
 // DeregisterInstance 同步反注册服务
 func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) error {
+	ctx, span := g.tracer.StartRPC(req.GetContext(), "DeregisterInstance",
+		tracing.ServiceAttributes(&model.ServiceKey{Namespace: req.Namespace, Service: req.Service})...)
+	err := g.deregisterInstance(ctx, req, span)
+	tracing.End(span, err)
+	return err
+}
+
+func (g *Connector) deregisterInstance(parent context.Context, req *model.InstanceDeRegisterRequest,
+	span trace.Span) error {
 	if err := g.waitDiscoverReady(); err != nil {
 		return err
 	}
@@ -119,10 +140,11 @@ func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) err
 	}
 	// 释放server连接
 	defer conn.Release(opKey)
+	span.SetAttributes(tracing.AttrNetPeerName.String(conn.ConnID.Address))
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextDeRegisterInstanceReqID()
-		ctx, cancel  = connector.CreateHeadersContext(*req.Timeout,
+		ctx, cancel  = connector.CreateHeadersContextWithParent(parent, *req.Timeout,
 			connector.AppendAuthHeader(g.token),
 			connector.AppendHeaderWithReqId(reqID))
 	)
@@ -168,6 +190,14 @@ func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) err
 
 // Heartbeat 心跳上报
 func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
+	ctx, span := g.tracer.StartRPC(req.GetContext(), "Heartbeat",
+		tracing.ServiceAttributes(&model.ServiceKey{Namespace: req.Namespace, Service: req.Service})...)
+	err := g.heartbeat(ctx, req, span)
+	tracing.End(span, err)
+	return err
+}
+
+func (g *Connector) heartbeat(parent context.Context, req *model.InstanceHeartbeatRequest, span trace.Span) error {
 	if err := g.waitDiscoverReady(); err != nil {
 		return err
 	}
@@ -182,10 +212,11 @@ func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
 	}
 	// 释放server连接
 	defer conn.Release(opKey)
+	span.SetAttributes(tracing.AttrNetPeerName.String(conn.ConnID.Address))
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextHeartbeatReqID()
-		ctx, cancel  = connector.CreateHeadersContext(*req.Timeout,
+		ctx, cancel  = connector.CreateHeadersContextWithParent(parent, *req.Timeout,
 			connector.AppendAuthHeader(g.token),
 			connector.AppendHeaderWithReqId(reqID))
 	)
@@ -258,6 +289,13 @@ func (g *Connector) waitDiscoverReady() error {
 // 异常场景：当sdk已经退出过程中，则返回error
 // 异常场景：当服务端不可用或者上报失败，则返回error，调用者需进行重试
 func (g *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportClientResponse, error) {
+	_, span := g.tracer.StartRPC(model.GetContextOf(req), "ReportClient")
+	resp, err := g.reportClient(req, span)
+	tracing.End(span, err)
+	return resp, err
+}
+
+func (g *Connector) reportClient(req *model.ReportClientRequest, span trace.Span) (*model.ReportClientResponse, error) {
 	if err := g.waitDiscoverReady(); err != nil {
 		return nil, err
 	}
@@ -272,6 +310,7 @@ func (g *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportC
 	}
 	// 释放server连接
 	defer conn.Release(opKey)
+	span.SetAttributes(tracing.AttrNetPeerName.String(conn.ConnID.Address))
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextReportClientReqID()
@@ -0,0 +1,361 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaristest
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"time"
+
+	apiconfig "github.com/polarismesh/specification/source/go/api/v1/config_manage"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	// 配置文件发布时间的格式，与北极星服务端一致
+	releaseTimeLayout = "2006-01-02 15:04:05"
+)
+
+// configFileKey 配置文件的唯一标识
+type configFileKey struct {
+	namespace string
+	group     string
+	name      string
+}
+
+// configFile 服务端保存的配置文件
+type configFile struct {
+	content     string
+	tags        map[string]string
+	releaseName string
+	version     uint64
+	md5         string
+	releaseTime time.Time
+	// 已删除的配置文件保留版本号，使监听中的客户端能感知删除
+	deleted bool
+}
+
+// PublishConfigFile 发布配置文件，文件不存在时创建，返回发布后的版本号
+func (s *Server) PublishConfigFile(namespace, group, name, content string, tags map[string]string) uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.publishLocked(configFileKey{namespace: namespace, group: group, name: name}, content,
+		copyMetadata(tags), "")
+}
+
+// DeleteConfigFile 删除已发布的配置文件，文件不存在时返回 false
+func (s *Server) DeleteConfigFile(namespace, group, name string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key := configFileKey{namespace: namespace, group: group, name: name}
+	file, ok := s.configFiles[key]
+	if !ok || file.deleted {
+		return false
+	}
+	s.configVersion++
+	s.configFiles[key] = &configFile{version: s.configVersion, releaseTime: time.Now(), deleted: true}
+	delete(s.configDrafts, key)
+	s.notifyConfigLocked()
+	return true
+}
+
+// ConfigFile 获取已发布配置文件的内容及版本号，可用于校验SDK创建、发布配置的结果
+func (s *Server) ConfigFile(namespace, group, name string) (string, uint64, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	file, ok := s.configFiles[configFileKey{namespace: namespace, group: group, name: name}]
+	if !ok || file.deleted {
+		return "", 0, false
+	}
+	return file.content, file.version, true
+}
+
+func (s *Server) publishLocked(key configFileKey, content string, tags map[string]string,
+	releaseName string) uint64 {
+	s.configVersion++
+	sum := md5.Sum([]byte(content))
+	s.configFiles[key] = &configFile{
+		content:     content,
+		tags:        tags,
+		releaseName: releaseName,
+		version:     s.configVersion,
+		md5:         hex.EncodeToString(sum[:]),
+		releaseTime: time.Now(),
+	}
+	s.notifyConfigLocked()
+	return s.configVersion
+}
+
+// notifyConfigLocked 唤醒所有挂起的长轮询
+func (s *Server) notifyConfigLocked() {
+	close(s.configNotify)
+	s.configNotify = make(chan struct{})
+}
+
+// findChangedLocked 查找版本号大于客户端已知版本的配置文件
+func (s *Server) findChangedLocked(watchFiles []*apiconfig.ClientConfigFileInfo) *apiconfig.ClientConfigFileInfo {
+	for _, watchFile := range watchFiles {
+		key := configFileKey{
+			namespace: watchFile.GetNamespace().GetValue(),
+			group:     watchFile.GetGroup().GetValue(),
+			name:      watchFile.GetFileName().GetValue(),
+		}
+		file, ok := s.configFiles[key]
+		if ok && file.version > watchFile.GetVersion().GetValue() {
+			return &apiconfig.ClientConfigFileInfo{
+				Namespace: wrapperspb.String(key.namespace),
+				Group:     wrapperspb.String(key.group),
+				FileName:  wrapperspb.String(key.name),
+				Version:   wrapperspb.UInt64(file.version),
+			}
+		}
+	}
+	return nil
+}
+
+func toClientConfigFileInfo(key configFileKey, file *configFile) *apiconfig.ClientConfigFileInfo {
+	info := &apiconfig.ClientConfigFileInfo{
+		Namespace:   wrapperspb.String(key.namespace),
+		Group:       wrapperspb.String(key.group),
+		FileName:    wrapperspb.String(key.name),
+		Content:     wrapperspb.String(file.content),
+		Version:     wrapperspb.UInt64(file.version),
+		Md5:         wrapperspb.String(file.md5),
+		Name:        wrapperspb.String(file.releaseName),
+		ReleaseTime: wrapperspb.String(file.releaseTime.Format(releaseTimeLayout)),
+	}
+	tagKeys := make([]string, 0, len(file.tags))
+	for k := range file.tags {
+		tagKeys = append(tagKeys, k)
+	}
+	sort.Strings(tagKeys)
+	for _, k := range tagKeys {
+		info.Tags = append(info.Tags, &apiconfig.ConfigFileTag{
+			Key:   wrapperspb.String(k),
+			Value: wrapperspb.String(file.tags[k]),
+		})
+	}
+	return info
+}
+
+func toTags(tags []*apiconfig.ConfigFileTag) map[string]string {
+	values := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		values[tag.GetKey().GetValue()] = tag.GetValue().GetValue()
+	}
+	return values
+}
+
+func newConfigResponse(code apimodel.Code, info string) *apiconfig.ConfigClientResponse {
+	resp := &apiconfig.ConfigClientResponse{Code: wrapperspb.UInt32(uint32(code))}
+	if len(info) > 0 {
+		resp.Info = wrapperspb.String(info)
+	}
+	return resp
+}
+
+// configService 配置中心的 gRPC 接口实现
+type configService struct {
+	apiconfig.UnimplementedPolarisConfigGRPCServer
+	server *Server
+}
+
+// GetConfigFile 拉取配置文件
+func (c *configService) GetConfigFile(ctx context.Context,
+	req *apiconfig.ClientConfigFileInfo) (*apiconfig.ConfigClientResponse, error) {
+	if code, ok := c.server.intercept(ctx, OpGetConfigFile); ok {
+		return newConfigResponse(code, "injected error"), nil
+	}
+	key := configFileKey{
+		namespace: req.GetNamespace().GetValue(),
+		group:     req.GetGroup().GetValue(),
+		name:      req.GetFileName().GetValue(),
+	}
+	c.server.mutex.RLock()
+	defer c.server.mutex.RUnlock()
+	file, ok := c.server.configFiles[key]
+	if !ok || file.deleted {
+		return newConfigResponse(apimodel.Code_NotFoundResource, "config file not found"), nil
+	}
+	resp := newConfigResponse(apimodel.Code_ExecuteSuccess, "")
+	resp.ConfigFile = toClientConfigFileInfo(key, file)
+	return resp, nil
+}
+
+// WatchConfigFiles 长轮询监听配置文件，有文件版本大于客户端版本时立即返回，否则挂起至超时后返回数据未变更
+func (c *configService) WatchConfigFiles(ctx context.Context,
+	req *apiconfig.ClientWatchConfigFileRequest) (*apiconfig.ConfigClientResponse, error) {
+	if code, ok := c.server.intercept(ctx, OpWatchConfigFiles); ok {
+		return newConfigResponse(code, "injected error"), nil
+	}
+	timer := time.NewTimer(c.server.options.watchTimeout)
+	defer timer.Stop()
+	for {
+		c.server.mutex.RLock()
+		changed := c.server.findChangedLocked(req.GetWatchFiles())
+		notify := c.server.configNotify
+		c.server.mutex.RUnlock()
+		if changed != nil {
+			resp := newConfigResponse(apimodel.Code_ExecuteSuccess, "")
+			resp.ConfigFile = changed
+			return resp, nil
+		}
+		select {
+		case <-notify:
+		case <-timer.C:
+			return newConfigResponse(apimodel.Code_DataNoChange, ""), nil
+		case <-ctx.Done():
+			return newConfigResponse(apimodel.Code_DataNoChange, ""), nil
+		case <-c.server.done:
+			return newConfigResponse(apimodel.Code_DataNoChange, ""), nil
+		}
+	}
+}
+
+// CreateConfigFile 创建配置文件，创建后需发布才对客户端可见
+func (c *configService) CreateConfigFile(ctx context.Context,
+	req *apiconfig.ConfigFile) (*apiconfig.ConfigClientResponse, error) {
+	if code, ok := c.server.intercept(ctx, OpCreateConfigFile); ok {
+		return newConfigResponse(code, "injected error"), nil
+	}
+	key := configFileKey{
+		namespace: req.GetNamespace().GetValue(),
+		group:     req.GetGroup().GetValue(),
+		name:      req.GetName().GetValue(),
+	}
+	c.server.mutex.Lock()
+	defer c.server.mutex.Unlock()
+	if _, ok := c.server.configDrafts[key]; ok {
+		return newConfigResponse(apimodel.Code_ExistedResource, "config file existed"), nil
+	}
+	c.server.configDrafts[key] = &configFile{content: req.GetContent().GetValue(), tags: toTags(req.GetTags())}
+	return newConfigResponse(apimodel.Code_ExecuteSuccess, ""), nil
+}
+
+// UpdateConfigFile 更新配置文件，更新后需发布才对客户端可见
+func (c *configService) UpdateConfigFile(ctx context.Context,
+	req *apiconfig.ConfigFile) (*apiconfig.ConfigClientResponse, error) {
+	if code, ok := c.server.intercept(ctx, OpUpdateConfigFile); ok {
+		return newConfigResponse(code, "injected error"), nil
+	}
+	key := configFileKey{
+		namespace: req.GetNamespace().GetValue(),
+		group:     req.GetGroup().GetValue(),
+		name:      req.GetName().GetValue(),
+	}
+	c.server.mutex.Lock()
+	defer c.server.mutex.Unlock()
+	if _, ok := c.server.configDrafts[key]; !ok {
+		return newConfigResponse(apimodel.Code_NotFoundResource, "config file not found"), nil
+	}
+	c.server.configDrafts[key] = &configFile{content: req.GetContent().GetValue(), tags: toTags(req.GetTags())}
+	return newConfigResponse(apimodel.Code_ExecuteSuccess, ""), nil
+}
+
+// PublishConfigFile 发布已创建的配置文件
+func (c *configService) PublishConfigFile(ctx context.Context,
+	req *apiconfig.ConfigFileRelease) (*apiconfig.ConfigClientResponse, error) {
+	if code, ok := c.server.intercept(ctx, OpPublishConfigFile); ok {
+		return newConfigResponse(code, "injected error"), nil
+	}
+	key := configFileKey{
+		namespace: req.GetNamespace().GetValue(),
+		group:     req.GetGroup().GetValue(),
+		name:      req.GetFileName().GetValue(),
+	}
+	c.server.mutex.Lock()
+	defer c.server.mutex.Unlock()
+	draft, ok := c.server.configDrafts[key]
+	if !ok {
+		return newConfigResponse(apimodel.Code_NotFoundResource, "config file not found"), nil
+	}
+	c.server.publishLocked(key, draft.content, draft.tags, req.GetName().GetValue())
+	return newConfigResponse(apimodel.Code_ExecuteSuccess, ""), nil
+}
+
+// UpsertAndPublishConfigFile 创建或更新配置文件并立即发布
+func (c *configService) UpsertAndPublishConfigFile(ctx context.Context,
+	req *apiconfig.ConfigFilePublishInfo) (*apiconfig.ConfigClientResponse, error) {
+	if code, ok := c.server.intercept(ctx, OpPublishConfigFile); ok {
+		return newConfigResponse(code, "injected error"), nil
+	}
+	key := configFileKey{
+		namespace: req.GetNamespace().GetValue(),
+		group:     req.GetGroup().GetValue(),
+		name:      req.GetFileName().GetValue(),
+	}
+	draft := &configFile{content: req.GetContent().GetValue(), tags: toTags(req.GetTags())}
+	c.server.mutex.Lock()
+	defer c.server.mutex.Unlock()
+	c.server.configDrafts[key] = draft
+	c.server.publishLocked(key, draft.content, draft.tags, req.GetReleaseName().GetValue())
+	return newConfigResponse(apimodel.Code_ExecuteSuccess, ""), nil
+}
+
+// GetConfigFileMetadataList 拉取配置分组下已发布的配置文件列表
+func (c *configService) GetConfigFileMetadataList(ctx context.Context,
+	req *apiconfig.ConfigFileGroupRequest) (*apiconfig.ConfigClientListResponse, error) {
+	namespace := req.GetConfigFileGroup().GetNamespace().GetValue()
+	group := req.GetConfigFileGroup().GetName().GetValue()
+	resp := &apiconfig.ConfigClientListResponse{
+		Code:      wrapperspb.UInt32(uint32(apimodel.Code_ExecuteSuccess)),
+		Namespace: namespace,
+		Group:     group,
+	}
+	if code, ok := c.server.intercept(ctx, OpGetConfigGroup); ok {
+		resp.Code = wrapperspb.UInt32(uint32(code))
+		return resp, nil
+	}
+	c.server.mutex.RLock()
+	defer c.server.mutex.RUnlock()
+	keys := make([]configFileKey, 0)
+	var revision uint64
+	for key, file := range c.server.configFiles {
+		if key.namespace != namespace || key.group != group {
+			continue
+		}
+		if file.version > revision {
+			revision = file.version
+		}
+		if !file.deleted {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		resp.Code = wrapperspb.UInt32(uint32(apimodel.Code_NotFoundResource))
+		return resp, nil
+	}
+	resp.Revision = wrapperspb.String(strconv.FormatUint(revision, 10))
+	if resp.GetRevision().GetValue() == req.GetRevision().GetValue() {
+		resp.Code = wrapperspb.UInt32(uint32(apimodel.Code_DataNoChange))
+		return resp, nil
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].name < keys[j].name
+	})
+	for _, key := range keys {
+		info := toClientConfigFileInfo(key, c.server.configFiles[key])
+		info.Content = nil
+		resp.ConfigFileInfos = append(resp.ConfigFileInfos, info)
+	}
+	return resp, nil
+}
@@ -0,0 +1,612 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaristest
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// 实例未指定权重时使用的默认权重
+	defaultInstanceWeight = 100
+)
+
+// service 服务端保存的单个服务数据
+type service struct {
+	metadata         map[string]string
+	instances        []*apiservice.Instance
+	routing          *apitraffic.Routing
+	rateLimit        *apitraffic.RateLimit
+	circuitBreaker   *apifault.CircuitBreaker
+	faultDetector    *apifault.FaultDetector
+	nearbyRouteRules []*apitraffic.RouteRule
+	lossless         []*apitraffic.LosslessRule
+	lanes            []*apitraffic.LaneGroup
+	blockAllowRules  []*apisecurity.BlockAllowListRule
+	// 各类资源的版本号，资源每次变更时递增
+	revisions map[model.EventType]string
+}
+
+// discoverType 发现请求类型对应的应答类型及资源类型
+type discoverType struct {
+	respType  apiservice.DiscoverResponse_DiscoverResponseType
+	eventType model.EventType
+}
+
+var discoverTypes = map[apiservice.DiscoverRequest_DiscoverRequestType]discoverType{
+	apiservice.DiscoverRequest_INSTANCE:          {apiservice.DiscoverResponse_INSTANCE, model.EventInstances},
+	apiservice.DiscoverRequest_ROUTING:           {apiservice.DiscoverResponse_ROUTING, model.EventRouting},
+	apiservice.DiscoverRequest_RATE_LIMIT:        {apiservice.DiscoverResponse_RATE_LIMIT, model.EventRateLimiting},
+	apiservice.DiscoverRequest_CIRCUIT_BREAKER:   {apiservice.DiscoverResponse_CIRCUIT_BREAKER, model.EventCircuitBreaker},
+	apiservice.DiscoverRequest_SERVICES:          {apiservice.DiscoverResponse_SERVICES, model.EventServices},
+	apiservice.DiscoverRequest_FAULT_DETECTOR:    {apiservice.DiscoverResponse_FAULT_DETECTOR, model.EventFaultDetect},
+	apiservice.DiscoverRequest_NEARBY_ROUTE_RULE: {apiservice.DiscoverResponse_NEARBY_ROUTE_RULE, model.EventNearbyRouteRule},
+	apiservice.DiscoverRequest_LOSSLESS:          {apiservice.DiscoverResponse_LOSSLESS, model.EventLossless},
+	apiservice.DiscoverRequest_LANE:              {apiservice.DiscoverResponse_LANE, model.EventLane},
+	apiservice.DiscoverRequest_BLOCK_ALLOW_RULE:  {apiservice.DiscoverResponse_BLOCK_ALLOW_RULE, model.EventBlockAllowRule},
+}
+
+// RegisterService 创建服务并设置服务元数据，服务已存在时只更新元数据
+// 添加实例或设置规则时会自动创建服务，只有需要服务元数据或空服务时才需要显式调用
+func (s *Server) RegisterService(namespace, name string, metadata map[string]string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc := s.serviceLocked(namespace, name)
+	svc.metadata = copyMetadata(metadata)
+	svc.revisions[model.EventInstances] = s.nextRevisionLocked()
+}
+
+// RemoveService 删除服务及其全部实例和规则
+func (s *Server) RemoveService(namespace, name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.services, model.ServiceKey{Namespace: namespace, Service: name})
+}
+
+// AddInstance 向服务添加一个健康、不隔离、权重为100的实例，返回实例ID
+func (s *Server) AddInstance(namespace, name, host string, port uint32, metadata map[string]string) string {
+	return s.AddInstanceWith(namespace, name, &apiservice.Instance{
+		Host:     wrapperspb.String(host),
+		Port:     wrapperspb.UInt32(port),
+		Metadata: copyMetadata(metadata),
+	})
+}
+
+// AddInstanceWith 向服务添加实例，未指定的ID、健康状态、隔离状态及权重会被补齐，返回实例ID
+// 相同地址的实例已存在时会被替换
+func (s *Server) AddInstanceWith(namespace, name string, instance *apiservice.Instance) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.upsertInstanceLocked(namespace, name, proto.Clone(instance).(*apiservice.Instance))
+}
+
+// SetInstances 替换服务的全部实例
+func (s *Server) SetInstances(namespace, name string, instances []*apiservice.Instance) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc := s.serviceLocked(namespace, name)
+	revision := s.nextRevisionLocked()
+	svc.instances = make([]*apiservice.Instance, 0, len(instances))
+	for _, instance := range instances {
+		instance = proto.Clone(instance).(*apiservice.Instance)
+		fillInstance(namespace, name, instance, revision)
+		svc.instances = append(svc.instances, instance)
+	}
+	svc.revisions[model.EventInstances] = revision
+}
+
+// UpdateInstance 修改指定实例，例如调整健康状态、隔离状态或权重，实例不存在时返回 false
+func (s *Server) UpdateInstance(namespace, name, id string, update func(instance *apiservice.Instance)) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc, ok := s.services[model.ServiceKey{Namespace: namespace, Service: name}]
+	if !ok {
+		return false
+	}
+	for _, instance := range svc.instances {
+		if instance.GetId().GetValue() != id {
+			continue
+		}
+		update(instance)
+		revision := s.nextRevisionLocked()
+		instance.Id = wrapperspb.String(id)
+		fillInstance(namespace, name, instance, revision)
+		svc.revisions[model.EventInstances] = revision
+		return true
+	}
+	return false
+}
+
+// RemoveInstance 删除指定实例，实例不存在时返回 false
+func (s *Server) RemoveInstance(namespace, name, id string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc, ok := s.services[model.ServiceKey{Namespace: namespace, Service: name}]
+	if !ok {
+		return false
+	}
+	index := findInstance(svc.instances, id, "", 0)
+	if index < 0 {
+		return false
+	}
+	svc.instances = append(svc.instances[:index], svc.instances[index+1:]...)
+	svc.revisions[model.EventInstances] = s.nextRevisionLocked()
+	return true
+}
+
+// Instances 获取服务当前的实例列表，可用于校验SDK注册、反注册的结果
+func (s *Server) Instances(namespace, name string) []*apiservice.Instance {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	svc, ok := s.services[model.ServiceKey{Namespace: namespace, Service: name}]
+	if !ok {
+		return nil
+	}
+	instances := make([]*apiservice.Instance, 0, len(svc.instances))
+	for _, instance := range svc.instances {
+		instances = append(instances, proto.Clone(instance).(*apiservice.Instance))
+	}
+	return instances
+}
+
+// SetRouting 设置服务的路由规则，传入 nil 时删除
+func (s *Server) SetRouting(namespace, name string, routing *apitraffic.Routing) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc := s.serviceLocked(namespace, name)
+	revision := s.nextRevisionLocked()
+	svc.routing = nil
+	if routing != nil {
+		svc.routing = proto.Clone(routing).(*apitraffic.Routing)
+		svc.routing.Namespace = wrapperspb.String(namespace)
+		svc.routing.Service = wrapperspb.String(name)
+		svc.routing.Revision = wrapperspb.String(revision)
+	}
+	svc.revisions[model.EventRouting] = revisionIf(routing != nil, revision)
+}
+
+// SetRateLimit 设置服务的限流规则，传入 nil 时删除，未指定ID的规则使用规则名或序号作为ID
+func (s *Server) SetRateLimit(namespace, name string, rateLimit *apitraffic.RateLimit) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc := s.serviceLocked(namespace, name)
+	revision := s.nextRevisionLocked()
+	svc.rateLimit = nil
+	if rateLimit != nil {
+		svc.rateLimit = proto.Clone(rateLimit).(*apitraffic.RateLimit)
+		svc.rateLimit.Revision = wrapperspb.String(revision)
+		for i, rule := range svc.rateLimit.GetRules() {
+			rule.Namespace = wrapperspb.String(namespace)
+			rule.Service = wrapperspb.String(name)
+			if len(rule.GetId().GetValue()) == 0 {
+				id := rule.GetName().GetValue()
+				if len(id) == 0 {
+					id = fmt.Sprintf("%s/%s/%d", namespace, name, i)
+				}
+				rule.Id = wrapperspb.String(id)
+			}
+			rule.Revision = wrapperspb.String(revision)
+		}
+	}
+	svc.revisions[model.EventRateLimiting] = revisionIf(rateLimit != nil, revision)
+}
+
+// SetCircuitBreaker 设置服务的熔断规则，传入 nil 时删除
+func (s *Server) SetCircuitBreaker(namespace, name string, circuitBreaker *apifault.CircuitBreaker) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc := s.serviceLocked(namespace, name)
+	revision := s.nextRevisionLocked()
+	svc.circuitBreaker = nil
+	if circuitBreaker != nil {
+		svc.circuitBreaker = proto.Clone(circuitBreaker).(*apifault.CircuitBreaker)
+		svc.circuitBreaker.Revision = wrapperspb.String(revision)
+	}
+	svc.revisions[model.EventCircuitBreaker] = revisionIf(circuitBreaker != nil, revision)
+}
+
+// SetFaultDetector 设置服务的主动探测规则，传入 nil 时删除
+func (s *Server) SetFaultDetector(namespace, name string, faultDetector *apifault.FaultDetector) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc := s.serviceLocked(namespace, name)
+	revision := s.nextRevisionLocked()
+	svc.faultDetector = nil
+	if faultDetector != nil {
+		svc.faultDetector = proto.Clone(faultDetector).(*apifault.FaultDetector)
+		svc.faultDetector.Revision = revision
+	}
+	svc.revisions[model.EventFaultDetect] = revisionIf(faultDetector != nil, revision)
+}
+
+// SetNearbyRouteRules 设置服务的就近路由规则，传入空列表时删除
+func (s *Server) SetNearbyRouteRules(namespace, name string, rules []*apitraffic.RouteRule) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc := s.serviceLocked(namespace, name)
+	svc.nearbyRouteRules = nil
+	for _, rule := range rules {
+		svc.nearbyRouteRules = append(svc.nearbyRouteRules, proto.Clone(rule).(*apitraffic.RouteRule))
+	}
+	svc.revisions[model.EventNearbyRouteRule] = revisionIf(len(rules) > 0, s.nextRevisionLocked())
+}
+
+// SetLossless 设置服务的无损上下线规则，传入空列表时删除
+func (s *Server) SetLossless(namespace, name string, rules []*apitraffic.LosslessRule) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc := s.serviceLocked(namespace, name)
+	svc.lossless = nil
+	for _, rule := range rules {
+		svc.lossless = append(svc.lossless, proto.Clone(rule).(*apitraffic.LosslessRule))
+	}
+	svc.revisions[model.EventLossless] = revisionIf(len(rules) > 0, s.nextRevisionLocked())
+}
+
+// SetLanes 设置服务的泳道，传入空列表时删除
+func (s *Server) SetLanes(namespace, name string, groups []*apitraffic.LaneGroup) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc := s.serviceLocked(namespace, name)
+	svc.lanes = nil
+	for _, group := range groups {
+		svc.lanes = append(svc.lanes, proto.Clone(group).(*apitraffic.LaneGroup))
+	}
+	svc.revisions[model.EventLane] = revisionIf(len(groups) > 0, s.nextRevisionLocked())
+}
+
+// SetBlockAllowRules 设置服务的鉴权规则，传入空列表时删除
+func (s *Server) SetBlockAllowRules(namespace, name string, rules []*apisecurity.BlockAllowListRule) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	svc := s.serviceLocked(namespace, name)
+	svc.blockAllowRules = nil
+	for _, rule := range rules {
+		svc.blockAllowRules = append(svc.blockAllowRules, proto.Clone(rule).(*apisecurity.BlockAllowListRule))
+	}
+	svc.revisions[model.EventBlockAllowRule] = revisionIf(len(rules) > 0, s.nextRevisionLocked())
+}
+
+// serviceLocked 获取服务，不存在时创建
+func (s *Server) serviceLocked(namespace, name string) *service {
+	key := model.ServiceKey{Namespace: namespace, Service: name}
+	svc, ok := s.services[key]
+	if !ok {
+		svc = &service{revisions: map[model.EventType]string{model.EventInstances: s.nextRevisionLocked()}}
+		s.services[key] = svc
+	}
+	return svc
+}
+
+// upsertInstanceLocked 添加实例，ID或地址相同的实例已存在时替换
+func (s *Server) upsertInstanceLocked(namespace, name string, instance *apiservice.Instance) string {
+	svc := s.serviceLocked(namespace, name)
+	revision := s.nextRevisionLocked()
+	fillInstance(namespace, name, instance, revision)
+	index := findInstance(svc.instances, instance.GetId().GetValue(), instance.GetHost().GetValue(),
+		instance.GetPort().GetValue())
+	if index >= 0 {
+		svc.instances[index] = instance
+	} else {
+		svc.instances = append(svc.instances, instance)
+	}
+	svc.revisions[model.EventInstances] = revision
+	return instance.GetId().GetValue()
+}
+
+// removeInstanceLocked 按ID或地址删除实例，返回是否删除
+func (s *Server) removeInstanceLocked(namespace, name string, instance *apiservice.Instance) bool {
+	svc, ok := s.services[model.ServiceKey{Namespace: namespace, Service: name}]
+	if !ok {
+		return false
+	}
+	index := findInstance(svc.instances, instance.GetId().GetValue(), instance.GetHost().GetValue(),
+		instance.GetPort().GetValue())
+	if index < 0 {
+		return false
+	}
+	svc.instances = append(svc.instances[:index], svc.instances[index+1:]...)
+	svc.revisions[model.EventInstances] = s.nextRevisionLocked()
+	return true
+}
+
+// existsInstanceLocked 按ID或地址查找实例是否存在
+func (s *Server) existsInstanceLocked(namespace, name string, instance *apiservice.Instance) bool {
+	svc, ok := s.services[model.ServiceKey{Namespace: namespace, Service: name}]
+	if !ok {
+		return false
+	}
+	return findInstance(svc.instances, instance.GetId().GetValue(), instance.GetHost().GetValue(),
+		instance.GetPort().GetValue()) >= 0
+}
+
+// buildDiscoverResponse 根据当前数据生成发现应答，请求的版本号与当前一致时返回数据未变更
+func (s *Server) buildDiscoverResponse(req *apiservice.DiscoverRequest) *apiservice.DiscoverResponse {
+	namespace := req.GetService().GetNamespace().GetValue()
+	name := req.GetService().GetName().GetValue()
+	dType, ok := discoverTypes[req.GetType()]
+	resp := &apiservice.DiscoverResponse{
+		Code: wrapperspb.UInt32(uint32(apimodel.Code_ExecuteSuccess)),
+		Type: dType.respType,
+		Service: &apiservice.Service{
+			Namespace: wrapperspb.String(namespace),
+			Name:      wrapperspb.String(name),
+		},
+	}
+	if !ok {
+		resp.Code = wrapperspb.UInt32(uint32(apimodel.Code_InvalidParameter))
+		resp.Info = wrapperspb.String(fmt.Sprintf("unsupported discover type %v", req.GetType()))
+		return resp
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if dType.eventType == model.EventServices {
+		s.fillServicesLocked(resp, namespace)
+		return resp
+	}
+	svc, ok := s.services[model.ServiceKey{Namespace: namespace, Service: name}]
+	if !ok {
+		resp.Code = wrapperspb.UInt32(uint32(apimodel.Code_NotFoundResource))
+		resp.Info = wrapperspb.String(fmt.Sprintf("service %s/%s not found", namespace, name))
+		return resp
+	}
+	revision := svc.revisions[dType.eventType]
+	resp.Service.Metadata = copyMetadata(svc.metadata)
+	resp.Service.Revision = wrapperspb.String(revision)
+	if len(revision) > 0 && revision == req.GetService().GetRevision().GetValue() {
+		resp.Code = wrapperspb.UInt32(uint32(apimodel.Code_DataNoChange))
+		return resp
+	}
+	switch dType.eventType {
+	case model.EventInstances:
+		for _, instance := range svc.instances {
+			resp.Instances = append(resp.Instances, proto.Clone(instance).(*apiservice.Instance))
+		}
+	case model.EventRouting:
+		if svc.routing != nil {
+			resp.Routing = proto.Clone(svc.routing).(*apitraffic.Routing)
+		}
+	case model.EventRateLimiting:
+		if svc.rateLimit != nil {
+			resp.RateLimit = proto.Clone(svc.rateLimit).(*apitraffic.RateLimit)
+		}
+	case model.EventCircuitBreaker:
+		if svc.circuitBreaker != nil {
+			resp.CircuitBreaker = proto.Clone(svc.circuitBreaker).(*apifault.CircuitBreaker)
+		}
+	case model.EventFaultDetect:
+		if svc.faultDetector != nil {
+			resp.FaultDetector = proto.Clone(svc.faultDetector).(*apifault.FaultDetector)
+		}
+	case model.EventNearbyRouteRule:
+		for _, rule := range svc.nearbyRouteRules {
+			resp.NearbyRouteRules = append(resp.NearbyRouteRules, proto.Clone(rule).(*apitraffic.RouteRule))
+		}
+	case model.EventLossless:
+		for _, rule := range svc.lossless {
+			resp.LosslessRuleList = append(resp.LosslessRuleList, proto.Clone(rule).(*apitraffic.LosslessRule))
+		}
+	case model.EventLane:
+		for _, group := range svc.lanes {
+			resp.Lanes = append(resp.Lanes, proto.Clone(group).(*apitraffic.LaneGroup))
+		}
+	case model.EventBlockAllowRule:
+		for _, rule := range svc.blockAllowRules {
+			resp.BlockAllowListRule = append(resp.BlockAllowListRule,
+				proto.Clone(rule).(*apisecurity.BlockAllowListRule))
+		}
+	}
+	return resp
+}
+
+// fillServicesLocked 填充命名空间下的服务列表，命名空间为空时返回全部服务
+func (s *Server) fillServicesLocked(resp *apiservice.DiscoverResponse, namespace string) {
+	keys := make([]model.ServiceKey, 0, len(s.services))
+	for key := range s.services {
+		if len(namespace) == 0 || key.Namespace == namespace {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Service < keys[j].Service
+	})
+	hash := sha1.New()
+	for _, key := range keys {
+		revision := s.services[key].revisions[model.EventInstances]
+		_, _ = io.WriteString(hash, key.Namespace+"/"+key.Service+"/"+revision+";")
+		resp.Services = append(resp.Services, &apiservice.Service{
+			Namespace: wrapperspb.String(key.Namespace),
+			Name:      wrapperspb.String(key.Service),
+			Metadata:  copyMetadata(s.services[key].metadata),
+			Revision:  wrapperspb.String(revision),
+		})
+	}
+	resp.Service.Revision = wrapperspb.String(hex.EncodeToString(hash.Sum(nil)))
+}
+
+// fillInstance 补齐实例的服务信息及缺省字段
+func fillInstance(namespace, name string, instance *apiservice.Instance, revision string) {
+	instance.Namespace = wrapperspb.String(namespace)
+	instance.Service = wrapperspb.String(name)
+	if len(instance.GetId().GetValue()) == 0 {
+		instance.Id = wrapperspb.String(instanceID(namespace, name, instance.GetHost().GetValue(),
+			instance.GetPort().GetValue()))
+	}
+	if instance.Healthy == nil {
+		instance.Healthy = wrapperspb.Bool(true)
+	}
+	if instance.Isolate == nil {
+		instance.Isolate = wrapperspb.Bool(false)
+	}
+	if instance.Weight == nil {
+		instance.Weight = wrapperspb.UInt32(defaultInstanceWeight)
+	}
+	instance.Revision = wrapperspb.String(revision)
+}
+
+// instanceID 与北极星服务端一致，由服务及地址计算实例ID
+func instanceID(namespace, name, host string, port uint32) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s##%s##%s##%d", namespace, name, host, port)))
+	return hex.EncodeToString(sum[:])
+}
+
+// findInstance 按ID或地址查找实例下标，不存在时返回 -1
+func findInstance(instances []*apiservice.Instance, id string, host string, port uint32) int {
+	for i, instance := range instances {
+		if len(id) > 0 && instance.GetId().GetValue() == id {
+			return i
+		}
+		if len(host) > 0 && instance.GetHost().GetValue() == host && instance.GetPort().GetValue() == port {
+			return i
+		}
+	}
+	return -1
+}
+
+func revisionIf(exists bool, revision string) string {
+	if !exists {
+		return ""
+	}
+	return revision
+}
+
+func copyMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		values[k] = v
+	}
+	return values
+}
+
+func newResponse(code apimodel.Code, info string) *apiservice.Response {
+	resp := &apiservice.Response{Code: wrapperspb.UInt32(uint32(code))}
+	if len(info) > 0 {
+		resp.Info = wrapperspb.String(info)
+	}
+	return resp
+}
+
+// namingService 服务发现的 gRPC 接口实现
+type namingService struct {
+	apiservice.UnimplementedPolarisGRPCServer
+	server *Server
+}
+
+// ReportClient 客户端上报
+func (n *namingService) ReportClient(ctx context.Context, req *apiservice.Client) (*apiservice.Response, error) {
+	if code, ok := n.server.intercept(ctx, OpReportClient); ok {
+		return newResponse(code, "injected error"), nil
+	}
+	resp := newResponse(apimodel.Code_ExecuteSuccess, "")
+	resp.Client = req
+	return resp, nil
+}
+
+// RegisterInstance 注册实例，服务不存在时自动创建
+func (n *namingService) RegisterInstance(ctx context.Context,
+	req *apiservice.Instance) (*apiservice.Response, error) {
+	if code, ok := n.server.intercept(ctx, OpRegister); ok {
+		return newResponse(code, "injected error"), nil
+	}
+	if len(req.GetHost().GetValue()) == 0 || req.GetPort().GetValue() == 0 {
+		return newResponse(apimodel.Code_InvalidParameter, "host or port is empty"), nil
+	}
+	instance := proto.Clone(req).(*apiservice.Instance)
+	instance.ServiceToken = nil
+	instance.Healthy = wrapperspb.Bool(true)
+	n.server.mutex.Lock()
+	id := n.server.upsertInstanceLocked(req.GetNamespace().GetValue(), req.GetService().GetValue(), instance)
+	n.server.mutex.Unlock()
+	resp := newResponse(apimodel.Code_ExecuteSuccess, "")
+	resp.Instance = &apiservice.Instance{Id: wrapperspb.String(id)}
+	return resp, nil
+}
+
+// DeregisterInstance 反注册实例，实例不存在时同样返回成功
+func (n *namingService) DeregisterInstance(ctx context.Context,
+	req *apiservice.Instance) (*apiservice.Response, error) {
+	if code, ok := n.server.intercept(ctx, OpDeregister); ok {
+		return newResponse(code, "injected error"), nil
+	}
+	n.server.mutex.Lock()
+	n.server.removeInstanceLocked(req.GetNamespace().GetValue(), req.GetService().GetValue(), req)
+	n.server.mutex.Unlock()
+	return newResponse(apimodel.Code_ExecuteSuccess, ""), nil
+}
+
+// Heartbeat 实例心跳，实例不存在时返回资源不存在
+func (n *namingService) Heartbeat(ctx context.Context, req *apiservice.Instance) (*apiservice.Response, error) {
+	if code, ok := n.server.intercept(ctx, OpHeartbeat); ok {
+		return newResponse(code, "injected error"), nil
+	}
+	n.server.mutex.RLock()
+	exists := n.server.existsInstanceLocked(req.GetNamespace().GetValue(), req.GetService().GetValue(), req)
+	n.server.mutex.RUnlock()
+	if !exists {
+		return newResponse(apimodel.Code_NotFoundResource, "instance not found"), nil
+	}
+	return newResponse(apimodel.Code_ExecuteSuccess, ""), nil
+}
+
+// Discover 统一发现接口
+func (n *namingService) Discover(stream apiservice.PolarisGRPC_DiscoverServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		var resp *apiservice.DiscoverResponse
+		if code, ok := n.server.intercept(stream.Context(), OpDiscover); ok {
+			resp = &apiservice.DiscoverResponse{
+				Code:    wrapperspb.UInt32(uint32(code)),
+				Info:    wrapperspb.String("injected error"),
+				Type:    discoverTypes[req.GetType()].respType,
+				Service: req.GetService(),
+			}
+		} else {
+			resp = n.server.buildDiscoverResponse(req)
+		}
+		if err = stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
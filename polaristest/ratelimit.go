@@ -0,0 +1,213 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaristest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/polarismesh/specification/source/go/api/v1/traffic_manage/ratelimiter"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// 限流服务的成功返回码
+	rateLimitCodeSuccess uint32 = 200000
+)
+
+// quotaCounter 单个限流目标在单个统计周期上的计数器
+type quotaCounter struct {
+	key       uint32
+	maxAmount uint32
+	// 统计周期，单位毫秒
+	durationMilli int64
+	// 当前窗口的起始时间，单位毫秒
+	windowStart int64
+	used        int64
+	clients     map[string]struct{}
+}
+
+// roll 进入新的统计窗口时清零计数
+func (c *quotaCounter) roll(nowMilli int64) {
+	windowStart := nowMilli - nowMilli%c.durationMilli
+	if windowStart != c.windowStart {
+		c.windowStart = windowStart
+		c.used = 0
+	}
+}
+
+func (c *quotaCounter) left() int64 {
+	return int64(c.maxAmount) - c.used
+}
+
+// rateLimiter 按固定窗口统计全局配额的限流服务
+type rateLimiter struct {
+	mutex      sync.Mutex
+	clientKeys map[string]uint32
+	counters   map[string]*quotaCounter
+	byKey      map[uint32]*quotaCounter
+	lastKey    uint32
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		clientKeys: make(map[string]uint32),
+		counters:   make(map[string]*quotaCounter),
+		byKey:      make(map[uint32]*quotaCounter),
+	}
+}
+
+// initialize 处理初始化请求，为每个统计周期分配计数器
+func (r *rateLimiter) initialize(req *ratelimiter.RateLimitInitRequest) *ratelimiter.RateLimitInitResponse {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	clientKey, ok := r.clientKeys[req.GetClientId()]
+	if !ok {
+		r.lastKey++
+		clientKey = r.lastKey
+		r.clientKeys[req.GetClientId()] = clientKey
+	}
+	nowMilli := model.CurrentMillisecond()
+	resp := &ratelimiter.RateLimitInitResponse{
+		Code:      rateLimitCodeSuccess,
+		Target:    req.GetTarget(),
+		ClientKey: clientKey,
+		Timestamp: nowMilli,
+	}
+	target := req.GetTarget()
+	for _, total := range req.GetTotals() {
+		if total.GetDuration() == 0 {
+			continue
+		}
+		name := fmt.Sprintf("%s|%s|%s|%d", target.GetNamespace(), target.GetService(), target.GetLabels(),
+			total.GetDuration())
+		counter, ok := r.counters[name]
+		if !ok {
+			r.lastKey++
+			counter = &quotaCounter{
+				key:           r.lastKey,
+				durationMilli: int64(total.GetDuration()) * 1000,
+				clients:       make(map[string]struct{}),
+			}
+			r.counters[name] = counter
+			r.byKey[counter.key] = counter
+		}
+		counter.maxAmount = total.GetMaxAmount()
+		counter.clients[req.GetClientId()] = struct{}{}
+		counter.roll(nowMilli)
+		resp.Counters = append(resp.Counters, &ratelimiter.QuotaCounter{
+			Duration:    total.GetDuration(),
+			CounterKey:  counter.key,
+			Left:        counter.left(),
+			Mode:        ratelimiter.Mode_BATCH_OCCUPY,
+			ClientCount: uint32(len(counter.clients)),
+		})
+	}
+	return resp
+}
+
+// acquire 处理配额上报请求，累加已使用配额并返回剩余配额
+func (r *rateLimiter) acquire(req *ratelimiter.RateLimitReportRequest) *ratelimiter.RateLimitReportResponse {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	nowMilli := model.CurrentMillisecond()
+	resp := &ratelimiter.RateLimitReportResponse{
+		Code:      rateLimitCodeSuccess,
+		Timestamp: nowMilli,
+	}
+	for _, use := range req.GetQuotaUses() {
+		counter, ok := r.byKey[use.GetCounterKey()]
+		if !ok {
+			continue
+		}
+		counter.roll(nowMilli)
+		counter.used += int64(use.GetUsed())
+		resp.QuotaLefts = append(resp.QuotaLefts, &ratelimiter.QuotaLeft{
+			CounterKey:  counter.key,
+			Left:        counter.left(),
+			Mode:        ratelimiter.Mode_BATCH_OCCUPY,
+			ClientCount: uint32(len(counter.clients)),
+		})
+	}
+	return resp
+}
+
+// rateLimitService 分布式限流的 gRPC 接口实现
+type rateLimitService struct {
+	server *Server
+}
+
+// Service 限流消息处理接口
+func (r *rateLimitService) Service(stream ratelimiter.RateLimitGRPCV2_ServiceServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		var resp *ratelimiter.RateLimitResponse
+		switch req.GetCmd() {
+		case ratelimiter.RateLimitCmd_INIT:
+			initReq := req.GetRateLimitInitRequest()
+			var initResp *ratelimiter.RateLimitInitResponse
+			if code, ok := r.server.intercept(stream.Context(), OpRateLimitInit); ok {
+				initResp = &ratelimiter.RateLimitInitResponse{
+					Code:      uint32(code),
+					Target:    initReq.GetTarget(),
+					Timestamp: model.CurrentMillisecond(),
+				}
+			} else {
+				initResp = r.server.limiter.initialize(initReq)
+			}
+			resp = &ratelimiter.RateLimitResponse{
+				Cmd:                   ratelimiter.RateLimitCmd_INIT,
+				RateLimitInitResponse: initResp,
+			}
+		case ratelimiter.RateLimitCmd_ACQUIRE:
+			var reportResp *ratelimiter.RateLimitReportResponse
+			if code, ok := r.server.intercept(stream.Context(), OpRateLimitAcquire); ok {
+				reportResp = &ratelimiter.RateLimitReportResponse{
+					Code:      uint32(code),
+					Timestamp: model.CurrentMillisecond(),
+				}
+			} else {
+				reportResp = r.server.limiter.acquire(req.GetRateLimitReportRequest())
+			}
+			resp = &ratelimiter.RateLimitResponse{
+				Cmd:                     ratelimiter.RateLimitCmd_ACQUIRE,
+				RateLimitReportResponse: reportResp,
+			}
+		default:
+			continue
+		}
+		if err = stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// TimeAdjust 时间对齐接口
+func (r *rateLimitService) TimeAdjust(ctx context.Context,
+	req *ratelimiter.TimeAdjustRequest) (*ratelimiter.TimeAdjustResponse, error) {
+	return &ratelimiter.TimeAdjustResponse{ServerTimestamp: model.CurrentMillisecond()}, nil
+}
@@ -0,0 +1,339 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package polaristest 提供进程内的北极星服务端模拟实现，用于在单元测试中替代真实的北极星集群。
+//
+// Server 在随机端口上同时提供服务发现、配置中心及分布式限流的 gRPC 接口，测试代码可以通过 Go API
+// 预置及修改服务、实例、规则和配置文件，并可按接口注入错误码与时延，例如：
+//
+//	srv := polaristest.Start(t)
+//	srv.AddInstance("default", "echo", "127.0.0.1", 8080, nil)
+//	consumer := polaris.NewConsumerAPIByContext(srv.SDKContext(t))
+package polaristest
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	apiconfig "github.com/polarismesh/specification/source/go/api/v1/config_manage"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/polarismesh/specification/source/go/api/v1/traffic_manage/ratelimiter"
+	"google.golang.org/grpc"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Operation 服务端接口类型，用于错误注入及请求计数
+type Operation string
+
+const (
+	// OpDiscover 服务发现，包括实例、服务列表及各类规则的拉取
+	OpDiscover Operation = "discover"
+	// OpRegister 注册实例
+	OpRegister Operation = "register"
+	// OpDeregister 反注册实例
+	OpDeregister Operation = "deregister"
+	// OpHeartbeat 实例心跳
+	OpHeartbeat Operation = "heartbeat"
+	// OpReportClient 客户端上报
+	OpReportClient Operation = "reportClient"
+	// OpGetConfigFile 拉取配置文件
+	OpGetConfigFile Operation = "getConfigFile"
+	// OpWatchConfigFiles 监听配置文件变更
+	OpWatchConfigFiles Operation = "watchConfigFiles"
+	// OpGetConfigGroup 拉取配置分组下的文件列表
+	OpGetConfigGroup Operation = "getConfigGroup"
+	// OpCreateConfigFile 创建配置文件
+	OpCreateConfigFile Operation = "createConfigFile"
+	// OpUpdateConfigFile 更新配置文件
+	OpUpdateConfigFile Operation = "updateConfigFile"
+	// OpPublishConfigFile 发布配置文件，包括创建并发布
+	OpPublishConfigFile Operation = "publishConfigFile"
+	// OpRateLimitInit 分布式限流初始化
+	OpRateLimitInit Operation = "rateLimitInit"
+	// OpRateLimitAcquire 分布式限流配额上报
+	OpRateLimitAcquire Operation = "rateLimitAcquire"
+)
+
+const (
+	// DefaultWatchTimeout 配置长轮询在没有变更时的默认挂起时长
+	DefaultWatchTimeout = 10 * time.Second
+	// DefaultRefreshInterval Config 生成的SDK配置中服务的默认刷新间隔，缩短以便测试中尽快感知数据变更
+	DefaultRefreshInterval = 200 * time.Millisecond
+)
+
+type options struct {
+	listenAddress   string
+	watchTimeout    time.Duration
+	refreshInterval time.Duration
+}
+
+// Option 服务端选项
+type Option func(*options)
+
+// WithListenAddress 指定监听地址，默认为 127.0.0.1:0，即随机端口
+func WithListenAddress(address string) Option {
+	return func(o *options) {
+		o.listenAddress = address
+	}
+}
+
+// WithWatchTimeout 指定配置长轮询在没有变更时的挂起时长
+func WithWatchTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.watchTimeout = timeout
+	}
+}
+
+// WithRefreshInterval 指定 Config 生成的SDK配置中服务的刷新间隔
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.refreshInterval = interval
+	}
+}
+
+// fault 单个接口注入的故障
+type fault struct {
+	code    apimodel.Code
+	latency time.Duration
+}
+
+// Server 进程内的北极星服务端
+type Server struct {
+	options    options
+	listener   net.Listener
+	grpcServer *grpc.Server
+	host       string
+	port       int
+	// SDK 本地缓存等持久化文件所在的临时目录，Close 时删除
+	dir string
+
+	mutex    sync.RWMutex
+	revision uint64
+	services map[model.ServiceKey]*service
+
+	configVersion uint64
+	configFiles   map[configFileKey]*configFile
+	configDrafts  map[configFileKey]*configFile
+	// 配置发生变更时关闭并重建，用于唤醒挂起的长轮询
+	configNotify chan struct{}
+
+	faultMutex sync.RWMutex
+	faults     map[Operation]*fault
+	counts     map[Operation]int
+
+	limiter *rateLimiter
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewServer 创建并启动服务端，服务端会自动注册分布式限流服务 Polaris/polaris.limiter 并指向自身
+func NewServer(opts ...Option) (*Server, error) {
+	s := &Server{
+		options: options{
+			listenAddress:   "127.0.0.1:0",
+			watchTimeout:    DefaultWatchTimeout,
+			refreshInterval: DefaultRefreshInterval,
+		},
+		services:     make(map[model.ServiceKey]*service),
+		configFiles:  make(map[configFileKey]*configFile),
+		configDrafts: make(map[configFileKey]*configFile),
+		configNotify: make(chan struct{}),
+		faults:       make(map[Operation]*fault),
+		counts:       make(map[Operation]int),
+		limiter:      newRateLimiter(),
+		done:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&s.options)
+	}
+	dir, err := ioutil.TempDir("", "polaristest-")
+	if err != nil {
+		return nil, fmt.Errorf("polaristest: fail to create temp dir: %v", err)
+	}
+	s.dir = dir
+	listener, err := net.Listen("tcp", s.options.listenAddress)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("polaristest: fail to listen %s: %v", s.options.listenAddress, err)
+	}
+	addr := listener.Addr().(*net.TCPAddr)
+	s.listener = listener
+	s.host = addr.IP.String()
+	s.port = addr.Port
+
+	// 缩短握手超时，避免SDK销毁时遗留的半开连接阻塞 Close
+	s.grpcServer = grpc.NewServer(grpc.ConnectionTimeout(time.Second))
+	apiservice.RegisterPolarisGRPCServer(s.grpcServer, &namingService{server: s})
+	apiconfig.RegisterPolarisConfigGRPCServer(s.grpcServer, &configService{server: s})
+	ratelimiter.RegisterRateLimitGRPCV2Server(s.grpcServer, &rateLimitService{server: s})
+	go func() {
+		_ = s.grpcServer.Serve(listener)
+	}()
+
+	// 限流服务通过实例元数据中的协议筛选可用节点
+	s.AddInstance(config.DefaultLimiterNamespace, config.DefaultLimiterService, s.host, uint32(s.port),
+		map[string]string{"protocol": "grpc"})
+	return s, nil
+}
+
+// Start 创建并启动服务端，失败时终止测试，测试结束时自动关闭
+func Start(tb testing.TB, opts ...Option) *Server {
+	tb.Helper()
+	s, err := NewServer(opts...)
+	if err != nil {
+		tb.Fatalf("%v", err)
+	}
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// Addr 服务端地址，格式为 <host>:<port>
+func (s *Server) Addr() string {
+	return net.JoinHostPort(s.host, strconv.Itoa(s.port))
+}
+
+// Close 关闭服务端，并清理SDK持久化文件所在的临时目录
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.grpcServer.Stop()
+		_ = os.RemoveAll(s.dir)
+	})
+}
+
+// Config 生成指向当前服务端的SDK配置
+// 服务发现与配置中心均连接当前服务端，本地缓存写入独立的临时目录且启动时不加载，并关闭监控上报
+func (s *Server) Config() config.Configuration {
+	cfg := config.NewDefaultConfiguration([]string{s.Addr()})
+	cfg.GetConfigFile().GetConfigConnectorConfig().SetAddresses([]string{s.Addr()})
+	dir, err := ioutil.TempDir(s.dir, "sdk-")
+	if err != nil {
+		dir = s.dir
+	}
+	localCache := cfg.GetConsumer().GetLocalCache()
+	localCache.SetPersistDir(filepath.Join(dir, "backup"))
+	localCache.SetStartUseFileCache(false)
+	localCache.SetServiceRefreshInterval(s.options.refreshInterval)
+	cfg.GetConfigFile().GetLocalCache().SetPersistDir(filepath.Join(dir, "config"))
+	cfg.GetConfigFile().GetLocalCache().SetFallbackToLocalCache(false)
+	cfg.GetGlobal().GetStatReporter().SetEnable(false)
+	return cfg
+}
+
+// NewSDKContext 使用 Config 生成的配置创建SDK上下文，使用完毕后需调用 Destroy 释放
+func (s *Server) NewSDKContext() (api.SDKContext, error) {
+	return api.InitContextByConfig(s.Config())
+}
+
+// SDKContext 创建指向当前服务端的SDK上下文，失败时终止测试，测试结束时自动销毁
+func (s *Server) SDKContext(tb testing.TB) api.SDKContext {
+	tb.Helper()
+	sdkCtx, err := s.NewSDKContext()
+	if err != nil {
+		tb.Fatalf("polaristest: fail to create sdk context: %v", err)
+	}
+	tb.Cleanup(sdkCtx.Destroy)
+	return sdkCtx
+}
+
+// InjectError 使指定接口固定返回错误码，直到调用 ClearFaults
+func (s *Server) InjectError(op Operation, code apimodel.Code) {
+	s.faultMutex.Lock()
+	defer s.faultMutex.Unlock()
+	s.faultLocked(op).code = code
+}
+
+// InjectLatency 使指定接口在应答前等待一段时间，直到调用 ClearFaults
+func (s *Server) InjectLatency(op Operation, latency time.Duration) {
+	s.faultMutex.Lock()
+	defer s.faultMutex.Unlock()
+	s.faultLocked(op).latency = latency
+}
+
+// ClearFaults 清除所有注入的错误码与时延
+func (s *Server) ClearFaults() {
+	s.faultMutex.Lock()
+	defer s.faultMutex.Unlock()
+	s.faults = make(map[Operation]*fault)
+}
+
+// RequestCount 获取指定接口收到的请求数，流式接口按消息计数
+func (s *Server) RequestCount(op Operation) int {
+	s.faultMutex.RLock()
+	defer s.faultMutex.RUnlock()
+	return s.counts[op]
+}
+
+// ResetRequestCounts 清零所有接口的请求计数
+func (s *Server) ResetRequestCounts() {
+	s.faultMutex.Lock()
+	defer s.faultMutex.Unlock()
+	s.counts = make(map[Operation]int)
+}
+
+func (s *Server) faultLocked(op Operation) *fault {
+	f, ok := s.faults[op]
+	if !ok {
+		f = &fault{}
+		s.faults[op] = f
+	}
+	return f
+}
+
+// intercept 对请求计数并执行注入的故障，返回注入的错误码，未注入时返回 false
+func (s *Server) intercept(ctx context.Context, op Operation) (apimodel.Code, bool) {
+	s.faultMutex.Lock()
+	s.counts[op]++
+	var f fault
+	if value, ok := s.faults[op]; ok {
+		f = *value
+	}
+	s.faultMutex.Unlock()
+	if f.latency > 0 {
+		timer := time.NewTimer(f.latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		case <-s.done:
+			timer.Stop()
+		}
+	}
+	if f.code != 0 {
+		return f.code, true
+	}
+	return 0, false
+}
+
+// nextRevisionLocked 生成新的资源版本号
+func (s *Server) nextRevisionLocked() string {
+	s.revision++
+	return strconv.FormatUint(s.revision, 10)
+}
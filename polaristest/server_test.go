@@ -0,0 +1,317 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaristest
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/polarismesh/specification/source/go/api/v1/traffic_manage/ratelimiter"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestMain(m *testing.M) {
+	logDir, err := ioutil.TempDir("", "polaristest-log-")
+	if err != nil {
+		panic(err)
+	}
+	if err = api.ConfigLoggers(logDir, api.NoneLog); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	_ = os.RemoveAll(logDir)
+	os.Exit(code)
+}
+
+// waitFor 在超时时间内轮询条件直到满足
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return cond()
+}
+
+func getAllInstances(consumer polaris.ConsumerAPI, namespace, service string) (*model.InstancesResponse, error) {
+	req := &polaris.GetAllInstancesRequest{}
+	req.Namespace = namespace
+	req.Service = service
+	req.SetTimeout(500 * time.Millisecond)
+	req.SetRetryCount(0)
+	return consumer.GetAllInstances(req)
+}
+
+func TestServerDiscovery(t *testing.T) {
+	srv := Start(t)
+	srv.RegisterService("default", "echo", map[string]string{"owner": "test"})
+	id := srv.AddInstance("default", "echo", "127.0.0.1", 8080, map[string]string{"env": "test"})
+	srv.AddInstance("default", "echo", "127.0.0.1", 8081, nil)
+	consumer := polaris.NewConsumerAPIByContext(srv.SDKContext(t))
+
+	resp, err := getAllInstances(consumer, "default", "echo")
+	if err != nil {
+		t.Fatalf("get all instances: %v", err)
+	}
+	if len(resp.GetInstances()) != 2 {
+		t.Fatalf("expect 2 instances, got %d", len(resp.GetInstances()))
+	}
+	if resp.GetMetadata()["owner"] != "test" {
+		t.Fatalf("expect service metadata, got %v", resp.GetMetadata())
+	}
+
+	// 测试过程中修改数据，SDK 定期刷新后可感知
+	if !srv.UpdateInstance("default", "echo", id, func(instance *apiservice.Instance) {
+		instance.Weight = wrapperspb.UInt32(0)
+	}) {
+		t.Fatalf("instance %s not found", id)
+	}
+	srv.AddInstance("default", "echo", "127.0.0.1", 8082, nil)
+	ok := waitFor(t, 5*time.Second, func() bool {
+		resp, err := getAllInstances(consumer, "default", "echo")
+		if err != nil || len(resp.GetInstances()) != 3 {
+			return false
+		}
+		for _, instance := range resp.GetInstances() {
+			if instance.GetId() == id {
+				return instance.GetWeight() == 0
+			}
+		}
+		return false
+	})
+	if !ok {
+		t.Fatalf("consumer did not observe instance changes")
+	}
+
+	resp, err = getAllInstances(consumer, "default", "missing")
+	if err == nil && len(resp.GetInstances()) > 0 {
+		t.Fatalf("expect no instance for unknown service")
+	}
+}
+
+func TestServerRegister(t *testing.T) {
+	srv := Start(t)
+	provider := polaris.NewProviderAPIByContext(srv.SDKContext(t))
+
+	registerReq := &polaris.InstanceRegisterRequest{}
+	registerReq.Namespace = "default"
+	registerReq.Service = "provider"
+	registerReq.Host = "127.0.0.1"
+	registerReq.Port = 9090
+	registerReq.SetTTL(5)
+	registerResp, err := provider.RegisterInstance(registerReq)
+	if err != nil {
+		t.Fatalf("register instance: %v", err)
+	}
+	instances := srv.Instances("default", "provider")
+	if len(instances) != 1 || instances[0].GetId().GetValue() != registerResp.InstanceID {
+		t.Fatalf("unexpected instances after register: %v", instances)
+	}
+	if !waitFor(t, 5*time.Second, func() bool { return srv.RequestCount(OpHeartbeat) > 0 }) {
+		t.Fatalf("expect heartbeat from provider")
+	}
+
+	deregisterReq := &polaris.InstanceDeRegisterRequest{}
+	deregisterReq.Namespace = "default"
+	deregisterReq.Service = "provider"
+	deregisterReq.Host = "127.0.0.1"
+	deregisterReq.Port = 9090
+	if err = provider.Deregister(deregisterReq); err != nil {
+		t.Fatalf("deregister instance: %v", err)
+	}
+	if instances = srv.Instances("default", "provider"); len(instances) != 0 {
+		t.Fatalf("expect no instance after deregister, got %d", len(instances))
+	}
+}
+
+func TestServerConfigFile(t *testing.T) {
+	srv := Start(t)
+	srv.PublishConfigFile("default", "app", "app.yaml", "key: v1", nil)
+	configAPI := polaris.NewConfigAPIByContext(srv.SDKContext(t))
+
+	file, err := configAPI.GetConfigFile("default", "app", "app.yaml")
+	if err != nil {
+		t.Fatalf("get config file: %v", err)
+	}
+	if file.GetContent() != "key: v1" {
+		t.Fatalf("unexpected content %q", file.GetContent())
+	}
+	// SDK 在订阅后延迟数秒才开始长轮询
+	srv.PublishConfigFile("default", "app", "app.yaml", "key: v2", nil)
+	if !waitFor(t, 15*time.Second, func() bool { return file.GetContent() == "key: v2" }) {
+		t.Fatalf("config file change not observed, content %q", file.GetContent())
+	}
+
+	if err = configAPI.CreateConfigFile("default", "app", "created.yaml", "created"); err != nil {
+		t.Fatalf("create config file: %v", err)
+	}
+	if _, _, ok := srv.ConfigFile("default", "app", "created.yaml"); ok {
+		t.Fatalf("config file should not be visible before publish")
+	}
+	if err = configAPI.PublishConfigFile("default", "app", "created.yaml"); err != nil {
+		t.Fatalf("publish config file: %v", err)
+	}
+	if content, _, ok := srv.ConfigFile("default", "app", "created.yaml"); !ok || content != "created" {
+		t.Fatalf("unexpected published content %q", content)
+	}
+}
+
+func TestServerInjectError(t *testing.T) {
+	srv := Start(t)
+	srv.AddInstance("default", "echo", "127.0.0.1", 8080, nil)
+	srv.InjectError(OpDiscover, apimodel.Code_ExecuteException)
+	consumer := polaris.NewConsumerAPIByContext(srv.SDKContext(t))
+
+	if _, err := getAllInstances(consumer, "default", "echo"); err == nil {
+		t.Fatalf("expect error when discover fails")
+	}
+	if srv.RequestCount(OpDiscover) == 0 {
+		t.Fatalf("expect discover requests to be counted")
+	}
+	srv.ClearFaults()
+	ok := waitFor(t, 5*time.Second, func() bool {
+		resp, err := getAllInstances(consumer, "default", "echo")
+		return err == nil && len(resp.GetInstances()) == 1
+	})
+	if !ok {
+		t.Fatalf("consumer did not recover after faults cleared")
+	}
+}
+
+func TestServerInjectLatency(t *testing.T) {
+	srv := Start(t)
+	srv.AddInstance("default", "echo", "127.0.0.1", 8080, nil)
+	srv.InjectLatency(OpHeartbeat, 200*time.Millisecond)
+	srv.InjectError(OpRegister, apimodel.Code_ExecuteException)
+
+	conn, err := grpc.Dial(srv.Addr(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	client := apiservice.NewPolarisGRPCClient(conn)
+	instance := &apiservice.Instance{
+		Namespace: wrapperspb.String("default"),
+		Service:   wrapperspb.String("echo"),
+		Host:      wrapperspb.String("127.0.0.1"),
+		Port:      wrapperspb.UInt32(8080),
+	}
+	start := time.Now()
+	resp, err := client.Heartbeat(context.Background(), instance)
+	if err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("expect injected latency, elapsed %v", elapsed)
+	}
+	if resp.GetCode().GetValue() != uint32(apimodel.Code_ExecuteSuccess) {
+		t.Fatalf("unexpected heartbeat code %d", resp.GetCode().GetValue())
+	}
+	resp, err = client.RegisterInstance(context.Background(), instance)
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if resp.GetCode().GetValue() != uint32(apimodel.Code_ExecuteException) {
+		t.Fatalf("expect injected code, got %d", resp.GetCode().GetValue())
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	srv := Start(t)
+	srv.SetRateLimit("default", "echo", &apitraffic.RateLimit{
+		Rules: []*apitraffic.Rule{
+			{
+				Name:       wrapperspb.String("global"),
+				Resource:   apitraffic.Rule_QPS,
+				Type:       apitraffic.Rule_GLOBAL,
+				Action:     wrapperspb.String("reject"),
+				AmountMode: apitraffic.Rule_GLOBAL_TOTAL,
+				Amounts: []*apitraffic.Amount{
+					{MaxAmount: wrapperspb.UInt32(5), ValidDuration: durationpb.New(time.Minute)},
+				},
+			},
+		},
+	})
+	limitAPI := polaris.NewLimitAPIByContext(srv.SDKContext(t))
+
+	limited := waitFor(t, 10*time.Second, func() bool {
+		req := polaris.NewQuotaRequest()
+		req.SetNamespace("default")
+		req.SetService("echo")
+		future, err := limitAPI.GetQuota(req)
+		if err != nil {
+			t.Fatalf("get quota: %v", err)
+		}
+		return future.Get().Code == model.QuotaResultLimited
+	})
+	if !limited {
+		t.Fatalf("expect requests to be limited")
+	}
+	if !waitFor(t, 5*time.Second, func() bool { return srv.RequestCount(OpRateLimitInit) > 0 }) {
+		t.Fatalf("expect rate limit init requests")
+	}
+}
+
+func TestRateLimiterWindow(t *testing.T) {
+	limiter := newRateLimiter()
+	initResp := limiter.initialize(&ratelimiter.RateLimitInitRequest{
+		ClientId: "client-1",
+		Target:   &ratelimiter.LimitTarget{Namespace: "default", Service: "echo"},
+		Totals:   []*ratelimiter.QuotaTotal{{Duration: 3600, MaxAmount: 10}},
+	})
+	if initResp.GetClientKey() == 0 || len(initResp.GetCounters()) != 1 {
+		t.Fatalf("unexpected init response %v", initResp)
+	}
+	counter := initResp.GetCounters()[0]
+	if counter.GetLeft() != 10 || counter.GetClientCount() != 1 {
+		t.Fatalf("unexpected counter %v", counter)
+	}
+	reportResp := limiter.acquire(&ratelimiter.RateLimitReportRequest{
+		ClientKey: initResp.GetClientKey(),
+		QuotaUses: []*ratelimiter.QuotaSum{{CounterKey: counter.GetCounterKey(), Used: 4}},
+	})
+	if len(reportResp.GetQuotaLefts()) != 1 || reportResp.GetQuotaLefts()[0].GetLeft() != 6 {
+		t.Fatalf("unexpected report response %v", reportResp)
+	}
+
+	// 另一个客户端初始化同一目标时共享计数器
+	initResp = limiter.initialize(&ratelimiter.RateLimitInitRequest{
+		ClientId: "client-2",
+		Target:   &ratelimiter.LimitTarget{Namespace: "default", Service: "echo"},
+		Totals:   []*ratelimiter.QuotaTotal{{Duration: 3600, MaxAmount: 10}},
+	})
+	shared := initResp.GetCounters()[0]
+	if shared.GetCounterKey() != counter.GetCounterKey() || shared.GetLeft() != 6 || shared.GetClientCount() != 2 {
+		t.Fatalf("unexpected shared counter %v", shared)
+	}
+}